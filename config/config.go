@@ -28,6 +28,32 @@ type Config struct {
 	MidtransServerKey string
 	MidtransClientKey string
 	MidtransEnv       string
+
+	// Maintenance
+	CleaningTurnaroundHours int
+	RepairTurnaroundHours   int
+
+	// Pajak
+	PPNRate      float64
+	PPNInclusive bool
+
+	// Email
+	MailDriver   string
+	MailFrom     string
+	MailDropDir  string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	// Laporan terjadwal
+	ReportSchedulerEnabled bool
+	ReportTimezone         string
+
+	// Rollup dan cache laporan
+	ReportRollupSchedule   string // cron dalam zona waktu laporan, kosong mematikan pembangunan ulang terjadwal
+	ReportRollupWindowDays int    // hari terakhir yang dibangun ulang setiap jadwal, 0 membangun ulang semua hari
+	ReportCacheTTLSeconds  int
 }
 
 func LoadConfig() *Config {
@@ -56,6 +82,32 @@ func LoadConfig() *Config {
 		MidtransServerKey: getEnv("MIDTRANS_SERVER_KEY", "HEHEHE"),
 		MidtransClientKey: getEnv("MIDTRANS_CLIENT_KEY", "HEHEHE"),
 		MidtransEnv:       getEnv("MIDTRANS_ENV", "sandbox"),
+
+		// Maintenance
+		CleaningTurnaroundHours: getEnvAsInt("MAINTENANCE_CLEANING_TURNAROUND_HOURS", 24),
+		RepairTurnaroundHours:   getEnvAsInt("MAINTENANCE_REPAIR_TURNAROUND_HOURS", 72),
+
+		// Pajak
+		PPNRate:      getEnvAsFloat("PPN_RATE", 11),
+		PPNInclusive: getEnvAsBool("PPN_INCLUSIVE", true),
+
+		// Email
+		MailDriver:   getEnv("MAIL_DRIVER", "file"),
+		MailFrom:     getEnv("MAIL_FROM", "ToyRental <no-reply@toyrentals.local>"),
+		MailDropDir:  getEnv("MAIL_DROP_DIR", "mail"),
+		SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		// Laporan terjadwal
+		ReportSchedulerEnabled: getEnvAsBool("REPORT_SCHEDULER_ENABLED", true),
+		ReportTimezone:         getEnv("REPORT_TIMEZONE", "Asia/Jakarta"),
+
+		// Rollup dan cache laporan
+		ReportRollupSchedule:   getEnv("REPORT_ROLLUP_SCHEDULE", "0 2 * * *"),
+		ReportRollupWindowDays: getEnvAsInt("REPORT_ROLLUP_WINDOW_DAYS", 35),
+		ReportCacheTTLSeconds:  getEnvAsInt("REPORT_CACHE_TTL_SECONDS", 300),
 	}
 
 }
@@ -83,3 +135,11 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
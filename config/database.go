@@ -45,6 +45,37 @@ func (db *Database) AutoMigrate() error {
 		&entity.RentalItem{},
 		&entity.Payment{},
 		&entity.UserToken{},
+		&entity.ToyUnit{},
+		&entity.RentalItemUnit{},
+		&entity.ToyUnitLog{},
+		&entity.MaintenanceTask{},
+		&entity.FeePolicy{},
+		&entity.FeePolicyRule{},
+		&entity.PricingRule{},
+		&entity.Holiday{},
+		&entity.RentalPriceLine{},
+		&entity.PaymentItem{},
+		&entity.Promotion{},
+		&entity.PromotionRedemption{},
+		&entity.Wallet{},
+		&entity.WalletEntry{},
+		&entity.JournalEntry{},
+		&entity.JournalLine{},
+		&entity.TaxInvoiceSequence{},
+		&entity.Branch{},
+		&entity.BranchTransfer{},
+		&entity.BranchTransferUnit{},
+		&entity.UserAddress{},
+		&entity.DeliveryZone{},
+		&entity.DeliverySlot{},
+		&entity.DeliveryTask{},
+		&entity.ReportSubscription{},
+		&entity.ReportDelivery{},
+		&entity.ReportDailyRental{},
+		&entity.ReportDailyToy{},
+		&entity.ReportDailyCategory{},
+		&entity.ReportRollupRun{},
+		&entity.ReportRollupDirtyDay{},
 	)
 }
 
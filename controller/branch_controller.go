@@ -0,0 +1,481 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+)
+
+type IBranchController interface {
+	FindAll(c *gin.Context)
+	FinById(c *gin.Context)
+	Insert(c *gin.Context)
+	UpdateById(c *gin.Context)
+	DeleteById(c *gin.Context)
+	ToyAvailability(c *gin.Context)
+	FindTransfers(c *gin.Context)
+	FindTransferById(c *gin.Context)
+	CreateTransfer(c *gin.Context)
+	ReceiveTransfer(c *gin.Context)
+	CancelTransfer(c *gin.Context)
+}
+
+type BranchController struct {
+	branchSvc service.IBranchService
+}
+
+func NewBranchController(branchSvc service.IBranchService) IBranchController {
+	return &BranchController{
+		branchSvc: branchSvc,
+	}
+}
+
+// FindAll godoc
+// @Summary Mengambil daftar cabang yang aktif
+// @Tags Branch
+// @Produce json
+// @Success 200 {array} entity.Branch
+// @Router /branch [get]
+func (b *BranchController) FindAll(c *gin.Context) {
+	var logger = helpers.Logger
+
+	branches, err := b.branchSvc.FindActive(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to find branches: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find branches")
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, branches, nil, "Berhasil mendapatkan cabang")
+}
+
+// FinById godoc
+// @Summary Mengambil cabang berdasarkan id
+// @Tags Branch
+// @Produce json
+// @Param id path string true "Branch ID"
+// @Success 200 {object} entity.Branch
+// @Router /branch/{id} [get]
+func (b *BranchController) FinById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	branch, err := b.branchSvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("branch with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Branch not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to find branch %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, branch, nil, "Berhasil mendapatkan cabang")
+}
+
+// Insert godoc
+// @Summary Membuat cabang
+// @Tags Branch
+// @Accept json
+// @Produce json
+// @Param request body entity.BranchRequest true "Data cabang"
+// @Success 200 {object} entity.Branch
+// @Router /branch [post]
+func (b *BranchController) Insert(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var request entity.BranchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	branch, err := b.branchSvc.CreateBranch(c.Request.Context(), request)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, entity.ErrBranchCodeExists) {
+			status = http.StatusConflict
+		}
+		logger.Error("Gagal membuat cabang: ", err)
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, branch, nil, "Berhasil membuat cabang")
+}
+
+// UpdateById godoc
+// @Summary Memperbarui cabang
+// @Description Jam buka diisi bebas, misalnya "Senin-Jumat 09:00-21:00". Kode cabang utama tidak dapat diubah
+// @Tags Branch
+// @Accept json
+// @Produce json
+// @Param id path string true "Branch ID"
+// @Param request body entity.BranchRequest true "Data cabang"
+// @Success 200 {object} entity.Branch
+// @Router /branch/{id} [put]
+func (b *BranchController) UpdateById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.BranchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	branch, err := b.branchSvc.UpdateBranch(c.Request.Context(), id, request)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("branch with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Branch not found")
+			return
+		}
+
+		status := http.StatusBadRequest
+		if errors.Is(err, entity.ErrBranchCodeExists) || errors.Is(err, entity.ErrDefaultBranchLocked) {
+			status = http.StatusConflict
+		}
+		logger.Error("Gagal memperbarui cabang: ", err)
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, branch, nil, "Berhasil memperbarui cabang")
+}
+
+// DeleteById godoc
+// @Summary Menghapus cabang
+// @Description Cabang yang masih memiliki unit tidak dapat dihapus
+// @Tags Branch
+// @Produce json
+// @Param id path string true "Branch ID"
+// @Success 200 {object} nil
+// @Router /branch/{id} [delete]
+func (b *BranchController) DeleteById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	if err := b.branchSvc.DeleteById(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			logger.Error(fmt.Errorf("branch with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Branch not found")
+		case errors.Is(err, entity.ErrBranchHasUnits), errors.Is(err, entity.ErrDefaultBranchLocked):
+			logger.Error(err)
+			response.ResponseError(c, http.StatusConflict, err.Error())
+		default:
+			logger.Error(fmt.Errorf("failed to delete branch %s: %v", id, err))
+			response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Berhasil menghapus cabang")
+}
+
+// ToyAvailability godoc
+// @Summary Mengambil ketersediaan unit mainan per cabang
+// @Description in_transit adalah unit yang sedang dikirim ke cabang tersebut dan belum dapat disewa
+// @Tags Branch
+// @Produce json
+// @Param id path string true "Toy ID"
+// @Success 200 {array} entity.ToyBranchStock
+// @Router /toy/{id}/availability [get]
+func (b *BranchController) ToyAvailability(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	availability, err := b.branchSvc.FindToyAvailability(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("toy with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Toy not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to find availability of toy %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, availability, nil, "Berhasil mendapatkan ketersediaan mainan")
+}
+
+// FindTransfers godoc
+// @Summary Mengambil daftar transfer unit antar cabang
+// @Tags Branch
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Param status query string false "Filter status (in_transit, received, cancelled)"
+// @Param branch_id query string false "Filter cabang asal atau tujuan (admin cabang selalu dibatasi ke cabangnya)"
+// @Success 200 {array} entity.BranchTransfer
+// @Router /branch-transfer [get]
+func (b *BranchController) FindTransfers(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	filter := entity.BranchTransferFilter{
+		Status:   c.Query("status"),
+		BranchID: branchScope(c),
+	}
+
+	data, totalData, err := b.branchSvc.FindTransfers(c.Request.Context(), filter, limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find branch transfers: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find branch transfers")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan transfer cabang")
+}
+
+// FindTransferById godoc
+// @Summary Mengambil transfer beserta unitnya
+// @Tags Branch
+// @Produce json
+// @Param id path string true "Branch Transfer ID"
+// @Success 200 {object} entity.BranchTransfer
+// @Router /branch-transfer/{id} [get]
+func (b *BranchController) FindTransferById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	transfer, err := b.branchSvc.FindTransferByID(c.Request.Context(), id)
+	if err != nil {
+		b.handleTransferError(c, id, err)
+		return
+	}
+
+	if !canAccessBranch(c, transfer.FromBranchID) && !canAccessBranch(c, transfer.ToBranchID) {
+		b.handleTransferError(c, id, entity.ErrBranchAccessDenied)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, transfer, nil, "Berhasil mendapatkan transfer cabang")
+}
+
+// CreateTransfer godoc
+// @Summary Mengirim unit ke cabang lain
+// @Description Unit harus tersedia di cabang asal. Unit baru dapat disewa di cabang tujuan setelah transfer diterima
+// @Tags Branch
+// @Accept json
+// @Produce json
+// @Param request body entity.CreateBranchTransferRequest true "Data transfer"
+// @Success 200 {object} entity.BranchTransfer
+// @Router /branch-transfer [post]
+func (b *BranchController) CreateTransfer(c *gin.Context) {
+	var logger = helpers.Logger
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	var request entity.CreateBranchTransferRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	// Admin cabang hanya dapat mengirim unit dari cabangnya sendiri
+	if !canAccessBranch(c, request.FromBranchID) {
+		b.handleTransferError(c, "", entity.ErrBranchAccessDenied)
+		return
+	}
+
+	transfer, err := b.branchSvc.CreateTransfer(c.Request.Context(), request, claims.UserID)
+	if err != nil {
+		b.handleTransferError(c, "", err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, transfer, nil, "Unit berhasil dikirim")
+}
+
+// ReceiveTransfer godoc
+// @Summary Menerima transfer di cabang tujuan
+// @Tags Branch
+// @Produce json
+// @Param id path string true "Branch Transfer ID"
+// @Success 200 {object} entity.BranchTransfer
+// @Router /branch-transfer/{id}/receive [put]
+func (b *BranchController) ReceiveTransfer(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	current, err := b.branchSvc.FindTransferByID(c.Request.Context(), id)
+	if err != nil {
+		b.handleTransferError(c, id, err)
+		return
+	}
+
+	if !canAccessBranch(c, current.ToBranchID) {
+		b.handleTransferError(c, id, entity.ErrBranchAccessDenied)
+		return
+	}
+
+	transfer, err := b.branchSvc.ReceiveTransfer(c.Request.Context(), id, claims.UserID)
+	if err != nil {
+		b.handleTransferError(c, id, err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, transfer, nil, "Transfer berhasil diterima")
+}
+
+// CancelTransfer godoc
+// @Summary Membatalkan transfer yang belum diterima
+// @Description Unit dikembalikan ke cabang asal
+// @Tags Branch
+// @Produce json
+// @Param id path string true "Branch Transfer ID"
+// @Success 200 {object} entity.BranchTransfer
+// @Router /branch-transfer/{id}/cancel [put]
+func (b *BranchController) CancelTransfer(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	current, err := b.branchSvc.FindTransferByID(c.Request.Context(), id)
+	if err != nil {
+		b.handleTransferError(c, id, err)
+		return
+	}
+
+	if !canAccessBranch(c, current.FromBranchID) {
+		b.handleTransferError(c, id, entity.ErrBranchAccessDenied)
+		return
+	}
+
+	transfer, err := b.branchSvc.CancelTransfer(c.Request.Context(), id)
+	if err != nil {
+		b.handleTransferError(c, id, err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, transfer, nil, "Transfer berhasil dibatalkan")
+}
+
+func (b *BranchController) handleTransferError(c *gin.Context, id string, err error) {
+	var logger = helpers.Logger
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Error(fmt.Errorf("branch transfer with id %s not found", id))
+		response.ResponseError(c, http.StatusNotFound, "Branch transfer not found")
+	case errors.Is(err, entity.ErrBranchAccessDenied):
+		logger.Error(err)
+		response.ResponseError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, entity.ErrTransferNotInTransit), errors.Is(err, entity.ErrUnitNotAtBranch):
+		logger.Error(err)
+		response.ResponseError(c, http.StatusConflict, err.Error())
+	default:
+		logger.Error("Gagal memproses transfer cabang: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+	}
+}
+
+// claimsBranchID mengembalikan cabang admin yang sedang login, nil untuk admin pusat
+func claimsBranchID(c *gin.Context) *uuid.UUID {
+	claims, exists := c.Get("claims")
+	if !exists {
+		return nil
+	}
+
+	claimsData, ok := claims.(*helpers.ClaimsToken)
+	if !ok || claimsData.Role != entity.RoleAdmin {
+		return nil
+	}
+	return claimsData.BranchID
+}
+
+// branchScope menentukan cabang untuk daftar dan laporan admin. Admin cabang selalu dibatasi
+// ke cabangnya, admin pusat dapat memilih lewat query branch_id atau kosong untuk semua cabang.
+func branchScope(c *gin.Context) string {
+	if branchID := claimsBranchID(c); branchID != nil {
+		return branchID.String()
+	}
+	return c.Query("branch_id")
+}
+
+// canAccessBranch mengizinkan admin pusat atau admin dari cabang tersebut
+func canAccessBranch(c *gin.Context, branchID uuid.UUID) bool {
+	own := claimsBranchID(c)
+	return own == nil || *own == branchID
+}
@@ -0,0 +1,555 @@
+package controller
+
+import (
+	"encoding/csv"
+	"final-project/entity"
+	"final-project/utils/helpers"
+	"final-project/utils/money"
+	"final-project/utils/response"
+	"final-project/utils/xlsx"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	reportFormatJSON = "json"
+	reportFormatCSV  = "csv"
+	reportFormatXLSX = "xlsx"
+
+	mimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// reportTable adalah satu tabel ekspor. Nilai baris boleh berupa string, int, float64,
+// money.Money, time.Time, *time.Time, *int atau nil.
+type reportTable struct {
+	Name    string
+	Headers []string
+	Rows    [][]interface{}
+}
+
+// reportFormat membaca format laporan dari query format, atau dari header Accept jika query kosong.
+// Respons error sudah dikirim saat ok bernilai false.
+func reportFormat(c *gin.Context) (string, bool) {
+	var logger = helpers.Logger
+
+	switch format := strings.ToLower(c.Query("format")); format {
+	case reportFormatJSON, reportFormatCSV, reportFormatXLSX:
+		return format, true
+	case "":
+	default:
+		logger.Error("Format laporan tidak valid: ", format)
+		response.ResponseError(c, http.StatusBadRequest, "Format harus salah satu dari: json, csv, atau xlsx")
+		return "", false
+	}
+
+	switch c.NegotiateFormat(gin.MIMEJSON, "text/csv", mimeXLSX) {
+	case "text/csv":
+		return reportFormatCSV, true
+	case mimeXLSX:
+		return reportFormatXLSX, true
+	default:
+		return reportFormatJSON, true
+	}
+}
+
+// reportFilename menyusun nama berkas ekspor beserta rentang tanggalnya
+func reportFilename(name, startDate, endDate string) string {
+	if startDate == "" {
+		return fmt.Sprintf("laporan-%s_%s", name, time.Now().Format("2006-01-02"))
+	}
+	return fmt.Sprintf("laporan-%s_%s_%s", name, startDate, endDate)
+}
+
+// exportReport mengalirkan tabel laporan sebagai CSV atau XLSX. Dengan locale=id angka memakai
+// titik sebagai pemisah ribuan, koma sebagai desimal, tanggal dd/mm/yyyy dan CSV dipisah titik koma.
+func exportReport(c *gin.Context, format, filename string, tables ...reportTable) {
+	var logger = helpers.Logger
+
+	c.Header("Content-Type", reportContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+	c.Status(http.StatusOK)
+
+	if err := writeReport(c.Writer, format, strings.EqualFold(c.Query("locale"), "id"), tables); err != nil {
+		logger.Error("Gagal menulis ekspor laporan: ", err)
+	}
+}
+
+func reportContentType(format string) string {
+	if format == reportFormatXLSX {
+		return mimeXLSX
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// writeReport menulis tabel laporan ke w, dipakai untuk unduhan maupun lampiran laporan terjadwal
+func writeReport(w io.Writer, format string, indonesian bool, tables []reportTable) error {
+	if format == reportFormatXLSX {
+		return writeReportXLSX(w, indonesian, tables)
+	}
+
+	writer := csv.NewWriter(w)
+	if indonesian {
+		writer.Comma = ';'
+	}
+
+	for i, table := range tables {
+		// Beberapa tabel dalam satu CSV dipisah baris kosong dan diawali namanya
+		if len(tables) > 1 {
+			if i > 0 {
+				_ = writer.Write([]string{})
+			}
+			_ = writer.Write([]string{table.Name})
+		}
+
+		_ = writer.Write(table.Headers)
+		for _, row := range table.Rows {
+			record := make([]string, len(row))
+			for j, value := range row {
+				record[j] = formatReportValue(value, indonesian)
+			}
+			_ = writer.Write(record)
+		}
+		writer.Flush()
+	}
+
+	return writer.Error()
+}
+
+func writeReportXLSX(w io.Writer, indonesian bool, tables []reportTable) error {
+	dateFormat := "yyyy-mm-dd"
+	if indonesian {
+		dateFormat = "dd/mm/yyyy"
+	}
+
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = table.Name
+	}
+
+	writer, err := xlsx.NewWriter(w, dateFormat, names...)
+	if err != nil {
+		return err
+	}
+
+	for i, table := range tables {
+		if i > 0 {
+			if err := writer.NextSheet(); err != nil {
+				return err
+			}
+		}
+
+		headers := make([]xlsx.Cell, len(table.Headers))
+		for j, header := range table.Headers {
+			headers[j] = xlsx.Header(header)
+		}
+		if err := writer.WriteRow(headers...); err != nil {
+			return err
+		}
+
+		for _, row := range table.Rows {
+			cells := make([]xlsx.Cell, len(row))
+			for j, value := range row {
+				cells[j] = reportCell(value)
+			}
+			if err := writer.WriteRow(cells...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writer.Close()
+}
+
+func reportCell(value interface{}) xlsx.Cell {
+	switch v := value.(type) {
+	case int:
+		return xlsx.Integer(int64(v))
+	case *int:
+		if v == nil {
+			return xlsx.Text("")
+		}
+		return xlsx.Integer(int64(*v))
+	case money.Money:
+		return xlsx.Integer(v.Int64())
+	case float64:
+		return xlsx.Decimal(v)
+	case time.Time:
+		return xlsx.Date(v)
+	case *time.Time:
+		if v == nil {
+			return xlsx.Text("")
+		}
+		return xlsx.Date(*v)
+	case nil:
+		return xlsx.Text("")
+	default:
+		return xlsx.Text(spreadsheetText(fmt.Sprint(v)))
+	}
+}
+
+func formatReportValue(value interface{}, indonesian bool) string {
+	switch v := value.(type) {
+	case int:
+		return formatReportNumber(strconv.Itoa(v), indonesian)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return formatReportNumber(strconv.Itoa(*v), indonesian)
+	case money.Money:
+		return formatReportNumber(strconv.FormatInt(v.Int64(), 10), indonesian)
+	case float64:
+		return formatReportNumber(strconv.FormatFloat(v, 'f', 2, 64), indonesian)
+	case time.Time:
+		if indonesian {
+			return v.Format("02/01/2006")
+		}
+		return v.Format("2006-01-02")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return formatReportValue(*v, indonesian)
+	case nil:
+		return ""
+	default:
+		return spreadsheetText(fmt.Sprint(v))
+	}
+}
+
+// formatReportNumber mengubah angka berformat Go ("-1234567.89") menjadi format Indonesia ("-1.234.567,89")
+func formatReportNumber(number string, indonesian bool) string {
+	if !indonesian {
+		return number
+	}
+
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	integer, fraction, hasFraction := strings.Cut(number, ".")
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	if hasFraction {
+		b.WriteByte(',')
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// reportDate menampilkan periode laporan harian atau mingguan sebagai tanggal, periode bulanan tetap teks
+func reportDate(period string) interface{} {
+	if date, err := time.Parse("2006-01-02", period); err == nil {
+		return date
+	}
+	return period
+}
+
+func salesReportTable(items []entity.SalesReportItem) reportTable {
+	table := reportTable{
+		Name:    "Penjualan",
+		Headers: []string{"Periode", "Jumlah Rental", "Pendapatan Sewa", "Denda Keterlambatan", "Denda Kerusakan", "Total Pendapatan", "DPP", "PPN", "Bruto", "Deposit", "Jumlah Transaksi"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{
+			reportDate(item.Date), item.RentalCount, item.RentalRevenue, item.LateFeeRevenue, item.DamageFeeRevenue,
+			item.TotalRevenue, item.NetRevenue, item.TaxAmount, item.GrossRevenue, item.DepositAmount, item.TransactionCount,
+		})
+	}
+	return table
+}
+
+func cashSalesReportTable(items []entity.CashSalesReportItem) reportTable {
+	table := reportTable{
+		Name:    "Penjualan Basis Kas",
+		Headers: []string{"Periode", "Jenis Pembayaran", "Metode Pembayaran", "Jumlah Transaksi", "Kas Diterima", "Refund", "Deposit", "PPN", "Pendapatan Bersih"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{
+			reportDate(item.Date), item.PaymentType, item.PaymentMethod, item.TransactionCount, item.ReceivedAmount,
+			item.RefundedAmount, item.DepositAmount, item.TaxAmount, item.NetRevenue,
+		})
+	}
+	return table
+}
+
+func popularToysTable(items []entity.PopularToyItem) reportTable {
+	table := reportTable{
+		Name:    "Mainan Populer",
+		Headers: []string{"ID Mainan", "Nama Mainan", "Jumlah Rental", "Rata-rata Durasi (Hari)", "Pendapatan"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{
+			item.ToyID.String(), item.ToyName, item.RentalCount, item.AverageDuration, item.Revenue,
+		})
+	}
+	return table
+}
+
+func topCustomersTable(items []entity.TopCustomerItem) reportTable {
+	table := reportTable{
+		Name:    "Pelanggan Teratas",
+		Headers: []string{"ID Pengguna", "Nama", "Email", "Nomor Telepon", "Jumlah Rental", "Total Belanja", "Rata-rata Nilai Rental", "Jumlah Denda Keterlambatan", "Jumlah Denda Kerusakan", "Rental Pertama", "Rental Terakhir"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{
+			item.UserID.String(), item.FullName, item.Email, item.PhoneNumber, item.RentalCount, item.TotalSpent,
+			item.AverageRentalValue, item.LateFeeCount, item.DamageFeeCount, item.FirstRentalDate, item.LastRentalDate,
+		})
+	}
+	return table
+}
+
+// cohortTable menampilkan satu baris per kohort dengan kolom retensi bulan ke-0 sampai bulan terjauh
+func cohortTable(cohorts []entity.CohortItem) reportTable {
+	maxOffset := -1
+	for _, cohort := range cohorts {
+		if n := len(cohort.Retention) - 1; n > maxOffset {
+			maxOffset = n
+		}
+	}
+
+	table := reportTable{
+		Name:    "Kohort",
+		Headers: []string{"Kohort", "Jumlah Pelanggan"},
+	}
+	for offset := 0; offset <= maxOffset; offset++ {
+		table.Headers = append(table.Headers, fmt.Sprintf("Bulan ke-%d (%%)", offset))
+	}
+
+	for _, cohort := range cohorts {
+		row := []interface{}{cohort.CohortMonth, cohort.CustomerCount}
+		for offset := 0; offset <= maxOffset; offset++ {
+			if offset < len(cohort.Retention) {
+				row = append(row, cohort.Retention[offset].RetentionRate)
+			} else {
+				row = append(row, nil)
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+func customerRetentionTable(summary entity.CustomerRetentionSummary) reportTable {
+	return reportTable{
+		Name:    "Ringkasan Retensi",
+		Headers: []string{"Jumlah Pelanggan", "Pelanggan Berulang", "Tingkat Rental Ulang (%)", "Rata-rata Jarak Antar Rental (Hari)", "Rata-rata Nilai Seumur Hidup"},
+		Rows: [][]interface{}{{
+			summary.CustomerCount, summary.RepeatCustomerCount, summary.RepeatRate,
+			summary.AverageDaysBetweenRentals, summary.AverageLifetimeValue,
+		}},
+	}
+}
+
+func customerValueTable(items []entity.CustomerValueItem) reportTable {
+	table := reportTable{
+		Name:    "Nilai Pelanggan",
+		Headers: []string{"ID Pengguna", "Nama", "Email", "Rental Pertama", "Rental Terakhir", "Jumlah Rental", "Nilai Seumur Hidup", "Rata-rata Jarak Antar Rental (Hari)"},
+	}
+	for _, item := range items {
+		var averageDays interface{}
+		if item.AverageDaysBetweenRentals != nil {
+			averageDays = *item.AverageDaysBetweenRentals
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			item.UserID.String(), item.FullName, item.Email, item.FirstRentalDate, item.LastRentalDate,
+			item.RentalCount, item.LifetimeValue, averageDays,
+		})
+	}
+	return table
+}
+
+func rentalStatusTable(items []entity.RentalStatusItem) reportTable {
+	table := reportTable{
+		Name:    "Status Penyewaan",
+		Headers: []string{"Status", "Jumlah", "Persentase (%)"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{item.Status, item.Count, item.PercentageTotal})
+	}
+	return table
+}
+
+func categoryTable(items []entity.CategorySummary) reportTable {
+	table := reportTable{
+		Name:    "Kategori",
+		Headers: []string{"ID Kategori", "Kategori", "Jumlah Rental", "Pendapatan", "Porsi Pendapatan (%)", "Rata-rata Durasi (Hari)", "Tingkat Kerusakan (%)"},
+	}
+	for _, item := range items {
+		categoryID := ""
+		if item.CategoryID != nil {
+			categoryID = item.CategoryID.String()
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			categoryID, item.Name, item.RentalCount, item.Revenue, item.Percentage, item.AverageDuration, item.DamageRate,
+		})
+	}
+	return table
+}
+
+func toyUtilizationTable(name string, items []entity.ToyUtilizationItem) reportTable {
+	table := reportTable{
+		Name:    name,
+		Headers: []string{"ID Mainan", "Nama Mainan", "Jumlah Unit", "Hari-Unit Tersewa", "Hari-Unit Tersedia", "Utilisasi (%)", "Pendapatan", "Pendapatan per Unit", "Rental Terakhir", "Hari Sejak Rental Terakhir"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{
+			item.ToyID.String(), item.ToyName, item.UnitCount, item.RentedUnitDays, item.AvailableUnitDays, item.UtilizationRate,
+			item.Revenue, item.RevenuePerUnit, item.LastRentalDate, item.DaysSinceLastRental,
+		})
+	}
+	return table
+}
+
+func categoryUtilizationTable(items []entity.CategoryUtilizationItem) reportTable {
+	table := reportTable{
+		Name:    "Utilisasi Kategori",
+		Headers: []string{"ID Kategori", "Kategori", "Jumlah Mainan", "Jumlah Unit", "Hari-Unit Tersewa", "Hari-Unit Tersedia", "Utilisasi (%)", "Pendapatan", "Pendapatan per Unit"},
+	}
+	for _, item := range items {
+		categoryID := ""
+		if item.CategoryID != nil {
+			categoryID = item.CategoryID.String()
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			categoryID, item.Name, item.ToyCount, item.UnitCount, item.RentedUnitDays, item.AvailableUnitDays,
+			item.UtilizationRate, item.Revenue, item.RevenuePerUnit,
+		})
+	}
+	return table
+}
+
+func inventoryTable(items []entity.InventoryStatusItem) reportTable {
+	table := reportTable{
+		Name:    "Inventaris",
+		Headers: []string{"ID Mainan", "Nama Mainan", "Kategori", "Kondisi", "Total Unit", "Stok di Cabang", "Tersedia", "Disewa", "Rusak", "Hilang", "Nilai Penggantian"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{
+			item.ToyID.String(), item.ToyName, strings.Join(item.Categories, ", "), item.Condition, item.TotalStock,
+			item.CurrentStock, item.AvailableCount, item.RentedCount, item.DamagedCount, item.LostCount, item.ReplacementCost,
+		})
+	}
+	return table
+}
+
+func dashboardTable(dashboard *entity.DashboardReport) reportTable {
+	change := func(value *float64) interface{} {
+		if value == nil {
+			return nil
+		}
+		return *value
+	}
+
+	current, previous, diff := dashboard.Current, dashboard.Previous, dashboard.Change
+	return reportTable{
+		Name:    "KPI",
+		Headers: []string{"Indikator", "Periode Ini", "Periode Sebelumnya", "Perubahan (%)"},
+		Rows: [][]interface{}{
+			{"Pendapatan", current.Revenue, previous.Revenue, change(diff.Revenue)},
+			{"Jumlah Rental", current.RentalCount, previous.RentalCount, change(diff.RentalCount)},
+			{"Rental Aktif", current.ActiveRentals, previous.ActiveRentals, change(diff.ActiveRentals)},
+			{"Rental Terlambat", current.OverdueCount, previous.OverdueCount, change(diff.OverdueCount)},
+			{"Rata-rata Nilai Rental", current.AverageBasket, previous.AverageBasket, change(diff.AverageBasket)},
+			{"Pelanggan Baru", current.NewCustomers, previous.NewCustomers, change(diff.NewCustomers)},
+			{"Utilisasi (%)", current.UtilizationRate, previous.UtilizationRate, change(diff.UtilizationRate)},
+		},
+	}
+}
+
+func dashboardSeriesTable(series []entity.DashboardPoint) reportTable {
+	table := reportTable{
+		Name:    "Harian",
+		Headers: []string{"Tanggal", "Pendapatan", "Jumlah Rental"},
+	}
+	for _, point := range series {
+		table.Rows = append(table.Rows, []interface{}{reportDate(point.Date), point.Revenue, point.RentalCount})
+	}
+	return table
+}
+
+func damageSummaryTable(summary entity.DamageSummary) reportTable {
+	return reportTable{
+		Name:    "Ringkasan Kerusakan",
+		Headers: []string{"Rental Dikembalikan", "Rental dengan Insiden", "Tingkat Kerusakan (%)", "Unit Rusak", "Unit Hilang", "Biaya Ditagih", "Biaya Terkumpul", "Biaya Belum Terkumpul"},
+		Rows: [][]interface{}{{
+			summary.ReturnedRentals, summary.IncidentRentals, summary.DamageRate, summary.DamagedUnits, summary.LostUnits,
+			summary.FeesCharged, summary.FeesCollected, summary.FeesOutstanding,
+		}},
+	}
+}
+
+func damageToyTable(items []entity.DamageToyItem) reportTable {
+	table := reportTable{
+		Name:    "Kerusakan per Mainan",
+		Headers: []string{"ID Mainan", "Nama Mainan", "Unit Dikembalikan", "Unit Rusak", "Unit Hilang", "Tingkat Insiden (%)", "Biaya Kerusakan Tercatat"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{
+			item.ToyID.String(), item.ToyName, item.ReturnedUnits, item.DamagedUnits, item.LostUnits, item.IncidentRate, item.FeesAssessed,
+		})
+	}
+	return table
+}
+
+func damageCategoryTable(items []entity.DamageCategoryItem) reportTable {
+	table := reportTable{
+		Name:    "Kerusakan per Kategori",
+		Headers: []string{"ID Kategori", "Kategori", "Unit Dikembalikan", "Unit Rusak", "Unit Hilang", "Tingkat Insiden (%)", "Biaya Kerusakan Tercatat"},
+	}
+	for _, item := range items {
+		categoryID := ""
+		if item.CategoryID != nil {
+			categoryID = item.CategoryID.String()
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			categoryID, item.Name, item.ReturnedUnits, item.DamagedUnits, item.LostUnits, item.IncidentRate, item.FeesAssessed,
+		})
+	}
+	return table
+}
+
+func damageCustomerTable(items []entity.DamageCustomerItem) reportTable {
+	table := reportTable{
+		Name:    "Kerusakan per Pelanggan",
+		Headers: []string{"ID Pengguna", "Nama", "Email", "Rental Dikembalikan", "Jumlah Insiden", "Unit Rusak", "Unit Hilang", "Biaya Ditagih", "Biaya Terkumpul", "Ditandai"},
+	}
+	for _, item := range items {
+		flagged := "Tidak"
+		if item.Flagged {
+			flagged = "Ya"
+		}
+		table.Rows = append(table.Rows, []interface{}{
+			item.UserID.String(), item.FullName, item.Email, item.ReturnedRentals, item.IncidentCount, item.DamagedUnits,
+			item.LostUnits, item.FeesCharged, item.FeesCollected, flagged,
+		})
+	}
+	return table
+}
+
+func overdueTable(items []entity.OverdueRentalItem) reportTable {
+	table := reportTable{
+		Name:    "Rental Terlambat",
+		Headers: []string{"ID Rental", "Nama", "Email", "Telepon", "Tanggal Rental", "Tanggal Kembali", "Hari Terlambat", "Jumlah Unit", "Total Harga Rental"},
+	}
+	for _, item := range items {
+		table.Rows = append(table.Rows, []interface{}{
+			item.RentalID.String(), item.FullName, item.Email, item.PhoneNumber, item.RentalDate, item.ExpectedReturnDate,
+			item.DaysOverdue, item.UnitCount, item.TotalRentalPrice,
+		})
+	}
+	return table
+}
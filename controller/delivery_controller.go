@@ -0,0 +1,581 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+)
+
+type IDeliveryController interface {
+	FindZones(c *gin.Context)
+	InsertZone(c *gin.Context)
+	UpdateZone(c *gin.Context)
+	DeleteZone(c *gin.Context)
+	FindSlots(c *gin.Context)
+	InsertSlot(c *gin.Context)
+	UpdateSlot(c *gin.Context)
+	DeleteSlot(c *gin.Context)
+	FindTasks(c *gin.Context)
+	MyTasks(c *gin.Context)
+	FindTaskById(c *gin.Context)
+	AssignCourier(c *gin.Context)
+	UpdateTaskStatus(c *gin.Context)
+}
+
+type DeliveryController struct {
+	deliverySvc service.IDeliveryService
+}
+
+func NewDeliveryController(deliverySvc service.IDeliveryService) IDeliveryController {
+	return &DeliveryController{
+		deliverySvc: deliverySvc,
+	}
+}
+
+// FindZones godoc
+// @Summary Mengambil daftar zona antar jemput
+// @Tags Delivery
+// @Produce json
+// @Param branch_id query string false "Filter cabang (admin cabang selalu dibatasi ke cabangnya)"
+// @Success 200 {array} entity.DeliveryZone
+// @Router /delivery/zone [get]
+func (d *DeliveryController) FindZones(c *gin.Context) {
+	var logger = helpers.Logger
+
+	zones, err := d.deliverySvc.FindZones(c.Request.Context(), branchScope(c))
+	if err != nil {
+		logger.Error("Failed to find delivery zones: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find delivery zones")
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, zones, nil, "Berhasil mendapatkan zona antar jemput")
+}
+
+// InsertZone godoc
+// @Summary Membuat zona antar jemput
+// @Description Isi postal_codes (boleh awalan seperti "401") dan/atau pita jarak min_distance_km sampai max_distance_km dari koordinat cabang. Zona kode pos diutamakan, bila beberapa zona cocok dipilih ongkos termurah
+// @Tags Delivery
+// @Accept json
+// @Produce json
+// @Param request body entity.DeliveryZoneRequest true "Data zona"
+// @Success 200 {object} entity.DeliveryZone
+// @Router /delivery/zone [post]
+func (d *DeliveryController) InsertZone(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var request entity.DeliveryZoneRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	if !canAccessBranch(c, request.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	zone, err := d.deliverySvc.CreateZone(c.Request.Context(), request)
+	if err != nil {
+		logger.Error("Gagal membuat zona antar jemput: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, zone, nil, "Berhasil membuat zona antar jemput")
+}
+
+// UpdateZone godoc
+// @Summary Memperbarui zona antar jemput
+// @Description Ongkos baru hanya berlaku untuk rental berikutnya
+// @Tags Delivery
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery Zone ID"
+// @Param request body entity.DeliveryZoneRequest true "Data zona"
+// @Success 200 {object} entity.DeliveryZone
+// @Router /delivery/zone/{id} [put]
+func (d *DeliveryController) UpdateZone(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.DeliveryZoneRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	zone, err := d.deliverySvc.FindZoneByID(c.Request.Context(), id)
+	if err != nil {
+		d.handleNotFound(c, "delivery zone", id, err)
+		return
+	}
+
+	if !canAccessBranch(c, zone.BranchID) || !canAccessBranch(c, request.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	zone, err = d.deliverySvc.UpdateZone(c.Request.Context(), id, request)
+	if err != nil {
+		logger.Error("Gagal memperbarui zona antar jemput: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, zone, nil, "Berhasil memperbarui zona antar jemput")
+}
+
+// DeleteZone godoc
+// @Summary Menghapus zona antar jemput
+// @Tags Delivery
+// @Produce json
+// @Param id path string true "Delivery Zone ID"
+// @Router /delivery/zone/{id} [delete]
+func (d *DeliveryController) DeleteZone(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	zone, err := d.deliverySvc.FindZoneByID(c.Request.Context(), id)
+	if err != nil {
+		d.handleNotFound(c, "delivery zone", id, err)
+		return
+	}
+
+	if !canAccessBranch(c, zone.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	if err := d.deliverySvc.DeleteZone(c.Request.Context(), id); err != nil {
+		logger.Error("Gagal menghapus zona antar jemput: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Berhasil menghapus zona antar jemput")
+}
+
+// FindSlots godoc
+// @Summary Mengambil jadwal antar jemput yang aktif
+// @Description booked adalah jumlah tugas yang sudah memakai jadwal, jadwal penuh bila booked sama dengan capacity
+// @Tags Delivery
+// @Produce json
+// @Param branch_id query string false "Filter cabang"
+// @Param date query string false "Filter tanggal (YYYY-MM-DD)"
+// @Success 200 {array} entity.DeliverySlot
+// @Router /delivery/slot [get]
+func (d *DeliveryController) FindSlots(c *gin.Context) {
+	var logger = helpers.Logger
+
+	filter := entity.DeliverySlotFilter{
+		BranchID: c.Query("branch_id"),
+		Date:     c.Query("date"),
+	}
+
+	slots, err := d.deliverySvc.FindSlots(c.Request.Context(), filter, true)
+	if err != nil {
+		logger.Error("Failed to find delivery slots: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find delivery slots")
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, slots, nil, "Berhasil mendapatkan jadwal antar jemput")
+}
+
+// InsertSlot godoc
+// @Summary Membuat jadwal antar jemput
+// @Description Kuota berlaku untuk gabungan tugas antar dan jemput pada jadwal tersebut
+// @Tags Delivery
+// @Accept json
+// @Produce json
+// @Param request body entity.DeliverySlotRequest true "Data jadwal"
+// @Success 200 {object} entity.DeliverySlot
+// @Router /delivery/slot [post]
+func (d *DeliveryController) InsertSlot(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var request entity.DeliverySlotRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	if !canAccessBranch(c, request.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	slot, err := d.deliverySvc.CreateSlot(c.Request.Context(), request)
+	if err != nil {
+		logger.Error("Gagal membuat jadwal antar jemput: ", err)
+		response.ResponseError(c, deliveryErrorStatus(err), err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, slot, nil, "Berhasil membuat jadwal antar jemput")
+}
+
+// UpdateSlot godoc
+// @Summary Memperbarui jadwal antar jemput
+// @Description Cabang jadwal tidak dapat diubah. Jadwal yang sudah dipesan tidak dapat digeser waktunya dan kuotanya tidak boleh di bawah jumlah pesanan
+// @Tags Delivery
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery Slot ID"
+// @Param request body entity.DeliverySlotRequest true "Data jadwal"
+// @Success 200 {object} entity.DeliverySlot
+// @Router /delivery/slot/{id} [put]
+func (d *DeliveryController) UpdateSlot(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.DeliverySlotRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	slot, err := d.deliverySvc.FindSlotByID(c.Request.Context(), id)
+	if err != nil {
+		d.handleNotFound(c, "delivery slot", id, err)
+		return
+	}
+
+	if !canAccessBranch(c, slot.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	slot, err = d.deliverySvc.UpdateSlot(c.Request.Context(), id, request)
+	if err != nil {
+		logger.Error("Gagal memperbarui jadwal antar jemput: ", err)
+		response.ResponseError(c, deliveryErrorStatus(err), err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, slot, nil, "Berhasil memperbarui jadwal antar jemput")
+}
+
+// DeleteSlot godoc
+// @Summary Menghapus jadwal antar jemput
+// @Description Jadwal yang sudah dipesan tidak dapat dihapus, nonaktifkan lewat update agar tidak dapat dipilih lagi
+// @Tags Delivery
+// @Produce json
+// @Param id path string true "Delivery Slot ID"
+// @Router /delivery/slot/{id} [delete]
+func (d *DeliveryController) DeleteSlot(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	slot, err := d.deliverySvc.FindSlotByID(c.Request.Context(), id)
+	if err != nil {
+		d.handleNotFound(c, "delivery slot", id, err)
+		return
+	}
+
+	if !canAccessBranch(c, slot.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	if err := d.deliverySvc.DeleteSlot(c.Request.Context(), id); err != nil {
+		logger.Error("Gagal menghapus jadwal antar jemput: ", err)
+		response.ResponseError(c, deliveryErrorStatus(err), err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Berhasil menghapus jadwal antar jemput")
+}
+
+// FindTasks godoc
+// @Summary Mengambil daftar tugas kurir
+// @Description Diurutkan sesuai tanggal dan jam jadwal antar jemput
+// @Tags Delivery
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Param date query string false "Filter tanggal jadwal (YYYY-MM-DD)"
+// @Param status query string false "Filter status (scheduled, on_the_way, completed, failed, cancelled)"
+// @Param type query string false "Filter jenis (delivery, return_pickup)"
+// @Param courier_id query string false "Filter kurir"
+// @Param branch_id query string false "Filter cabang (admin cabang selalu dibatasi ke cabangnya)"
+// @Success 200 {array} entity.DeliveryTask
+// @Router /delivery/task [get]
+func (d *DeliveryController) FindTasks(c *gin.Context) {
+	d.findTasks(c, c.Query("courier_id"))
+}
+
+// MyTasks godoc
+// @Summary Mengambil tugas kurir milik staf yang sedang login
+// @Tags Delivery
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Param date query string false "Filter tanggal jadwal (YYYY-MM-DD)"
+// @Param status query string false "Filter status (scheduled, on_the_way, completed, failed, cancelled)"
+// @Param type query string false "Filter jenis (delivery, return_pickup)"
+// @Success 200 {array} entity.DeliveryTask
+// @Router /delivery/task/mine [get]
+func (d *DeliveryController) MyTasks(c *gin.Context) {
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	d.findTasks(c, claims.UserID.String())
+}
+
+func (d *DeliveryController) findTasks(c *gin.Context, courierID string) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	filter := entity.DeliveryTaskFilter{
+		Date:      c.Query("date"),
+		Status:    c.Query("status"),
+		Type:      c.Query("type"),
+		CourierID: courierID,
+		BranchID:  branchScope(c),
+	}
+
+	data, totalData, err := d.deliverySvc.FindTasks(c.Request.Context(), filter, limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find delivery tasks: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find delivery tasks")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan tugas antar jemput")
+}
+
+// FindTaskById godoc
+// @Summary Mengambil tugas kurir berdasarkan id
+// @Tags Delivery
+// @Produce json
+// @Param id path string true "Delivery Task ID"
+// @Success 200 {object} entity.DeliveryTask
+// @Router /delivery/task/{id} [get]
+func (d *DeliveryController) FindTaskById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	task, err := d.deliverySvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		d.handleNotFound(c, "delivery task", id, err)
+		return
+	}
+
+	if !canAccessBranch(c, task.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, task, nil, "Berhasil mendapatkan tugas antar jemput")
+}
+
+// AssignCourier godoc
+// @Summary Menugaskan kurir ke tugas antar jemput
+// @Description Kurir adalah staf dengan peran admin. Kosongkan courier_id untuk melepas kurir
+// @Tags Delivery
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery Task ID"
+// @Param request body entity.AssignCourierRequest true "Kurir"
+// @Success 200 {object} entity.DeliveryTask
+// @Router /delivery/task/{id}/assign [put]
+func (d *DeliveryController) AssignCourier(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.AssignCourierRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	task, err := d.deliverySvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		d.handleNotFound(c, "delivery task", id, err)
+		return
+	}
+
+	if !canAccessBranch(c, task.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	updated, err := d.deliverySvc.AssignCourier(c.Request.Context(), id, request.CourierID)
+	if err != nil {
+		logger.Error("Gagal menugaskan kurir: ", err)
+		response.ResponseError(c, deliveryErrorStatus(err), err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, updated, nil, "Berhasil menugaskan kurir")
+}
+
+// UpdateTaskStatus godoc
+// @Summary Memperbarui status tugas antar jemput
+// @Description Alur status: scheduled -> on_the_way -> completed atau failed. Tugas gagal dapat dicoba lagi (on_the_way) atau dibatalkan
+// @Tags Delivery
+// @Accept json
+// @Produce json
+// @Param id path string true "Delivery Task ID"
+// @Param request body entity.UpdateDeliveryTaskStatusRequest true "Status baru"
+// @Success 200 {object} entity.DeliveryTask
+// @Router /delivery/task/{id}/status [put]
+func (d *DeliveryController) UpdateTaskStatus(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.UpdateDeliveryTaskStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	task, err := d.deliverySvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		d.handleNotFound(c, "delivery task", id, err)
+		return
+	}
+
+	if !canAccessBranch(c, task.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	updated, err := d.deliverySvc.UpdateTaskStatus(c.Request.Context(), id, request)
+	if err != nil {
+		logger.Error("Gagal memperbarui status tugas antar jemput: ", err)
+		response.ResponseError(c, deliveryErrorStatus(err), err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, updated, nil, "Berhasil memperbarui status tugas antar jemput")
+}
+
+func (d *DeliveryController) handleNotFound(c *gin.Context, name string, id string, err error) {
+	var logger = helpers.Logger
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.Error(fmt.Errorf("%s with id %s not found", name, id))
+		response.ResponseError(c, http.StatusNotFound, "Data not found")
+		return
+	}
+
+	logger.Error(fmt.Errorf("failed to find %s %s: %v", name, id, err))
+	response.ResponseError(c, http.StatusInternalServerError, err.Error())
+}
+
+// deliveryErrorStatus memetakan kesalahan antar jemput ke status HTTP, selain itu dianggap permintaan tidak valid
+func deliveryErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, entity.ErrDeliverySlotFull),
+		errors.Is(err, entity.ErrDeliverySlotExists),
+		errors.Is(err, entity.ErrDeliverySlotInUse),
+		errors.Is(err, entity.ErrInvalidDeliveryTaskState):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrAddressNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// isDeliveryError menandai kesalahan pilihan antar jemput saat membuat atau menghitung harga rental
+func isDeliveryError(err error) bool {
+	for _, target := range []error{
+		entity.ErrInvalidDeliveryMethod,
+		entity.ErrInvalidReturnMethod,
+		entity.ErrDeliveryAddressRequired,
+		entity.ErrDeliverySlotRequired,
+		entity.ErrDeliverySlotNotFound,
+		entity.ErrDeliverySlotMismatch,
+		entity.ErrDeliverySlotFull,
+		entity.ErrOutsideDeliveryZone,
+		entity.ErrAddressNotFound,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type IDocumentController interface {
+	RentalInvoice(c *gin.Context)
+	PaymentReceipt(c *gin.Context)
+}
+
+type DocumentController struct {
+	documentSvc service.IDocumentService
+	rentalSvc   service.IRentalService
+	paymentSvc  service.IPaymentService
+}
+
+func NewDocumentController(documentSvc service.IDocumentService, rentalSvc service.IRentalService, paymentSvc service.IPaymentService) IDocumentController {
+	return &DocumentController{
+		documentSvc: documentSvc,
+		rentalSvc:   rentalSvc,
+		paymentSvc:  paymentSvc,
+	}
+}
+
+// RentalInvoice godoc
+// @Summary Unduh invoice rental (PDF)
+// @Description Rincian sewa, lama sewa, denda, PPN, deposit dan pembayaran. Hanya pemilik rental atau admin
+// @Tags Document
+// @Produce application/pdf
+// @Param id path string true "Rental ID"
+// @Success 200 {file} file
+// @Router /rental/{id}/invoice.pdf [get]
+func (d *DocumentController) RentalInvoice(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	// Kepemilikan diperiksa sebelum PDF dibuat
+	if _, _, ok := authorizeRental(c, d.rentalSvc, id); !ok {
+		return
+	}
+
+	document, err := d.documentSvc.RentalInvoice(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("rental with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Rental not found")
+			return
+		}
+
+		logger.Error("Gagal membuat invoice rental: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	d.send(c, document)
+}
+
+// PaymentReceipt godoc
+// @Summary Unduh kwitansi pembayaran (PDF)
+// @Description Hanya untuk pembayaran yang sudah lunas. Hanya pemilik rental atau admin
+// @Tags Document
+// @Produce application/pdf
+// @Param id path string true "ID Pembayaran"
+// @Success 200 {file} file
+// @Router /payment/{id}/receipt.pdf [get]
+func (d *DocumentController) PaymentReceipt(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("ID is required")
+		response.ResponseError(c, http.StatusBadRequest, "ID wajib diisi")
+		return
+	}
+
+	payment, err := d.paymentSvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("payment with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Payment not found")
+			return
+		}
+
+		logger.Error("Gagal mendapatkan pembayaran: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Kepemilikan diperiksa lewat rentalnya sebelum PDF dibuat
+	if _, _, ok := authorizeRental(c, d.rentalSvc, payment.RentalID.String()); !ok {
+		return
+	}
+
+	document, err := d.documentSvc.PaymentReceipt(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			logger.Error(fmt.Errorf("payment with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Payment not found")
+		case errors.Is(err, entity.ErrPaymentNotSettled):
+			response.ResponseError(c, http.StatusConflict, err.Error())
+		default:
+			logger.Error("Gagal membuat kwitansi pembayaran: ", err)
+			response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	d.send(c, document)
+}
+
+func (d *DocumentController) send(c *gin.Context, document *entity.DocumentFile) {
+	c.Header("Content-Disposition", "attachment; filename="+document.Filename)
+	c.Data(http.StatusOK, document.ContentType, document.Content)
+}
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+)
+
+type IFeePolicyController interface {
+	FindAll(c *gin.Context)
+	FindActive(c *gin.Context)
+	FinById(c *gin.Context)
+	Insert(c *gin.Context)
+	Activate(c *gin.Context)
+}
+
+type FeePolicyController struct {
+	feePolicySvc service.IFeePolicyService
+}
+
+func NewFeePolicyController(feePolicySvc service.IFeePolicyService) IFeePolicyController {
+	return &FeePolicyController{
+		feePolicySvc: feePolicySvc,
+	}
+}
+
+// FindAll godoc
+// @Summary Mengambil semua versi kebijakan biaya
+// @Tags Fee Policy
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.FeePolicy
+// @Router /fee-policy [get]
+func (f *FeePolicyController) FindAll(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	data, totalData, err := f.feePolicySvc.FindAll(c.Request.Context(), limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find fee policies: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find fee policies")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan kebijakan biaya")
+}
+
+// FindActive godoc
+// @Summary Mengambil kebijakan biaya yang sedang aktif
+// @Tags Fee Policy
+// @Produce json
+// @Success 200 {object} entity.FeePolicy
+// @Router /fee-policy/active [get]
+func (f *FeePolicyController) FindActive(c *gin.Context) {
+	var logger = helpers.Logger
+
+	policy, err := f.feePolicySvc.FindActive(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, entity.ErrNoActiveFeePolicy) {
+			logger.Error(err)
+			response.ResponseError(c, http.StatusNotFound, err.Error())
+			return
+		}
+
+		logger.Error("Failed to find active fee policy: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, policy, nil, "Berhasil mendapatkan kebijakan biaya aktif")
+}
+
+// FinById godoc
+// @Summary Mengambil kebijakan biaya berdasarkan id
+// @Tags Fee Policy
+// @Produce json
+// @Param id path string true "Fee Policy ID"
+// @Success 200 {object} entity.FeePolicy
+// @Router /fee-policy/{id} [get]
+func (f *FeePolicyController) FinById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	policy, err := f.feePolicySvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("fee policy with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Fee policy not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to find fee policy %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, policy, nil, "Berhasil mendapatkan kebijakan biaya")
+}
+
+// Insert godoc
+// @Summary Membuat versi kebijakan biaya baru
+// @Description Versi lama tidak diubah; isi activate untuk langsung memakai versi baru
+// @Tags Fee Policy
+// @Accept json
+// @Produce json
+// @Param request body entity.FeePolicyRequest true "Data kebijakan"
+// @Success 200 {object} entity.FeePolicy
+// @Router /fee-policy [post]
+func (f *FeePolicyController) Insert(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var request entity.FeePolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	policy, err := f.feePolicySvc.CreateVersion(c.Request.Context(), request)
+	if err != nil {
+		logger.Error("Gagal membuat kebijakan biaya: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, policy, nil, "Berhasil membuat kebijakan biaya")
+}
+
+// Activate godoc
+// @Summary Mengaktifkan versi kebijakan biaya
+// @Tags Fee Policy
+// @Produce json
+// @Param id path string true "Fee Policy ID"
+// @Success 200 {object} entity.FeePolicy
+// @Router /fee-policy/{id}/activate [put]
+func (f *FeePolicyController) Activate(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	policy, err := f.feePolicySvc.Activate(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("fee policy with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Fee policy not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to activate fee policy %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, policy, nil, "Berhasil mengaktifkan kebijakan biaya")
+}
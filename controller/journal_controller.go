@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"encoding/csv"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/money"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type IJournalController interface {
+	FindAccounts(c *gin.Context)
+	FindEntries(c *gin.Context)
+	TrialBalance(c *gin.Context)
+	Export(c *gin.Context)
+}
+
+type JournalController struct {
+	journalSvc service.IJournalService
+}
+
+func NewJournalController(journalSvc service.IJournalService) IJournalController {
+	return &JournalController{
+		journalSvc: journalSvc,
+	}
+}
+
+// FindAccounts godoc
+// @Summary Bagan akun
+// @Tags Accounting
+// @Produce json
+// @Success 200 {array} entity.Account
+// @Router /accounting/accounts [get]
+func (j *JournalController) FindAccounts(c *gin.Context) {
+	response.ResponseSuccess(c, http.StatusOK, j.journalSvc.FindAccounts(), nil, "Berhasil mendapatkan bagan akun")
+}
+
+// FindEntries godoc
+// @Summary Daftar jurnal
+// @Tags Accounting
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Param start_date query string false "Tanggal mulai (YYYY-MM-DD)"
+// @Param end_date query string false "Tanggal akhir (YYYY-MM-DD)"
+// @Param event query string false "Filter kejadian (payment_settled, payment_refunded, rental_returned, deposit_refunded, wallet_entry, write_off)"
+// @Param rental_id query string false "Filter rental"
+// @Success 200 {array} entity.JournalEntry
+// @Router /accounting/journal [get]
+func (j *JournalController) FindEntries(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	filter, ok := journalFilter(c)
+	if !ok {
+		return
+	}
+	filter.Event = c.Query("event")
+	filter.RentalID = c.Query("rental_id")
+
+	data, totalData, err := j.journalSvc.FindEntries(c.Request.Context(), filter, limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find journal entries: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find journal entries")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan jurnal")
+}
+
+// TrialBalance godoc
+// @Summary Neraca saldo
+// @Description Total debit dan kredit per akun. Tanpa filter tanggal seluruh jurnal dijumlahkan
+// @Tags Accounting
+// @Produce json
+// @Param start_date query string false "Tanggal mulai (YYYY-MM-DD)"
+// @Param end_date query string false "Tanggal akhir (YYYY-MM-DD)"
+// @Success 200 {array} entity.TrialBalanceItem
+// @Router /accounting/trial-balance [get]
+func (j *JournalController) TrialBalance(c *gin.Context) {
+	var logger = helpers.Logger
+
+	filter, ok := journalFilter(c)
+	if !ok {
+		return
+	}
+
+	items, err := j.journalSvc.GetTrialBalance(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("Gagal mendapatkan neraca saldo: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var totalDebit, totalCredit money.Money
+	for _, item := range items {
+		totalDebit += item.Debit
+		totalCredit += item.Credit
+	}
+
+	metadata := map[string]interface{}{
+		"periode_mulai": c.Query("start_date"),
+		"periode_akhir": c.Query("end_date"),
+		"total_debit":   totalDebit,
+		"total_kredit":  totalCredit,
+		"seimbang":      totalDebit == totalCredit,
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, items, metadata, "Berhasil mendapatkan neraca saldo")
+}
+
+// Export godoc
+// @Summary Ekspor jurnal ke CSV
+// @Description Satu baris per akun per jurnal, diurutkan berdasarkan tanggal jurnal
+// @Tags Accounting
+// @Produce text/csv
+// @Param start_date query string true "Tanggal mulai (YYYY-MM-DD)"
+// @Param end_date query string true "Tanggal akhir (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Router /accounting/journal/export [get]
+func (j *JournalController) Export(c *gin.Context) {
+	var logger = helpers.Logger
+
+	if c.Query("start_date") == "" || c.Query("end_date") == "" {
+		logger.Error("Tanggal mulai dan akhir wajib diisi")
+		response.ResponseError(c, http.StatusBadRequest, "Tanggal mulai dan akhir wajib diisi")
+		return
+	}
+
+	filter, ok := journalFilter(c)
+	if !ok {
+		return
+	}
+
+	rows, err := j.journalSvc.ExportJournal(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("Gagal mengekspor jurnal: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("jurnal_%s_%s.csv", c.Query("start_date"), c.Query("end_date"))
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"tanggal", "no_jurnal", "kejadian", "keterangan", "rental_id", "kode_akun", "nama_akun", "debit", "kredit"})
+	for _, row := range rows {
+		rentalID := ""
+		if row.RentalID != nil {
+			rentalID = row.RentalID.String()
+		}
+		account, _ := entity.FindAccount(row.AccountCode)
+
+		_ = writer.Write([]string{
+			row.EntryDate.Format("2006-01-02 15:04:05"),
+			row.JournalEntryID.String(),
+			spreadsheetText(row.Event),
+			spreadsheetText(row.Description),
+			rentalID,
+			spreadsheetText(row.AccountCode),
+			spreadsheetText(account.Name),
+			strconv.FormatInt(row.Debit.Int64(), 10),
+			strconv.FormatInt(row.Credit.Int64(), 10),
+		})
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		logger.Error("Gagal menulis CSV jurnal: ", err)
+	}
+}
+
+// spreadsheetText mencegah teks bebas seperti keterangan dijalankan sebagai rumus saat file ekspor
+// dibuka di aplikasi spreadsheet, dengan menambahkan tanda kutip di depan teks yang diawali =, +, -, @,
+// tab atau carriage return
+func spreadsheetText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// journalFilter membaca filter tanggal opsional. Tanggal akhir mencakup seluruh hari tersebut.
+func journalFilter(c *gin.Context) (entity.JournalFilter, bool) {
+	var logger = helpers.Logger
+	var filter entity.JournalFilter
+
+	if startDateStr := c.Query("start_date"); startDateStr != "" {
+		startDate, err := time.Parse("2006-01-02", startDateStr)
+		if err != nil {
+			logger.Error("Format tanggal mulai tidak valid: ", err)
+			response.ResponseError(c, http.StatusBadRequest, "Format tanggal mulai tidak valid (YYYY-MM-DD)")
+			return filter, false
+		}
+		filter.StartDate = &startDate
+	}
+
+	if endDateStr := c.Query("end_date"); endDateStr != "" {
+		endDate, err := time.Parse("2006-01-02", endDateStr)
+		if err != nil {
+			logger.Error("Format tanggal akhir tidak valid: ", err)
+			response.ResponseError(c, http.StatusBadRequest, "Format tanggal akhir tidak valid (YYYY-MM-DD)")
+			return filter, false
+		}
+		endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+		filter.EndDate = &endDate
+	}
+
+	if filter.StartDate != nil && filter.EndDate != nil && filter.EndDate.Before(*filter.StartDate) {
+		logger.Error("Tanggal akhir tidak boleh sebelum tanggal mulai")
+		response.ResponseError(c, http.StatusBadRequest, "Tanggal akhir tidak boleh sebelum tanggal mulai")
+		return filter, false
+	}
+
+	return filter, true
+}
@@ -0,0 +1,239 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+)
+
+type IMaintenanceTaskController interface {
+	FindAll(c *gin.Context)
+	FinById(c *gin.Context)
+	Start(c *gin.Context)
+	Complete(c *gin.Context)
+}
+
+type MaintenanceTaskController struct {
+	maintenanceSvc service.IMaintenanceTaskService
+}
+
+func NewMaintenanceTaskController(maintenanceSvc service.IMaintenanceTaskService) IMaintenanceTaskController {
+	return &MaintenanceTaskController{
+		maintenanceSvc: maintenanceSvc,
+	}
+}
+
+// FindAll godoc
+// @Summary Mengambil antrean tugas perawatan unit
+// @Description Tanpa filter status, hanya tugas yang belum selesai yang ditampilkan
+// @Tags Maintenance
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Param status query string false "Filter status (pending, in_progress, completed)"
+// @Param type query string false "Filter jenis (cleaning, repair)"
+// @Param toy_id query string false "Filter mainan"
+// @Param branch_id query string false "Filter cabang unit (admin cabang selalu dibatasi ke cabangnya)"
+// @Param overdue query bool false "Hanya tugas yang melewati tenggat"
+// @Success 200 {array} entity.MaintenanceTask
+// @Router /maintenance [get]
+func (m *MaintenanceTaskController) FindAll(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	filter := entity.MaintenanceTaskFilter{
+		Status:   c.Query("status"),
+		Type:     c.Query("type"),
+		ToyID:    c.Query("toy_id"),
+		BranchID: branchScope(c),
+		Overdue:  c.Query("overdue") == "true",
+	}
+
+	data, totalData, err := m.maintenanceSvc.FindAllFiltered(c.Request.Context(), filter, limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find maintenance tasks: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find maintenance tasks")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan antrean perawatan")
+}
+
+// FinById godoc
+// @Summary Mengambil tugas perawatan berdasarkan id
+// @Tags Maintenance
+// @Produce json
+// @Param id path string true "Maintenance Task ID"
+// @Success 200 {object} entity.MaintenanceTask
+// @Router /maintenance/{id} [get]
+func (m *MaintenanceTaskController) FinById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	task, err := m.maintenanceSvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("maintenance task with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Maintenance task not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to find maintenance task %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, task, nil, "Berhasil mendapatkan tugas perawatan")
+}
+
+// Start godoc
+// @Summary Mulai mengerjakan tugas perawatan
+// @Tags Maintenance
+// @Accept json
+// @Produce json
+// @Param id path string true "Maintenance Task ID"
+// @Param request body entity.StartMaintenanceTaskRequest false "Catatan"
+// @Success 200 {object} entity.MaintenanceTask
+// @Router /maintenance/{id}/start [put]
+func (m *MaintenanceTaskController) Start(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	var request entity.StartMaintenanceTaskRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			logger.Error("Gagal binding request: ", err)
+			response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+			return
+		}
+	}
+
+	task, err := m.maintenanceSvc.StartTask(c.Request.Context(), id, claims.UserID, request)
+	if err != nil {
+		m.handleTaskError(c, id, err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, task, nil, "Tugas perawatan mulai dikerjakan")
+}
+
+// Complete godoc
+// @Summary Menyelesaikan tugas perawatan
+// @Description released mengembalikan unit ke stok, repair mengalihkan ke perbaikan, written_off mempensiunkan unit
+// @Tags Maintenance
+// @Accept json
+// @Produce json
+// @Param id path string true "Maintenance Task ID"
+// @Param request body entity.CompleteMaintenanceTaskRequest true "Hasil perawatan"
+// @Success 200 {object} entity.MaintenanceTask
+// @Router /maintenance/{id}/complete [put]
+func (m *MaintenanceTaskController) Complete(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	var request entity.CompleteMaintenanceTaskRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	task, err := m.maintenanceSvc.CompleteTask(c.Request.Context(), id, claims.UserID, request)
+	if err != nil {
+		m.handleTaskError(c, id, err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, task, nil, "Tugas perawatan selesai")
+}
+
+func (m *MaintenanceTaskController) handleTaskError(c *gin.Context, id string, err error) {
+	var logger = helpers.Logger
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		logger.Error(fmt.Errorf("maintenance task with id %s not found", id))
+		response.ResponseError(c, http.StatusNotFound, "Maintenance task not found")
+	case errors.Is(err, entity.ErrMaintenanceTaskClosed):
+		logger.Error(err)
+		response.ResponseError(c, http.StatusConflict, err.Error())
+	default:
+		logger.Error(fmt.Errorf("failed to update maintenance task %s: %v", id, err))
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+	}
+}
+
+func requestClaims(c *gin.Context) (*helpers.ClaimsToken, bool) {
+	var logger = helpers.Logger
+
+	claims, exists := c.Get("claims")
+	if !exists {
+		logger.Error("Claims not found in context")
+		response.ResponseError(c, http.StatusUnauthorized, "Claims not found in context")
+		return nil, false
+	}
+
+	claimsData, ok := claims.(*helpers.ClaimsToken)
+	if !ok {
+		logger.Error("Invalid claims type")
+		response.ResponseError(c, http.StatusUnauthorized, "Invalid claims type")
+		return nil, false
+	}
+
+	return claimsData, true
+}
+
+// canAccessOwnedBy mengizinkan admin atau pemilik data
+func canAccessOwnedBy(claims *helpers.ClaimsToken, userID uuid.UUID) bool {
+	return claims.Role == entity.RoleAdmin || claims.UserID == userID
+}
@@ -1,11 +1,14 @@
 package controller
 
 import (
+	"errors"
 	"final-project/entity"
 	"final-project/service"
 	"final-project/utils/helpers"
 	"final-project/utils/response"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"net/http"
 )
 
@@ -14,15 +17,18 @@ type IPaymentController interface {
 	GetPaymentByID(c *gin.Context)
 	GetPaymentsByRentalID(c *gin.Context)
 	HandlePaymentCallback(c *gin.Context)
+	GetTaxInvoice(c *gin.Context)
 }
 
 type PaymentController struct {
 	paymentSvc service.IPaymentService
+	rentalSvc  service.IRentalService
 }
 
-func NewPaymentController(paymentSvc service.IPaymentService) IPaymentController {
+func NewPaymentController(paymentSvc service.IPaymentService, rentalSvc service.IRentalService) IPaymentController {
 	return &PaymentController{
 		paymentSvc: paymentSvc,
+		rentalSvc:  rentalSvc,
 	}
 }
 
@@ -44,10 +50,24 @@ func (p *PaymentController) CreatePayment(c *gin.Context) {
 		return
 	}
 
-	payment, err := p.paymentSvc.CreatePaymentForRental(c.Request.Context(), request.RentalID)
+	// Kepemilikan diperiksa sebelum saldo dompet pemilik rental dipotong
+	claims, rental, ok := authorizeRental(c, p.rentalSvc, request.RentalID)
+	if !ok {
+		return
+	}
+	if request.UseWallet && claims.UserID != rental.UserID {
+		response.ResponseError(c, http.StatusForbidden, "Saldo dompet hanya dapat dipakai oleh pemilik rental")
+		return
+	}
+
+	payment, err := p.paymentSvc.CreatePaymentForRental(c.Request.Context(), request.RentalID, request.UseWallet)
 	if err != nil {
 		logger.Error("Failed to create payment: ", err)
-		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		status := http.StatusInternalServerError
+		if errors.Is(err, entity.ErrNothingToSettle) {
+			status = http.StatusBadRequest
+		}
+		response.ResponseError(c, status, err.Error())
 		return
 	}
 
@@ -136,3 +156,49 @@ func (p *PaymentController) HandlePaymentCallback(c *gin.Context) {
 
 	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Callback berhasil diproses")
 }
+
+// GetTaxInvoice godoc
+// @Summary Faktur pajak pembayaran
+// @Description Nomor faktur berurutan, DPP dan PPN untuk pembayaran lunas yang dikenai PPN. Hanya pemilik rental atau admin
+// @Tags Payment
+// @Produce json
+// @Param id path string true "ID Pembayaran"
+// @Success 200 {object} entity.TaxInvoice
+// @Router /payment/{id}/tax-invoice [get]
+func (p *PaymentController) GetTaxInvoice(c *gin.Context) {
+	var logger = helpers.Logger
+
+	id := c.Param("id")
+	if id == "" {
+		logger.Error("ID is required")
+		response.ResponseError(c, http.StatusBadRequest, "ID wajib diisi")
+		return
+	}
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	invoice, err := p.paymentSvc.GetTaxInvoice(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			logger.Error(fmt.Errorf("payment with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Payment not found")
+		case errors.Is(err, entity.ErrTaxInvoiceNotFound):
+			response.ResponseError(c, http.StatusNotFound, err.Error())
+		default:
+			logger.Error("Gagal mendapatkan faktur pajak: ", err)
+			response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if !canAccessOwnedBy(claims, invoice.UserID) {
+		response.ResponseError(c, http.StatusForbidden, "Anda tidak memiliki akses ke faktur ini")
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, invoice, nil, "Berhasil mendapatkan faktur pajak")
+}
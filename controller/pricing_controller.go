@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+)
+
+type IPricingController interface {
+	FindAllRules(c *gin.Context)
+	InsertRule(c *gin.Context)
+	UpdateRule(c *gin.Context)
+	DeleteRule(c *gin.Context)
+	FindAllHolidays(c *gin.Context)
+	InsertHoliday(c *gin.Context)
+	DeleteHoliday(c *gin.Context)
+}
+
+type PricingController struct {
+	pricingSvc service.IPricingService
+}
+
+func NewPricingController(pricingSvc service.IPricingService) IPricingController {
+	return &PricingController{
+		pricingSvc: pricingSvc,
+	}
+}
+
+// FindAllRules godoc
+// @Summary Mengambil semua aturan harga
+// @Tags Pricing
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.PricingRule
+// @Router /pricing/rule [get]
+func (p *PricingController) FindAllRules(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	data, totalData, err := p.pricingSvc.FindAll(c.Request.Context(), limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find pricing rules: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find pricing rules")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan aturan harga")
+}
+
+// InsertRule godoc
+// @Summary Membuat aturan harga
+// @Description Jenis aturan: weekend_surcharge, holiday_surcharge, atau long_rental_discount
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param request body entity.PricingRuleRequest true "Data aturan"
+// @Success 200 {object} entity.PricingRule
+// @Router /pricing/rule [post]
+func (p *PricingController) InsertRule(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var request entity.PricingRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	rule, err := p.pricingSvc.CreateRule(c.Request.Context(), request)
+	if err != nil {
+		logger.Error("Gagal membuat aturan harga: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, rule, nil, "Berhasil membuat aturan harga")
+}
+
+// UpdateRule godoc
+// @Summary Memperbarui aturan harga
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Pricing Rule ID"
+// @Param request body entity.PricingRuleRequest true "Data aturan"
+// @Success 200 {object} entity.PricingRule
+// @Router /pricing/rule/{id} [put]
+func (p *PricingController) UpdateRule(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.PricingRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	rule, err := p.pricingSvc.UpdateRule(c.Request.Context(), id, request)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("pricing rule with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Pricing rule not found")
+			return
+		}
+
+		logger.Error("Gagal memperbarui aturan harga: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, rule, nil, "Berhasil memperbarui aturan harga")
+}
+
+// DeleteRule godoc
+// @Summary Menghapus aturan harga
+// @Tags Pricing
+// @Produce json
+// @Param id path string true "Pricing Rule ID"
+// @Success 200 {object} nil
+// @Router /pricing/rule/{id} [delete]
+func (p *PricingController) DeleteRule(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	if _, err := p.pricingSvc.FindById(c.Request.Context(), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("pricing rule with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Pricing rule not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to find pricing rule %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := p.pricingSvc.DeleteById(c.Request.Context(), id); err != nil {
+		logger.Error(fmt.Errorf("failed to delete pricing rule %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Berhasil menghapus aturan harga")
+}
+
+// FindAllHolidays godoc
+// @Summary Mengambil semua hari libur
+// @Tags Pricing
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.Holiday
+// @Router /pricing/holiday [get]
+func (p *PricingController) FindAllHolidays(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	data, totalData, err := p.pricingSvc.FindAllHolidays(c.Request.Context(), limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find holidays: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find holidays")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan hari libur")
+}
+
+// InsertHoliday godoc
+// @Summary Menambahkan hari libur
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param request body entity.HolidayRequest true "Data hari libur"
+// @Success 200 {object} entity.Holiday
+// @Router /pricing/holiday [post]
+func (p *PricingController) InsertHoliday(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var request entity.HolidayRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	holiday, err := p.pricingSvc.CreateHoliday(c.Request.Context(), request)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, entity.ErrHolidayExists) {
+			status = http.StatusConflict
+		}
+		logger.Error("Gagal menambahkan hari libur: ", err)
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, holiday, nil, "Berhasil menambahkan hari libur")
+}
+
+// DeleteHoliday godoc
+// @Summary Menghapus hari libur
+// @Tags Pricing
+// @Produce json
+// @Param id path string true "Holiday ID"
+// @Success 200 {object} nil
+// @Router /pricing/holiday/{id} [delete]
+func (p *PricingController) DeleteHoliday(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	if err := p.pricingSvc.DeleteHoliday(c.Request.Context(), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("holiday with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Holiday not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to delete holiday %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Berhasil menghapus hari libur")
+}
@@ -0,0 +1,355 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/money"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+	"time"
+)
+
+type IPromotionController interface {
+	FindAll(c *gin.Context)
+	FindById(c *gin.Context)
+	FindRedemptions(c *gin.Context)
+	Insert(c *gin.Context)
+	UpdateById(c *gin.Context)
+	DeleteById(c *gin.Context)
+	UsageReport(c *gin.Context)
+}
+
+type PromotionController struct {
+	promotionSvc service.IPromotionService
+}
+
+func NewPromotionController(promotionSvc service.IPromotionService) IPromotionController {
+	return &PromotionController{
+		promotionSvc: promotionSvc,
+	}
+}
+
+// FindAll godoc
+// @Summary Mengambil semua promo
+// @Tags Promotion
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.Promotion
+// @Router /promotion [get]
+func (p *PromotionController) FindAll(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	data, totalData, err := p.promotionSvc.FindAll(c.Request.Context(), limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find promotions: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find promotions")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan promo")
+}
+
+// FindById godoc
+// @Summary Mengambil promo berdasarkan ID
+// @Tags Promotion
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} entity.Promotion
+// @Router /promotion/{id} [get]
+func (p *PromotionController) FindById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	promotion, err := p.promotionSvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("promotion with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Promotion not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to find promotion %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, promotion, nil, "Berhasil mendapatkan promo")
+}
+
+// FindRedemptions godoc
+// @Summary Mengambil riwayat pemakaian promo
+// @Tags Promotion
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.PromotionRedemption
+// @Router /promotion/{id}/redemptions [get]
+func (p *PromotionController) FindRedemptions(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	data, totalData, err := p.promotionSvc.FindRedemptions(c.Request.Context(), id, limitInt, offset)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("promotion with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Promotion not found")
+			return
+		}
+
+		logger.Error("Failed to find promotion redemptions: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find promotion redemptions")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan riwayat pemakaian promo")
+}
+
+// Insert godoc
+// @Summary Membuat promo
+// @Description Jenis potongan: percentage atau fixed. Kosongkan category_ids dan toy_ids agar promo berlaku untuk semua mainan
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Param request body entity.PromotionRequest true "Data promo"
+// @Success 200 {object} entity.Promotion
+// @Router /promotion [post]
+func (p *PromotionController) Insert(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var request entity.PromotionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	promotion, err := p.promotionSvc.CreatePromotion(c.Request.Context(), request)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, entity.ErrPromotionCodeExists) {
+			status = http.StatusConflict
+		}
+		logger.Error("Gagal membuat promo: ", err)
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, promotion, nil, "Berhasil membuat promo")
+}
+
+// UpdateById godoc
+// @Summary Memperbarui promo
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Param request body entity.PromotionRequest true "Data promo"
+// @Success 200 {object} entity.Promotion
+// @Router /promotion/{id} [put]
+func (p *PromotionController) UpdateById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.PromotionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	promotion, err := p.promotionSvc.UpdatePromotion(c.Request.Context(), id, request)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("promotion with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Promotion not found")
+			return
+		}
+
+		status := http.StatusBadRequest
+		if errors.Is(err, entity.ErrPromotionCodeExists) {
+			status = http.StatusConflict
+		}
+		logger.Error("Gagal memperbarui promo: ", err)
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, promotion, nil, "Berhasil memperbarui promo")
+}
+
+// DeleteById godoc
+// @Summary Menghapus promo
+// @Tags Promotion
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} nil
+// @Router /promotion/{id} [delete]
+func (p *PromotionController) DeleteById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	if _, err := p.promotionSvc.FindById(c.Request.Context(), id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("promotion with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Promotion not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to find promotion %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := p.promotionSvc.DeleteById(c.Request.Context(), id); err != nil {
+		logger.Error(fmt.Errorf("failed to delete promotion %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Berhasil menghapus promo")
+}
+
+// UsageReport godoc
+// @Summary Laporan pemakaian promo
+// @Description Jumlah pemakaian, pengguna unik, total potongan dan pendapatan rental per promo dalam rentang waktu tertentu
+// @Tags Promotion
+// @Produce json
+// @Param start_date query string true "Tanggal mulai (YYYY-MM-DD)"
+// @Param end_date query string true "Tanggal akhir (YYYY-MM-DD)"
+// @Success 200 {array} entity.PromotionUsageItem
+// @Router /promotion/report [get]
+func (p *PromotionController) UsageReport(c *gin.Context) {
+	var logger = helpers.Logger
+
+	startDateStr := c.Query("start_date")
+	endDateStr := c.Query("end_date")
+
+	if startDateStr == "" || endDateStr == "" {
+		logger.Error("Tanggal mulai dan akhir wajib diisi")
+		response.ResponseError(c, http.StatusBadRequest, "Tanggal mulai dan akhir wajib diisi")
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", startDateStr)
+	if err != nil {
+		logger.Error("Format tanggal mulai tidak valid: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format tanggal mulai tidak valid (YYYY-MM-DD)")
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", endDateStr)
+	if err != nil {
+		logger.Error("Format tanggal akhir tidak valid: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format tanggal akhir tidak valid (YYYY-MM-DD)")
+		return
+	}
+
+	endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+
+	if endDate.Before(startDate) {
+		logger.Error("Tanggal akhir tidak boleh sebelum tanggal mulai")
+		response.ResponseError(c, http.StatusBadRequest, "Tanggal akhir tidak boleh sebelum tanggal mulai")
+		return
+	}
+
+	report, err := p.promotionSvc.GetUsageReport(c.Request.Context(), startDate, endDate)
+	if err != nil {
+		logger.Error("Gagal mendapatkan laporan promo: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var totalRedemptions int
+	var totalDiscount money.Money
+	for _, item := range report {
+		totalRedemptions += item.RedemptionCount
+		totalDiscount += item.TotalDiscount
+	}
+
+	metadata := map[string]interface{}{
+		"periode_mulai":   startDateStr,
+		"periode_akhir":   endDateStr,
+		"total_pemakaian": totalRedemptions,
+		"total_potongan":  totalDiscount,
+		"jumlah_promo":    len(report),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, report, metadata, "Berhasil mendapatkan laporan pemakaian promo")
+}
+
+// promotionErrorStatus memetakan kesalahan kode promo ke status HTTP. Kuota yang habis
+// dianggap konflik karena bergantung pada pemakaian oleh pesanan lain.
+func promotionErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, entity.ErrPromotionUsageExceeded), errors.Is(err, entity.ErrPromotionUserLimit):
+		return http.StatusConflict, true
+	case errors.Is(err, entity.ErrPromotionNotFound),
+		errors.Is(err, entity.ErrPromotionInactive),
+		errors.Is(err, entity.ErrPromotionMinOrder),
+		errors.Is(err, entity.ErrPromotionNotApplicable):
+		return http.StatusBadRequest, true
+	}
+	return 0, false
+}
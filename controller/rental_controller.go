@@ -20,6 +20,11 @@ type IRentalController interface {
 	UpdateById(c *gin.Context)
 	DeleteById(c *gin.Context)
 	ReturnRental(c *gin.Context)
+	RefundDeposit(c *gin.Context)
+	Balance(c *gin.Context)
+	Settle(c *gin.Context)
+	Quote(c *gin.Context)
+	WriteOff(c *gin.Context)
 }
 
 type RentalController struct {
@@ -38,6 +43,7 @@ func NewRentalController(rentalSvc service.IRentalService) IRentalController {
 // @Produce json
 // @Param page query string false "Page"
 // @Param limit query string false "Limit"
+// @Param branch_id query string false "Filter cabang pengambilan atau pengembalian (admin cabang selalu dibatasi ke cabangnya)"
 // @Success 200 {object} entity.Rental
 // @Router /rental [get]
 func (r *RentalController) FindAll(c *gin.Context) {
@@ -51,7 +57,17 @@ func (r *RentalController) FindAll(c *gin.Context) {
 
 	var offset = (pageInt - 1) * limitInt
 
-	data, totalData, err := r.RentalSvc.FindAll(c.Request.Context(), limitInt, offset)
+	var (
+		data      []entity.Rental
+		totalData int64
+		err       error
+	)
+
+	if branchID := branchScope(c); branchID != "" {
+		data, totalData, err = r.RentalSvc.FindAllByBranch(c.Request.Context(), branchID, limitInt, offset)
+	} else {
+		data, totalData, err = r.RentalSvc.FindAll(c.Request.Context(), limitInt, offset)
+	}
 	if err != nil {
 		logger.Error("Failed to find all rentals: ", err)
 		response.ResponseError(c, http.StatusInternalServerError, "Failed to find all rentals")
@@ -103,6 +119,7 @@ func (r *RentalController) FinById(c *gin.Context) {
 
 // Insert godoc
 // @Summary Insert rental baru
+// @Description Pilih delivery_method delivery dan/atau return_method pickup untuk antar jemput. Ongkosnya ditagihkan sebagai rincian tersendiri
 // @Tags Rental
 // @Accept json
 // @Produce json
@@ -138,6 +155,22 @@ func (r *RentalController) Insert(c *gin.Context) {
 	rental, err := r.RentalSvc.CreateRental(c.Request.Context(), reqBody)
 	if err != nil {
 		logger.Error("Failed to insert rental: ", err)
+		if errors.Is(err, entity.ErrInsufficientUnits) {
+			response.ResponseError(c, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.Is(err, entity.ErrBelowMinRentalDays) || errors.Is(err, entity.ErrAboveMaxRentalDays) || errors.Is(err, entity.ErrBranchNotFound) || errors.Is(err, entity.ErrBranchInactive) {
+			response.ResponseError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if status, ok := promotionErrorStatus(err); ok {
+			response.ResponseError(c, status, err.Error())
+			return
+		}
+		if isDeliveryError(err) {
+			response.ResponseError(c, deliveryErrorStatus(err), err.Error())
+			return
+		}
 		response.ResponseError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -145,6 +178,48 @@ func (r *RentalController) Insert(c *gin.Context) {
 	response.ResponseSuccess(c, http.StatusOK, rental, nil, "Success insert rental")
 }
 
+// Quote godoc
+// @Summary Menghitung rincian harga rental sebelum booking
+// @Tags Rental
+// @Accept json
+// @Produce json
+// @Param request body entity.QuoteRentalRequest true "Periode dan item rental"
+// @Success 200 {object} entity.PriceQuote
+// @Router /rental/quote [post]
+func (r *RentalController) Quote(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var reqBody entity.QuoteRentalRequest
+	if err := c.ShouldBindJSON(&reqBody); err != nil {
+		logger.Error("Failed to bind JSON: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Failed to bind JSON")
+		return
+	}
+
+	// Batas pemakaian per pengguna hanya bisa dicek bila pemanggil sudah login
+	if claims, exists := c.Get("claims"); exists {
+		if claimsData, ok := claims.(*helpers.ClaimsToken); ok {
+			reqBody.UserID = claimsData.UserID
+		}
+	}
+
+	quote, err := r.RentalSvc.QuoteRental(c.Request.Context(), reqBody)
+	if err != nil {
+		logger.Error("Failed to quote rental: ", err)
+		status := http.StatusBadRequest
+		if promoStatus, ok := promotionErrorStatus(err); ok {
+			status = promoStatus
+		}
+		if isDeliveryError(err) {
+			status = deliveryErrorStatus(err)
+		}
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, quote, nil, "Berhasil menghitung harga rental")
+}
+
 // UpdateById godoc
 // @Summary Perpanjang sewa rental
 // @Tags Rental
@@ -238,6 +313,7 @@ func (r *RentalController) DeleteById(c *gin.Context) {
 
 // Return godoc
 // @Summary Pengembalian rental
+// @Description Unit yang kembali tercatat di return_branch_id, default cabang admin cabang atau cabang pengembalian rental
 // @Tags Rental
 // @Accept json
 // @Produce json
@@ -254,15 +330,222 @@ func (r *RentalController) ReturnRental(c *gin.Context) {
 		return
 	}
 
+	// Admin cabang menerima barang di cabangnya sendiri
+	if request.ReturnBranchID == nil {
+		request.ReturnBranchID = claimsBranchID(c)
+	} else if !canAccessBranch(c, *request.ReturnBranchID) {
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
 	rental, err := r.RentalSvc.ReturnRental(c.Request.Context(), idStr, request)
 	if err != nil {
 		status := http.StatusBadRequest
 		if err.Error() == "rental tidak ditemukan" {
 			status = http.StatusNotFound
 		}
+		if errors.Is(err, entity.ErrRentalReturned) {
+			status = http.StatusConflict
+		}
 		response.ResponseError(c, status, err.Error())
 		return
 	}
 
 	response.ResponseSuccess(c, http.StatusOK, rental, nil, "Success return")
 }
+
+// RefundDeposit godoc
+// @Summary Mengulang pengembalian sisa deposit
+// @Description Hanya untuk rental dengan status deposit refund_pending
+// @Tags Rental
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Success 200 {object} entity.Rental
+// @Router /rental/{id}/deposit/refund [put]
+func (r *RentalController) RefundDeposit(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	rental, err := r.RentalSvc.RefundDeposit(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("rental with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Rental not found")
+			return
+		}
+
+		logger.Error("Gagal mengembalikan deposit: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, rental, nil, "Berhasil mengembalikan deposit")
+}
+
+// Balance godoc
+// @Summary Sisa tagihan rental
+// @Description Total tagihan, yang sudah dibayar, dan sisa tagihan per komponen
+// @Tags Rental
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Success 200 {object} entity.RentalBalance
+// @Router /rental/{id}/balance [get]
+func (r *RentalController) Balance(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	if _, _, ok := authorizeRental(c, r.RentalSvc, id); !ok {
+		return
+	}
+
+	balance, err := r.RentalSvc.GetBalance(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("rental with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Rental not found")
+			return
+		}
+
+		logger.Error("Gagal menghitung sisa tagihan: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, balance, nil, "Berhasil mendapatkan sisa tagihan rental")
+}
+
+// Settle godoc
+// @Summary Membayar sisa tagihan rental
+// @Description Membuat pembayaran hanya untuk tagihan yang belum dibayar, termasuk denda setelah pengembalian
+// @Tags Rental
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Param use_wallet query bool false "Pakai saldo dompet lebih dulu"
+// @Success 200 {object} entity.Payment
+// @Router /rental/{id}/settle [post]
+func (r *RentalController) Settle(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	claims, rental, ok := authorizeRental(c, r.RentalSvc, id)
+	if !ok {
+		return
+	}
+
+	// Saldo dompet hanya boleh dipakai pemiliknya, admin menagih sisa lewat pembayaran biasa
+	useWallet := c.DefaultQuery("use_wallet", "false") == "true"
+	if useWallet && claims.UserID != rental.UserID {
+		response.ResponseError(c, http.StatusForbidden, "Saldo dompet hanya dapat dipakai oleh pemilik rental")
+		return
+	}
+
+	payment, err := r.RentalSvc.SettleRental(c.Request.Context(), id, useWallet)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("rental with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Rental not found")
+			return
+		}
+		if errors.Is(err, entity.ErrNothingToSettle) {
+			response.ResponseError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		logger.Error("Gagal membuat pembayaran sisa tagihan: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, payment, nil, "Pembayaran sisa tagihan berhasil dibuat")
+}
+
+// WriteOff godoc
+// @Summary Menghapus sisa tagihan rental yang tidak tertagih
+// @Description Hanya untuk rental yang sudah dikembalikan. Sisa tagihan dicatat sebagai beban penghapusan piutang dan rental ditutup
+// @Tags Rental
+// @Accept json
+// @Produce json
+// @Param id path string true "Rental ID"
+// @Param request body entity.WriteOffRentalRequest true "Alasan penghapusan"
+// @Success 200 {object} entity.Rental
+// @Router /rental/{id}/write-off [put]
+func (r *RentalController) WriteOff(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.WriteOffRentalRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	rental, err := r.RentalSvc.WriteOff(c.Request.Context(), id, request)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("rental with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Rental not found")
+			return
+		}
+
+		logger.Error("Gagal menghapus sisa tagihan: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, rental, nil, "Berhasil menghapus sisa tagihan rental")
+}
+
+// authorizeRental memuat rental dan hanya mengizinkan admin atau pemiliknya. Response error sudah
+// dikirim bila hasilnya false.
+func authorizeRental(c *gin.Context, rentalSvc service.IRentalService, id string) (*helpers.ClaimsToken, entity.Rental, bool) {
+	var logger = helpers.Logger
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return nil, entity.Rental{}, false
+	}
+
+	rental, err := rentalSvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("rental with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Rental not found")
+			return nil, rental, false
+		}
+
+		logger.Error(fmt.Errorf("failed to find rental by id %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return nil, rental, false
+	}
+
+	if !canAccessOwnedBy(claims, rental.UserID) {
+		response.ResponseError(c, http.StatusForbidden, "Anda tidak memiliki akses ke rental ini")
+		return nil, rental, false
+	}
+	return claims, rental, true
+}
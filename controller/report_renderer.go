@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/mailer"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type reportRenderer struct {
+	reportSvc service.IBusinessReportService
+}
+
+// NewReportRenderer menyusun lampiran laporan terjadwal dengan tabel dan format yang sama seperti
+// ekspor dari endpoint business-report
+func NewReportRenderer(reportSvc service.IBusinessReportService) service.IReportRenderer {
+	return &reportRenderer{
+		reportSvc: reportSvc,
+	}
+}
+
+func (r *reportRenderer) Render(ctx context.Context, subscription entity.ReportSubscription, startDate, endDate time.Time) (mailer.Attachment, error) {
+	name, tables, err := r.tables(ctx, subscription, startDate, endDate)
+	if err != nil {
+		return mailer.Attachment{}, err
+	}
+
+	var buf bytes.Buffer
+	if err := writeReport(&buf, subscription.Format, strings.EqualFold(subscription.Parameters.Locale, "id"), tables); err != nil {
+		return mailer.Attachment{}, err
+	}
+
+	filename := reportFilename(name, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	return mailer.Attachment{
+		Filename:    filename + "." + subscription.Format,
+		ContentType: reportContentType(subscription.Format),
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// tables memanggil laporan sesuai jenis langganan. Nilai parameter kosong memakai default yang sama
+// dengan query endpoint laporan.
+func (r *reportRenderer) tables(ctx context.Context, subscription entity.ReportSubscription, startDate, endDate time.Time) (string, []reportTable, error) {
+	params := subscription.Parameters
+	branchID := ""
+	if subscription.BranchID != nil {
+		branchID = subscription.BranchID.String()
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	switch subscription.ReportType {
+	case entity.ReportTypeSales:
+		if params.Basis == entity.SalesBasisCash {
+			items, err := r.reportSvc.GetCashSalesReport(ctx, startDate, endDate, params.GroupBy, branchID)
+			return "penjualan-kas", []reportTable{cashSalesReportTable(items)}, err
+		}
+		items, err := r.reportSvc.GetSalesReport(ctx, startDate, endDate, params.GroupBy, branchID)
+		return "penjualan", []reportTable{salesReportTable(items)}, err
+
+	case entity.ReportTypePopularToys:
+		items, err := r.reportSvc.GetPopularToysReport(ctx, startDate, endDate, limit, branchID)
+		return "mainan-populer", []reportTable{popularToysTable(items)}, err
+
+	case entity.ReportTypeTopCustomers:
+		items, err := r.reportSvc.GetTopCustomersReport(ctx, startDate, endDate, limit, branchID)
+		return "pelanggan-teratas", []reportTable{topCustomersTable(items)}, err
+
+	case entity.ReportTypeRentalStatus:
+		items, err := r.reportSvc.GetRentalStatusReport(ctx, startDate, endDate, branchID)
+		return "status-penyewaan", []reportTable{rentalStatusTable(items)}, err
+
+	case entity.ReportTypeCategories:
+		items, err := r.reportSvc.GetCategoryReport(ctx, startDate, endDate, branchID)
+		return "kategori", []reportTable{categoryTable(items)}, err
+
+	case entity.ReportTypeUtilization:
+		utilization, err := r.reportSvc.GetUtilizationReport(ctx, startDate, endDate, params.IdleDays, branchID)
+		if err != nil {
+			return "", nil, err
+		}
+		return "utilisasi", []reportTable{
+			toyUtilizationTable("Utilisasi Mainan", utilization.Toys),
+			categoryUtilizationTable(utilization.Categories),
+			toyUtilizationTable("Mainan Menganggur", utilization.IdleToys),
+		}, nil
+
+	case entity.ReportTypeDamage:
+		damage, err := r.reportSvc.GetDamageReport(ctx, startDate, endDate, params.MinIncidents, branchID)
+		if err != nil {
+			return "", nil, err
+		}
+		return "kerusakan", []reportTable{
+			damageSummaryTable(damage.Summary),
+			damageToyTable(damage.Toys),
+			damageCategoryTable(damage.Categories),
+			damageCustomerTable(damage.Customers),
+		}, nil
+
+	case entity.ReportTypeInventory:
+		filter := entity.InventoryStatusFilter{
+			CategoryID: params.CategoryID,
+			Condition:  params.Condition,
+		}
+		items, err := r.reportSvc.GetInventoryReport(ctx, filter, branchID)
+		return "inventaris", []reportTable{inventoryTable(items)}, err
+
+	case entity.ReportTypeDashboard:
+		dashboard, err := r.reportSvc.GetDashboard(ctx, startDate, endDate, branchID)
+		if err != nil {
+			return "", nil, err
+		}
+		return "dashboard", []reportTable{
+			dashboardTable(dashboard),
+			dashboardSeriesTable(dashboard.Series),
+		}, nil
+
+	case entity.ReportTypeOverdue:
+		// Rental terlambat adalah posisi saat laporan dikirim, bukan rentang periode
+		items, err := r.reportSvc.GetOverdueReport(ctx, time.Now(), branchID)
+		return "rental-terlambat", []reportTable{overdueTable(items)}, err
+
+	default:
+		return "", nil, fmt.Errorf("jenis laporan tidak dikenal: %s", subscription.ReportType)
+	}
+}
@@ -0,0 +1,295 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+)
+
+type IReportSubscriptionController interface {
+	FindAll(c *gin.Context)
+	FindById(c *gin.Context)
+	Insert(c *gin.Context)
+	UpdateById(c *gin.Context)
+	DeleteById(c *gin.Context)
+	FindDeliveries(c *gin.Context)
+	SendNow(c *gin.Context)
+}
+
+type ReportSubscriptionController struct {
+	subscriptionSvc service.IReportSubscriptionService
+}
+
+func NewReportSubscriptionController(subscriptionSvc service.IReportSubscriptionService) IReportSubscriptionController {
+	return &ReportSubscriptionController{
+		subscriptionSvc: subscriptionSvc,
+	}
+}
+
+// FindAll godoc
+// @Summary Mengambil daftar langganan laporan
+// @Tags Report Subscription
+// @Produce json
+// @Param branch_id query string false "Filter cabang (admin cabang selalu dibatasi ke cabangnya)"
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.ReportSubscription
+// @Router /report-subscription [get]
+func (r *ReportSubscriptionController) FindAll(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	data, totalData, err := r.subscriptionSvc.FindAll(c.Request.Context(), branchScope(c), limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find report subscriptions: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find report subscriptions")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan langganan laporan")
+}
+
+// FindById godoc
+// @Summary Mengambil langganan laporan berdasarkan ID
+// @Tags Report Subscription
+// @Produce json
+// @Param id path string true "Report Subscription ID"
+// @Success 200 {object} entity.ReportSubscription
+// @Router /report-subscription/{id} [get]
+func (r *ReportSubscriptionController) FindById(c *gin.Context) {
+	subscription, ok := r.findAccessible(c)
+	if !ok {
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, subscription, nil, "Berhasil mendapatkan langganan laporan")
+}
+
+// Insert godoc
+// @Summary Membuat langganan laporan
+// @Description Laporan dikirim ke email penerima sesuai jadwal cron lima kolom (menit jam tanggal bulan hari) dalam zona waktu laporan, misalnya "0 7 * * 1" untuk setiap Senin pukul 07.00. Periode laporan (parameters.period) dihitung mundur dari waktu pengiriman: yesterday, last_7_days (default), last_week, last_30_days atau last_month. Admin cabang hanya dapat berlangganan laporan cabangnya
+// @Tags Report Subscription
+// @Accept json
+// @Produce json
+// @Param request body entity.ReportSubscriptionRequest true "Data langganan laporan"
+// @Success 200 {object} entity.ReportSubscription
+// @Router /report-subscription [post]
+func (r *ReportSubscriptionController) Insert(c *gin.Context) {
+	var logger = helpers.Logger
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	var request entity.ReportSubscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	if own := claimsBranchID(c); own != nil {
+		request.BranchID = own
+	}
+
+	subscription, err := r.subscriptionSvc.CreateSubscription(c.Request.Context(), request, claims.UserID)
+	if err != nil {
+		logger.Error("Gagal membuat langganan laporan: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, subscription, nil, "Berhasil membuat langganan laporan")
+}
+
+// UpdateById godoc
+// @Summary Memperbarui langganan laporan
+// @Description Jadwal berikutnya dihitung ulang dari waktu perubahan
+// @Tags Report Subscription
+// @Accept json
+// @Produce json
+// @Param id path string true "Report Subscription ID"
+// @Param request body entity.ReportSubscriptionRequest true "Data langganan laporan"
+// @Success 200 {object} entity.ReportSubscription
+// @Router /report-subscription/{id} [put]
+func (r *ReportSubscriptionController) UpdateById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	existing, ok := r.findAccessible(c)
+	if !ok {
+		return
+	}
+
+	var request entity.ReportSubscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	if own := claimsBranchID(c); own != nil {
+		request.BranchID = own
+	}
+
+	subscription, err := r.subscriptionSvc.UpdateSubscription(c.Request.Context(), existing.ID.String(), request)
+	if err != nil {
+		logger.Error("Gagal memperbarui langganan laporan: ", err)
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, subscription, nil, "Berhasil memperbarui langganan laporan")
+}
+
+// DeleteById godoc
+// @Summary Menghapus langganan laporan
+// @Tags Report Subscription
+// @Produce json
+// @Param id path string true "Report Subscription ID"
+// @Success 200 {object} nil
+// @Router /report-subscription/{id} [delete]
+func (r *ReportSubscriptionController) DeleteById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	subscription, ok := r.findAccessible(c)
+	if !ok {
+		return
+	}
+
+	if err := r.subscriptionSvc.DeleteById(c.Request.Context(), subscription.ID.String()); err != nil {
+		logger.Error(fmt.Errorf("failed to delete report subscription %s: %v", subscription.ID, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Berhasil menghapus langganan laporan")
+}
+
+// FindDeliveries godoc
+// @Summary Mengambil riwayat pengiriman langganan laporan
+// @Tags Report Subscription
+// @Produce json
+// @Param id path string true "Report Subscription ID"
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.ReportDelivery
+// @Router /report-subscription/{id}/deliveries [get]
+func (r *ReportSubscriptionController) FindDeliveries(c *gin.Context) {
+	var logger = helpers.Logger
+
+	subscription, ok := r.findAccessible(c)
+	if !ok {
+		return
+	}
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	data, totalData, err := r.subscriptionSvc.FindDeliveries(c.Request.Context(), subscription.ID.String(), limitInt, offset)
+	if err != nil {
+		logger.Error("Failed to find report deliveries: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find report deliveries")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan riwayat pengiriman laporan")
+}
+
+// SendNow godoc
+// @Summary Mengirim laporan langganan sekarang
+// @Description Laporan untuk periode yang dihitung dari waktu sekarang dikirim tanpa mengubah jadwal berikutnya. Hasilnya tercatat di riwayat pengiriman
+// @Tags Report Subscription
+// @Produce json
+// @Param id path string true "Report Subscription ID"
+// @Success 200 {object} entity.ReportDelivery
+// @Router /report-subscription/{id}/send [post]
+func (r *ReportSubscriptionController) SendNow(c *gin.Context) {
+	var logger = helpers.Logger
+
+	subscription, ok := r.findAccessible(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := r.subscriptionSvc.SendNow(c.Request.Context(), subscription.ID.String())
+	if err != nil {
+		logger.Error(fmt.Errorf("failed to send report subscription %s: %v", subscription.ID, err))
+		status := http.StatusInternalServerError
+		if delivery != nil {
+			// Riwayat gagal sudah tercatat, kesalahannya ada di penyusunan laporan atau server email
+			status = http.StatusBadGateway
+		}
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, delivery, nil, "Berhasil mengirim laporan")
+}
+
+// findAccessible mengambil langganan dari path id dan memastikan admin cabang hanya mengakses
+// langganan cabangnya. Respons error sudah dikirim saat ok bernilai false.
+func (r *ReportSubscriptionController) findAccessible(c *gin.Context) (*entity.ReportSubscription, bool) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return nil, false
+	}
+
+	subscription, err := r.subscriptionSvc.FindById(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("report subscription with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Report subscription not found")
+			return nil, false
+		}
+
+		logger.Error(fmt.Errorf("failed to find report subscription %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	if own := claimsBranchID(c); own != nil && (subscription.BranchID == nil || *subscription.BranchID != *own) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return nil, false
+	}
+
+	return subscription, true
+}
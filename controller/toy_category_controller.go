@@ -16,6 +16,7 @@ import (
 type IToyCategoryController interface {
 	FindAll(c *gin.Context)
 	FinById(c *gin.Context)
+	Tree(c *gin.Context)
 	Insert(c *gin.Context)
 	UpdateById(c *gin.Context)
 	DeleteById(c *gin.Context)
@@ -99,38 +100,51 @@ func (tc *ToyCategoryController) FinById(c *gin.Context) {
 	response.ResponseSuccess(c, http.StatusOK, data, nil, "Success get toy category")
 }
 
+// Tree godoc
+// @Summary Mengambil pohon kategori mainan
+// @Tags Toy Category
+// @Produce json
+// @Success 200 {array} entity.ToyCategoryTree
+// @Router /toy/category/tree [get]
+func (tc *ToyCategoryController) Tree(c *gin.Context) {
+	var logger = helpers.Logger
+
+	tree, err := tc.toyCategorySvc.GetTree(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to get toy category tree: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to get toy category tree")
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, tree, nil, "Success get toy category tree")
+}
+
 // Insert godoc
 // @Summary Insert toy category
 // @Tags Toy Category
 // @Accept json
 // @Produce json
-// @Param toy_category body entity.ToyCategory true "Toy Category"
-// @Success 200 {object} response.APISuccessResponse
+// @Param toy_category body entity.ToyCategoryRequest true "Toy Category"
+// @Success 200 {object} entity.ToyCategory
 // @Router /toy/category [post]
 func (tc *ToyCategoryController) Insert(c *gin.Context) {
 	var logger = helpers.Logger
 
-	var reqBody entity.ToyCategory
+	var reqBody entity.ToyCategoryRequest
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
 		logger.Error("Failed to bind JSON: ", err)
 		response.ResponseError(c, http.StatusBadRequest, "Failed to bind JSON")
 		return
 	}
 
-	if err := reqBody.Validate(); err != nil {
-		logger.Error("Failed to validate toy category: ", err)
-		response.ResponseError(c, http.StatusBadRequest, "Failed to validate toy category")
-		return
-	}
-
-	err := tc.toyCategorySvc.Insert(c.Request.Context(), &reqBody)
+	category, err := tc.toyCategorySvc.CreateCategory(c.Request.Context(), reqBody)
 	if err != nil {
 		logger.Error("Failed to insert toy category: ", err)
-		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		response.ResponseError(c, categoryErrorStatus(err, http.StatusBadRequest), err.Error())
 		return
 	}
 
-	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Success insert toy category")
+	response.ResponseSuccess(c, http.StatusOK, category, nil, "Success insert toy category")
 }
 
 // UpdateById godoc
@@ -139,8 +153,8 @@ func (tc *ToyCategoryController) Insert(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Toy Category ID"
-// @Param toy_category body entity.ToyCategory true "Toy Category"
-// @Success 200 {object} response.APISuccessResponse
+// @Param toy_category body entity.ToyCategoryRequest true "Toy Category"
+// @Success 200 {object} entity.ToyCategory
 // @Router /toy/category/{id} [put]
 func (tc *ToyCategoryController) UpdateById(c *gin.Context) {
 	var logger = helpers.Logger
@@ -152,20 +166,14 @@ func (tc *ToyCategoryController) UpdateById(c *gin.Context) {
 		return
 	}
 
-	var reqBody entity.ToyCategory
+	var reqBody entity.ToyCategoryRequest
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
 		logger.Error("Failed to bind JSON: ", err)
 		response.ResponseError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if err := reqBody.Validate(); err != nil {
-		logger.Error("Failed to validate toy category: ", err)
-		response.ResponseError(c, http.StatusBadRequest, err)
-		return
-	}
-
-	err := tc.toyCategorySvc.UpdateById(c.Request.Context(), id, &reqBody)
+	category, err := tc.toyCategorySvc.UpdateCategory(c.Request.Context(), id, reqBody)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Error(fmt.Errorf("toy category with id %s not found", id))
@@ -174,17 +182,19 @@ func (tc *ToyCategoryController) UpdateById(c *gin.Context) {
 		}
 
 		logger.Error(fmt.Errorf("failed to update toy category by id %s: %v", id, err))
-		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		response.ResponseError(c, categoryErrorStatus(err, http.StatusBadRequest), err.Error())
 		return
 	}
 
-	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Success update toy category")
+	response.ResponseSuccess(c, http.StatusOK, category, nil, "Success update toy category")
 }
 
 // DeleteById godoc
 // @Summary Delete toy berdasarkan id
 // @Tags Toy Category
+// @Description Kategori yang masih memiliki mainan atau sub kategori hanya dapat dihapus dengan reassign_to
 // @Param id path string true "Toy Category ID"
+// @Param reassign_to query string false "ID kategori tujuan untuk mainan dan sub kategori"
 // @Success 200 {object} response.APISuccessResponse
 // @Router /toy/category/{id} [delete]
 func (tc *ToyCategoryController) DeleteById(c *gin.Context) {
@@ -197,7 +207,7 @@ func (tc *ToyCategoryController) DeleteById(c *gin.Context) {
 		return
 	}
 
-	err := tc.toyCategorySvc.DeleteById(c.Request.Context(), id)
+	err := tc.toyCategorySvc.DeleteCategory(c.Request.Context(), id, c.Query("reassign_to"))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			logger.Error(fmt.Errorf("toy category with id %s not found", id))
@@ -206,9 +216,21 @@ func (tc *ToyCategoryController) DeleteById(c *gin.Context) {
 		}
 
 		logger.Error(fmt.Errorf("failed to delete toy category by id %s: %v", id, err))
-		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		response.ResponseError(c, categoryErrorStatus(err, http.StatusInternalServerError), err.Error())
 		return
 	}
 
 	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Success delete toy category")
 }
+
+func categoryErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, entity.ErrCategoryHasToys),
+		errors.Is(err, entity.ErrCategoryHasChildren),
+		errors.Is(err, entity.ErrCategorySlugConflict):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrCategoryInvalidMove):
+		return http.StatusBadRequest
+	}
+	return fallback
+}
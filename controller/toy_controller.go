@@ -37,6 +37,8 @@ func NewToyController(toySvc service.IToyService) IToyController {
 // @Produce json
 // @Param page query string false "Page"
 // @Param limit query string false "Limit"
+// @Param category_id query string false "Filter kategori (termasuk sub kategori)"
+// @Param branch_id query string false "Hanya mainan yang tersedia di cabang ini, stock berisi jumlah unit di cabang"
 // @Success 200 {object} entity.Toy
 // @Router /toy [get]
 func (t ToyController) FindAll(c *gin.Context) {
@@ -50,7 +52,18 @@ func (t ToyController) FindAll(c *gin.Context) {
 
 	var offset = (pageInt - 1) * limitInt
 
-	data, totalData, err := t.toySvc.FindAll(c.Request.Context(), limitInt, offset)
+	var (
+		data      []entity.Toy
+		totalData int64
+		err       error
+	)
+
+	categoryID, branchID := c.Query("category_id"), c.Query("branch_id")
+	if categoryID != "" || branchID != "" {
+		data, totalData, err = t.toySvc.Search(c.Request.Context(), categoryID, branchID, limitInt, offset)
+	} else {
+		data, totalData, err = t.toySvc.FindAll(c.Request.Context(), limitInt, offset)
+	}
 	if err != nil {
 		logger.Error("Failed to find all toys: ", err)
 		response.ResponseError(c, http.StatusInternalServerError, "Failed to find all toys")
@@ -0,0 +1,200 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+type IToyUnitController interface {
+	FindByToyID(c *gin.Context)
+	Register(c *gin.Context)
+	UpdateById(c *gin.Context)
+	History(c *gin.Context)
+}
+
+type ToyUnitController struct {
+	toyUnitSvc service.IToyUnitService
+}
+
+func NewToyUnitController(toyUnitSvc service.IToyUnitService) IToyUnitController {
+	return &ToyUnitController{
+		toyUnitSvc: toyUnitSvc,
+	}
+}
+
+// FindByToyID godoc
+// @Summary Mengambil daftar unit sebuah mainan
+// @Tags Toy Unit
+// @Produce json
+// @Param id path string true "Toy ID"
+// @Param status query string false "Filter status (available, rented, maintenance, retired, lost)"
+// @Param branch_id query string false "Filter cabang (admin cabang selalu dibatasi ke cabangnya)"
+// @Success 200 {array} entity.ToyUnit
+// @Router /toy/{id}/units [get]
+func (t *ToyUnitController) FindByToyID(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	units, err := t.toyUnitSvc.FindByToyID(c.Request.Context(), id, c.Query("status"), branchScope(c))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("toy with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Toy not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to find units of toy %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, units, nil, "Berhasil mendapatkan unit mainan")
+}
+
+// Register godoc
+// @Summary Mendaftarkan unit baru untuk sebuah mainan
+// @Description Isi serial_numbers untuk nomor seri manual, atau quantity untuk nomor seri otomatis.
+// @Description Tanpa branch_id unit disimpan di cabang utama, atau di cabang admin untuk admin cabang
+// @Tags Toy Unit
+// @Accept json
+// @Produce json
+// @Param id path string true "Toy ID"
+// @Param request body entity.RegisterToyUnitsRequest true "Data unit"
+// @Success 200 {array} entity.ToyUnit
+// @Router /toy/{id}/units [post]
+func (t *ToyUnitController) Register(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.RegisterToyUnitsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	if request.BranchID == nil {
+		request.BranchID = claimsBranchID(c)
+	} else if !canAccessBranch(c, *request.BranchID) {
+		logger.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	units, err := t.toyUnitSvc.RegisterUnits(c.Request.Context(), id, request)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("toy with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Toy not found")
+			return
+		}
+
+		status := http.StatusBadRequest
+		if errors.Is(err, entity.ErrSerialNumberExists) {
+			status = http.StatusConflict
+		}
+		logger.Error("Gagal mendaftarkan unit: ", err)
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, units, nil, "Berhasil mendaftarkan unit mainan")
+}
+
+// UpdateById godoc
+// @Summary Memperbarui status atau kondisi unit
+// @Tags Toy Unit
+// @Accept json
+// @Produce json
+// @Param id path string true "Toy Unit ID"
+// @Param request body entity.UpdateToyUnitRequest true "Data unit"
+// @Success 200 {object} entity.ToyUnit
+// @Router /toy/unit/{id} [put]
+func (t *ToyUnitController) UpdateById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	var request entity.UpdateToyUnitRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	unit, err := t.toyUnitSvc.UpdateUnit(c.Request.Context(), id, request)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("toy unit with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Toy unit not found")
+			return
+		}
+
+		status := http.StatusBadRequest
+		if errors.Is(err, entity.ErrUnitRented) || errors.Is(err, entity.ErrUnitInMaintenance) || errors.Is(err, entity.ErrUnitInTransit) {
+			status = http.StatusConflict
+		}
+		logger.Error("Gagal memperbarui unit: ", err)
+		response.ResponseError(c, status, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, unit, nil, "Berhasil memperbarui unit mainan")
+}
+
+// History godoc
+// @Summary Mengambil riwayat rental dan kondisi sebuah unit
+// @Tags Toy Unit
+// @Produce json
+// @Param id path string true "Toy Unit ID"
+// @Success 200 {object} entity.ToyUnitHistory
+// @Router /toy/unit/{id}/history [get]
+func (t *ToyUnitController) History(c *gin.Context) {
+	var logger = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		logger.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	history, err := t.toyUnitSvc.GetHistory(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("toy unit with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "Toy unit not found")
+			return
+		}
+
+		logger.Error(fmt.Errorf("failed to get history of toy unit %s: %v", id, err))
+		response.ResponseError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, history, nil, "Berhasil mendapatkan riwayat unit mainan")
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type IUserAddressController interface {
+	FindAll(c *gin.Context)
+	FinById(c *gin.Context)
+	Insert(c *gin.Context)
+	UpdateById(c *gin.Context)
+	DeleteById(c *gin.Context)
+}
+
+type UserAddressController struct {
+	addressSvc service.IUserAddressService
+}
+
+func NewUserAddressController(addressSvc service.IUserAddressService) IUserAddressController {
+	return &UserAddressController{
+		addressSvc: addressSvc,
+	}
+}
+
+// FindAll godoc
+// @Summary Buku alamat pengguna yang sedang login
+// @Description Alamat utama ditampilkan paling atas
+// @Tags Address
+// @Produce json
+// @Success 200 {array} entity.UserAddress
+// @Router /address [get]
+func (a *UserAddressController) FindAll(c *gin.Context) {
+	var logger = helpers.Logger
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	addresses, err := a.addressSvc.FindByUserID(c.Request.Context(), claims.UserID)
+	if err != nil {
+		logger.Error("Failed to find addresses: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find addresses")
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, addresses, nil, "Berhasil mendapatkan alamat")
+}
+
+// FinById godoc
+// @Summary Mengambil alamat milik pengguna yang sedang login
+// @Tags Address
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} entity.UserAddress
+// @Router /address/{id} [get]
+func (a *UserAddressController) FinById(c *gin.Context) {
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	address, err := a.addressSvc.FindOwned(c.Request.Context(), claims.UserID, c.Param("id"))
+	if err != nil {
+		a.handleError(c, "Gagal mendapatkan alamat: ", err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, address, nil, "Berhasil mendapatkan alamat")
+}
+
+// Insert godoc
+// @Summary Menambah alamat ke buku alamat
+// @Description Alamat pertama otomatis menjadi alamat utama. Koordinat dipakai untuk zona antar jemput berdasarkan jarak
+// @Tags Address
+// @Accept json
+// @Produce json
+// @Param request body entity.UserAddressRequest true "Data alamat"
+// @Success 200 {object} entity.UserAddress
+// @Router /address [post]
+func (a *UserAddressController) Insert(c *gin.Context) {
+	var logger = helpers.Logger
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	var request entity.UserAddressRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	address, err := a.addressSvc.CreateAddress(c.Request.Context(), claims.UserID, request)
+	if err != nil {
+		a.handleError(c, "Gagal menambah alamat: ", err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, address, nil, "Berhasil menambah alamat")
+}
+
+// UpdateById godoc
+// @Summary Memperbarui alamat
+// @Description Perubahan alamat tidak mengubah tujuan tugas antar jemput yang sudah terjadwal
+// @Tags Address
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Param request body entity.UserAddressRequest true "Data alamat"
+// @Success 200 {object} entity.UserAddress
+// @Router /address/{id} [put]
+func (a *UserAddressController) UpdateById(c *gin.Context) {
+	var logger = helpers.Logger
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	var request entity.UserAddressRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	address, err := a.addressSvc.UpdateAddress(c.Request.Context(), claims.UserID, c.Param("id"), request)
+	if err != nil {
+		a.handleError(c, "Gagal memperbarui alamat: ", err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, address, nil, "Berhasil memperbarui alamat")
+}
+
+// DeleteById godoc
+// @Summary Menghapus alamat
+// @Tags Address
+// @Produce json
+// @Param id path string true "Address ID"
+// @Router /address/{id} [delete]
+func (a *UserAddressController) DeleteById(c *gin.Context) {
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	if err := a.addressSvc.DeleteAddress(c.Request.Context(), claims.UserID, c.Param("id")); err != nil {
+		a.handleError(c, "Gagal menghapus alamat: ", err)
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, nil, nil, "Berhasil menghapus alamat")
+}
+
+func (a *UserAddressController) handleError(c *gin.Context, message string, err error) {
+	var logger = helpers.Logger
+
+	logger.Error(message, err)
+	if errors.Is(err, entity.ErrAddressNotFound) {
+		response.ResponseError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	response.ResponseError(c, http.StatusBadRequest, err.Error())
+}
@@ -22,6 +22,7 @@ type IUserController interface {
 	Login(c *gin.Context)
 	Logout(c *gin.Context)
 	Me(c *gin.Context)
+	AssignBranch(c *gin.Context)
 }
 
 type UserController struct {
@@ -192,6 +193,9 @@ func (uc *UserController) UpdateById(c *gin.Context) {
 		return
 	}
 
+	// Cabang admin hanya dapat diatur lewat endpoint admin
+	user.BranchID = nil
+
 	if err := user.Validate(false); err != nil {
 		log.Error("Failed to validate user: ", err)
 		response.ResponseError(c, http.StatusBadRequest, err)
@@ -362,3 +366,54 @@ func (uc *UserController) Me(c *gin.Context) {
 
 	response.ResponseSuccess(c, http.StatusOK, data, nil, "Success to find user by id")
 }
+
+// AssignBranch godoc
+// @Summary      Menetapkan cabang admin
+// @Description  Admin cabang hanya melihat data cabangnya pada daftar dan laporan. Kosongkan branch_id untuk admin pusat. Berlaku setelah admin login ulang
+// @Tags         users
+// @Security ApiCookieAuth
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Param        request  body  entity.AssignUserBranchRequest  true  "Cabang"
+// @Success      200  {object}  entity.User
+// @Router       /admin/user/{id}/branch [put]
+func (uc *UserController) AssignBranch(c *gin.Context) {
+	var log = helpers.Logger
+
+	var id = c.Param("id")
+	if id == "" {
+		log.Error("Id is required")
+		response.ResponseError(c, http.StatusBadRequest, "Id is required")
+		return
+	}
+
+	// Admin cabang tidak dapat memindahkan dirinya atau admin lain ke cabang berbeda
+	if claimsBranchID(c) != nil {
+		log.Error(entity.ErrBranchAccessDenied)
+		response.ResponseError(c, http.StatusForbidden, entity.ErrBranchAccessDenied.Error())
+		return
+	}
+
+	var request entity.AssignUserBranchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Error("Failed to bind JSON: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Failed to bind JSON")
+		return
+	}
+
+	user, err := uc.userService.AssignBranch(c.Request.Context(), id, request.BranchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error(fmt.Errorf("user with id %s not found", id))
+			response.ResponseError(c, http.StatusNotFound, "User not found")
+			return
+		}
+
+		log.Error(fmt.Errorf("failed to assign branch to user %s: %v", id, err))
+		response.ResponseError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, user, nil, "Berhasil menetapkan cabang admin")
+}
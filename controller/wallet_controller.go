@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"errors"
+	"final-project/entity"
+	"final-project/service"
+	"final-project/utils/helpers"
+	"final-project/utils/response"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"math"
+	"net/http"
+)
+
+type IWalletController interface {
+	MyWallet(c *gin.Context)
+	MyEntries(c *gin.Context)
+	FindByUserID(c *gin.Context)
+	FindEntriesByUserID(c *gin.Context)
+	Adjust(c *gin.Context)
+}
+
+type WalletController struct {
+	walletSvc service.IWalletService
+}
+
+func NewWalletController(walletSvc service.IWalletService) IWalletController {
+	return &WalletController{
+		walletSvc: walletSvc,
+	}
+}
+
+// MyWallet godoc
+// @Summary Saldo dompet pengguna yang sedang login
+// @Tags Wallet
+// @Produce json
+// @Success 200 {object} entity.Wallet
+// @Router /wallet [get]
+func (w *WalletController) MyWallet(c *gin.Context) {
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	w.findWallet(c, claims.UserID.String())
+}
+
+// MyEntries godoc
+// @Summary Riwayat mutasi dompet pengguna yang sedang login
+// @Tags Wallet
+// @Produce json
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.WalletEntry
+// @Router /wallet/entries [get]
+func (w *WalletController) MyEntries(c *gin.Context) {
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	w.findEntries(c, claims.UserID.String())
+}
+
+// FindByUserID godoc
+// @Summary Saldo dompet pengguna
+// @Tags Wallet
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} entity.Wallet
+// @Router /wallet/user/{user_id} [get]
+func (w *WalletController) FindByUserID(c *gin.Context) {
+	w.findWallet(c, c.Param("user_id"))
+}
+
+// FindEntriesByUserID godoc
+// @Summary Riwayat mutasi dompet pengguna
+// @Tags Wallet
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param page query string false "Page"
+// @Param limit query string false "Limit"
+// @Success 200 {array} entity.WalletEntry
+// @Router /wallet/user/{user_id}/entries [get]
+func (w *WalletController) FindEntriesByUserID(c *gin.Context) {
+	w.findEntries(c, c.Param("user_id"))
+}
+
+// Adjust godoc
+// @Summary Penyesuaian saldo dompet oleh admin
+// @Description Jenis credit atau debit dengan alasan goodwill atau adjustment. Admin pembuat dicatat pada mutasi
+// @Tags Wallet
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Param request body entity.WalletAdjustmentRequest true "Data penyesuaian"
+// @Success 200 {object} entity.WalletEntry
+// @Router /wallet/user/{user_id}/adjust [post]
+func (w *WalletController) Adjust(c *gin.Context) {
+	var logger = helpers.Logger
+
+	userID := c.Param("user_id")
+	if userID == "" {
+		logger.Error("User id is required")
+		response.ResponseError(c, http.StatusBadRequest, "User id is required")
+		return
+	}
+
+	claims, ok := requestClaims(c)
+	if !ok {
+		return
+	}
+
+	var request entity.WalletAdjustmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error("Gagal binding request: ", err)
+		response.ResponseError(c, http.StatusBadRequest, "Format data tidak valid")
+		return
+	}
+
+	entry, err := w.walletSvc.Adjust(c.Request.Context(), userID, claims.UserID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			logger.Error(fmt.Errorf("user with id %s not found", userID))
+			response.ResponseError(c, http.StatusNotFound, "User not found")
+		case errors.Is(err, entity.ErrInsufficientWalletBalance):
+			logger.Error(err)
+			response.ResponseError(c, http.StatusConflict, err.Error())
+		default:
+			logger.Error("Gagal menyesuaikan saldo dompet: ", err)
+			response.ResponseError(c, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, entry, nil, "Berhasil menyesuaikan saldo dompet")
+}
+
+func (w *WalletController) findWallet(c *gin.Context, userID string) {
+	var logger = helpers.Logger
+
+	wallet, err := w.walletSvc.GetWallet(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("user with id %s not found", userID))
+			response.ResponseError(c, http.StatusNotFound, "User not found")
+			return
+		}
+
+		logger.Error("Failed to find wallet: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find wallet")
+		return
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, wallet, nil, "Berhasil mendapatkan saldo dompet")
+}
+
+func (w *WalletController) findEntries(c *gin.Context, userID string) {
+	var logger = helpers.Logger
+
+	var page = c.DefaultQuery("page", "1")
+	var pageInt = helpers.ParseToInt(page)
+
+	var limit = c.DefaultQuery("limit", "10")
+	var limitInt = helpers.ParseToInt(limit)
+
+	var offset = (pageInt - 1) * limitInt
+
+	data, totalData, err := w.walletSvc.FindEntries(c.Request.Context(), userID, limitInt, offset)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error(fmt.Errorf("user with id %s not found", userID))
+			response.ResponseError(c, http.StatusNotFound, "User not found")
+			return
+		}
+
+		logger.Error("Failed to find wallet entries: ", err)
+		response.ResponseError(c, http.StatusInternalServerError, "Failed to find wallet entries")
+		return
+	}
+
+	metaData := response.Page{
+		Limit:     limitInt,
+		Total:     int(totalData),
+		Page:      pageInt,
+		TotalPage: int(math.Ceil(float64(totalData) / float64(limitInt))),
+	}
+
+	response.ResponseSuccess(c, http.StatusOK, data, metaData, "Berhasil mendapatkan riwayat dompet")
+}
@@ -18,54 +18,37 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/user/{id}": {
+        "/accounting/accounts": {
             "get": {
-                "security": [
-                    {
-                        "ApiCookieAuth": []
-                    }
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
-                ],
-                "summary": "Mengambil data user berdasarkan id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Accounting"
                 ],
+                "summary": "Bagan akun",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Account"
+                            }
                         }
                     }
                 }
             }
         },
-        "/admin/users": {
+        "/accounting/journal": {
             "get": {
-                "security": [
-                    {
-                        "ApiCookieAuth": []
-                    }
-                ],
-                "description": "Get list of all users",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "Accounting"
                 ],
-                "summary": "List users",
+                "summary": "Daftar jurnal",
                 "parameters": [
                     {
                         "type": "string",
@@ -78,6 +61,30 @@ const docTemplate = `{
                         "description": "Limit",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter kejadian (payment_settled, payment_refunded, rental_returned, deposit_refunded, wallet_entry, write_off)",
+                        "name": "event",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter rental",
+                        "name": "rental_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -86,23 +93,23 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/entity.User"
+                                "$ref": "#/definitions/entity.JournalEntry"
                             }
                         }
                     }
                 }
             }
         },
-        "/business-report/customers": {
+        "/accounting/journal/export": {
             "get": {
-                "description": "Mendapatkan laporan pelanggan yang paling aktif berdasarkan jumlah penyewaan dan pengeluaran",
+                "description": "Satu baris per akun per jurnal, diurutkan berdasarkan tanggal jurnal",
                 "produces": [
-                    "application/json"
+                    "text/csv"
                 ],
                 "tags": [
-                    "Business Report"
+                    "Accounting"
                 ],
-                "summary": "Mendapatkan laporan pelanggan teratas",
+                "summary": "Ekspor jurnal ke CSV",
                 "parameters": [
                     {
                         "type": "string",
@@ -117,53 +124,39 @@ const docTemplate = `{
                         "name": "end_date",
                         "in": "query",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Jumlah data (default: 10)",
-                        "name": "limit",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
+                            "type": "file"
                         }
                     }
                 }
             }
         },
-        "/business-report/popular-toys": {
+        "/accounting/trial-balance": {
             "get": {
-                "description": "Mendapatkan laporan mainan paling populer berdasarkan jumlah penyewaan",
+                "description": "Total debit dan kredit per akun. Tanpa filter tanggal seluruh jurnal dijumlahkan",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Business Report"
+                    "Accounting"
                 ],
-                "summary": "Mendapatkan laporan mainan populer",
+                "summary": "Neraca saldo",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Tanggal mulai (YYYY-MM-DD)",
                         "name": "start_date",
-                        "in": "query",
-                        "required": true
+                        "in": "query"
                     },
                     {
                         "type": "string",
                         "description": "Tanggal akhir (YYYY-MM-DD)",
                         "name": "end_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Jumlah data (default: 10)",
-                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -171,93 +164,99 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.TrialBalanceItem"
+                            }
                         }
                     }
                 }
             }
         },
-        "/business-report/rental-status": {
+        "/address": {
             "get": {
-                "description": "Mendapatkan laporan jumlah penyewaan berdasarkan status",
+                "description": "Alamat utama ditampilkan paling atas",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Business Report"
+                    "Address"
                 ],
-                "summary": "Mendapatkan laporan status penyewaan",
+                "summary": "Buku alamat pengguna yang sedang login",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.UserAddress"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Alamat pertama otomatis menjadi alamat utama. Koordinat dipakai untuk zona antar jemput berdasarkan jarak",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address"
+                ],
+                "summary": "Menambah alamat ke buku alamat",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Tanggal mulai (YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tanggal akhir (YYYY-MM-DD)",
-                        "name": "end_date",
-                        "in": "query",
-                        "required": true
+                        "description": "Data alamat",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserAddressRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
+                            "$ref": "#/definitions/entity.UserAddress"
                         }
                     }
                 }
             }
         },
-        "/business-report/sales": {
+        "/address/{id}": {
             "get": {
-                "description": "Mendapatkan laporan penjualan dalam rentang waktu tertentu",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Business Report"
+                    "Address"
                 ],
-                "summary": "Mendapatkan laporan penjualan",
+                "summary": "Mengambil alamat milik pengguna yang sedang login",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Tanggal mulai (YYYY-MM-DD)",
-                        "name": "start_date",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Tanggal akhir (YYYY-MM-DD)",
-                        "name": "end_date",
-                        "in": "query",
+                        "description": "Address ID",
+                        "name": "id",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Pengelompokan (day, week, month)",
-                        "name": "group_by",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
+                            "$ref": "#/definitions/entity.UserAddress"
                         }
                     }
                 }
-            }
-        },
-        "/payment": {
-            "post": {
-                "description": "Buat pembayaran baru menggunakan midtrans",
+            },
+            "put": {
+                "description": "Perubahan alamat tidak mengubah tujuan tugas antar jemput yang sudah terjadwal",
                 "consumes": [
                     "application/json"
                 ],
@@ -265,17 +264,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Payment"
+                    "Address"
                 ],
-                "summary": "Membuat pembayaran rental",
+                "summary": "Memperbarui alamat",
                 "parameters": [
                     {
-                        "description": "ID Rental",
-                        "name": "rental_id",
+                        "type": "string",
+                        "description": "Address ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data alamat",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.CreatePaymentRequest"
+                            "$ref": "#/definitions/entity.UserAddressRequest"
                         }
                     }
                 ],
@@ -283,50 +289,50 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Payment"
+                            "$ref": "#/definitions/entity.UserAddress"
                         }
                     }
                 }
-            }
-        },
-        "/payment/callback": {
-            "post": {
-                "description": "Endpoint untuk menerima notifikasi dari Midtrans",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Payment"
+                    "Address"
                 ],
-                "summary": "Menangani callback dari Midtrans",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
-                        }
+                "summary": "Menghapus alamat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Address ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                }
+                ],
+                "responses": {}
             }
         },
-        "/payment/rental/{rental_id}": {
+        "/admin/user/{id}": {
             "get": {
-                "description": "Mendapatkan semua pembayaran berdasarkan ID rental",
+                "security": [
+                    {
+                        "ApiCookieAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Payment"
+                    "users"
                 ],
-                "summary": "Mendapatkan semua pembayaran untuk rental",
+                "summary": "Mengambil data user berdasarkan id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID Rental",
-                        "name": "rental_id",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -335,53 +341,73 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/entity.Payment"
-                            }
+                            "$ref": "#/definitions/entity.User"
                         }
                     }
                 }
             }
         },
-        "/payment/{id}": {
-            "get": {
-                "description": "Mendapatkan detail pembayaran berdasarkan ID",
+        "/admin/user/{id}/branch": {
+            "put": {
+                "security": [
+                    {
+                        "ApiCookieAuth": []
+                    }
+                ],
+                "description": "Admin cabang hanya melihat data cabangnya pada daftar dan laporan. Kosongkan branch_id untuk admin pusat. Berlaku setelah admin login ulang",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Payment"
+                    "users"
                 ],
-                "summary": "Mendapatkan detail pembayaran",
+                "summary": "Menetapkan cabang admin",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID Pembayaran",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Cabang",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AssignUserBranchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Payment"
+                            "$ref": "#/definitions/entity.User"
                         }
                     }
                 }
             }
         },
-        "/rental": {
+        "/admin/users": {
             "get": {
+                "security": [
+                    {
+                        "ApiCookieAuth": []
+                    }
+                ],
+                "description": "Get list of all users",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rental"
+                    "users"
                 ],
-                "summary": "Mendapatkan semua data rental",
+                "summary": "List users",
                 "parameters": [
                     {
                         "type": "string",
@@ -400,132 +426,37 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Rental"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Rental"
-                ],
-                "summary": "Insert rental baru",
-                "parameters": [
-                    {
-                        "description": "Rental",
-                        "name": "rental",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.CreateRentalRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/entity.Rental"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.User"
+                            }
                         }
                     }
                 }
             }
         },
-        "/rental/{id}": {
+        "/branch": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Rental"
-                ],
-                "summary": "Mendapatkan data rental berdasarkan id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Rental ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Branch"
                 ],
+                "summary": "Mengambil daftar cabang yang aktif",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Rental"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Branch"
+                            }
                         }
                     }
                 }
             },
-            "put": {
-                "security": [
-                    {
-                        "ApiCookieAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Rental"
-                ],
-                "summary": "Perpanjang sewa rental",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID Rental",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Data perpanjangan rental",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.ExtendRentalRequest"
-                        }
-                    }
-                ],
-                "responses": {}
-            },
-            "delete": {
-                "tags": [
-                    "Rental"
-                ],
-                "summary": "Hapus rental",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Rental ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/rental/{id}/return": {
-            "put": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -533,24 +464,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Rental"
+                    "Branch"
                 ],
-                "summary": "Pengembalian rental",
+                "summary": "Membuat cabang",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Rental ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Data pengembalian rental",
+                        "description": "Data cabang",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.ReturnRentalRequest"
+                            "$ref": "#/definitions/entity.BranchRequest"
                         }
                     }
                 ],
@@ -558,21 +482,21 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Rental"
+                            "$ref": "#/definitions/entity.Branch"
                         }
                     }
                 }
             }
         },
-        "/toy": {
+        "/branch-transfer": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Toy"
+                    "Branch"
                 ],
-                "summary": "Mengambil semua data toy",
+                "summary": "Mengambil daftar transfer unit antar cabang",
                 "parameters": [
                     {
                         "type": "string",
@@ -585,18 +509,34 @@ const docTemplate = `{
                         "description": "Limit",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status (in_transit, received, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang asal atau tujuan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Toy"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.BranchTransfer"
+                            }
                         }
                     }
                 }
             },
             "post": {
+                "description": "Unit harus tersedia di cabang asal. Unit baru dapat disewa di cabang tujuan setelah transfer diterima",
                 "consumes": [
                     "application/json"
                 ],
@@ -604,17 +544,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Toy"
+                    "Branch"
                 ],
-                "summary": "Menambahkan mainan baru",
+                "summary": "Mengirim unit ke cabang lain",
                 "parameters": [
                     {
-                        "description": "Data mainan baru",
-                        "name": "toy",
+                        "description": "Data transfer",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.ToyRequest"
+                            "$ref": "#/definitions/entity.CreateBranchTransferRequest"
                         }
                     }
                 ],
@@ -622,89 +562,110 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Toy"
+                            "$ref": "#/definitions/entity.BranchTransfer"
                         }
                     }
                 }
             }
         },
-        "/toy/category": {
+        "/branch-transfer/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Toy Category"
+                    "Branch"
                 ],
-                "summary": "Mengambil semua data toy category",
+                "summary": "Mengambil transfer beserta unitnya",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Branch Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.ToyCategory"
+                            "$ref": "#/definitions/entity.BranchTransfer"
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/branch-transfer/{id}/cancel": {
+            "put": {
+                "description": "Unit dikembalikan ke cabang asal",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Toy Category"
+                    "Branch"
                 ],
-                "summary": "Insert toy category",
+                "summary": "Membatalkan transfer yang belum diterima",
                 "parameters": [
                     {
-                        "description": "Toy Category",
-                        "name": "toy_category",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Branch Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.ToyCategory"
+                            "$ref": "#/definitions/entity.BranchTransfer"
                         }
                     }
+                }
+            }
+        },
+        "/branch-transfer/{id}/receive": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Branch"
+                ],
+                "summary": "Menerima transfer di cabang tujuan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Branch Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
+                            "$ref": "#/definitions/entity.BranchTransfer"
                         }
                     }
                 }
             }
         },
-        "/toy/category/{id}": {
+        "/branch/{id}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Toy Category"
+                    "Branch"
                 ],
-                "summary": "Mengambil data toy category berdasarkan id",
+                "summary": "Mengambil cabang berdasarkan id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Toy Category ID",
+                        "description": "Branch ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -714,12 +675,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.ToyCategory"
+                            "$ref": "#/definitions/entity.Branch"
                         }
                     }
                 }
             },
             "put": {
+                "description": "Jam buka diisi bebas, misalnya \"Senin-Jumat 09:00-21:00\". Kode cabang utama tidak dapat diubah",
                 "consumes": [
                     "application/json"
                 ],
@@ -727,24 +689,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Toy Category"
+                    "Branch"
                 ],
-                "summary": "Update toy berdasarkan id",
+                "summary": "Memperbarui cabang",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Toy Category ID",
+                        "description": "Branch ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Toy Category",
-                        "name": "toy_category",
+                        "description": "Data cabang",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/entity.ToyCategory"
+                            "$ref": "#/definitions/entity.BranchRequest"
                         }
                     }
                 ],
@@ -752,20 +714,24 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
+                            "$ref": "#/definitions/entity.Branch"
                         }
                     }
                 }
             },
             "delete": {
+                "description": "Cabang yang masih memiliki unit tidak dapat dihapus",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Toy Category"
+                    "Branch"
                 ],
-                "summary": "Delete toy berdasarkan id",
+                "summary": "Menghapus cabang",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Toy Category ID",
+                        "description": "Branch ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -773,34 +739,54 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
-                        }
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/toy/image": {
+        "/business-report/categories": {
             "get": {
+                "description": "Mendapatkan jumlah penyewaan, pendapatan, porsi pendapatan, rata-rata durasi dan tingkat kerusakan per kategori. Pendapatan mainan dengan beberapa kategori dibagi rata ke setiap kategorinya",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Toy Image"
+                    "Business Report"
                 ],
-                "summary": "Mengambil semua data toy image",
+                "summary": "Mendapatkan laporan kinerja kategori",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Page",
-                        "name": "page",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Limit",
-                        "name": "limit",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
                         "in": "query"
                     }
                 ],
@@ -808,802 +794,6686 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.ToyImage"
+                            "$ref": "#/definitions/response.APISuccessResponse"
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/business-report/cohorts": {
+            "get": {
+                "description": "Mengelompokkan pelanggan berdasarkan bulan rental pertamanya di rentang tanggal dan menghitung persentase yang menyewa lagi pada bulan ke-N",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Toy Image"
+                    "Business Report"
                 ],
-                "summary": "Insert toy image",
+                "summary": "Mendapatkan laporan retensi kohort pelanggan",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Upload multiple images",
-                        "name": "images",
-                        "in": "formData",
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah bulan retensi yang dihitung (default: 12, maksimal 36)",
+                        "name": "months",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.CohortItem"
+                            }
                         }
                     }
                 }
             }
         },
-        "/toy/image/{id}": {
-            "delete": {
+        "/business-report/customer-value": {
+            "get": {
+                "description": "Untuk pelanggan yang rental pertamanya di rentang tanggal: tingkat rental ulang, rata-rata jarak antar rental dan nilai seumur hidup sampai sekarang, beserta daftar pelanggan dengan nilai tertinggi",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Toy Image"
+                    "Business Report"
                 ],
-                "summary": "Delete toy berdasarkan id",
+                "summary": "Mendapatkan laporan nilai seumur hidup pelanggan",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Toy Image ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.ToyImage"
+                            "$ref": "#/definitions/entity.CustomerValueReport"
                         }
                     }
                 }
             }
         },
-        "/toy/{id}": {
+        "/business-report/customers": {
             "get": {
+                "description": "Mendapatkan laporan pelanggan yang paling aktif berdasarkan jumlah penyewaan dan pengeluaran",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Toy"
+                    "Business Report"
                 ],
-                "summary": "Mengambil data toy berdasarkan id",
+                "summary": "Mendapatkan laporan pelanggan teratas",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Toy ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Toy"
+                            "$ref": "#/definitions/response.APISuccessResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/business-report/damage": {
+            "get": {
+                "description": "Insiden kerusakan dan kehilangan pada rental yang dikembalikan dalam rentang tanggal, per mainan, kategori dan pelanggan, beserta biaya kerusakan yang ditagih dan yang sudah terkumpul. Pelanggan dengan insiden berulang ditandai",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Toy"
+                    "Business Report"
                 ],
-                "summary": "Update mainan berdasarkan id",
+                "summary": "Mendapatkan laporan kerusakan dan kehilangan",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Toy ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Tanggal mulai pengembalian (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "description": "Data mainan yang diperbarui",
-                        "name": "toy",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.ToyUpdateRequest"
-                        }
+                        "type": "string",
+                        "description": "Tanggal akhir pengembalian (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah insiden minimal untuk menandai pelanggan (default: 2)",
+                        "name": "min_incidents",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated Toy",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Toy"
+                            "$ref": "#/definitions/entity.DamageReport"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/business-report/dashboard": {
+            "get": {
+                "description": "Mendapatkan KPI utama (pendapatan, jumlah rental, rental aktif, keterlambatan, rata-rata nilai rental, pelanggan baru, utilisasi) untuk periode terpilih dan periode sebelumnya dengan panjang yang sama, beserta persentase perubahan dan deret harian",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Toy"
+                    "Business Report"
                 ],
-                "summary": "Menghapus mainan berdasarkan id",
+                "summary": "Mendapatkan dashboard KPI",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Toy Image ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.Toy"
+                            "$ref": "#/definitions/entity.DashboardReport"
                         }
                     }
                 }
             }
         },
-        "/user/auth/login": {
-            "post": {
+        "/business-report/inventory": {
+            "get": {
+                "description": "Mendapatkan posisi stok setiap mainan (tersedia, disewa, rusak, hilang) beserta nilai penggantian unit yang rusak dan hilang",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "users"
+                    "Business Report"
                 ],
-                "summary": "Login",
+                "summary": "Mendapatkan laporan status inventaris",
                 "parameters": [
                     {
-                        "description": "User",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.UserLoginRequest"
-                        }
+                        "type": "string",
+                        "description": "Filter kategori mainan",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter kondisi mainan (new, excellent, good, fair, poor)",
+                        "name": "condition",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter lokasi unit (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/response.APISuccessResponse"
                         }
                     }
                 }
             }
         },
-        "/user/auth/logout": {
-            "delete": {
-                "security": [
-                    {
-                        "ApiCookieAuth": []
-                    }
-                ],
+        "/business-report/overdue": {
+            "get": {
+                "description": "Rental yang sudah melewati tanggal kembali tetapi mainannya belum dikembalikan, diurutkan dari yang paling lama terlambat",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "users"
+                    "Business Report"
                 ],
-                "summary": "Logout",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                "summary": "Mendapatkan laporan rental terlambat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/response.APISuccessResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.OverdueRentalItem"
+                            }
                         }
                     }
                 }
             }
         },
-        "/user/auth/me": {
+        "/business-report/popular-toys": {
             "get": {
-                "security": [
+                "description": "Mendapatkan laporan mainan paling populer berdasarkan jumlah penyewaan",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Business Report"
+                ],
+                "summary": "Mendapatkan laporan mainan populer",
+                "parameters": [
                     {
-                        "ApiCookieAuth": []
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Jumlah data (default: 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.APISuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/business-report/rental-status": {
+            "get": {
+                "description": "Mendapatkan laporan jumlah penyewaan berdasarkan status",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "users"
+                    "Business Report"
+                ],
+                "summary": "Mendapatkan laporan status penyewaan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get user berdasarkan token",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/response.APISuccessResponse"
                         }
                     }
                 }
             }
         },
-        "/user/auth/register": {
-            "post": {
+        "/business-report/sales": {
+            "get": {
+                "description": "Mendapatkan laporan penjualan dalam rentang waktu tertentu. Basis accrual mengikuti tanggal rental dan harga rental, basis cash mengikuti pembayaran yang lunas dikurangi refund, dikelompokkan per jenis dan metode pembayaran",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "users"
+                    "Business Report"
                 ],
-                "summary": "Membuat user baru",
+                "summary": "Mendapatkan laporan penjualan",
                 "parameters": [
                     {
-                        "description": "User",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.User"
-                        }
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pengelompokan (day, week, month)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Basis pencatatan (accrual, cash), default accrual",
+                        "name": "basis",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/response.APISuccessResponse"
                         }
                     }
                 }
             }
         },
-        "/user/auth/{id}": {
-            "put": {
-                "security": [
+        "/business-report/utilization": {
+            "get": {
+                "description": "Membandingkan hari-unit tersewa dengan hari-unit tersedia per mainan dan kategori, pendapatan per unit, serta daftar mainan yang menganggur",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Business Report"
+                ],
+                "summary": "Mendapatkan laporan utilisasi mainan",
+                "parameters": [
                     {
-                        "ApiCookieAuth": []
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Batas hari tanpa penyewaan untuk dianggap menganggur (default: 30)",
+                        "name": "idle_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format keluaran (json, csv, xlsx), bisa juga lewat header Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Isi id untuk format angka dan tanggal Indonesia pada ekspor",
+                        "name": "locale",
+                        "in": "query"
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.UtilizationReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/slot": {
+            "get": {
+                "description": "booked adalah jumlah tugas yang sudah memakai jadwal, jadwal penuh bila booked sama dengan capacity",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "Delivery"
                 ],
-                "summary": "Update user berdasarkan id",
+                "summary": "Mengambil jadwal antar jemput yang aktif",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter cabang",
+                        "name": "branch_id",
+                        "in": "query"
                     },
                     {
-                        "description": "User",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/entity.User"
-                        }
+                        "type": "string",
+                        "description": "Filter tanggal (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DeliverySlot"
+                            }
                         }
                     }
                 }
             },
-            "delete": {
-                "security": [
-                    {
-                        "ApiCookieAuth": []
-                    }
+            "post": {
+                "description": "Kuota berlaku untuk gabungan tugas antar dan jemput pada jadwal tersebut",
+                "consumes": [
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "Delivery"
                 ],
-                "summary": "Delete user berdasarkan id",
+                "summary": "Membuat jadwal antar jemput",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Data jadwal",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliverySlotRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/entity.User"
+                            "$ref": "#/definitions/entity.DeliverySlot"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "entity.CreatePaymentRequest": {
+        },
+        "/delivery/slot/{id}": {
+            "put": {
+                "description": "Cabang jadwal tidak dapat diubah. Jadwal yang sudah dipesan tidak dapat digeser waktunya dan kuotanya tidak boleh di bawah jumlah pesanan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Memperbarui jadwal antar jemput",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data jadwal",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliverySlotRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliverySlot"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Jadwal yang sudah dipesan tidak dapat dihapus, nonaktifkan lewat update agar tidak dapat dipilih lagi",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Menghapus jadwal antar jemput",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery Slot ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/delivery/task": {
+            "get": {
+                "description": "Diurutkan sesuai tanggal dan jam jadwal antar jemput",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Mengambil daftar tugas kurir",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter tanggal jadwal (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status (scheduled, on_the_way, completed, failed, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter jenis (delivery, return_pickup)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter kurir",
+                        "name": "courier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DeliveryTask"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/task/mine": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Mengambil tugas kurir milik staf yang sedang login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter tanggal jadwal (YYYY-MM-DD)",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status (scheduled, on_the_way, completed, failed, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter jenis (delivery, return_pickup)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DeliveryTask"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/task/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Mengambil tugas kurir berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliveryTask"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/task/{id}/assign": {
+            "put": {
+                "description": "Kurir adalah staf dengan peran admin. Kosongkan courier_id untuk melepas kurir",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Menugaskan kurir ke tugas antar jemput",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Kurir",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.AssignCourierRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliveryTask"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/task/{id}/status": {
+            "put": {
+                "description": "Alur status: scheduled -\u003e on_the_way -\u003e completed atau failed. Tugas gagal dapat dicoba lagi (on_the_way) atau dibatalkan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Memperbarui status tugas antar jemput",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status baru",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateDeliveryTaskStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliveryTask"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/zone": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Mengambil daftar zona antar jemput",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter cabang (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.DeliveryZone"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Isi postal_codes (boleh awalan seperti \"401\") dan/atau pita jarak min_distance_km sampai max_distance_km dari koordinat cabang. Zona kode pos diutamakan, bila beberapa zona cocok dipilih ongkos termurah",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Membuat zona antar jemput",
+                "parameters": [
+                    {
+                        "description": "Data zona",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliveryZoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliveryZone"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery/zone/{id}": {
+            "put": {
+                "description": "Ongkos baru hanya berlaku untuk rental berikutnya",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Memperbarui zona antar jemput",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data zona",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliveryZoneRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.DeliveryZone"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Delivery"
+                ],
+                "summary": "Menghapus zona antar jemput",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery Zone ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/fee-policy": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fee Policy"
+                ],
+                "summary": "Mengambil semua versi kebijakan biaya",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.FeePolicy"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Versi lama tidak diubah; isi activate untuk langsung memakai versi baru",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fee Policy"
+                ],
+                "summary": "Membuat versi kebijakan biaya baru",
+                "parameters": [
+                    {
+                        "description": "Data kebijakan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeePolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeePolicy"
+                        }
+                    }
+                }
+            }
+        },
+        "/fee-policy/active": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fee Policy"
+                ],
+                "summary": "Mengambil kebijakan biaya yang sedang aktif",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeePolicy"
+                        }
+                    }
+                }
+            }
+        },
+        "/fee-policy/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fee Policy"
+                ],
+                "summary": "Mengambil kebijakan biaya berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fee Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeePolicy"
+                        }
+                    }
+                }
+            }
+        },
+        "/fee-policy/{id}/activate": {
+            "put": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Fee Policy"
+                ],
+                "summary": "Mengaktifkan versi kebijakan biaya",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fee Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.FeePolicy"
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance": {
+            "get": {
+                "description": "Tanpa filter status, hanya tugas yang belum selesai yang ditampilkan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maintenance"
+                ],
+                "summary": "Mengambil antrean tugas perawatan unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status (pending, in_progress, completed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter jenis (cleaning, repair)",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter mainan",
+                        "name": "toy_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang unit (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Hanya tugas yang melewati tenggat",
+                        "name": "overdue",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.MaintenanceTask"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maintenance"
+                ],
+                "summary": "Mengambil tugas perawatan berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Maintenance Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.MaintenanceTask"
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance/{id}/complete": {
+            "put": {
+                "description": "released mengembalikan unit ke stok, repair mengalihkan ke perbaikan, written_off mempensiunkan unit",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maintenance"
+                ],
+                "summary": "Menyelesaikan tugas perawatan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Maintenance Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Hasil perawatan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CompleteMaintenanceTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.MaintenanceTask"
+                        }
+                    }
+                }
+            }
+        },
+        "/maintenance/{id}/start": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Maintenance"
+                ],
+                "summary": "Mulai mengerjakan tugas perawatan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Maintenance Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Catatan",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/entity.StartMaintenanceTaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.MaintenanceTask"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment": {
+            "post": {
+                "description": "Buat pembayaran baru menggunakan midtrans",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Membuat pembayaran rental",
+                "parameters": [
+                    {
+                        "description": "ID Rental",
+                        "name": "rental_id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreatePaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/callback": {
+            "post": {
+                "description": "Endpoint untuk menerima notifikasi dari Midtrans",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Menangani callback dari Midtrans",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.APISuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/rental/{rental_id}": {
+            "get": {
+                "description": "Mendapatkan semua pembayaran berdasarkan ID rental",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Mendapatkan semua pembayaran untuk rental",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Rental",
+                        "name": "rental_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Payment"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/{id}": {
+            "get": {
+                "description": "Mendapatkan detail pembayaran berdasarkan ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Mendapatkan detail pembayaran",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Pembayaran",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/{id}/receipt.pdf": {
+            "get": {
+                "description": "Hanya untuk pembayaran yang sudah lunas. Hanya pemilik rental atau admin",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Document"
+                ],
+                "summary": "Unduh kwitansi pembayaran (PDF)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Pembayaran",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/{id}/tax-invoice": {
+            "get": {
+                "description": "Nomor faktur berurutan, DPP dan PPN untuk pembayaran lunas yang dikenai PPN. Hanya pemilik rental atau admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Faktur pajak pembayaran",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Pembayaran",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.TaxInvoice"
+                        }
+                    }
+                }
+            }
+        },
+        "/pricing/holiday": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Mengambil semua hari libur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Holiday"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Menambahkan hari libur",
+                "parameters": [
+                    {
+                        "description": "Data hari libur",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.HolidayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Holiday"
+                        }
+                    }
+                }
+            }
+        },
+        "/pricing/holiday/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Menghapus hari libur",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Holiday ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/pricing/rule": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Mengambil semua aturan harga",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.PricingRule"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Jenis aturan: weekend_surcharge, holiday_surcharge, atau long_rental_discount",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Membuat aturan harga",
+                "parameters": [
+                    {
+                        "description": "Data aturan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PricingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PricingRule"
+                        }
+                    }
+                }
+            }
+        },
+        "/pricing/rule/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Memperbarui aturan harga",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pricing Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data aturan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PricingRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PricingRule"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Pricing"
+                ],
+                "summary": "Menghapus aturan harga",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pricing Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/promotion": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Mengambil semua promo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.Promotion"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Jenis potongan: percentage atau fixed. Kosongkan category_ids dan toy_ids agar promo berlaku untuk semua mainan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Membuat promo",
+                "parameters": [
+                    {
+                        "description": "Data promo",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Promotion"
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion/report": {
+            "get": {
+                "description": "Jumlah pemakaian, pengguna unik, total potongan dan pendapatan rental per promo dalam rentang waktu tertentu",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Laporan pemakaian promo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tanggal mulai (YYYY-MM-DD)",
+                        "name": "start_date",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tanggal akhir (YYYY-MM-DD)",
+                        "name": "end_date",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.PromotionUsageItem"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/promotion/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Mengambil promo berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Promotion"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Memperbarui promo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data promo",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Promotion"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Menghapus promo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/promotion/{id}/redemptions": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Mengambil riwayat pemakaian promo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.PromotionRedemption"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/rental": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Mendapatkan semua data rental",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang pengambilan atau pengembalian (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Rental"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Pilih delivery_method delivery dan/atau return_method pickup untuk antar jemput. Ongkosnya ditagihkan sebagai rincian tersendiri",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Insert rental baru",
+                "parameters": [
+                    {
+                        "description": "Rental",
+                        "name": "rental",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.CreateRentalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Rental"
+                        }
+                    }
+                }
+            }
+        },
+        "/rental/quote": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Menghitung rincian harga rental sebelum booking",
+                "parameters": [
+                    {
+                        "description": "Periode dan item rental",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.QuoteRentalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.PriceQuote"
+                        }
+                    }
+                }
+            }
+        },
+        "/rental/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Mendapatkan data rental berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rental ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Rental"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiCookieAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Perpanjang sewa rental",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID Rental",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data perpanjangan rental",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ExtendRentalRequest"
+                        }
+                    }
+                ],
+                "responses": {}
+            },
+            "delete": {
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Hapus rental",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rental ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.APISuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rental/{id}/balance": {
+            "get": {
+                "description": "Total tagihan, yang sudah dibayar, dan sisa tagihan per komponen",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Sisa tagihan rental",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rental ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.RentalBalance"
+                        }
+                    }
+                }
+            }
+        },
+        "/rental/{id}/deposit/refund": {
+            "put": {
+                "description": "Hanya untuk rental dengan status deposit refund_pending",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Mengulang pengembalian sisa deposit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rental ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Rental"
+                        }
+                    }
+                }
+            }
+        },
+        "/rental/{id}/invoice.pdf": {
+            "get": {
+                "description": "Rincian sewa, lama sewa, denda, PPN, deposit dan pembayaran. Hanya pemilik rental atau admin",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Document"
+                ],
+                "summary": "Unduh invoice rental (PDF)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rental ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/rental/{id}/return": {
+            "put": {
+                "description": "Unit yang kembali tercatat di return_branch_id, default cabang admin cabang atau cabang pengembalian rental",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Pengembalian rental",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rental ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data pengembalian rental",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReturnRentalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Rental"
+                        }
+                    }
+                }
+            }
+        },
+        "/rental/{id}/settle": {
+            "post": {
+                "description": "Membuat pembayaran hanya untuk tagihan yang belum dibayar, termasuk denda setelah pengembalian",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Membayar sisa tagihan rental",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rental ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Pakai saldo dompet lebih dulu",
+                        "name": "use_wallet",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Payment"
+                        }
+                    }
+                }
+            }
+        },
+        "/rental/{id}/write-off": {
+            "put": {
+                "description": "Hanya untuk rental yang sudah dikembalikan. Sisa tagihan dicatat sebagai beban penghapusan piutang dan rental ditutup",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rental"
+                ],
+                "summary": "Menghapus sisa tagihan rental yang tidak tertagih",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rental ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alasan penghapusan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.WriteOffRentalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Rental"
+                        }
+                    }
+                }
+            }
+        },
+        "/report-subscription": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Subscription"
+                ],
+                "summary": "Mengambil daftar langganan laporan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter cabang (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ReportSubscription"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Laporan dikirim ke email penerima sesuai jadwal cron lima kolom (menit jam tanggal bulan hari) dalam zona waktu laporan, misalnya \"0 7 * * 1\" untuk setiap Senin pukul 07.00. Periode laporan (parameters.period) dihitung mundur dari waktu pengiriman: yesterday, last_7_days (default), last_week, last_30_days atau last_month. Admin cabang hanya dapat berlangganan laporan cabangnya",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Subscription"
+                ],
+                "summary": "Membuat langganan laporan",
+                "parameters": [
+                    {
+                        "description": "Data langganan laporan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReportSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReportSubscription"
+                        }
+                    }
+                }
+            }
+        },
+        "/report-subscription/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Subscription"
+                ],
+                "summary": "Mengambil langganan laporan berdasarkan ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReportSubscription"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Jadwal berikutnya dihitung ulang dari waktu perubahan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Subscription"
+                ],
+                "summary": "Memperbarui langganan laporan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data langganan laporan",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReportSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReportSubscription"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Subscription"
+                ],
+                "summary": "Menghapus langganan laporan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/report-subscription/{id}/deliveries": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Subscription"
+                ],
+                "summary": "Mengambil riwayat pengiriman langganan laporan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ReportDelivery"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/report-subscription/{id}/send": {
+            "post": {
+                "description": "Laporan untuk periode yang dihitung dari waktu sekarang dikirim tanpa mengubah jadwal berikutnya. Hasilnya tercatat di riwayat pengiriman",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report Subscription"
+                ],
+                "summary": "Mengirim laporan langganan sekarang",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ReportDelivery"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy"
+                ],
+                "summary": "Mengambil semua data toy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter kategori (termasuk sub kategori)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hanya mainan yang tersedia di cabang ini, stock berisi jumlah unit di cabang",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Toy"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy"
+                ],
+                "summary": "Menambahkan mainan baru",
+                "parameters": [
+                    {
+                        "description": "Data mainan baru",
+                        "name": "toy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Toy"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/category": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Category"
+                ],
+                "summary": "Mengambil semua data toy category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyCategory"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Category"
+                ],
+                "summary": "Insert toy category",
+                "parameters": [
+                    {
+                        "description": "Toy Category",
+                        "name": "toy_category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyCategory"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/category/tree": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Category"
+                ],
+                "summary": "Mengambil pohon kategori mainan",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ToyCategoryTree"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/category/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Category"
+                ],
+                "summary": "Mengambil data toy category berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyCategory"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Category"
+                ],
+                "summary": "Update toy berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Toy Category",
+                        "name": "toy_category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyCategory"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Kategori yang masih memiliki mainan atau sub kategori hanya dapat dihapus dengan reassign_to",
+                "tags": [
+                    "Toy Category"
+                ],
+                "summary": "Delete toy berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID kategori tujuan untuk mainan dan sub kategori",
+                        "name": "reassign_to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.APISuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/image": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Image"
+                ],
+                "summary": "Mengambil semua data toy image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyImage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Image"
+                ],
+                "summary": "Insert toy image",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Upload multiple images",
+                        "name": "images",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.APISuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/image/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Image"
+                ],
+                "summary": "Delete toy berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy Image ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyImage"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/unit/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Unit"
+                ],
+                "summary": "Memperbarui status atau kondisi unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy Unit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data unit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UpdateToyUnitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyUnit"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/unit/{id}/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Unit"
+                ],
+                "summary": "Mengambil riwayat rental dan kondisi sebuah unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy Unit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyUnitHistory"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy"
+                ],
+                "summary": "Mengambil data toy berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Toy"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy"
+                ],
+                "summary": "Update mainan berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data mainan yang diperbarui",
+                        "name": "toy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.ToyUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated Toy",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Toy"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy"
+                ],
+                "summary": "Menghapus mainan berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy Image ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Toy"
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/{id}/availability": {
+            "get": {
+                "description": "in_transit adalah unit yang sedang dikirim ke cabang tersebut dan belum dapat disewa",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Branch"
+                ],
+                "summary": "Mengambil ketersediaan unit mainan per cabang",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ToyBranchStock"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/toy/{id}/units": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Unit"
+                ],
+                "summary": "Mengambil daftar unit sebuah mainan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter status (available, rented, maintenance, retired, lost)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter cabang (admin cabang selalu dibatasi ke cabangnya)",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ToyUnit"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Isi serial_numbers untuk nomor seri manual, atau quantity untuk nomor seri otomatis.\nTanpa branch_id unit disimpan di cabang utama, atau di cabang admin untuk admin cabang",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Toy Unit"
+                ],
+                "summary": "Mendaftarkan unit baru untuk sebuah mainan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Toy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data unit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.RegisterToyUnitsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.ToyUnit"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/user/auth/login": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.UserLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/auth/logout": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiCookieAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.APISuccessResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiCookieAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get user berdasarkan token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/auth/register": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Membuat user baru",
+                "parameters": [
+                    {
+                        "description": "User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/auth/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "ApiCookieAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiCookieAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete user berdasarkan id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Saldo dompet pengguna yang sedang login",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Wallet"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/entries": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Riwayat mutasi dompet pengguna yang sedang login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.WalletEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/user/{user_id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Saldo dompet pengguna",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.Wallet"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/user/{user_id}/adjust": {
+            "post": {
+                "description": "Jenis credit atau debit dengan alasan goodwill atau adjustment. Admin pembuat dicatat pada mutasi",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Penyesuaian saldo dompet oleh admin",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data penyesuaian",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/entity.WalletAdjustmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/entity.WalletEntry"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/user/{user_id}/entries": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Wallet"
+                ],
+                "summary": "Riwayat mutasi dompet pengguna",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/entity.WalletEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "entity.Account": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AssignCourierRequest": {
+            "type": "object",
+            "properties": {
+                "courier_id": {
+                    "description": "Kosongkan untuk melepas kurir dari tugas",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.AssignUserBranchRequest": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "description": "Kosongkan agar admin dapat mengakses semua cabang",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Branch": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "description": "dipakai untuk zona antar jemput berdasarkan jarak",
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_hours": {
+                    "description": "contoh: \"Senin-Jumat 09:00-21:00, Sabtu-Minggu 10:00-22:00\"",
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BranchRequest": {
+            "type": "object",
+            "required": [
+                "address",
+                "code",
+                "name"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "opening_hours": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.BranchTransfer": {
+            "type": "object",
+            "properties": {
+                "cancelled_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "from_branch": {
+                    "$ref": "#/definitions/entity.Branch"
+                },
+                "from_branch_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "received_by": {
+                    "type": "string"
+                },
+                "shipped_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_branch": {
+                    "$ref": "#/definitions/entity.Branch"
+                },
+                "to_branch_id": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.BranchTransferUnit"
+                    }
+                }
+            }
+        },
+        "entity.BranchTransferUnit": {
+            "type": "object",
+            "properties": {
+                "branch_transfer_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "toy_unit": {
+                    "$ref": "#/definitions/entity.ToyUnit"
+                },
+                "toy_unit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CategoryUtilizationItem": {
+            "type": "object",
+            "properties": {
+                "available_unit_days": {
+                    "type": "number"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rented_unit_days": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "revenue_per_unit": {
+                    "type": "integer"
+                },
+                "toy_count": {
+                    "type": "integer"
+                },
+                "unit_count": {
+                    "type": "integer"
+                },
+                "utilization_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.CohortItem": {
+            "type": "object",
+            "properties": {
+                "cohort_month": {
+                    "type": "string"
+                },
+                "customer_count": {
+                    "type": "integer"
+                },
+                "retention": {
+                    "description": "bulan yang belum berjalan tidak ditampilkan",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CohortRetention"
+                    }
+                }
+            }
+        },
+        "entity.CohortRetention": {
+            "type": "object",
+            "properties": {
+                "active_customers": {
+                    "type": "integer"
+                },
+                "month_offset": {
+                    "type": "integer"
+                },
+                "retention_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.CompleteMaintenanceTaskRequest": {
+            "type": "object",
+            "required": [
+                "outcome"
+            ],
+            "properties": {
+                "condition": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "description": "released: unit kembali tersedia, repair: dialihkan ke perbaikan, written_off: unit dipensiunkan",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CreateBranchTransferRequest": {
+            "type": "object",
+            "required": [
+                "from_branch_id",
+                "to_branch_id",
+                "toy_unit_ids"
+            ],
+            "properties": {
+                "from_branch_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "to_branch_id": {
+                    "type": "string"
+                },
+                "toy_unit_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.CreatePaymentRequest": {
+            "type": "object",
+            "required": [
+                "rental_id"
+            ],
+            "properties": {
+                "rental_id": {
+                    "type": "string"
+                },
+                "use_wallet": {
+                    "description": "Pakai saldo dompet lebih dulu, kekurangannya dibayar lewat payment gateway",
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.CreateRentalItemRequest": {
+            "type": "object",
+            "properties": {
+                "condition_before": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "toy_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CreateRentalRequest": {
+            "type": "object",
+            "properties": {
+                "address_id": {
+                    "description": "Alamat dari buku alamat, wajib bila memilih delivery atau pickup",
+                    "type": "string"
+                },
+                "delivery_method": {
+                    "description": "self_pickup (default) atau delivery",
+                    "type": "string"
+                },
+                "delivery_slot_id": {
+                    "type": "string"
+                },
+                "expected_return_date": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CreateRentalItemRequest"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "pickup_branch_id": {
+                    "description": "Cabang pengambilan, default cabang utama",
+                    "type": "string"
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "rental_date": {
+                    "type": "string"
+                },
+                "return_branch_id": {
+                    "description": "Cabang pengembalian, default sama dengan cabang pengambilan",
+                    "type": "string"
+                },
+                "return_method": {
+                    "description": "self_return (default) atau pickup",
+                    "type": "string"
+                },
+                "return_slot_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CustomerRetentionSummary": {
+            "type": "object",
+            "properties": {
+                "average_days_between_rentals": {
+                    "type": "number"
+                },
+                "average_lifetime_value": {
+                    "type": "integer"
+                },
+                "customer_count": {
+                    "type": "integer"
+                },
+                "repeat_customer_count": {
+                    "type": "integer"
+                },
+                "repeat_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.CustomerValueItem": {
+            "type": "object",
+            "properties": {
+                "average_days_between_rentals": {
+                    "description": "kosong jika baru sekali menyewa",
+                    "type": "number"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_rental_date": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "last_rental_date": {
+                    "type": "string"
+                },
+                "lifetime_value": {
+                    "type": "integer"
+                },
+                "rental_count": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.CustomerValueReport": {
+            "type": "object",
+            "properties": {
+                "customers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CustomerValueItem"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/entity.CustomerRetentionSummary"
+                }
+            }
+        },
+        "entity.DamageCategoryItem": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "damaged_units": {
+                    "type": "integer"
+                },
+                "fees_assessed": {
+                    "type": "integer"
+                },
+                "incident_rate": {
+                    "type": "number"
+                },
+                "lost_units": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "returned_units": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DamageCustomerItem": {
+            "type": "object",
+            "properties": {
+                "damaged_units": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "fees_charged": {
+                    "type": "integer"
+                },
+                "fees_collected": {
+                    "type": "integer"
+                },
+                "flagged": {
+                    "description": "insiden berulang, pertimbangkan deposit",
+                    "type": "boolean"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "incident_count": {
+                    "description": "jumlah rental dengan insiden",
+                    "type": "integer"
+                },
+                "lost_units": {
+                    "type": "integer"
+                },
+                "returned_rentals": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DamageReport": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DamageCategoryItem"
+                    }
+                },
+                "customers": {
+                    "description": "hanya pelanggan dengan insiden, urut dari yang terbanyak",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DamageCustomerItem"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/entity.DamageSummary"
+                },
+                "toys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DamageToyItem"
+                    }
+                }
+            }
+        },
+        "entity.DamageSummary": {
+            "type": "object",
+            "properties": {
+                "damage_rate": {
+                    "description": "persentase rental dengan insiden",
+                    "type": "number"
+                },
+                "damaged_units": {
+                    "type": "integer"
+                },
+                "fees_charged": {
+                    "type": "integer"
+                },
+                "fees_collected": {
+                    "type": "integer"
+                },
+                "fees_outstanding": {
+                    "type": "integer"
+                },
+                "incident_rentals": {
+                    "type": "integer"
+                },
+                "lost_units": {
+                    "type": "integer"
+                },
+                "returned_rentals": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DamageToyItem": {
+            "type": "object",
+            "properties": {
+                "damaged_units": {
+                    "type": "integer"
+                },
+                "fees_assessed": {
+                    "type": "integer"
+                },
+                "incident_rate": {
+                    "description": "persentase unit kembali rusak atau hilang",
+                    "type": "number"
+                },
+                "lost_units": {
+                    "type": "integer"
+                },
+                "returned_units": {
+                    "type": "integer"
+                },
+                "toy_id": {
+                    "type": "string"
+                },
+                "toy_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DashboardPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "rental_count": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.DashboardReport": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "$ref": "#/definitions/entity.KPIChange"
+                },
+                "current": {
+                    "$ref": "#/definitions/entity.KPISummary"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "previous": {
+                    "$ref": "#/definitions/entity.KPISummary"
+                },
+                "previous_end_date": {
+                    "type": "string"
+                },
+                "previous_start_date": {
+                    "type": "string"
+                },
+                "series": {
+                    "description": "harian, hari tanpa transaksi bernilai nol",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DashboardPoint"
+                    }
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DeliverySlot": {
+            "type": "object",
+            "properties": {
+                "booked": {
+                    "type": "integer"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DeliverySlotRequest": {
+            "type": "object",
+            "required": [
+                "branch_id",
+                "capacity",
+                "date",
+                "end_time",
+                "start_time"
+            ],
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "Format tanggal YYYY-MM-DD",
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "start_time": {
+                    "description": "Format jam HH:MM",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DeliveryTask": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "address_id": {
+                    "type": "string"
+                },
+                "address_notes": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "courier_id": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "recipient_name": {
+                    "type": "string"
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "slot": {
+                    "$ref": "#/definitions/entity.DeliverySlot"
+                },
+                "slot_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DeliveryZone": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_distance_km": {
+                    "description": "0 berarti zona hanya berdasarkan kode pos",
+                    "type": "number"
+                },
+                "min_distance_km": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "postal_codes": {
+                    "description": "Dipisah koma. Awalan seperti \"401\" mencakup semua kode pos yang diawali 401",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.DeliveryZoneRequest": {
+            "type": "object",
+            "required": [
+                "branch_id",
+                "name"
+            ],
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "fee": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_distance_km": {
+                    "type": "number"
+                },
+                "min_distance_km": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "postal_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.ExtendRentalRequest": {
+            "type": "object",
+            "required": [
+                "new_expected_return_date"
+            ],
+            "properties": {
+                "new_expected_return_date": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.FeePolicy": {
+            "type": "object",
+            "properties": {
+                "activated_at": {
+                    "type": "string"
+                },
+                "condition_step_percent": {
+                    "type": "number"
+                },
+                "daily_late_fee_cap": {
+                    "type": "integer"
+                },
+                "damage_percent": {
+                    "description": "Kerusakan, dalam persen dari harga pengganti. Nilai batas 0 berarti tanpa batas.",
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "grace_period_minutes": {
+                    "description": "Keterlambatan",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "late_day_hours": {
+                    "type": "integer"
+                },
+                "lost_percent": {
+                    "type": "number"
+                },
+                "max_damage_fee": {
+                    "type": "integer"
+                },
+                "max_late_fee": {
+                    "type": "integer"
+                },
+                "max_unit_damage_percent": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FeePolicyRule"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.FeePolicyRequest": {
+            "type": "object",
+            "required": [
+                "late_day_hours",
+                "name"
+            ],
+            "properties": {
+                "activate": {
+                    "type": "boolean"
+                },
+                "condition_step_percent": {
+                    "type": "number"
+                },
+                "daily_late_fee_cap": {
+                    "type": "integer"
+                },
+                "damage_percent": {
+                    "type": "number"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "grace_period_minutes": {
+                    "type": "integer"
+                },
+                "late_day_hours": {
+                    "type": "integer"
+                },
+                "lost_percent": {
+                    "type": "number"
+                },
+                "max_damage_fee": {
+                    "type": "integer"
+                },
+                "max_late_fee": {
+                    "type": "integer"
+                },
+                "max_unit_damage_percent": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.FeePolicyRuleRequest"
+                    }
+                }
+            }
+        },
+        "entity.FeePolicyRule": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "condition_step_percent": {
+                    "type": "number"
+                },
+                "damage_percent": {
+                    "type": "number"
+                },
+                "fee_policy_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lost_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.FeePolicyRuleRequest": {
+            "type": "object",
+            "required": [
+                "category_id"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "condition_step_percent": {
+                    "type": "number"
+                },
+                "damage_percent": {
+                    "type": "number"
+                },
+                "lost_percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.Holiday": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.HolidayRequest": {
+            "type": "object",
+            "required": [
+                "date",
+                "name"
+            ],
+            "properties": {
+                "date": {
+                    "description": "Format tanggal YYYY-MM-DD",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.JournalEntry": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "entry_date": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.JournalLine"
+                    }
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "source_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.JournalLine": {
+            "type": "object",
+            "properties": {
+                "account_code": {
+                    "type": "string"
+                },
+                "credit": {
+                    "type": "integer"
+                },
+                "debit": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "journal_entry_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.KPIChange": {
+            "type": "object",
+            "properties": {
+                "active_rentals": {
+                    "type": "number"
+                },
+                "average_basket": {
+                    "type": "number"
+                },
+                "new_customers": {
+                    "type": "number"
+                },
+                "overdue_count": {
+                    "type": "number"
+                },
+                "rental_count": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "utilization_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.KPISummary": {
+            "type": "object",
+            "properties": {
+                "active_rentals": {
+                    "type": "integer"
+                },
+                "average_basket": {
+                    "type": "integer"
+                },
+                "new_customers": {
+                    "description": "pelanggan yang rental pertamanya jatuh di periode ini",
+                    "type": "integer"
+                },
+                "overdue_count": {
+                    "type": "integer"
+                },
+                "rental_count": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "integer"
+                },
+                "utilization_rate": {
+                    "type": "number"
+                }
+            }
+        },
+        "entity.MaintenanceTask": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "completed_by": {
+                    "type": "string"
+                },
+                "due_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "rental_item_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "started_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "toy_id": {
+                    "type": "string"
+                },
+                "toy_unit": {
+                    "$ref": "#/definitions/entity.ToyUnit"
+                },
+                "toy_unit_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.OverdueRentalItem": {
+            "type": "object",
+            "properties": {
+                "days_overdue": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expected_return_date": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "rental_date": {
+                    "type": "string"
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "total_rental_price": {
+                    "type": "integer"
+                },
+                "unit_count": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Payment": {
+            "type": "object",
+            "properties": {
+                "expiry_time": {
+                    "type": "string"
+                },
+                "fraud_status": {
+                    "type": "string"
+                },
+                "gross_amount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PaymentItem"
+                    }
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "payment_type": {
+                    "type": "string"
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "snap_token": {
+                    "type": "string"
+                },
+                "snap_url": {
+                    "type": "string"
+                },
+                "tax_invoice_date": {
+                    "type": "string"
+                },
+                "tax_invoice_number": {
+                    "type": "string"
+                },
+                "transaction_status": {
+                    "type": "string"
+                },
+                "transaction_time": {
+                    "type": "string"
+                },
+                "va_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PaymentItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "toy_id": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PriceLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "toy_id": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PriceQuote": {
+            "type": "object",
+            "properties": {
+                "delivery_fee": {
+                    "type": "integer"
+                },
+                "deposit": {
+                    "description": "ditagihkan bersama pembayaran awal, dikembalikan setelah rental selesai",
+                    "type": "integer"
+                },
+                "discount_amount": {
+                    "type": "integer"
+                },
+                "expected_return_date": {
+                    "type": "string"
+                },
+                "grand_total": {
+                    "description": "total termasuk PPN, belum termasuk deposit",
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PriceLine"
+                    }
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "rental_date": {
+                    "type": "string"
+                },
+                "rental_days": {
+                    "type": "integer"
+                },
+                "tax": {
+                    "type": "integer"
+                },
+                "tax_inclusive": {
+                    "type": "boolean"
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PricingRule": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "min_days": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PricingRuleRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "percent",
+                "type"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "min_days": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Promotion": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ToyCategory"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "discount_value": {
+                    "type": "number"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_discount": {
+                    "description": "0 berarti tanpa batas",
+                    "type": "integer"
+                },
+                "min_order_amount": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "per_user_limit": {
+                    "description": "0 berarti tanpa batas",
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "toys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Toy"
+                    }
+                },
+                "usage_limit": {
+                    "description": "0 berarti tanpa batas",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PromotionRedemption": {
+            "type": "object",
+            "properties": {
+                "discount_amount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "promotion_id": {
+                    "type": "string"
+                },
+                "redeemed_at": {
+                    "type": "string"
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.PromotionRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "discount_type",
+                "discount_value",
+                "ends_at",
+                "name",
+                "starts_at"
+            ],
+            "properties": {
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "discount_value": {
+                    "type": "number"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_discount": {
+                    "type": "integer"
+                },
+                "min_order_amount": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "toy_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "usage_limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.PromotionUsageItem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "last_redemption_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "paid_rental_count": {
+                    "type": "integer"
+                },
+                "promotion_id": {
+                    "type": "string"
+                },
+                "redemption_count": {
+                    "type": "integer"
+                },
+                "rental_revenue": {
+                    "type": "integer"
+                },
+                "total_discount": {
+                    "type": "integer"
+                },
+                "unique_users": {
+                    "type": "integer"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.QuoteRentalRequest": {
+            "type": "object",
+            "required": [
+                "expected_return_date",
+                "items",
+                "rental_date"
+            ],
+            "properties": {
+                "address_id": {
+                    "description": "Alamat dari buku alamat, wajib bila memilih delivery atau pickup",
+                    "type": "string"
+                },
+                "delivery_method": {
+                    "description": "self_pickup (default) atau delivery",
+                    "type": "string"
+                },
+                "delivery_slot_id": {
+                    "type": "string"
+                },
+                "expected_return_date": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.CreateRentalItemRequest"
+                    }
+                },
+                "pickup_branch_id": {
+                    "type": "string"
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "rental_date": {
+                    "type": "string"
+                },
+                "return_branch_id": {
+                    "type": "string"
+                },
+                "return_method": {
+                    "description": "self_return (default) atau pickup",
+                    "type": "string"
+                },
+                "return_slot_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.RegisterToyUnitsRequest": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "description": "Cabang tempat unit disimpan, default cabang utama",
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "serial_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "entity.Rental": {
+            "type": "object",
+            "properties": {
+                "actual_return_date": {
+                    "type": "string"
+                },
+                "damage_fee": {
+                    "type": "integer"
+                },
+                "delivery_fee": {
+                    "description": "ongkos antar jemput, sudah termasuk dalam TotalRentalPrice",
+                    "type": "integer"
+                },
+                "delivery_method": {
+                    "type": "string"
+                },
+                "delivery_tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.DeliveryTask"
+                    }
+                },
+                "deposit_amount": {
+                    "type": "integer"
+                },
+                "deposit_applied": {
+                    "type": "integer"
+                },
+                "deposit_refunded": {
+                    "type": "integer"
+                },
+                "deposit_settled_at": {
+                    "type": "string"
+                },
+                "deposit_status": {
+                    "type": "string"
+                },
+                "discount_amount": {
+                    "type": "integer"
+                },
+                "expected_return_date": {
+                    "type": "string"
+                },
+                "fee_policy_id": {
+                    "type": "string"
+                },
+                "fee_policy_version": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "late_fee": {
+                    "type": "integer"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "payment_status": {
+                    "type": "string"
+                },
+                "pickup_branch_id": {
+                    "type": "string"
+                },
+                "price_lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RentalPriceLine"
+                    }
+                },
+                "promo_code": {
+                    "type": "string"
+                },
+                "promotion_id": {
+                    "type": "string"
+                },
+                "rental_date": {
+                    "type": "string"
+                },
+                "rental_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RentalItem"
+                    }
+                },
+                "return_branch_id": {
+                    "type": "string"
+                },
+                "return_method": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tax_inclusive": {
+                    "type": "boolean"
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "total_amount": {
+                    "type": "integer"
+                },
+                "total_rental_price": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "written_off_amount": {
+                    "type": "integer"
+                },
+                "written_off_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.RentalBalance": {
+            "type": "object",
+            "properties": {
+                "damage_fee": {
+                    "type": "integer"
+                },
+                "damage_fee_due": {
+                    "type": "integer"
+                },
+                "damage_fee_tax": {
+                    "type": "integer"
+                },
+                "deposit_applied": {
+                    "type": "integer"
+                },
+                "late_fee": {
+                    "type": "integer"
+                },
+                "late_fee_due": {
+                    "type": "integer"
+                },
+                "late_fee_tax": {
+                    "type": "integer"
+                },
+                "outstanding": {
+                    "type": "integer"
+                },
+                "overpaid": {
+                    "type": "integer"
+                },
+                "paid": {
+                    "type": "integer"
+                },
+                "rental_charge": {
+                    "type": "integer"
+                },
+                "rental_due": {
+                    "type": "integer"
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "rental_tax": {
+                    "type": "integer"
+                },
+                "tax": {
+                    "type": "integer"
+                },
+                "total_charged": {
+                    "type": "integer"
+                },
+                "written_off": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.RentalItem": {
+            "type": "object",
+            "properties": {
+                "condition_after": {
+                    "type": "string"
+                },
+                "condition_before": {
+                    "type": "string"
+                },
+                "damage_description": {
+                    "type": "string"
+                },
+                "damage_fee": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price_per_unit": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "toy": {
+                    "$ref": "#/definitions/entity.Toy"
+                },
+                "toy_id": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RentalItemUnit"
+                    }
+                }
+            }
+        },
+        "entity.RentalItemUnit": {
+            "type": "object",
+            "properties": {
+                "condition_after": {
+                    "type": "string"
+                },
+                "condition_before": {
+                    "type": "string"
+                },
+                "damage_description": {
+                    "type": "string"
+                },
+                "damage_fee": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rental_item_id": {
+                    "type": "string"
+                },
+                "returned_at": {
+                    "type": "string"
+                },
+                "toy_unit": {
+                    "$ref": "#/definitions/entity.ToyUnit"
+                },
+                "toy_unit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.RentalPriceLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "toy_id": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ReportDelivery": {
+            "type": "object",
+            "properties": {
+                "delivered_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manual": {
+                    "description": "dikirim lewat endpoint kirim sekarang",
+                    "type": "boolean"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "string"
+                },
+                "report_type": {
+                    "type": "string"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReportParameters": {
+            "type": "object",
+            "properties": {
+                "basis": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "idle_days": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "locale": {
+                    "description": "Isi id untuk format angka dan tanggal Indonesia",
+                    "type": "string"
+                },
+                "min_incidents": {
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "yesterday, last_7_days (default), last_week, last_30_days atau last_month",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReportSubscription": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "description": "kosong berarti semua cabang",
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "$ref": "#/definitions/entity.ReportParameters"
+                },
+                "recipients": {
+                    "description": "Dipisah koma",
+                    "type": "string"
+                },
+                "report_type": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "description": "Lima kolom cron: menit jam tanggal bulan hari, misalnya \"0 7 * * 1\" untuk Senin pukul 07.00",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReportSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "recipients",
+                "report_type",
+                "schedule"
+            ],
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "format": {
+                    "description": "csv (default) atau xlsx",
+                    "type": "string"
+                },
+                "is_active": {
+                    "description": "default true",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "$ref": "#/definitions/entity.ReportParameters"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "report_type": {
+                    "type": "string"
+                },
+                "schedule": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReturnRentalItemRequest": {
+            "type": "object",
+            "required": [
+                "condition_after",
+                "rental_item_id"
+            ],
+            "properties": {
+                "condition_after": {
+                    "type": "string"
+                },
+                "damage_description": {
+                    "type": "string"
+                },
+                "rental_item_id": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ReturnRentalUnitRequest"
+                    }
+                }
+            }
+        },
+        "entity.ReturnRentalRequest": {
+            "type": "object",
+            "required": [
+                "actual_return_date",
+                "items"
+            ],
+            "properties": {
+                "actual_return_date": {
+                    "type": "string"
+                },
+                "deposit_refund_method": {
+                    "description": "Cara mengembalikan sisa deposit: gateway (default) atau credit",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ReturnRentalItemRequest"
+                    }
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "return_branch_id": {
+                    "description": "Cabang tempat barang benar-benar dikembalikan bila berbeda dari rencana",
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ReturnRentalUnitRequest": {
+            "type": "object",
+            "required": [
+                "condition_after",
+                "toy_unit_id"
+            ],
+            "properties": {
+                "condition_after": {
+                    "type": "string"
+                },
+                "damage_description": {
+                    "type": "string"
+                },
+                "toy_unit_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.StartMaintenanceTaskRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.TaxInvoice": {
+            "type": "object",
+            "properties": {
+                "customer_email": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "gross_amount": {
+                    "type": "integer"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.PriceLine"
+                    }
+                },
+                "net_amount": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "rental_id": {
+                    "type": "string"
+                },
+                "tax_amount": {
+                    "type": "integer"
+                },
+                "tax_rate": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Toy": {
+            "type": "object",
+            "properties": {
+                "age_recommendation": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ToyCategory"
+                    }
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "deposit_amount": {
+                    "description": "deposit per unit, 0 berarti tanpa deposit",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ToyImage"
+                    }
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "late_fee_per_day": {
+                    "type": "integer"
+                },
+                "min_rental_days": {
+                    "type": "integer"
+                },
+                "monthly_price": {
+                    "description": "0 berarti tanpa tarif bulanan",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "primary_image": {
+                    "type": "string"
+                },
+                "rental_price": {
+                    "type": "integer"
+                },
+                "replacement_price": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "description": "jumlah unit tersedia, diturunkan dari toy_units",
+                    "type": "integer"
+                },
+                "weekly_price": {
+                    "description": "0 berarti tanpa tarif mingguan",
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ToyBranchStock": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "branch_code": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "in_transit": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ToyCategory": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.ToyCategoryRequest": {
             "type": "object",
             "required": [
-                "rental_id"
+                "name"
             ],
             "properties": {
-                "rental_id": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "slug": {
                     "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
                 }
             }
         },
-        "entity.CreateRentalItemRequest": {
+        "entity.ToyCategoryTree": {
             "type": "object",
             "properties": {
-                "condition_before": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ToyCategoryTree"
+                    }
+                },
+                "description": {
                     "type": "string"
                 },
-                "quantity": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "sort_order": {
                     "type": "integer"
+                }
+            }
+        },
+        "entity.ToyImage": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
                 },
-                "toy_id": {
+                "image_url": {
                     "type": "string"
                 }
             }
         },
-        "entity.CreateRentalRequest": {
+        "entity.ToyRequest": {
             "type": "object",
+            "required": [
+                "category_ids",
+                "condition",
+                "image_ids",
+                "late_fee_per_day",
+                "name",
+                "rental_price",
+                "replacement_price",
+                "stock"
+            ],
             "properties": {
-                "expected_return_date": {
+                "age_recommendation": {
                     "type": "string"
                 },
-                "items": {
+                "category_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.CreateRentalItemRequest"
+                        "type": "string"
                     }
                 },
-                "notes": {
+                "condition": {
                     "type": "string"
                 },
-                "rental_date": {
+                "deposit_amount": {
+                    "type": "integer"
+                },
+                "description": {
                     "type": "string"
                 },
-                "user_id": {
+                "image_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "late_fee_per_day": {
+                    "type": "integer"
+                },
+                "min_rental_days": {
+                    "type": "integer"
+                },
+                "monthly_price": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "primary_image_id": {
                     "type": "string"
+                },
+                "rental_price": {
+                    "type": "integer"
+                },
+                "replacement_price": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "description": "jumlah unit awal yang didaftarkan",
+                    "type": "integer"
+                },
+                "weekly_price": {
+                    "type": "integer"
                 }
             }
         },
-        "entity.ExtendRentalRequest": {
+        "entity.ToyUnit": {
             "type": "object",
-            "required": [
-                "new_expected_return_date"
-            ],
             "properties": {
-                "new_expected_return_date": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "description": "BranchID adalah lokasi unit. Selama TransferID terisi unit masih dalam perjalanan ke cabang ini.",
+                    "type": "string"
+                },
+                "condition": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string"
                 },
                 "notes": {
                     "type": "string"
+                },
+                "serial_number": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "toy_id": {
+                    "type": "string"
+                },
+                "transfer_id": {
+                    "type": "string"
                 }
             }
         },
-        "entity.Payment": {
+        "entity.ToyUnitHistory": {
             "type": "object",
             "properties": {
-                "expiry_time": {
-                    "type": "string"
-                },
-                "fraud_status": {
-                    "type": "string"
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ToyUnitLog"
+                    }
                 },
-                "gross_amount": {
-                    "type": "number"
+                "rentals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ToyUnitRentalHistory"
+                    }
                 },
-                "id": {
+                "unit": {
+                    "$ref": "#/definitions/entity.ToyUnit"
+                }
+            }
+        },
+        "entity.ToyUnitLog": {
+            "type": "object",
+            "properties": {
+                "condition_from": {
                     "type": "string"
                 },
-                "order_id": {
+                "condition_to": {
                     "type": "string"
                 },
-                "payment_method": {
+                "event": {
                     "type": "string"
                 },
-                "payment_type": {
+                "id": {
                     "type": "string"
                 },
-                "rental_id": {
+                "logged_at": {
                     "type": "string"
                 },
-                "snap_token": {
+                "notes": {
                     "type": "string"
                 },
-                "snap_url": {
+                "rental_item_id": {
                     "type": "string"
                 },
-                "transaction_status": {
+                "status_from": {
                     "type": "string"
                 },
-                "transaction_time": {
+                "status_to": {
                     "type": "string"
                 },
-                "va_number": {
+                "toy_unit_id": {
                     "type": "string"
                 }
             }
         },
-        "entity.Rental": {
+        "entity.ToyUnitRentalHistory": {
             "type": "object",
             "properties": {
-                "actual_return_date": {
+                "condition_after": {
+                    "type": "string"
+                },
+                "condition_before": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "damage_description": {
                     "type": "string"
                 },
                 "damage_fee": {
-                    "type": "number"
+                    "type": "integer"
                 },
-                "expected_return_date": {
+                "rental_date": {
                     "type": "string"
                 },
-                "id": {
+                "rental_id": {
                     "type": "string"
                 },
-                "late_fee": {
-                    "type": "number"
+                "rental_item_id": {
+                    "type": "string"
                 },
-                "notes": {
+                "rental_status": {
                     "type": "string"
                 },
-                "payment_status": {
+                "returned_at": {
                     "type": "string"
                 },
-                "rental_date": {
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.ToyUpdateRequest": {
+            "type": "object",
+            "required": [
+                "category_ids",
+                "condition",
+                "image_ids",
+                "late_fee_per_day",
+                "name",
+                "rental_price",
+                "replacement_price"
+            ],
+            "properties": {
+                "age_recommendation": {
                     "type": "string"
                 },
-                "rental_items": {
+                "category_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.RentalItem"
+                        "type": "string"
                     }
                 },
-                "status": {
+                "condition": {
                     "type": "string"
                 },
-                "total_amount": {
-                    "type": "number"
+                "deposit_amount": {
+                    "type": "integer"
                 },
-                "total_rental_price": {
-                    "type": "number"
+                "description": {
+                    "type": "string"
                 },
-                "user_id": {
+                "image_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_available": {
+                    "type": "boolean"
+                },
+                "late_fee_per_day": {
+                    "type": "integer"
+                },
+                "min_rental_days": {
+                    "type": "integer"
+                },
+                "monthly_price": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "primary_image_id": {
                     "type": "string"
+                },
+                "rental_price": {
+                    "type": "integer"
+                },
+                "replacement_price": {
+                    "type": "integer"
+                },
+                "weekly_price": {
+                    "type": "integer"
                 }
             }
         },
-        "entity.RentalItem": {
+        "entity.ToyUtilizationItem": {
             "type": "object",
             "properties": {
-                "condition_after": {
-                    "type": "string"
+                "available_unit_days": {
+                    "type": "number"
                 },
-                "condition_before": {
+                "days_since_last_rental": {
+                    "description": "kosong jika belum pernah disewa",
+                    "type": "integer"
+                },
+                "image_url": {
                     "type": "string"
                 },
-                "damage_description": {
+                "last_rental_date": {
                     "type": "string"
                 },
-                "damage_fee": {
+                "rented_unit_days": {
                     "type": "number"
                 },
-                "id": {
+                "revenue": {
+                    "type": "integer"
+                },
+                "revenue_per_unit": {
+                    "type": "integer"
+                },
+                "toy_id": {
                     "type": "string"
                 },
-                "price_per_unit": {
-                    "type": "number"
+                "toy_name": {
+                    "type": "string"
                 },
-                "quantity": {
+                "unit_count": {
                     "type": "integer"
                 },
-                "rental_id": {
+                "utilization_rate": {
+                    "description": "persentase hari-unit yang tersewa",
+                    "type": "number"
+                }
+            }
+        },
+        "entity.TrialBalanceItem": {
+            "type": "object",
+            "properties": {
+                "account_code": {
                     "type": "string"
                 },
-                "status": {
+                "account_name": {
                     "type": "string"
                 },
-                "toy": {
-                    "$ref": "#/definitions/entity.Toy"
-                },
-                "toy_id": {
+                "account_type": {
                     "type": "string"
+                },
+                "balance": {
+                    "type": "integer"
+                },
+                "credit": {
+                    "type": "integer"
+                },
+                "debit": {
+                    "type": "integer"
                 }
             }
         },
-        "entity.ReturnRentalItemRequest": {
+        "entity.UpdateDeliveryTaskStatusRequest": {
             "type": "object",
             "required": [
-                "condition_after",
-                "rental_item_id"
+                "status"
             ],
             "properties": {
-                "condition_after": {
-                    "type": "string"
-                },
-                "damage_description": {
+                "notes": {
                     "type": "string"
                 },
-                "rental_item_id": {
+                "status": {
+                    "description": "on_the_way, completed, failed atau cancelled",
                     "type": "string"
                 }
             }
         },
-        "entity.ReturnRentalRequest": {
+        "entity.UpdateToyUnitRequest": {
             "type": "object",
             "required": [
-                "actual_return_date",
-                "items"
+                "condition",
+                "status"
             ],
             "properties": {
-                "actual_return_date": {
+                "barcode": {
                     "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.ReturnRentalItemRequest"
-                    }
+                "condition": {
+                    "type": "string"
                 },
                 "notes": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "entity.Toy": {
+        "entity.User": {
             "type": "object",
             "properties": {
-                "age_recommendation": {
+                "address": {
                     "type": "string"
                 },
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.ToyCategory"
-                    }
+                "branch_id": {
+                    "description": "BranchID membatasi admin pada satu cabang, kosong berarti admin pusat",
+                    "type": "string"
                 },
-                "condition": {
+                "email": {
                     "type": "string"
                 },
-                "description": {
+                "full_name": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "images": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.ToyImage"
-                    }
-                },
-                "is_available": {
+                "is_active": {
                     "type": "boolean"
                 },
-                "late_fee_per_day": {
-                    "type": "number"
-                },
-                "name": {
+                "password": {
                     "type": "string"
                 },
-                "primary_image": {
+                "phone_number": {
                     "type": "string"
                 },
-                "rental_price": {
-                    "type": "number"
-                },
-                "replacement_price": {
-                    "type": "number"
+                "role": {
+                    "type": "string"
                 },
-                "stock": {
-                    "type": "integer"
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "entity.ToyCategory": {
+        "entity.UserAddress": {
             "type": "object",
             "properties": {
-                "description": {
+                "city": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "is_default": {
+                    "type": "boolean"
+                },
+                "label": {
+                    "description": "contoh: Rumah, Kantor",
                     "type": "string"
-                }
-            }
-        },
-        "entity.ToyImage": {
-            "type": "object",
-            "properties": {
-                "id": {
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "notes": {
+                    "description": "patokan untuk kurir",
                     "type": "string"
                 },
-                "image_url": {
+                "phone_number": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "recipient_name": {
+                    "type": "string"
+                },
+                "street": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "entity.ToyRequest": {
+        "entity.UserAddressRequest": {
             "type": "object",
             "required": [
-                "category_ids",
-                "condition",
-                "image_ids",
-                "late_fee_per_day",
-                "name",
-                "rental_price",
-                "replacement_price",
-                "stock"
+                "city",
+                "label",
+                "phone_number",
+                "postal_code",
+                "recipient_name",
+                "street"
             ],
             "properties": {
-                "age_recommendation": {
+                "city": {
                     "type": "string"
                 },
-                "category_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "condition": {
-                    "type": "string"
+                "is_default": {
+                    "description": "Jadikan alamat utama, alamat pertama selalu menjadi alamat utama",
+                    "type": "boolean"
                 },
-                "description": {
+                "label": {
                     "type": "string"
                 },
-                "image_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "is_available": {
-                    "type": "boolean"
+                "latitude": {
+                    "type": "number"
                 },
-                "late_fee_per_day": {
+                "longitude": {
                     "type": "number"
                 },
-                "name": {
+                "notes": {
                     "type": "string"
                 },
-                "primary_image_id": {
+                "phone_number": {
                     "type": "string"
                 },
-                "rental_price": {
-                    "type": "number"
+                "postal_code": {
+                    "type": "string"
                 },
-                "replacement_price": {
-                    "type": "number"
+                "recipient_name": {
+                    "type": "string"
                 },
-                "stock": {
-                    "type": "integer"
+                "street": {
+                    "type": "string"
                 }
             }
         },
-        "entity.ToyUpdateRequest": {
+        "entity.UserLoginRequest": {
             "type": "object",
-            "required": [
-                "category_ids",
-                "condition",
-                "image_ids",
-                "late_fee_per_day",
-                "name",
-                "rental_price",
-                "replacement_price",
-                "stock"
-            ],
             "properties": {
-                "age_recommendation": {
+                "email": {
                     "type": "string"
                 },
-                "category_ids": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.UtilizationReport": {
+            "type": "object",
+            "properties": {
+                "categories": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/entity.CategoryUtilizationItem"
                     }
                 },
-                "condition": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "image_ids": {
+                "idle_toys": {
+                    "description": "mainan yang tidak disewa lebih lama dari batas hari",
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/entity.ToyUtilizationItem"
                     }
                 },
-                "is_available": {
-                    "type": "boolean"
-                },
-                "late_fee_per_day": {
-                    "type": "number"
+                "toys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ToyUtilizationItem"
+                    }
+                }
+            }
+        },
+        "entity.Wallet": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer"
                 },
-                "name": {
+                "id": {
                     "type": "string"
                 },
-                "primary_image_id": {
+                "user_id": {
                     "type": "string"
+                }
+            }
+        },
+        "entity.WalletAdjustmentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "notes",
+                "reason",
+                "type"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "integer"
                 },
-                "rental_price": {
-                    "type": "number"
+                "notes": {
+                    "type": "string"
                 },
-                "replacement_price": {
-                    "type": "number"
+                "reason": {
+                    "description": "Alasan penyesuaian: goodwill atau adjustment",
+                    "type": "string"
                 },
-                "stock": {
-                    "type": "integer"
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "entity.User": {
+        "entity.WalletEntry": {
             "type": "object",
             "properties": {
-                "address": {
+                "amount": {
+                    "type": "integer"
+                },
+                "balance_after": {
+                    "type": "integer"
+                },
+                "created_by": {
+                    "description": "admin yang membuat penyesuaian",
                     "type": "string"
                 },
-                "email": {
+                "id": {
                     "type": "string"
                 },
-                "full_name": {
+                "notes": {
                     "type": "string"
                 },
-                "id": {
+                "payment_id": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
+                "reason": {
+                    "type": "string"
                 },
-                "password": {
+                "rental_id": {
                     "type": "string"
                 },
-                "phone_number": {
+                "type": {
                     "type": "string"
                 },
-                "role": {
+                "user_id": {
                     "type": "string"
                 },
-                "username": {
+                "wallet_id": {
                     "type": "string"
                 }
             }
         },
-        "entity.UserLoginRequest": {
+        "entity.WriteOffRentalRequest": {
             "type": "object",
+            "required": [
+                "notes"
+            ],
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
+                "notes": {
+                    "description": "Alasan penghapusan piutang, dicatat pada jurnal",
                     "type": "string"
                 }
             }
@@ -0,0 +1,182 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+// DefaultBranchCode adalah kode cabang utama yang dibuat otomatis untuk data sebelum multi cabang
+const DefaultBranchCode = "MAIN"
+
+const (
+	BranchTransferStatusInTransit = "in_transit"
+	BranchTransferStatusReceived  = "received"
+	BranchTransferStatusCancelled = "cancelled"
+)
+
+const ToyUnitEventTransferred = "transferred"
+
+var (
+	ErrBranchNotFound        = errors.New("cabang tidak ditemukan")
+	ErrBranchCodeExists      = errors.New("kode cabang sudah digunakan")
+	ErrBranchInactive        = errors.New("cabang tidak aktif")
+	ErrBranchHasUnits        = errors.New("cabang masih memiliki unit, pindahkan unit ke cabang lain terlebih dahulu")
+	ErrDefaultBranchLocked   = errors.New("kode cabang utama tidak dapat diubah dan cabangnya tidak dapat dihapus")
+	ErrBranchAccessDenied    = errors.New("admin cabang hanya dapat mengakses data cabangnya sendiri")
+	ErrBranchAdminOnly       = errors.New("cabang hanya dapat ditetapkan untuk admin")
+	ErrTransferSameBranch    = errors.New("cabang asal dan tujuan transfer tidak boleh sama")
+	ErrTransferNotInTransit  = errors.New("transfer sudah diterima atau dibatalkan")
+	ErrUnitNotAtBranch       = errors.New("unit tidak tersedia di cabang asal transfer")
+	ErrUnitInTransit         = errors.New("unit sedang dalam perjalanan antar cabang")
+	ErrTransferUnitsRequired = errors.New("pilih minimal satu unit untuk ditransfer")
+)
+
+// Branch adalah toko fisik tempat unit disimpan, diambil dan dikembalikan
+type Branch struct {
+	BaseEntity
+	Code         string   `gorm:"size:20;not null;uniqueIndex:idx_branches_code,where:deleted_at IS NULL" json:"code"`
+	Name         string   `gorm:"size:100;not null" json:"name"`
+	Address      string   `gorm:"type:text;not null" json:"address"`
+	City         string   `gorm:"size:100" json:"city"`
+	PhoneNumber  string   `gorm:"size:20" json:"phone_number"`
+	OpeningHours string   `gorm:"type:text" json:"opening_hours"`    // contoh: "Senin-Jumat 09:00-21:00, Sabtu-Minggu 10:00-22:00"
+	Latitude     *float64 `gorm:"type:decimal(9,6)" json:"latitude"` // dipakai untuk zona antar jemput berdasarkan jarak
+	Longitude    *float64 `gorm:"type:decimal(9,6)" json:"longitude"`
+	IsActive     bool     `gorm:"not null;default:true" json:"is_active"`
+}
+
+func (*Branch) TableName() string {
+	return "branches"
+}
+
+func (b *Branch) Validate() []string {
+	err := validation.ValidateStruct(b,
+		validation.Field(&b.Code,
+			validation.Required.Error("Kode cabang wajib diisi"),
+			validation.RuneLength(2, 20).Error("Kode cabang harus antara 2-20 karakter"),
+			validation.Match(regexp.MustCompile(`^[A-Z0-9-]+$`)).Error("Kode cabang hanya boleh berisi huruf kapital, angka dan tanda hubung"),
+		),
+		validation.Field(&b.Name,
+			validation.Required.Error("Nama cabang wajib diisi"),
+			validation.RuneLength(3, 100).Error("Nama cabang harus antara 3-100 karakter"),
+		),
+		validation.Field(&b.Address,
+			validation.Required.Error("Alamat cabang wajib diisi"),
+			validation.RuneLength(5, 1000).Error("Alamat cabang harus antara 5-1000 karakter"),
+		),
+		validation.Field(&b.City,
+			validation.RuneLength(0, 100).Error("Kota maksimal 100 karakter"),
+		),
+		validation.Field(&b.PhoneNumber,
+			validation.RuneLength(0, 20).Error("Nomor telepon maksimal 20 karakter"),
+			validation.Match(regexp.MustCompile(`^[0-9+\-\s]*$`)).Error("Nomor telepon hanya boleh berisi angka, +, - dan spasi"),
+		),
+		validation.Field(&b.OpeningHours,
+			validation.RuneLength(0, 500).Error("Jam buka maksimal 500 karakter"),
+		),
+		validation.Field(&b.Latitude,
+			validation.When(b.Longitude != nil, validation.NotNil.Error("Latitude wajib diisi bersama longitude")),
+			validation.Min(-90.0).Error("Latitude harus antara -90 dan 90"),
+			validation.Max(90.0).Error("Latitude harus antara -90 dan 90"),
+		),
+		validation.Field(&b.Longitude,
+			validation.When(b.Latitude != nil, validation.NotNil.Error("Longitude wajib diisi bersama latitude")),
+			validation.Min(-180.0).Error("Longitude harus antara -180 dan 180"),
+			validation.Max(180.0).Error("Longitude harus antara -180 dan 180"),
+		),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
+
+type BranchRequest struct {
+	Code         string   `json:"code" binding:"required"`
+	Name         string   `json:"name" binding:"required"`
+	Address      string   `json:"address" binding:"required"`
+	City         string   `json:"city"`
+	PhoneNumber  string   `json:"phone_number"`
+	OpeningHours string   `json:"opening_hours"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	IsActive     *bool    `json:"is_active"`
+}
+
+// BranchTransfer memindahkan unit antar cabang. Selama status in_transit unit sudah tercatat
+// di cabang tujuan namun belum dapat disewakan sampai transfer diterima.
+type BranchTransfer struct {
+	BaseEntity
+	FromBranchID uuid.UUID  `gorm:"type:uuid;not null;index" json:"from_branch_id"`
+	ToBranchID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"to_branch_id"`
+	Status       string     `gorm:"size:20;not null;default:in_transit;index;check:status IN ('in_transit', 'received', 'cancelled')" json:"status"`
+	Notes        string     `gorm:"type:text" json:"notes"`
+	ShippedAt    time.Time  `gorm:"not null" json:"shipped_at"`
+	CreatedBy    *uuid.UUID `gorm:"type:uuid" json:"created_by"`
+	ReceivedAt   *time.Time `json:"received_at"`
+	ReceivedBy   *uuid.UUID `gorm:"type:uuid" json:"received_by"`
+	CancelledAt  *time.Time `json:"cancelled_at"`
+
+	FromBranch Branch               `gorm:"foreignKey:FromBranchID" json:"from_branch"`
+	ToBranch   Branch               `gorm:"foreignKey:ToBranchID" json:"to_branch"`
+	Units      []BranchTransferUnit `gorm:"foreignKey:BranchTransferID" json:"units"`
+}
+
+func (*BranchTransfer) TableName() string {
+	return "branch_transfers"
+}
+
+// BranchTransferUnit mencatat unit yang dikirim pada sebuah transfer
+type BranchTransferUnit struct {
+	BaseEntity
+	BranchTransferID uuid.UUID `gorm:"type:uuid;not null;index" json:"branch_transfer_id"`
+	ToyUnitID        uuid.UUID `gorm:"type:uuid;not null;index" json:"toy_unit_id"`
+
+	ToyUnit ToyUnit `gorm:"foreignKey:ToyUnitID" json:"toy_unit"`
+}
+
+func (*BranchTransferUnit) TableName() string {
+	return "branch_transfer_units"
+}
+
+type CreateBranchTransferRequest struct {
+	FromBranchID uuid.UUID   `json:"from_branch_id" binding:"required"`
+	ToBranchID   uuid.UUID   `json:"to_branch_id" binding:"required"`
+	ToyUnitIDs   []uuid.UUID `json:"toy_unit_ids" binding:"required"`
+	Notes        string      `json:"notes"`
+}
+
+// BranchTransferFilter menyaring daftar transfer, BranchID mencocokkan cabang asal maupun tujuan
+type BranchTransferFilter struct {
+	Status   string
+	BranchID string
+}
+
+type AssignUserBranchRequest struct {
+	// Kosongkan agar admin dapat mengakses semua cabang
+	BranchID *uuid.UUID `json:"branch_id"`
+}
+
+// ToyBranchStock adalah ketersediaan unit sebuah mainan di satu cabang
+type ToyBranchStock struct {
+	BranchID   uuid.UUID `json:"branch_id"`
+	BranchCode string    `json:"branch_code"`
+	BranchName string    `json:"branch_name"`
+	Available  int       `json:"available"`
+	InTransit  int       `json:"in_transit"`
+}
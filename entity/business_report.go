@@ -1,57 +1,134 @@
 package entity
 
 import (
+	"final-project/utils/money"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
 )
 
 type SalesReportItem struct {
-	Date             string  `json:"date"`
-	RentalCount      int     `json:"rental_count"`
-	RentalRevenue    float64 `json:"rental_revenue"`
-	LateFeeRevenue   float64 `json:"late_fee_revenue"`
-	DamageFeeRevenue float64 `json:"damage_fee_revenue"`
-	TotalRevenue     float64 `json:"total_revenue"`
-	TransactionCount int     `json:"transaction_count"`
+	Date             string      `json:"date"`
+	RentalCount      int         `json:"rental_count"`
+	RentalRevenue    money.Money `json:"rental_revenue"`
+	LateFeeRevenue   money.Money `json:"late_fee_revenue"`
+	DamageFeeRevenue money.Money `json:"damage_fee_revenue"`
+	TotalRevenue     money.Money `json:"total_revenue"`
+	NetRevenue       money.Money `json:"net_revenue"`    // pendapatan tanpa PPN (DPP)
+	TaxAmount        money.Money `json:"tax_amount"`     // PPN keluaran
+	GrossRevenue     money.Money `json:"gross_revenue"`  // DPP ditambah PPN
+	DepositAmount    money.Money `json:"deposit_amount"` // deposit bukan pendapatan, tidak termasuk total_revenue
+	TransactionCount int         `json:"transaction_count"`
+}
+
+const (
+	SalesBasisAccrual = "accrual"
+	SalesBasisCash    = "cash"
+)
+
+// CashSalesReportItem adalah penerimaan kas dari pembayaran yang lunas di payment gateway. Pembayaran dengan
+// saldo dompet tidak termasuk karena tidak ada kas yang masuk. Refund dicatat pada tanggal refund-nya.
+type CashSalesReportItem struct {
+	Date             string      `json:"date"`
+	PaymentType      string      `json:"payment_type"`
+	PaymentMethod    string      `json:"payment_method"`
+	TransactionCount int         `json:"transaction_count"`
+	ReceivedAmount   money.Money `json:"received_amount"`
+	RefundedAmount   money.Money `json:"refunded_amount"`
+	DepositAmount    money.Money `json:"deposit_amount"` // deposit bukan pendapatan, sudah dikurangi refund
+	TaxAmount        money.Money `json:"tax_amount"`     // PPN yang ikut dibayar, sudah dikurangi refund
+	NetRevenue       money.Money `json:"net_revenue"`    // kas diterima dikurangi refund, deposit dan PPN
 }
 
 type PopularToyItem struct {
-	ToyID           uuid.UUID `json:"toy_id"`
-	ToyName         string    `json:"toy_name"`
-	ImageURL        string    `json:"image_url"`
-	RentalCount     int       `json:"rental_count"`
-	AverageDuration float64   `json:"average_duration"`
-	Revenue         float64   `json:"revenue"`
+	ToyID           uuid.UUID   `json:"toy_id"`
+	ToyName         string      `json:"toy_name"`
+	ImageURL        string      `json:"image_url"`
+	RentalCount     int         `json:"rental_count"`
+	AverageDuration float64     `json:"average_duration"`
+	Revenue         money.Money `json:"revenue"`
 }
 
 type TopCustomerItem struct {
-	UserID             uuid.UUID `json:"user_id"`
-	FullName           string    `json:"full_name"`
-	Email              string    `json:"email"`
-	PhoneNumber        string    `json:"phone_number"`
-	RentalCount        int       `json:"rental_count"`
-	TotalSpent         float64   `json:"total_spent"`
-	AverageRentalValue float64   `json:"average_rental_value"`
-	LateFeeCount       int       `json:"late_fee_count"`
-	DamageFeeCount     int       `json:"damage_fee_count"`
-	FirstRentalDate    time.Time `json:"first_rental_date"`
-	LastRentalDate     time.Time `json:"last_rental_date"`
+	UserID             uuid.UUID   `json:"user_id"`
+	FullName           string      `json:"full_name"`
+	Email              string      `json:"email"`
+	PhoneNumber        string      `json:"phone_number"`
+	RentalCount        int         `json:"rental_count"`
+	TotalSpent         money.Money `json:"total_spent"`
+	AverageRentalValue money.Money `json:"average_rental_value"`
+	LateFeeCount       int         `json:"late_fee_count"`
+	DamageFeeCount     int         `json:"damage_fee_count"`
+	FirstRentalDate    time.Time   `json:"first_rental_date"`
+	LastRentalDate     time.Time   `json:"last_rental_date"`
 }
 
 type InventoryStatusItem struct {
-	ToyID           uuid.UUID `json:"toy_id"`
-	ToyName         string    `json:"toy_name"`
-	ImageURL        string    `json:"image_url"`
-	Categories      []string  `json:"categories"`
-	CurrentStock    int       `json:"current_stock"`
-	TotalStock      int       `json:"total_stock"`
-	RentedCount     int       `json:"rented_count"`
-	AvailableCount  int       `json:"available_count"`
-	DamagedCount    int       `json:"damaged_count"`
-	LostCount       int       `json:"lost_count"`
-	Condition       string    `json:"condition"`
-	ReplacementCost float64   `json:"replacement_cost"`
+	ToyID           uuid.UUID   `json:"toy_id"`
+	ToyName         string      `json:"toy_name"`
+	ImageURL        string      `json:"image_url"`
+	Categories      []string    `json:"categories"`
+	CurrentStock    int         `json:"current_stock"`
+	TotalStock      int         `json:"total_stock"`
+	RentedCount     int         `json:"rented_count"`
+	AvailableCount  int         `json:"available_count"`
+	DamagedCount    int         `json:"damaged_count"`
+	LostCount       int         `json:"lost_count"`
+	Condition       string      `json:"condition"`
+	ReplacementCost money.Money `json:"replacement_cost"` // nilai penggantian unit yang rusak dan hilang
+}
+
+// InventoryStatusFilter menyaring laporan inventaris, field kosong berarti tanpa filter
+type InventoryStatusFilter struct {
+	CategoryID string
+	Condition  string
+}
+
+// CohortRetention adalah jumlah pelanggan kohort yang menyewa lagi pada bulan ke-MonthOffset sejak rental pertamanya
+type CohortRetention struct {
+	MonthOffset     int     `json:"month_offset"`
+	ActiveCustomers int     `json:"active_customers"`
+	RetentionRate   float64 `json:"retention_rate"`
+}
+
+// CohortItem mengelompokkan pelanggan berdasarkan bulan rental pertamanya
+type CohortItem struct {
+	CohortMonth   string            `json:"cohort_month"`
+	CustomerCount int               `json:"customer_count"`
+	Retention     []CohortRetention `json:"retention"` // bulan yang belum berjalan tidak ditampilkan
+}
+
+// CohortActivity adalah baris mentah kohort dari database sebelum disusun menjadi CohortItem
+type CohortActivity struct {
+	CohortMonth     string
+	CohortSize      int
+	MonthOffset     int
+	ActiveCustomers int
+}
+
+// CustomerValueItem adalah nilai seumur hidup pelanggan sejak rental pertama sampai sekarang
+type CustomerValueItem struct {
+	UserID                    uuid.UUID   `json:"user_id"`
+	FullName                  string      `json:"full_name"`
+	Email                     string      `json:"email"`
+	FirstRentalDate           time.Time   `json:"first_rental_date"`
+	LastRentalDate            time.Time   `json:"last_rental_date"`
+	RentalCount               int         `json:"rental_count"`
+	LifetimeValue             money.Money `json:"lifetime_value"`
+	AverageDaysBetweenRentals *float64    `json:"average_days_between_rentals"` // kosong jika baru sekali menyewa
+}
+
+type CustomerRetentionSummary struct {
+	CustomerCount             int         `json:"customer_count"`
+	RepeatCustomerCount       int         `json:"repeat_customer_count"`
+	RepeatRate                float64     `json:"repeat_rate"`
+	AverageDaysBetweenRentals float64     `json:"average_days_between_rentals"`
+	AverageLifetimeValue      money.Money `json:"average_lifetime_value"`
+}
+
+type CustomerValueReport struct {
+	Summary   CustomerRetentionSummary `json:"summary"`
+	Customers []CustomerValueItem      `json:"customers"`
 }
 
 type RentalStatusItem struct {
@@ -60,8 +137,171 @@ type RentalStatusItem struct {
 	PercentageTotal float64 `json:"percentage_total"`
 }
 
+// CategorySummary merangkum kinerja satu kategori. Pendapatan mainan yang masuk beberapa kategori dibagi rata
+// ke setiap kategorinya sehingga jumlah pendapatan semua kategori sama dengan total pendapatan sewa.
 type CategorySummary struct {
-	Name        string  `json:"name"`
-	RentalCount int     `json:"rental_count"`
-	Percentage  float64 `json:"percentage"`
+	CategoryID      *uuid.UUID  `json:"category_id"` // kosong untuk mainan tanpa kategori
+	Name            string      `json:"name"`
+	RentalCount     int         `json:"rental_count"`
+	Revenue         money.Money `json:"revenue"`
+	Percentage      float64     `json:"percentage"` // porsi dari total pendapatan sewa
+	AverageDuration float64     `json:"average_duration"`
+	DamageRate      float64     `json:"damage_rate"` // persentase unit yang kembali rusak atau hilang
+}
+
+// ToyUtilizationItem membandingkan hari-unit yang tersewa dengan hari-unit yang tersedia selama periode laporan
+type ToyUtilizationItem struct {
+	ToyID               uuid.UUID   `json:"toy_id"`
+	ToyName             string      `json:"toy_name"`
+	ImageURL            string      `json:"image_url"`
+	UnitCount           int         `json:"unit_count"`
+	RentedUnitDays      float64     `json:"rented_unit_days"`
+	AvailableUnitDays   float64     `json:"available_unit_days"`
+	UtilizationRate     float64     `json:"utilization_rate"` // persentase hari-unit yang tersewa
+	Revenue             money.Money `json:"revenue"`
+	RevenuePerUnit      money.Money `json:"revenue_per_unit"`
+	LastRentalDate      *time.Time  `json:"last_rental_date"`
+	DaysSinceLastRental *int        `json:"days_since_last_rental"` // kosong jika belum pernah disewa
+}
+
+// CategoryUtilizationItem menjumlahkan utilisasi mainan dalam satu kategori. Mainan dengan beberapa kategori
+// dihitung penuh di setiap kategorinya karena yang dibandingkan adalah rasio, bukan total pendapatan.
+type CategoryUtilizationItem struct {
+	CategoryID        *uuid.UUID  `json:"category_id"`
+	Name              string      `json:"name"`
+	ToyCount          int         `json:"toy_count"`
+	UnitCount         int         `json:"unit_count"`
+	RentedUnitDays    float64     `json:"rented_unit_days"`
+	AvailableUnitDays float64     `json:"available_unit_days"`
+	UtilizationRate   float64     `json:"utilization_rate"`
+	Revenue           money.Money `json:"revenue"`
+	RevenuePerUnit    money.Money `json:"revenue_per_unit"`
+}
+
+type UtilizationReport struct {
+	Toys       []ToyUtilizationItem      `json:"toys"`
+	Categories []CategoryUtilizationItem `json:"categories"`
+	IdleToys   []ToyUtilizationItem      `json:"idle_toys"` // mainan yang tidak disewa lebih lama dari batas hari
+}
+
+// KPISummary adalah indikator utama satu periode. Rental aktif dan terlambat dihitung pada akhir periode.
+type KPISummary struct {
+	Revenue         money.Money `json:"revenue"`
+	RentalCount     int         `json:"rental_count"`
+	ActiveRentals   int         `json:"active_rentals"`
+	OverdueCount    int         `json:"overdue_count"`
+	AverageBasket   money.Money `json:"average_basket"`
+	NewCustomers    int         `json:"new_customers"` // pelanggan yang rental pertamanya jatuh di periode ini
+	UtilizationRate float64     `json:"utilization_rate"`
+}
+
+// KPIChange berisi persentase perubahan terhadap periode sebelumnya, kosong jika nilai sebelumnya nol
+type KPIChange struct {
+	Revenue         *float64 `json:"revenue"`
+	RentalCount     *float64 `json:"rental_count"`
+	ActiveRentals   *float64 `json:"active_rentals"`
+	OverdueCount    *float64 `json:"overdue_count"`
+	AverageBasket   *float64 `json:"average_basket"`
+	NewCustomers    *float64 `json:"new_customers"`
+	UtilizationRate *float64 `json:"utilization_rate"`
+}
+
+type DashboardPoint struct {
+	Date        string      `json:"date"`
+	Revenue     money.Money `json:"revenue"`
+	RentalCount int         `json:"rental_count"`
+}
+
+type DashboardReport struct {
+	StartDate         time.Time        `json:"start_date"`
+	EndDate           time.Time        `json:"end_date"`
+	PreviousStartDate time.Time        `json:"previous_start_date"`
+	PreviousEndDate   time.Time        `json:"previous_end_date"`
+	Current           KPISummary       `json:"current"`
+	Previous          KPISummary       `json:"previous"`
+	Change            KPIChange        `json:"change"`
+	Series            []DashboardPoint `json:"series"` // harian, hari tanpa transaksi bernilai nol
+}
+
+// DamageSummary merangkum insiden kerusakan dan kehilangan pada rental yang dikembalikan dalam periode.
+// Biaya ditagih dan terkumpul sudah termasuk PPN, mengikuti alokasi pembayaran di RentalBalance.
+type DamageSummary struct {
+	ReturnedRentals int         `json:"returned_rentals"`
+	IncidentRentals int         `json:"incident_rentals"`
+	DamageRate      float64     `json:"damage_rate"` // persentase rental dengan insiden
+	DamagedUnits    int         `json:"damaged_units"`
+	LostUnits       int         `json:"lost_units"`
+	FeesCharged     money.Money `json:"fees_charged"`
+	FeesCollected   money.Money `json:"fees_collected"`
+	FeesOutstanding money.Money `json:"fees_outstanding"`
+}
+
+// DamageToyItem adalah insiden per mainan. FeesAssessed adalah biaya kerusakan per item sebelum batas
+// maksimum per rental dan sebelum PPN.
+type DamageToyItem struct {
+	ToyID         uuid.UUID   `json:"toy_id"`
+	ToyName       string      `json:"toy_name"`
+	ReturnedUnits int         `json:"returned_units"`
+	DamagedUnits  int         `json:"damaged_units"`
+	LostUnits     int         `json:"lost_units"`
+	IncidentRate  float64     `json:"incident_rate"` // persentase unit kembali rusak atau hilang
+	FeesAssessed  money.Money `json:"fees_assessed"`
+}
+
+// DamageCategoryItem adalah insiden per kategori. Biaya mainan dengan beberapa kategori dibagi rata.
+type DamageCategoryItem struct {
+	CategoryID    *uuid.UUID  `json:"category_id"`
+	Name          string      `json:"name"`
+	ReturnedUnits int         `json:"returned_units"`
+	DamagedUnits  int         `json:"damaged_units"`
+	LostUnits     int         `json:"lost_units"`
+	IncidentRate  float64     `json:"incident_rate"`
+	FeesAssessed  money.Money `json:"fees_assessed"`
+}
+
+type DamageCustomerItem struct {
+	UserID          uuid.UUID   `json:"user_id"`
+	FullName        string      `json:"full_name"`
+	Email           string      `json:"email"`
+	ReturnedRentals int         `json:"returned_rentals"`
+	IncidentCount   int         `json:"incident_count"` // jumlah rental dengan insiden
+	DamagedUnits    int         `json:"damaged_units"`
+	LostUnits       int         `json:"lost_units"`
+	FeesCharged     money.Money `json:"fees_charged"`
+	FeesCollected   money.Money `json:"fees_collected"`
+	Flagged         bool        `json:"flagged"` // insiden berulang, pertimbangkan deposit
+}
+
+// DamageFeeRental adalah komponen tagihan rental yang dikenai biaya kerusakan untuk menghitung bagian yang terkumpul
+type DamageFeeRental struct {
+	RentalID         uuid.UUID
+	UserID           uuid.UUID
+	TotalRentalPrice money.Money
+	LateFee          money.Money
+	DamageFee        money.Money
+	TaxRate          float64
+	TaxInclusive     bool
+	DepositApplied   money.Money
+	Paid             money.Money
+}
+
+type DamageReport struct {
+	Summary    DamageSummary        `json:"summary"`
+	Toys       []DamageToyItem      `json:"toys"`
+	Categories []DamageCategoryItem `json:"categories"`
+	Customers  []DamageCustomerItem `json:"customers"` // hanya pelanggan dengan insiden, urut dari yang terbanyak
+}
+
+// OverdueRentalItem adalah rental yang melewati tanggal kembali tetapi mainannya belum dikembalikan
+type OverdueRentalItem struct {
+	RentalID           uuid.UUID   `json:"rental_id"`
+	UserID             uuid.UUID   `json:"user_id"`
+	FullName           string      `json:"full_name"`
+	Email              string      `json:"email"`
+	PhoneNumber        string      `json:"phone_number"`
+	RentalDate         time.Time   `json:"rental_date"`
+	ExpectedReturnDate time.Time   `json:"expected_return_date"`
+	DaysOverdue        int         `json:"days_overdue"`
+	UnitCount          int         `json:"unit_count"`
+	TotalRentalPrice   money.Money `json:"total_rental_price"`
 }
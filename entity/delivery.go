@@ -0,0 +1,342 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	DeliveryMethodSelfPickup = "self_pickup"
+	DeliveryMethodDelivery   = "delivery"
+	ReturnMethodSelfReturn   = "self_return"
+	ReturnMethodPickup       = "pickup"
+)
+
+const (
+	DeliveryTaskTypeDelivery     = "delivery"
+	DeliveryTaskTypeReturnPickup = "return_pickup"
+)
+
+const (
+	DeliveryTaskStatusScheduled = "scheduled"
+	DeliveryTaskStatusOnTheWay  = "on_the_way"
+	DeliveryTaskStatusCompleted = "completed"
+	DeliveryTaskStatusFailed    = "failed"
+	DeliveryTaskStatusCancelled = "cancelled"
+)
+
+const (
+	PriceLineDelivery     = "delivery"
+	PriceLineReturnPickup = "return_pickup"
+)
+
+const (
+	DeliverySlotTimeFormat = "15:04"
+	earthRadiusKm          = 6371.0
+)
+
+var (
+	ErrInvalidDeliveryMethod    = errors.New("cara pengambilan harus self_pickup atau delivery")
+	ErrInvalidReturnMethod      = errors.New("cara pengembalian harus self_return atau pickup")
+	ErrDeliveryAddressRequired  = errors.New("alamat wajib dipilih untuk antar atau jemput mainan")
+	ErrDeliverySlotRequired     = errors.New("jadwal antar atau jemput wajib dipilih")
+	ErrDeliverySlotNotFound     = errors.New("jadwal antar jemput tidak ditemukan")
+	ErrDeliverySlotMismatch     = errors.New("jadwal antar jemput tidak sesuai dengan cabang atau tanggal rental")
+	ErrDeliverySlotFull         = errors.New("kuota jadwal antar jemput sudah penuh, pilih jadwal lain")
+	ErrDeliverySlotExists       = errors.New("jadwal dengan jam mulai yang sama sudah ada di cabang dan tanggal tersebut")
+	ErrDeliverySlotInUse        = errors.New("jadwal sudah dipakai tugas antar jemput")
+	ErrOutsideDeliveryZone      = errors.New("alamat berada di luar jangkauan antar jemput cabang")
+	ErrInvalidDeliveryTaskState = errors.New("perubahan status tugas antar jemput tidak valid")
+	ErrCourierNotStaff          = errors.New("kurir harus pengguna dengan peran admin")
+)
+
+// DeliveryOptions adalah pilihan antar jemput saat membuat atau menghitung harga rental
+type DeliveryOptions struct {
+	// self_pickup (default) atau delivery
+	DeliveryMethod string `json:"delivery_method"`
+	// self_return (default) atau pickup
+	ReturnMethod string `json:"return_method"`
+	// Alamat dari buku alamat, wajib bila memilih delivery atau pickup
+	AddressID      *uuid.UUID `json:"address_id"`
+	DeliverySlotID *uuid.UUID `json:"delivery_slot_id"`
+	ReturnSlotID   *uuid.UUID `json:"return_slot_id"`
+}
+
+// Normalize mengisi cara pengambilan dan pengembalian default lalu memeriksa nilainya
+func (o *DeliveryOptions) Normalize() error {
+	if o.DeliveryMethod == "" {
+		o.DeliveryMethod = DeliveryMethodSelfPickup
+	}
+	if o.ReturnMethod == "" {
+		o.ReturnMethod = ReturnMethodSelfReturn
+	}
+
+	if o.DeliveryMethod != DeliveryMethodSelfPickup && o.DeliveryMethod != DeliveryMethodDelivery {
+		return ErrInvalidDeliveryMethod
+	}
+	if o.ReturnMethod != ReturnMethodSelfReturn && o.ReturnMethod != ReturnMethodPickup {
+		return ErrInvalidReturnMethod
+	}
+	return nil
+}
+
+// DeliveryZone menentukan ongkos antar jemput sebuah cabang. Alamat masuk zona bila kode posnya
+// cocok dengan salah satu PostalCodes, atau bila jaraknya berada di antara MinDistanceKm dan MaxDistanceKm.
+type DeliveryZone struct {
+	BaseEntity
+	BranchID uuid.UUID `gorm:"type:uuid;not null;index" json:"branch_id"`
+	Name     string    `gorm:"size:100;not null" json:"name"`
+	// Dipisah koma. Awalan seperti "401" mencakup semua kode pos yang diawali 401
+	PostalCodes   string      `gorm:"type:text" json:"postal_codes"`
+	MinDistanceKm float64     `gorm:"type:decimal(6,2);not null;default:0" json:"min_distance_km"`
+	MaxDistanceKm float64     `gorm:"type:decimal(6,2);not null;default:0" json:"max_distance_km"` // 0 berarti zona hanya berdasarkan kode pos
+	Fee           money.Money `gorm:"type:decimal(10,2);not null" json:"fee"`
+	IsActive      bool        `gorm:"not null;default:true" json:"is_active"`
+}
+
+func (*DeliveryZone) TableName() string {
+	return "delivery_zones"
+}
+
+func (z *DeliveryZone) Validate() []string {
+	err := validation.ValidateStruct(z,
+		validation.Field(&z.BranchID,
+			validation.Required.Error("Cabang wajib diisi"),
+		),
+		validation.Field(&z.Name,
+			validation.Required.Error("Nama zona wajib diisi"),
+			validation.RuneLength(3, 100).Error("Nama zona harus antara 3-100 karakter"),
+		),
+		validation.Field(&z.PostalCodes,
+			validation.When(z.MaxDistanceKm == 0, validation.Required.Error("Kode pos atau jarak maksimal wajib diisi")),
+			validation.Match(regexp.MustCompile(`^[0-9]{1,5}(,[0-9]{1,5})*$`)).Error("Kode pos berupa angka 1-5 digit dipisah koma"),
+		),
+		validation.Field(&z.MinDistanceKm,
+			validation.Min(0.0).Error("Jarak minimal tidak boleh negatif"),
+		),
+		validation.Field(&z.MaxDistanceKm,
+			validation.Min(0.0).Error("Jarak maksimal tidak boleh negatif"),
+			validation.When(z.MaxDistanceKm > 0, validation.Min(z.MinDistanceKm).Exclusive().Error("Jarak maksimal harus lebih besar dari jarak minimal")),
+		),
+		validation.Field(&z.Fee,
+			validation.Min(money.Money(0)).Error("Ongkos tidak boleh negatif"),
+		),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
+
+// CoversPostalCode memeriksa apakah kode pos cocok dengan salah satu kode pos atau awalan zona
+func (z *DeliveryZone) CoversPostalCode(postalCode string) bool {
+	if postalCode == "" || z.PostalCodes == "" {
+		return false
+	}
+	for _, prefix := range strings.Split(z.PostalCodes, ",") {
+		if strings.HasPrefix(postalCode, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CoversDistance memeriksa apakah jarak berada di pita jarak zona
+func (z *DeliveryZone) CoversDistance(distanceKm float64) bool {
+	return z.MaxDistanceKm > 0 && distanceKm >= z.MinDistanceKm && distanceKm < z.MaxDistanceKm
+}
+
+type DeliveryZoneRequest struct {
+	BranchID      uuid.UUID   `json:"branch_id" binding:"required"`
+	Name          string      `json:"name" binding:"required"`
+	PostalCodes   []string    `json:"postal_codes"`
+	MinDistanceKm float64     `json:"min_distance_km"`
+	MaxDistanceKm float64     `json:"max_distance_km"`
+	Fee           money.Money `json:"fee"`
+	IsActive      *bool       `json:"is_active"`
+}
+
+// DeliverySlot adalah jendela waktu antar jemput sebuah cabang. Capacity membatasi jumlah tugas
+// antar maupun jemput yang belum dibatalkan pada jendela tersebut.
+type DeliverySlot struct {
+	BaseEntity
+	BranchID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_delivery_slots_window,where:deleted_at IS NULL" json:"branch_id"`
+	Date      time.Time `gorm:"type:date;not null;uniqueIndex:idx_delivery_slots_window,where:deleted_at IS NULL" json:"date"`
+	StartTime string    `gorm:"size:5;not null;uniqueIndex:idx_delivery_slots_window,where:deleted_at IS NULL" json:"start_time"`
+	EndTime   string    `gorm:"size:5;not null" json:"end_time"`
+	Capacity  int       `gorm:"not null" json:"capacity"`
+	IsActive  bool      `gorm:"not null;default:true" json:"is_active"`
+	Booked    int64     `gorm:"-" json:"booked"`
+}
+
+func (*DeliverySlot) TableName() string {
+	return "delivery_slots"
+}
+
+func (s *DeliverySlot) Validate() []string {
+	validTime := validation.By(func(value interface{}) error {
+		clock, _ := value.(string)
+		if _, err := time.Parse(DeliverySlotTimeFormat, clock); err != nil {
+			return errors.New("Format jam harus HH:MM")
+		}
+		return nil
+	})
+
+	err := validation.ValidateStruct(s,
+		validation.Field(&s.BranchID,
+			validation.Required.Error("Cabang wajib diisi"),
+		),
+		validation.Field(&s.Date,
+			validation.Required.Error("Tanggal jadwal wajib diisi"),
+		),
+		validation.Field(&s.StartTime,
+			validation.Required.Error("Jam mulai wajib diisi"),
+			validTime,
+		),
+		validation.Field(&s.EndTime,
+			validation.Required.Error("Jam selesai wajib diisi"),
+			validTime,
+			validation.By(func(value interface{}) error {
+				if s.EndTime <= s.StartTime {
+					return errors.New("Jam selesai harus setelah jam mulai")
+				}
+				return nil
+			}),
+		),
+		validation.Field(&s.Capacity,
+			validation.Required.Error("Kuota wajib diisi"),
+			validation.Min(1).Error("Kuota minimal 1"),
+		),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
+
+// IsOn memeriksa apakah jadwal jatuh pada tanggal yang sama dengan waktu rental
+func (s *DeliverySlot) IsOn(date time.Time) bool {
+	return s.Date.Format(HolidayDateFormat) == date.Format(HolidayDateFormat)
+}
+
+// Remaining adalah sisa kuota jadwal setelah dikurangi tugas yang sudah dipesan
+func (s *DeliverySlot) Remaining() int64 {
+	return max(int64(s.Capacity)-s.Booked, 0)
+}
+
+type DeliverySlotRequest struct {
+	BranchID uuid.UUID `json:"branch_id" binding:"required"`
+	// Format tanggal YYYY-MM-DD
+	Date string `json:"date" binding:"required"`
+	// Format jam HH:MM
+	StartTime string `json:"start_time" binding:"required"`
+	EndTime   string `json:"end_time" binding:"required"`
+	Capacity  int    `json:"capacity" binding:"required"`
+	IsActive  *bool  `json:"is_active"`
+}
+
+type DeliverySlotFilter struct {
+	BranchID string
+	Date     string
+}
+
+// DeliveryTask adalah tugas kurir untuk mengantar atau menjemput mainan sebuah rental. Alamat disalin
+// saat rental dibuat agar perubahan buku alamat tidak mengubah tujuan tugas yang sudah terjadwal.
+type DeliveryTask struct {
+	BaseEntity
+	RentalID      uuid.UUID   `gorm:"type:uuid;not null;index" json:"rental_id"`
+	BranchID      uuid.UUID   `gorm:"type:uuid;not null;index" json:"branch_id"`
+	SlotID        uuid.UUID   `gorm:"type:uuid;not null;index" json:"slot_id"`
+	Type          string      `gorm:"size:20;not null;check:type IN ('delivery', 'return_pickup')" json:"type"`
+	Status        string      `gorm:"size:20;not null;default:scheduled;index;check:status IN ('scheduled', 'on_the_way', 'completed', 'failed', 'cancelled')" json:"status"`
+	AddressID     *uuid.UUID  `gorm:"type:uuid" json:"address_id"`
+	RecipientName string      `gorm:"size:100;not null" json:"recipient_name"`
+	PhoneNumber   string      `gorm:"size:20;not null" json:"phone_number"`
+	Address       string      `gorm:"type:text;not null" json:"address"`
+	AddressNotes  string      `gorm:"type:text" json:"address_notes"`
+	Fee           money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"fee"`
+	CourierID     *uuid.UUID  `gorm:"type:uuid;index" json:"courier_id"`
+	CompletedAt   *time.Time  `json:"completed_at"`
+	Notes         string      `gorm:"type:text" json:"notes"`
+
+	Slot DeliverySlot `gorm:"foreignKey:SlotID" json:"slot"`
+}
+
+func (*DeliveryTask) TableName() string {
+	return "delivery_tasks"
+}
+
+// deliveryTaskTransitions adalah perubahan status yang boleh dilakukan kurir atau admin
+var deliveryTaskTransitions = map[string][]string{
+	DeliveryTaskStatusScheduled: {DeliveryTaskStatusOnTheWay, DeliveryTaskStatusCancelled},
+	DeliveryTaskStatusOnTheWay:  {DeliveryTaskStatusCompleted, DeliveryTaskStatusFailed},
+	DeliveryTaskStatusFailed:    {DeliveryTaskStatusOnTheWay, DeliveryTaskStatusCancelled},
+}
+
+func (t *DeliveryTask) CanTransitionTo(status string) bool {
+	for _, next := range deliveryTaskTransitions[t.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryTaskFilter struct {
+	Date      string
+	Status    string
+	Type      string
+	CourierID string
+	BranchID  string
+}
+
+type AssignCourierRequest struct {
+	// Kosongkan untuk melepas kurir dari tugas
+	CourierID *uuid.UUID `json:"courier_id"`
+}
+
+type UpdateDeliveryTaskStatusRequest struct {
+	// on_the_way, completed, failed atau cancelled
+	Status string `json:"status" binding:"required"`
+	Notes  string `json:"notes"`
+}
+
+// DistanceKm menghitung jarak garis lurus antara dua koordinat dengan rumus haversine
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+)
+
+const (
+	DepositStatusNone          = "none"
+	DepositStatusPending       = "pending"
+	DepositStatusHeld          = "held"
+	DepositStatusRefunded      = "refunded"
+	DepositStatusRefundPending = "refund_pending"
+	DepositStatusCredited      = "credited"
+	DepositStatusApplied       = "applied"
+)
+
+const (
+	DepositRefundGateway = "gateway"
+	DepositRefundCredit  = "credit"
+)
+
+const PriceLineDeposit = "deposit"
+
+var ErrInvalidDepositRefundMethod = errors.New("metode pengembalian deposit harus gateway atau credit")
+
+// DepositSettlement adalah hasil penyelesaian deposit saat rental dikembalikan
+type DepositSettlement struct {
+	Applied   money.Money
+	Remainder money.Money
+}
+
+// SettleDeposit memotong deposit yang ditahan dengan denda keterlambatan dan kerusakan.
+// Sisa deposit dikembalikan ke pelanggan, kekurangannya tetap ditagihkan.
+func (r *Rental) SettleDeposit() DepositSettlement {
+	if r.DepositStatus != DepositStatusHeld || r.DepositAmount <= 0 {
+		return DepositSettlement{}
+	}
+
+	tax := r.TaxPolicy()
+	lateFee, _ := tax.Apply(r.LateFee)
+	damageFee, _ := tax.Apply(r.DamageFee)
+	fees := lateFee + damageFee
+	applied := money.Min(r.DepositAmount, fees)
+	return DepositSettlement{
+		Applied:   applied,
+		Remainder: r.DepositAmount - applied,
+	}
+}
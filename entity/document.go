@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"errors"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var ErrPaymentNotSettled = errors.New("kwitansi hanya tersedia untuk pembayaran yang sudah lunas")
+
+// DocumentFile adalah dokumen siap unduh beserta pemilik datanya
+type DocumentFile struct {
+	Filename    string
+	ContentType string
+	UserID      uuid.UUID
+	Content     []byte
+}
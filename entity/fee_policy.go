@@ -0,0 +1,265 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+	"math"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+var (
+	ErrNoActiveFeePolicy      = errors.New("belum ada kebijakan biaya yang aktif")
+	ErrDuplicateFeePolicyRule = errors.New("aturan kategori pada kebijakan biaya tidak boleh duplikat")
+)
+
+// FeePolicy adalah aturan perhitungan denda keterlambatan dan biaya kerusakan.
+// Setiap versi bersifat tetap; perubahan kebijakan dilakukan dengan membuat versi baru.
+type FeePolicy struct {
+	BaseEntity
+	Version     int    `gorm:"not null;uniqueIndex" json:"version"`
+	Name        string `gorm:"size:100;not null" json:"name"`
+	Description string `gorm:"type:text" json:"description"`
+	IsActive    bool   `gorm:"not null;default:false;index" json:"is_active"`
+
+	// Keterlambatan
+	GracePeriodMinutes int         `gorm:"not null;default:0" json:"grace_period_minutes"`
+	LateDayHours       int         `gorm:"not null;default:24" json:"late_day_hours"`
+	DailyLateFeeCap    money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"daily_late_fee_cap"`
+	MaxLateFee         money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"max_late_fee"`
+
+	// Kerusakan, dalam persen dari harga pengganti. Nilai batas 0 berarti tanpa batas.
+	DamagePercent        float64     `gorm:"type:decimal(5,2);not null" json:"damage_percent"`
+	ConditionStepPercent float64     `gorm:"type:decimal(5,2);not null" json:"condition_step_percent"`
+	LostPercent          float64     `gorm:"type:decimal(5,2);not null" json:"lost_percent"`
+	MaxUnitDamagePercent float64     `gorm:"type:decimal(5,2);not null" json:"max_unit_damage_percent"`
+	MaxDamageFee         money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"max_damage_fee"`
+
+	ActivatedAt *time.Time      `json:"activated_at"`
+	Rules       []FeePolicyRule `gorm:"foreignKey:FeePolicyID" json:"rules"`
+}
+
+func (*FeePolicy) TableName() string {
+	return "fee_policies"
+}
+
+// FeePolicyRule menimpa persentase kerusakan untuk mainan pada kategori tertentu
+type FeePolicyRule struct {
+	BaseEntity
+	FeePolicyID          uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_fee_policy_rules_category" json:"fee_policy_id"`
+	CategoryID           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_fee_policy_rules_category" json:"category_id"`
+	DamagePercent        float64   `gorm:"type:decimal(5,2);not null" json:"damage_percent"`
+	ConditionStepPercent float64   `gorm:"type:decimal(5,2);not null" json:"condition_step_percent"`
+	LostPercent          float64   `gorm:"type:decimal(5,2);not null" json:"lost_percent"`
+}
+
+func (*FeePolicyRule) TableName() string {
+	return "fee_policy_rules"
+}
+
+// DefaultFeePolicy adalah versi awal yang sama dengan aturan sebelum kebijakan dapat diubah
+func DefaultFeePolicy() FeePolicy {
+	return FeePolicy{
+		Version:              1,
+		Name:                 "Kebijakan awal",
+		IsActive:             true,
+		LateDayHours:         48,
+		DamagePercent:        70,
+		ConditionStepPercent: 15,
+		LostPercent:          100,
+		MaxUnitDamagePercent: 100,
+	}
+}
+
+// LateDays menghitung jumlah hari keterlambatan, setiap periode yang dimulai dihitung penuh
+func (p *FeePolicy) LateDays(expected time.Time, actual time.Time) int {
+	late := actual.Sub(expected)
+	if late <= time.Duration(p.GracePeriodMinutes)*time.Minute {
+		return 0
+	}
+
+	dayHours := p.LateDayHours
+	if dayHours <= 0 {
+		dayHours = 24
+	}
+
+	return int(late.Hours()/float64(dayHours)) + 1
+}
+
+// LateFee menghitung denda keterlambatan dari total denda harian seluruh item
+func (p *FeePolicy) LateFee(dailyFee money.Money, days int) money.Money {
+	if days <= 0 {
+		return 0
+	}
+
+	if p.DailyLateFeeCap > 0 {
+		dailyFee = money.Min(dailyFee, p.DailyLateFeeCap)
+	}
+
+	fee := dailyFee.Mul(days)
+	if p.MaxLateFee > 0 {
+		fee = money.Min(fee, p.MaxLateFee)
+	}
+	return fee
+}
+
+// ruleFor mengambil persentase kerusakan untuk kategori mainan.
+// Jika beberapa kategori memiliki aturan, persentase tertinggi yang dipakai.
+func (p *FeePolicy) ruleFor(categoryIDs []uuid.UUID) (damage, step, lost float64) {
+	damage, step, lost = p.DamagePercent, p.ConditionStepPercent, p.LostPercent
+
+	matched := false
+	for _, rule := range p.Rules {
+		for _, categoryID := range categoryIDs {
+			if rule.CategoryID != categoryID {
+				continue
+			}
+			if !matched {
+				damage, step, lost = rule.DamagePercent, rule.ConditionStepPercent, rule.LostPercent
+				matched = true
+				continue
+			}
+			damage = math.Max(damage, rule.DamagePercent)
+			step = math.Max(step, rule.ConditionStepPercent)
+			lost = math.Max(lost, rule.LostPercent)
+		}
+	}
+	return damage, step, lost
+}
+
+// UnitDamageFee menghitung biaya kerusakan satu unit berdasarkan perubahan kondisinya
+func (p *FeePolicy) UnitDamageFee(toy Toy, conditionBefore string, conditionAfter string) money.Money {
+	categoryIDs := make([]uuid.UUID, 0, len(toy.Categories))
+	for _, category := range toy.Categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+	damage, step, lost := p.ruleFor(categoryIDs)
+
+	var percent float64
+	switch conditionAfter {
+	case ConditionLost:
+		percent = lost
+	case ConditionDamaged:
+		percent = damage
+	default:
+		beforeValue := ConditionRank[conditionBefore]
+		afterValue := ConditionRank[conditionAfter]
+		if afterValue < beforeValue {
+			percent = step * float64(beforeValue-afterValue)
+		}
+	}
+
+	if conditionAfter != ConditionLost && p.MaxUnitDamagePercent > 0 {
+		percent = math.Min(percent, p.MaxUnitDamagePercent)
+	}
+
+	return toy.ReplacementPrice.Percent(percent)
+}
+
+// CapDamageFee menerapkan batas maksimum biaya kerusakan per rental
+func (p *FeePolicy) CapDamageFee(fee money.Money) money.Money {
+	if p.MaxDamageFee > 0 {
+		return money.Min(fee, p.MaxDamageFee)
+	}
+	return fee
+}
+
+func (p *FeePolicy) Validate() []string {
+	percent := func(message string) validation.Rule {
+		return validation.Max(100.0).Error(message)
+	}
+
+	err := validation.ValidateStruct(p,
+		validation.Field(&p.Name,
+			validation.Required.Error("Nama kebijakan wajib diisi"),
+			validation.RuneLength(3, 100).Error("Nama kebijakan harus antara 3-100 karakter"),
+		),
+		validation.Field(&p.GracePeriodMinutes,
+			validation.Min(0).Error("Masa tenggang tidak boleh negatif"),
+		),
+		validation.Field(&p.LateDayHours,
+			validation.Required.Error("Durasi hari keterlambatan wajib diisi"),
+			validation.Min(1).Error("Durasi hari keterlambatan minimal 1 jam"),
+		),
+		validation.Field(&p.DailyLateFeeCap,
+			validation.Min(money.Money(0)).Error("Batas denda harian tidak boleh negatif"),
+		),
+		validation.Field(&p.MaxLateFee,
+			validation.Min(money.Money(0)).Error("Batas denda keterlambatan tidak boleh negatif"),
+		),
+		validation.Field(&p.DamagePercent,
+			validation.Min(0.0).Error("Persentase kerusakan tidak boleh negatif"),
+			percent("Persentase kerusakan maksimal 100"),
+		),
+		validation.Field(&p.ConditionStepPercent,
+			validation.Min(0.0).Error("Persentase penurunan kondisi tidak boleh negatif"),
+			percent("Persentase penurunan kondisi maksimal 100"),
+		),
+		validation.Field(&p.LostPercent,
+			validation.Min(0.0).Error("Persentase kehilangan tidak boleh negatif"),
+			validation.Max(300.0).Error("Persentase kehilangan maksimal 300"),
+		),
+		validation.Field(&p.MaxUnitDamagePercent,
+			validation.Min(0.0).Error("Batas kerusakan per unit tidak boleh negatif"),
+			percent("Batas kerusakan per unit maksimal 100"),
+		),
+		validation.Field(&p.MaxDamageFee,
+			validation.Min(money.Money(0)).Error("Batas biaya kerusakan tidak boleh negatif"),
+		),
+	)
+
+	var errorMessages []string
+	if err != nil {
+		if validationErrors, ok := err.(validation.Errors); ok {
+			for _, fieldErr := range validationErrors {
+				errorMessages = append(errorMessages, fieldErr.Error())
+			}
+		} else {
+			errorMessages = append(errorMessages, err.Error())
+		}
+	}
+
+	seen := make(map[uuid.UUID]bool, len(p.Rules))
+	for _, rule := range p.Rules {
+		if seen[rule.CategoryID] {
+			errorMessages = append(errorMessages, ErrDuplicateFeePolicyRule.Error())
+			break
+		}
+		seen[rule.CategoryID] = true
+
+		if rule.DamagePercent < 0 || rule.DamagePercent > 100 ||
+			rule.ConditionStepPercent < 0 || rule.ConditionStepPercent > 100 ||
+			rule.LostPercent < 0 || rule.LostPercent > 300 {
+			errorMessages = append(errorMessages, "Persentase aturan kategori di luar batas yang diizinkan")
+			break
+		}
+	}
+
+	return errorMessages
+}
+
+type FeePolicyRuleRequest struct {
+	CategoryID           uuid.UUID `json:"category_id" binding:"required"`
+	DamagePercent        float64   `json:"damage_percent"`
+	ConditionStepPercent float64   `json:"condition_step_percent"`
+	LostPercent          float64   `json:"lost_percent"`
+}
+
+// FeePolicyRequest membuat versi kebijakan baru
+type FeePolicyRequest struct {
+	Name                 string                 `json:"name" binding:"required"`
+	Description          string                 `json:"description"`
+	GracePeriodMinutes   int                    `json:"grace_period_minutes"`
+	LateDayHours         int                    `json:"late_day_hours" binding:"required"`
+	DailyLateFeeCap      money.Money            `json:"daily_late_fee_cap"`
+	MaxLateFee           money.Money            `json:"max_late_fee"`
+	DamagePercent        float64                `json:"damage_percent"`
+	ConditionStepPercent float64                `json:"condition_step_percent"`
+	LostPercent          float64                `json:"lost_percent"`
+	MaxUnitDamagePercent float64                `json:"max_unit_damage_percent"`
+	MaxDamageFee         money.Money            `json:"max_damage_fee"`
+	Rules                []FeePolicyRuleRequest `json:"rules"`
+	Activate             bool                   `json:"activate"`
+}
@@ -0,0 +1,301 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	AccountTypeAsset     = "asset"
+	AccountTypeLiability = "liability"
+	AccountTypeRevenue   = "revenue"
+	AccountTypeContra    = "contra_revenue"
+	AccountTypeExpense   = "expense"
+)
+
+const (
+	AccountCashClearing     = "1100"
+	AccountReceivable       = "1200"
+	AccountDepositLiability = "2100"
+	AccountWalletLiability  = "2200"
+	AccountOutputTax        = "2300"
+	AccountRentalRevenue    = "4100"
+	AccountLateFeeRevenue   = "4200"
+	AccountDamageRevenue    = "4300"
+	AccountRefunds          = "4900"
+	AccountBadDebt          = "6100"
+)
+
+// Account adalah akun pada bagan akun. Bagan akun tetap sehingga cukup didefinisikan di kode.
+type Account struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// DebitNormal menandakan akun yang saldonya bertambah di sisi debit
+func (a Account) DebitNormal() bool {
+	return a.Type == AccountTypeAsset || a.Type == AccountTypeContra || a.Type == AccountTypeExpense
+}
+
+var ChartOfAccounts = []Account{
+	{Code: AccountCashClearing, Name: "Kas & Kliring Payment Gateway", Type: AccountTypeAsset},
+	{Code: AccountReceivable, Name: "Piutang Pelanggan", Type: AccountTypeAsset},
+	{Code: AccountDepositLiability, Name: "Titipan Deposit Pelanggan", Type: AccountTypeLiability},
+	{Code: AccountWalletLiability, Name: "Saldo Dompet Pelanggan", Type: AccountTypeLiability},
+	{Code: AccountOutputTax, Name: "PPN Keluaran", Type: AccountTypeLiability},
+	{Code: AccountRentalRevenue, Name: "Pendapatan Sewa", Type: AccountTypeRevenue},
+	{Code: AccountLateFeeRevenue, Name: "Pendapatan Denda Keterlambatan", Type: AccountTypeRevenue},
+	{Code: AccountDamageRevenue, Name: "Pendapatan Biaya Kerusakan", Type: AccountTypeRevenue},
+	{Code: AccountRefunds, Name: "Pengembalian Dana & Kompensasi", Type: AccountTypeContra},
+	{Code: AccountBadDebt, Name: "Beban Penghapusan Piutang", Type: AccountTypeExpense},
+}
+
+// FindAccount mencari akun berdasarkan kodenya
+func FindAccount(code string) (Account, bool) {
+	for _, account := range ChartOfAccounts {
+		if account.Code == code {
+			return account, true
+		}
+	}
+	return Account{}, false
+}
+
+const (
+	JournalEventPaymentSettled  = "payment_settled"
+	JournalEventPaymentRefunded = "payment_refunded"
+	JournalEventRentalReturned  = "rental_returned"
+	JournalEventDepositRefunded = "deposit_refunded"
+	JournalEventWalletEntry     = "wallet_entry"
+	JournalEventWriteOff        = "write_off"
+)
+
+var ErrUnbalancedJournal = errors.New("jurnal tidak seimbang, total debit harus sama dengan total kredit")
+
+// JournalEntry adalah satu jurnal berpasangan. Setiap kejadian (event) dari satu sumber hanya dijurnal sekali.
+type JournalEntry struct {
+	BaseEntity
+	EntryDate   time.Time     `gorm:"not null;index" json:"entry_date"`
+	Event       string        `gorm:"size:50;not null;uniqueIndex:idx_journal_event_source;check:event IN ('payment_settled', 'payment_refunded', 'rental_returned', 'deposit_refunded', 'wallet_entry', 'write_off')" json:"event"`
+	SourceID    uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_journal_event_source" json:"source_id"`
+	RentalID    *uuid.UUID    `gorm:"type:uuid;index" json:"rental_id,omitempty"`
+	Description string        `gorm:"type:text" json:"description"`
+	Lines       []JournalLine `gorm:"foreignKey:JournalEntryID" json:"lines"`
+}
+
+func (*JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+type JournalLine struct {
+	BaseEntity
+	JournalEntryID uuid.UUID   `gorm:"type:uuid;not null;index" json:"journal_entry_id"`
+	AccountCode    string      `gorm:"size:10;not null;index" json:"account_code"`
+	Debit          money.Money `gorm:"type:decimal(12,2);not null;default:0;check:debit >= 0" json:"debit"`
+	Credit         money.Money `gorm:"type:decimal(12,2);not null;default:0;check:credit >= 0" json:"credit"`
+}
+
+func (*JournalLine) TableName() string {
+	return "journal_lines"
+}
+
+// post menambahkan nominal ke akun, positif di sisi debit dan negatif di sisi kredit.
+// Nominal untuk akun yang sama digabung sehingga satu akun hanya muncul sekali per jurnal.
+func (e *JournalEntry) post(accountCode string, amount money.Money) {
+	if amount == 0 {
+		return
+	}
+
+	for i := range e.Lines {
+		if e.Lines[i].AccountCode == accountCode {
+			net := e.Lines[i].Debit - e.Lines[i].Credit + amount
+			e.Lines[i].Debit, e.Lines[i].Credit = money.Max(net, 0), money.Max(-net, 0)
+			return
+		}
+	}
+
+	e.Lines = append(e.Lines, JournalLine{
+		AccountCode: accountCode,
+		Debit:       money.Max(amount, 0),
+		Credit:      money.Max(-amount, 0),
+	})
+}
+
+// IsEmpty menandakan jurnal tanpa nominal yang tidak perlu disimpan
+func (e *JournalEntry) IsEmpty() bool {
+	for _, line := range e.Lines {
+		if line.Debit != 0 || line.Credit != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *JournalEntry) IsBalanced() bool {
+	var debit, credit money.Money
+	for _, line := range e.Lines {
+		debit += line.Debit
+		credit += line.Credit
+	}
+	return debit == credit
+}
+
+func newJournalEntry(event string, sourceID uuid.UUID, rentalID *uuid.UUID, description string) *JournalEntry {
+	return &JournalEntry{
+		EntryDate:   time.Now(),
+		Event:       event,
+		SourceID:    sourceID,
+		RentalID:    rentalID,
+		Description: description,
+	}
+}
+
+// NewPaymentJournal mencatat pembayaran lunas. Deposit masuk ke titipan deposit, rincian lainnya
+// termasuk potongan promo dan saldo dompet melunasi piutang pelanggan.
+func NewPaymentJournal(payment Payment) *JournalEntry {
+	rentalID := payment.RentalID
+	entry := newJournalEntry(JournalEventPaymentSettled, payment.ID, &rentalID, "Pembayaran "+payment.OrderID)
+
+	if payment.PaymentMethod == PaymentMethodWallet {
+		entry.post(AccountWalletLiability, payment.GrossAmount)
+	} else {
+		entry.post(AccountCashClearing, payment.GrossAmount)
+	}
+
+	if len(payment.Items) == 0 {
+		entry.post(AccountReceivable, -payment.GrossAmount)
+		return entry
+	}
+
+	for _, item := range payment.Items {
+		if item.Kind == PriceLineDeposit {
+			entry.post(AccountDepositLiability, -item.Amount)
+		} else {
+			entry.post(AccountReceivable, -item.Amount)
+		}
+	}
+	return entry
+}
+
+// NewPaymentRefundJournal mencatat pembayaran yang dikembalikan penuh oleh payment gateway. Sebelum rental
+// dikembalikan pembayaran masih berupa uang muka, setelahnya dicatat sebagai pengembalian dana.
+func NewPaymentRefundJournal(payment Payment, rental Rental) *JournalEntry {
+	entry := newJournalEntry(JournalEventPaymentRefunded, payment.ID, &rental.ID, "Refund pembayaran "+payment.OrderID)
+	entry.post(AccountCashClearing, -payment.GrossAmount)
+
+	customerAccount := AccountReceivable
+	if rental.ActualReturnDate != nil {
+		customerAccount = AccountRefunds
+	}
+
+	if len(payment.Items) == 0 {
+		entry.post(customerAccount, payment.GrossAmount)
+		return entry
+	}
+
+	for _, item := range payment.Items {
+		if item.Kind == PriceLineDeposit {
+			entry.post(AccountDepositLiability, item.Amount)
+		} else {
+			entry.post(customerAccount, item.Amount)
+		}
+	}
+	return entry
+}
+
+// NewReturnJournal mengakui pendapatan (tanpa PPN) dan PPN keluaran saat rental dikembalikan,
+// lalu memotong deposit untuk denda
+func NewReturnJournal(rental Rental) *JournalEntry {
+	entry := newJournalEntry(JournalEventRentalReturned, rental.ID, &rental.ID, "Pengembalian rental "+rental.ID.String())
+
+	tax := rental.TaxPolicy()
+	for _, charge := range []struct {
+		account string
+		amount  money.Money
+	}{
+		{AccountRentalRevenue, rental.TotalRentalPrice},
+		{AccountLateFeeRevenue, rental.LateFee},
+		{AccountDamageRevenue, rental.DamageFee},
+	} {
+		gross, chargeTax := tax.Apply(charge.amount)
+		entry.post(charge.account, -(gross - chargeTax))
+		entry.post(AccountOutputTax, -chargeTax)
+		entry.post(AccountReceivable, gross)
+	}
+
+	if rental.DepositApplied > 0 {
+		entry.post(AccountDepositLiability, rental.DepositApplied)
+		entry.post(AccountReceivable, -rental.DepositApplied)
+	}
+	return entry
+}
+
+// NewDepositRefundJournal mencatat sisa deposit yang dikembalikan lewat payment gateway
+func NewDepositRefundJournal(rental Rental, amount money.Money) *JournalEntry {
+	entry := newJournalEntry(JournalEventDepositRefunded, rental.ID, &rental.ID, "Pengembalian deposit rental "+rental.ID.String())
+	entry.post(AccountDepositLiability, amount)
+	entry.post(AccountCashClearing, -amount)
+	return entry
+}
+
+// NewWalletJournal mencatat mutasi dompet. Pembayaran dengan saldo dompet sudah dijurnal bersama
+// pembayarannya sehingga menghasilkan jurnal kosong.
+func NewWalletJournal(walletEntry WalletEntry) *JournalEntry {
+	entry := newJournalEntry(JournalEventWalletEntry, walletEntry.ID, walletEntry.RentalID, walletEntry.Notes)
+
+	var counterAccount string
+	switch walletEntry.Reason {
+	case WalletReasonDepositRefund:
+		counterAccount = AccountDepositLiability
+	case WalletReasonOverpayment:
+		counterAccount = AccountReceivable
+	case WalletReasonGoodwill, WalletReasonAdjustment:
+		counterAccount = AccountRefunds
+	default:
+		return entry
+	}
+
+	entry.post(AccountWalletLiability, -walletEntry.SignedAmount())
+	entry.post(counterAccount, walletEntry.SignedAmount())
+	return entry
+}
+
+// NewWriteOffJournal menghapus piutang rental yang tidak tertagih
+func NewWriteOffJournal(rental Rental, amount money.Money, notes string) *JournalEntry {
+	entry := newJournalEntry(JournalEventWriteOff, rental.ID, &rental.ID, notes)
+	entry.post(AccountBadDebt, amount)
+	entry.post(AccountReceivable, -amount)
+	return entry
+}
+
+type JournalFilter struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	Event     string
+	RentalID  string
+}
+
+// TrialBalanceItem adalah total debit dan kredit satu akun. Balance bertanda positif di sisi normal akun.
+type TrialBalanceItem struct {
+	AccountCode string      `json:"account_code"`
+	AccountName string      `json:"account_name"`
+	AccountType string      `json:"account_type"`
+	Debit       money.Money `json:"debit"`
+	Credit      money.Money `json:"credit"`
+	Balance     money.Money `json:"balance"`
+}
+
+// JournalExportRow adalah satu baris jurnal untuk ekspor CSV
+type JournalExportRow struct {
+	EntryDate      time.Time   `json:"entry_date"`
+	JournalEntryID uuid.UUID   `json:"journal_entry_id"`
+	Event          string      `json:"event"`
+	Description    string      `json:"description"`
+	RentalID       *uuid.UUID  `json:"rental_id"`
+	AccountCode    string      `json:"account_code"`
+	Debit          money.Money `json:"debit"`
+	Credit         money.Money `json:"credit"`
+}
@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	MaintenanceTypeCleaning = "cleaning"
+	MaintenanceTypeRepair   = "repair"
+)
+
+const (
+	MaintenanceStatusPending    = "pending"
+	MaintenanceStatusInProgress = "in_progress"
+	MaintenanceStatusCompleted  = "completed"
+)
+
+const (
+	MaintenanceOutcomeReleased   = "released"
+	MaintenanceOutcomeRepair     = "repair"
+	MaintenanceOutcomeWrittenOff = "written_off"
+)
+
+var (
+	ErrMaintenanceTaskClosed = errors.New("tugas perawatan sudah selesai")
+	ErrUnitInMaintenance     = errors.New("unit sedang dalam antrean perawatan, selesaikan tugas perawatan terlebih dahulu")
+)
+
+// MaintenanceTurnaround adalah target waktu pengerjaan tugas perawatan per jenis
+type MaintenanceTurnaround struct {
+	Cleaning time.Duration
+	Repair   time.Duration
+}
+
+// DueAt menghitung tenggat tugas perawatan sejak waktu from
+func (t MaintenanceTurnaround) DueAt(taskType string, from time.Time) time.Time {
+	if taskType == MaintenanceTypeRepair {
+		return from.Add(t.Repair)
+	}
+	return from.Add(t.Cleaning)
+}
+
+// MaintenanceTypeForCondition menentukan jenis perawatan untuk unit yang dikembalikan
+func MaintenanceTypeForCondition(condition string) string {
+	if condition == ConditionDamaged {
+		return MaintenanceTypeRepair
+	}
+	return MaintenanceTypeCleaning
+}
+
+// MaintenanceTask adalah antrean pembersihan dan perbaikan unit sebelum dapat disewakan kembali
+type MaintenanceTask struct {
+	BaseEntity
+	ToyUnitID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"toy_unit_id"`
+	ToyID        uuid.UUID  `gorm:"type:uuid;not null;index" json:"toy_id"`
+	RentalItemID *uuid.UUID `gorm:"type:uuid;index" json:"rental_item_id"`
+	Type         string     `gorm:"size:50;not null;check:type IN ('cleaning', 'repair')" json:"type"`
+	Status       string     `gorm:"size:50;not null;default:pending;index;check:status IN ('pending', 'in_progress', 'completed')" json:"status"`
+	Outcome      string     `gorm:"size:50" json:"outcome"`
+	DueAt        time.Time  `gorm:"not null;index" json:"due_at"`
+	StartedAt    *time.Time `json:"started_at"`
+	StartedBy    *uuid.UUID `gorm:"type:uuid" json:"started_by"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	CompletedBy  *uuid.UUID `gorm:"type:uuid" json:"completed_by"`
+	Notes        string     `gorm:"type:text" json:"notes"`
+
+	ToyUnit ToyUnit `gorm:"foreignKey:ToyUnitID" json:"toy_unit"`
+}
+
+func (*MaintenanceTask) TableName() string {
+	return "maintenance_tasks"
+}
+
+// IsOverdue menandakan tugas yang belum selesai melewati tenggatnya
+func (m *MaintenanceTask) IsOverdue(now time.Time) bool {
+	return m.Status != MaintenanceStatusCompleted && now.After(m.DueAt)
+}
+
+type MaintenanceTaskFilter struct {
+	Status   string
+	Type     string
+	ToyID    string
+	BranchID string
+	Overdue  bool
+}
+
+type StartMaintenanceTaskRequest struct {
+	Notes string `json:"notes"`
+}
+
+type CompleteMaintenanceTaskRequest struct {
+	// released: unit kembali tersedia, repair: dialihkan ke perbaikan, written_off: unit dipensiunkan
+	Outcome   string `json:"outcome" binding:"required"`
+	Condition string `json:"condition"`
+	Notes     string `json:"notes"`
+}
@@ -2,6 +2,8 @@ package entity
 
 import (
 	"encoding/json"
+	"errors"
+	"final-project/utils/money"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
@@ -26,31 +28,67 @@ const (
 	TransactionStatusPartialRefund = "partial_refund"
 )
 
+var ErrPaymentItemsMismatch = errors.New("jumlah rincian pembayaran tidak sama dengan total tagihan")
+
 type Payment struct {
 	BaseEntity
-	RentalID          uuid.UUID  `gorm:"type:uuid;not null" json:"rental_id"`
-	OrderID           string     `gorm:"null" json:"order_id"`
-	PaymentType       string     `gorm:"size:50;not null;check:payment_type IN ('rental', 'late_fee', 'damage_fee', 'combined', 'extension')" json:"payment_type"`
-	GrossAmount       float64    `gorm:"type:decimal(10,2);not null" json:"gross_amount"`
-	SnapToken         string     `gorm:"type:text" json:"snap_token"`
-	SnapURL           string     `gorm:"type:text" json:"snap_url"`
-	ExpiryTime        *time.Time `json:"expiry_time"`
-	TransactionTime   *time.Time `json:"transaction_time"`
-	TransactionStatus string     `gorm:"size:50" json:"transaction_status"`
-	PaymentMethod     string     `gorm:"size:50" json:"payment_method"`
-	VANumber          string     `gorm:"size:100" json:"va_number"`
-	FraudStatus       string     `gorm:"size:50" json:"fraud_status"`
-	Metadata          []byte     `gorm:"type:jsonb" json:"-"`
+	RentalID          uuid.UUID   `gorm:"type:uuid;not null" json:"rental_id"`
+	OrderID           string      `gorm:"null" json:"order_id"`
+	PaymentType       string      `gorm:"size:50;not null;check:payment_type IN ('rental', 'late_fee', 'damage_fee', 'combined', 'extension')" json:"payment_type"`
+	GrossAmount       money.Money `gorm:"type:decimal(10,2);not null" json:"gross_amount"`
+	SnapToken         string      `gorm:"type:text" json:"snap_token"`
+	SnapURL           string      `gorm:"type:text" json:"snap_url"`
+	ExpiryTime        *time.Time  `json:"expiry_time"`
+	TransactionTime   *time.Time  `json:"transaction_time"`
+	TransactionStatus string      `gorm:"size:50" json:"transaction_status"`
+	PaymentMethod     string      `gorm:"size:50" json:"payment_method"`
+	VANumber          string      `gorm:"size:100" json:"va_number"`
+	FraudStatus       string      `gorm:"size:50" json:"fraud_status"`
+	Metadata          []byte      `gorm:"type:jsonb" json:"-"`
+	TaxInvoiceNumber  *string     `gorm:"size:30;uniqueIndex" json:"tax_invoice_number,omitempty"`
+	TaxInvoiceDate    *time.Time  `json:"tax_invoice_date,omitempty"`
 
-	Rental Rental `gorm:"foreignKey:RentalID" json:"-"`
+	Rental Rental        `gorm:"foreignKey:RentalID" json:"-"`
+	Items  []PaymentItem `gorm:"foreignKey:PaymentID" json:"items,omitempty"`
 }
 
 func (*Payment) TableName() string {
 	return "payments"
 }
 
+// PaymentItem adalah rincian tagihan yang dikirim apa adanya ke payment gateway
+type PaymentItem struct {
+	BaseEntity
+	PaymentID uuid.UUID `gorm:"type:uuid;not null;index" json:"payment_id"`
+	PriceLine `gorm:"embedded"`
+}
+
+func (*PaymentItem) TableName() string {
+	return "payment_items"
+}
+
+// SetItems mengisi rincian tagihan dan menyamakan GrossAmount dengan jumlah seluruh rinciannya
+func (p *Payment) SetItems(lines []PriceLine) {
+	p.Items = make([]PaymentItem, 0, len(lines))
+	for _, line := range lines {
+		p.Items = append(p.Items, PaymentItem{PriceLine: line})
+	}
+	p.GrossAmount = SumPriceLines(lines)
+}
+
+// ItemsTotal menjumlahkan rincian seperti yang dihitung payment gateway, yaitu harga unit dikali jumlah
+func (p *Payment) ItemsTotal() money.Money {
+	var total money.Money
+	for _, item := range p.Items {
+		total += item.UnitPrice.Mul(item.Quantity)
+	}
+	return total
+}
+
 type CreatePaymentRequest struct {
 	RentalID string `json:"rental_id" binding:"required"`
+	// Pakai saldo dompet lebih dulu, kekurangannya dibayar lewat payment gateway
+	UseWallet bool `json:"use_wallet"`
 }
 
 func (p *Payment) GetExtensionMetadata() (*ExtensionMetadata, error) {
@@ -0,0 +1,187 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	PricingRuleWeekendSurcharge   = "weekend_surcharge"
+	PricingRuleHolidaySurcharge   = "holiday_surcharge"
+	PricingRuleLongRentalDiscount = "long_rental_discount"
+)
+
+const (
+	PriceLineRental    = "rental"
+	PriceLineWeekend   = PricingRuleWeekendSurcharge
+	PriceLineHoliday   = PricingRuleHolidaySurcharge
+	PriceLineDiscount  = PricingRuleLongRentalDiscount
+	PriceLineLateFee   = "late_fee"
+	PriceLineDamageFee = "damage_fee"
+)
+
+const (
+	DaysPerWeek         = 7
+	DaysPerMonth        = 30
+	MaxRentalDays       = 365
+	HolidayDateFormat   = "2006-01-02"
+	maxPriceLineNameLen = 50
+)
+
+var (
+	ErrBelowMinRentalDays = errors.New("durasi rental kurang dari minimum hari sewa mainan")
+	ErrAboveMaxRentalDays = fmt.Errorf("durasi rental melebihi maksimal %d hari", MaxRentalDays)
+	ErrHolidayExists      = errors.New("tanggal libur sudah terdaftar")
+)
+
+// PricingRule adalah aturan tambahan biaya atau potongan yang berlaku untuk semua mainan
+type PricingRule struct {
+	BaseEntity
+	Name     string  `gorm:"size:100;not null" json:"name"`
+	Type     string  `gorm:"size:50;not null;check:type IN ('weekend_surcharge', 'holiday_surcharge', 'long_rental_discount')" json:"type"`
+	Percent  float64 `gorm:"type:decimal(5,2);not null" json:"percent"`
+	MinDays  int     `gorm:"not null;default:0" json:"min_days"`
+	IsActive bool    `gorm:"not null" json:"is_active"`
+}
+
+func (*PricingRule) TableName() string {
+	return "pricing_rules"
+}
+
+func (r *PricingRule) Validate() []string {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Name,
+			validation.Required.Error("Nama aturan wajib diisi"),
+			validation.RuneLength(3, 100).Error("Nama aturan harus antara 3-100 karakter"),
+		),
+		validation.Field(&r.Type,
+			validation.Required.Error("Jenis aturan wajib diisi"),
+			validation.In(PricingRuleWeekendSurcharge, PricingRuleHolidaySurcharge, PricingRuleLongRentalDiscount).
+				Error("Jenis aturan harus salah satu dari: weekend_surcharge, holiday_surcharge, atau long_rental_discount"),
+		),
+		validation.Field(&r.Percent,
+			validation.Required.Error("Persentase wajib diisi"),
+			validation.Min(0.0).Error("Persentase tidak boleh negatif"),
+			validation.Max(100.0).Error("Persentase maksimal 100"),
+		),
+		validation.Field(&r.MinDays,
+			validation.When(r.Type == PricingRuleLongRentalDiscount, validation.Required.Error("Minimal hari wajib diisi untuk potongan rental panjang")),
+			validation.Min(0).Error("Minimal hari tidak boleh negatif"),
+		),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
+
+// Holiday adalah tanggal libur yang dikenai holiday_surcharge
+type Holiday struct {
+	BaseEntity
+	Date time.Time `gorm:"type:date;not null;uniqueIndex:idx_holidays_date,where:deleted_at IS NULL" json:"date"`
+	Name string    `gorm:"size:100;not null" json:"name"`
+}
+
+func (*Holiday) TableName() string {
+	return "holidays"
+}
+
+// PriceLine adalah satu baris rincian harga. Amount selalu UnitPrice dikali Quantity
+// dan sudah dibulatkan ke rupiah penuh agar dapat diteruskan apa adanya ke payment gateway.
+type PriceLine struct {
+	ToyID       *uuid.UUID  `gorm:"type:uuid" json:"toy_id,omitempty"`
+	Kind        string      `gorm:"size:50;not null" json:"kind"`
+	Description string      `gorm:"size:255;not null" json:"description"`
+	Quantity    int         `gorm:"not null" json:"quantity"`
+	UnitPrice   money.Money `gorm:"type:decimal(10,2);not null" json:"unit_price"`
+	Amount      money.Money `gorm:"type:decimal(10,2);not null" json:"amount"`
+}
+
+// ShortDescription memotong deskripsi sesuai batas nama item payment gateway
+func (l PriceLine) ShortDescription() string {
+	runes := []rune(l.Description)
+	if len(runes) <= maxPriceLineNameLen {
+		return l.Description
+	}
+	return string(runes[:maxPriceLineNameLen])
+}
+
+// SumPriceLines menjumlahkan seluruh baris harga
+func SumPriceLines(lines []PriceLine) money.Money {
+	var total money.Money
+	for _, line := range lines {
+		total += line.Amount
+	}
+	return total
+}
+
+// RentalPriceLine menyimpan rincian harga yang disepakati saat rental dibuat
+type RentalPriceLine struct {
+	BaseEntity
+	RentalID  uuid.UUID `gorm:"type:uuid;not null;index" json:"rental_id"`
+	PriceLine `gorm:"embedded"`
+}
+
+func (*RentalPriceLine) TableName() string {
+	return "rental_price_lines"
+}
+
+type PriceQuote struct {
+	RentalDate         time.Time   `json:"rental_date"`
+	ExpectedReturnDate time.Time   `json:"expected_return_date"`
+	RentalDays         int         `json:"rental_days"`
+	Lines              []PriceLine `json:"lines"`
+	Total              money.Money `json:"total"`
+	TaxRate            float64     `json:"tax_rate"`
+	TaxInclusive       bool        `json:"tax_inclusive"`
+	Tax                money.Money `json:"tax"`
+	GrandTotal         money.Money `json:"grand_total"` // total termasuk PPN, belum termasuk deposit
+	Deposit            money.Money `json:"deposit"`     // ditagihkan bersama pembayaran awal, dikembalikan setelah rental selesai
+	PromoCode          string      `json:"promo_code,omitempty"`
+	DiscountAmount     money.Money `json:"discount_amount,omitempty"`
+	Promotion          *Promotion  `json:"-"`
+	DeliveryFee        money.Money `json:"delivery_fee,omitempty"`
+	// Tugas antar jemput yang akan dibuat bersama rental
+	DeliveryTasks []DeliveryTask `json:"-"`
+}
+
+type QuoteRentalRequest struct {
+	RentalDate         time.Time                 `json:"rental_date" binding:"required"`
+	ExpectedReturnDate time.Time                 `json:"expected_return_date" binding:"required"`
+	Items              []CreateRentalItemRequest `json:"items" binding:"required"`
+	PromoCode          string                    `json:"promo_code"`
+	UserID             uuid.UUID                 `json:"-"`
+	PickupBranchID     *uuid.UUID                `json:"pickup_branch_id"`
+	ReturnBranchID     *uuid.UUID                `json:"return_branch_id"`
+	DeliveryOptions
+}
+
+type PricingRuleRequest struct {
+	Name     string  `json:"name" binding:"required"`
+	Type     string  `json:"type" binding:"required"`
+	Percent  float64 `json:"percent" binding:"required"`
+	MinDays  int     `json:"min_days"`
+	IsActive bool    `json:"is_active"`
+}
+
+type HolidayRequest struct {
+	// Format tanggal YYYY-MM-DD
+	Date string `json:"date" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
@@ -0,0 +1,172 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	PromotionTypePercentage = "percentage"
+	PromotionTypeFixed      = "fixed"
+	PriceLinePromotion      = "promotion"
+)
+
+var (
+	ErrPromotionNotFound      = errors.New("kode promo tidak valid")
+	ErrPromotionInactive      = errors.New("kode promo tidak aktif atau di luar periode berlaku")
+	ErrPromotionUsageExceeded = errors.New("kuota kode promo sudah habis")
+	ErrPromotionUserLimit     = errors.New("batas pemakaian kode promo untuk akun ini sudah tercapai")
+	ErrPromotionMinOrder      = errors.New("total belanja belum memenuhi minimal pemakaian kode promo")
+	ErrPromotionNotApplicable = errors.New("kode promo tidak berlaku untuk mainan yang dipilih")
+	ErrPromotionCodeExists    = errors.New("kode promo sudah digunakan")
+)
+
+type Promotion struct {
+	BaseEntity
+	Code           string      `gorm:"size:50;not null;uniqueIndex:idx_promotions_code,where:deleted_at IS NULL" json:"code"`
+	Name           string      `gorm:"size:100;not null" json:"name"`
+	Description    string      `gorm:"type:text" json:"description"`
+	DiscountType   string      `gorm:"size:20;not null;check:discount_type IN ('percentage', 'fixed')" json:"discount_type"`
+	DiscountValue  float64     `gorm:"type:decimal(10,2);not null" json:"discount_value"`
+	MinOrderAmount money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"min_order_amount"`
+	MaxDiscount    money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"max_discount"` // 0 berarti tanpa batas
+	StartsAt       time.Time   `gorm:"not null" json:"starts_at"`
+	EndsAt         time.Time   `gorm:"not null" json:"ends_at"`
+	UsageLimit     int         `gorm:"not null;default:0" json:"usage_limit"`    // 0 berarti tanpa batas
+	PerUserLimit   int         `gorm:"not null;default:0" json:"per_user_limit"` // 0 berarti tanpa batas
+	IsActive       bool        `gorm:"not null" json:"is_active"`
+
+	Categories []ToyCategory `gorm:"many2many:promotion_categories" json:"categories"`
+	Toys       []Toy         `gorm:"many2many:promotion_toys" json:"toys"`
+}
+
+func (*Promotion) TableName() string {
+	return "promotions"
+}
+
+// IsRunning menandakan promo aktif dan berada dalam periode berlaku
+func (p *Promotion) IsRunning(at time.Time) bool {
+	return p.IsActive && !at.Before(p.StartsAt) && !at.After(p.EndsAt)
+}
+
+// Discount menghitung potongan dari subtotal item yang memenuhi syarat. DiscountValue berisi persen
+// untuk promo persentase dan nominal rupiah untuk promo potongan tetap.
+func (p *Promotion) Discount(eligibleSubtotal money.Money) money.Money {
+	if eligibleSubtotal <= 0 {
+		return 0
+	}
+
+	discount := money.FromFloat(p.DiscountValue)
+	if p.DiscountType == PromotionTypePercentage {
+		discount = eligibleSubtotal.Percent(p.DiscountValue)
+	}
+
+	if p.MaxDiscount > 0 {
+		discount = money.Min(discount, p.MaxDiscount)
+	}
+	return money.Min(discount, eligibleSubtotal)
+}
+
+func (p *Promotion) Validate() []string {
+	err := validation.ValidateStruct(p,
+		validation.Field(&p.Code,
+			validation.Required.Error("Kode promo wajib diisi"),
+			validation.RuneLength(3, 50).Error("Kode promo harus antara 3-50 karakter"),
+		),
+		validation.Field(&p.Name,
+			validation.Required.Error("Nama promo wajib diisi"),
+			validation.RuneLength(3, 100).Error("Nama promo harus antara 3-100 karakter"),
+		),
+		validation.Field(&p.DiscountType,
+			validation.Required.Error("Jenis potongan wajib diisi"),
+			validation.In(PromotionTypePercentage, PromotionTypeFixed).Error("Jenis potongan harus percentage atau fixed"),
+		),
+		validation.Field(&p.DiscountValue,
+			validation.Required.Error("Nilai potongan wajib diisi"),
+			validation.Min(0.0).Error("Nilai potongan tidak boleh negatif"),
+			validation.When(p.DiscountType == PromotionTypePercentage, validation.Max(100.0).Error("Potongan persentase maksimal 100")),
+		),
+		validation.Field(&p.MinOrderAmount,
+			validation.Min(money.Money(0)).Error("Minimal belanja tidak boleh negatif"),
+		),
+		validation.Field(&p.MaxDiscount,
+			validation.Min(money.Money(0)).Error("Maksimal potongan tidak boleh negatif"),
+		),
+		validation.Field(&p.StartsAt,
+			validation.Required.Error("Tanggal mulai wajib diisi"),
+		),
+		validation.Field(&p.EndsAt,
+			validation.Required.Error("Tanggal berakhir wajib diisi"),
+			validation.Min(p.StartsAt).Error("Tanggal berakhir harus setelah tanggal mulai"),
+		),
+		validation.Field(&p.UsageLimit,
+			validation.Min(0).Error("Kuota pemakaian tidak boleh negatif"),
+		),
+		validation.Field(&p.PerUserLimit,
+			validation.Min(0).Error("Batas pemakaian per pengguna tidak boleh negatif"),
+		),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
+
+// PromotionRedemption mencatat pemakaian kode promo pada sebuah rental
+type PromotionRedemption struct {
+	BaseEntity
+	PromotionID    uuid.UUID   `gorm:"type:uuid;not null;index" json:"promotion_id"`
+	RentalID       uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex" json:"rental_id"`
+	UserID         uuid.UUID   `gorm:"type:uuid;not null;index" json:"user_id"`
+	DiscountAmount money.Money `gorm:"type:decimal(10,2);not null" json:"discount_amount"`
+	RedeemedAt     time.Time   `gorm:"not null" json:"redeemed_at"`
+}
+
+func (*PromotionRedemption) TableName() string {
+	return "promotion_redemptions"
+}
+
+type PromotionRequest struct {
+	Code           string      `json:"code" binding:"required"`
+	Name           string      `json:"name" binding:"required"`
+	Description    string      `json:"description"`
+	DiscountType   string      `json:"discount_type" binding:"required"`
+	DiscountValue  float64     `json:"discount_value" binding:"required"`
+	MinOrderAmount money.Money `json:"min_order_amount"`
+	MaxDiscount    money.Money `json:"max_discount"`
+	StartsAt       time.Time   `json:"starts_at" binding:"required"`
+	EndsAt         time.Time   `json:"ends_at" binding:"required"`
+	UsageLimit     int         `json:"usage_limit"`
+	PerUserLimit   int         `json:"per_user_limit"`
+	IsActive       bool        `json:"is_active"`
+	CategoryIDs    []string    `json:"category_ids"`
+	ToyIDs         []string    `json:"toy_ids"`
+}
+
+type PromotionUsageItem struct {
+	PromotionID      uuid.UUID   `json:"promotion_id"`
+	Code             string      `json:"code"`
+	Name             string      `json:"name"`
+	UsageLimit       int         `json:"usage_limit"`
+	RedemptionCount  int         `json:"redemption_count"`
+	UniqueUsers      int         `json:"unique_users"`
+	TotalDiscount    money.Money `json:"total_discount"`
+	RentalRevenue    money.Money `json:"rental_revenue"`
+	PaidRentalCount  int         `json:"paid_rental_count"`
+	LastRedemptionAt *time.Time  `json:"last_redemption_at"`
+}
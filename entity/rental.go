@@ -2,6 +2,7 @@ package entity
 
 import (
 	"errors"
+	"final-project/utils/money"
 	"time"
 
 	"github.com/gofrs/uuid/v5"
@@ -34,21 +35,42 @@ var (
 
 type Rental struct {
 	BaseEntity
-	UserID             uuid.UUID  `gorm:"type:uuid;not null" json:"user_id,omitempty"`
-	Status             string     `gorm:"size:50;not null;check:status IN ('pending', 'active', 'completed', 'overdue', 'cancelled', 'extension')" json:"status,omitempty"`
-	RentalDate         time.Time  `gorm:"not null" json:"rental_date,omitempty"`
-	ExpectedReturnDate time.Time  `gorm:"not null" json:"expected_return_date,omitempty"`
-	ActualReturnDate   *time.Time `json:"actual_return_date,omitempty"`
-	TotalRentalPrice   float64    `gorm:"type:decimal(10,2);not null" json:"total_rental_price,omitempty"`
-	LateFee            float64    `gorm:"type:decimal(10,2)" json:"late_fee,omitempty"`
-	DamageFee          float64    `gorm:"type:decimal(10,2)" json:"damage_fee,omitempty"`
-	TotalAmount        float64    `gorm:"-" json:"total_amount,omitempty"`
-	PaymentStatus      string     `gorm:"size:50;not null;default:unpaid;check:payment_status IN ('unpaid', 'pending', 'paid', 'expired', 'failed', 'refunded', 'partially_paid', 'extension')" json:"payment_status,omitempty"`
-	Notes              string     `gorm:"type:text" json:"notes,omitempty"`
-
-	User        User         `gorm:"foreignKey:UserID" json:"-" swaggerignore:"true"`
-	RentalItems []RentalItem `gorm:"foreignKey:RentalID" json:"rental_items,omitempty"`
-	Payments    []Payment    `gorm:"foreignKey:RentalID" json:"payments,omitempty" swaggerignore:"true"`
+	UserID             uuid.UUID   `gorm:"type:uuid;not null" json:"user_id,omitempty"`
+	Status             string      `gorm:"size:50;not null;check:status IN ('pending', 'active', 'completed', 'overdue', 'cancelled', 'extension')" json:"status,omitempty"`
+	RentalDate         time.Time   `gorm:"not null" json:"rental_date,omitempty"`
+	ExpectedReturnDate time.Time   `gorm:"not null" json:"expected_return_date,omitempty"`
+	ActualReturnDate   *time.Time  `json:"actual_return_date,omitempty"`
+	TotalRentalPrice   money.Money `gorm:"type:decimal(10,2);not null" json:"total_rental_price,omitempty"`
+	LateFee            money.Money `gorm:"type:decimal(10,2)" json:"late_fee,omitempty"`
+	DamageFee          money.Money `gorm:"type:decimal(10,2)" json:"damage_fee,omitempty"`
+	TotalAmount        money.Money `gorm:"-" json:"total_amount,omitempty"`
+	PaymentStatus      string      `gorm:"size:50;not null;default:unpaid;check:payment_status IN ('unpaid', 'pending', 'paid', 'expired', 'failed', 'refunded', 'partially_paid', 'extension')" json:"payment_status,omitempty"`
+	Notes              string      `gorm:"type:text" json:"notes,omitempty"`
+	FeePolicyID        *uuid.UUID  `gorm:"type:uuid" json:"fee_policy_id,omitempty"`
+	FeePolicyVersion   int         `json:"fee_policy_version,omitempty"`
+	PromotionID        *uuid.UUID  `gorm:"type:uuid;index" json:"promotion_id,omitempty"`
+	PromoCode          string      `gorm:"size:50" json:"promo_code,omitempty"`
+	DiscountAmount     money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"discount_amount,omitempty"`
+	DepositAmount      money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"deposit_amount,omitempty"`
+	DepositStatus      string      `gorm:"size:20;not null;default:none;check:deposit_status IN ('none', 'pending', 'held', 'refunded', 'refund_pending', 'credited', 'applied')" json:"deposit_status,omitempty"`
+	DepositApplied     money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"deposit_applied,omitempty"`
+	DepositRefunded    money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"deposit_refunded,omitempty"`
+	DepositSettledAt   *time.Time  `json:"deposit_settled_at,omitempty"`
+	WrittenOffAmount   money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"written_off_amount,omitempty"`
+	TaxRate            float64     `gorm:"type:decimal(5,2);not null;default:0" json:"tax_rate,omitempty"`
+	TaxInclusive       bool        `gorm:"not null;default:false" json:"tax_inclusive,omitempty"`
+	WrittenOffAt       *time.Time  `json:"written_off_at,omitempty"`
+	PickupBranchID     *uuid.UUID  `gorm:"type:uuid;index" json:"pickup_branch_id,omitempty"`
+	ReturnBranchID     *uuid.UUID  `gorm:"type:uuid;index" json:"return_branch_id,omitempty"`
+	DeliveryMethod     string      `gorm:"size:20;not null;default:self_pickup;check:delivery_method IN ('self_pickup', 'delivery')" json:"delivery_method,omitempty"`
+	ReturnMethod       string      `gorm:"size:20;not null;default:self_return;check:return_method IN ('self_return', 'pickup')" json:"return_method,omitempty"`
+	DeliveryFee        money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"delivery_fee,omitempty"` // ongkos antar jemput, sudah termasuk dalam TotalRentalPrice
+
+	User          User              `gorm:"foreignKey:UserID" json:"-" swaggerignore:"true"`
+	RentalItems   []RentalItem      `gorm:"foreignKey:RentalID" json:"rental_items,omitempty"`
+	PriceLines    []RentalPriceLine `gorm:"foreignKey:RentalID" json:"price_lines,omitempty"`
+	Payments      []Payment         `gorm:"foreignKey:RentalID" json:"payments,omitempty" swaggerignore:"true"`
+	DeliveryTasks []DeliveryTask    `gorm:"foreignKey:RentalID" json:"delivery_tasks,omitempty"`
 }
 
 func (*Rental) TableName() string {
@@ -166,7 +188,13 @@ type CreateRentalRequest struct {
 	RentalDate         time.Time                 `json:"rental_date"`
 	ExpectedReturnDate time.Time                 `json:"expected_return_date"`
 	Items              []CreateRentalItemRequest `json:"items"`
+	PromoCode          string                    `json:"promo_code"`
 	Notes              string                    `json:"notes"`
+	// Cabang pengambilan, default cabang utama
+	PickupBranchID *uuid.UUID `json:"pickup_branch_id"`
+	// Cabang pengembalian, default sama dengan cabang pengambilan
+	ReturnBranchID *uuid.UUID `json:"return_branch_id"`
+	DeliveryOptions
 }
 
 type CreateRentalItemRequest struct {
@@ -179,10 +207,21 @@ type ReturnRentalRequest struct {
 	ActualReturnDate time.Time                 `json:"actual_return_date" binding:"required"`
 	Items            []ReturnRentalItemRequest `json:"items" binding:"required"`
 	Notes            string                    `json:"notes"`
+	// Cara mengembalikan sisa deposit: gateway (default) atau credit
+	DepositRefundMethod string `json:"deposit_refund_method"`
+	// Cabang tempat barang benar-benar dikembalikan bila berbeda dari rencana
+	ReturnBranchID *uuid.UUID `json:"return_branch_id"`
 }
 
 type ReturnRentalItemRequest struct {
-	RentalItemID      uuid.UUID `json:"rental_item_id" binding:"required"`
+	RentalItemID      uuid.UUID                 `json:"rental_item_id" binding:"required"`
+	ConditionAfter    string                    `json:"condition_after" binding:"required"`
+	DamageDescription string                    `json:"damage_description"`
+	Units             []ReturnRentalUnitRequest `json:"units"`
+}
+
+type ReturnRentalUnitRequest struct {
+	ToyUnitID         uuid.UUID `json:"toy_unit_id" binding:"required"`
 	ConditionAfter    string    `json:"condition_after" binding:"required"`
 	DamageDescription string    `json:"damage_description"`
 }
@@ -193,9 +232,10 @@ type ExtendRentalRequest struct {
 }
 
 type ExtensionMetadata struct {
-	OldExpectedReturnDate time.Time `json:"old_expected_return_date"`
-	NewExpectedReturnDate time.Time `json:"new_expected_return_date"`
-	AdditionalDays        int       `json:"additional_days"`
-	OriginalRentalPrice   float64   `json:"original_rental_price"`
-	AdditionalCost        float64   `json:"additional_cost"`
+	OldExpectedReturnDate time.Time   `json:"old_expected_return_date"`
+	NewExpectedReturnDate time.Time   `json:"new_expected_return_date"`
+	AdditionalDays        int         `json:"additional_days"`
+	OriginalRentalPrice   money.Money `json:"original_rental_price"`
+	AdditionalCost        money.Money `json:"additional_cost"`
+	Lines                 []PriceLine `json:"lines,omitempty"`
 }
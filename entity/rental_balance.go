@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var (
+	ErrNothingToSettle   = errors.New("tidak ada tagihan yang belum dibayar untuk rental ini")
+	ErrRentalNotReturned = errors.New("rental belum dikembalikan")
+	ErrRentalReturned    = errors.New("rental sudah dikembalikan")
+)
+
+// RentalBalance membandingkan total tagihan rental dengan yang sudah dibayar. Pembayaran, potongan
+// deposit dan piutang yang dihapus dialokasikan berurutan ke biaya rental, denda keterlambatan, lalu biaya kerusakan.
+// Setiap komponen tagihan sudah termasuk PPN-nya.
+type RentalBalance struct {
+	RentalID       uuid.UUID   `json:"rental_id"`
+	RentalCharge   money.Money `json:"rental_charge"`
+	LateFee        money.Money `json:"late_fee"`
+	DamageFee      money.Money `json:"damage_fee"`
+	RentalTax      money.Money `json:"rental_tax"`
+	LateFeeTax     money.Money `json:"late_fee_tax"`
+	DamageFeeTax   money.Money `json:"damage_fee_tax"`
+	Tax            money.Money `json:"tax"`
+	TotalCharged   money.Money `json:"total_charged"`
+	Paid           money.Money `json:"paid"`
+	DepositApplied money.Money `json:"deposit_applied"`
+	WrittenOff     money.Money `json:"written_off"`
+	RentalDue      money.Money `json:"rental_due"`
+	LateFeeDue     money.Money `json:"late_fee_due"`
+	DamageFeeDue   money.Money `json:"damage_fee_due"`
+	Outstanding    money.Money `json:"outstanding"`
+	Overpaid       money.Money `json:"overpaid"`
+}
+
+// NewRentalBalance menghitung sisa tagihan dari jumlah yang sudah dibayar lewat payment gateway
+func NewRentalBalance(rental Rental, paid money.Money) RentalBalance {
+	balance := RentalBalance{
+		RentalID:       rental.ID,
+		Paid:           paid,
+		DepositApplied: rental.DepositApplied,
+		WrittenOff:     rental.WrittenOffAmount,
+	}
+
+	tax := rental.TaxPolicy()
+	balance.RentalCharge, balance.RentalTax = tax.Apply(rental.TotalRentalPrice)
+	balance.LateFee, balance.LateFeeTax = tax.Apply(rental.LateFee)
+	balance.DamageFee, balance.DamageFeeTax = tax.Apply(rental.DamageFee)
+	balance.Tax = balance.RentalTax + balance.LateFeeTax + balance.DamageFeeTax
+	balance.TotalCharged = balance.RentalCharge + balance.LateFee + balance.DamageFee
+
+	credit := paid + rental.DepositApplied + rental.WrittenOffAmount
+	allocate := func(charge money.Money) money.Money {
+		covered := money.Min(credit, charge)
+		credit -= covered
+		return charge - covered
+	}
+
+	balance.RentalDue = allocate(balance.RentalCharge)
+	balance.LateFeeDue = allocate(balance.LateFee)
+	balance.DamageFeeDue = allocate(balance.DamageFee)
+	balance.Outstanding = balance.RentalDue + balance.LateFeeDue + balance.DamageFeeDue
+	balance.Overpaid = credit
+
+	return balance
+}
+
+// PaymentType menentukan jenis pembayaran dari komponen tagihan yang belum dibayar
+func (b RentalBalance) PaymentType() string {
+	fees := 0
+	if b.LateFeeDue > 0 {
+		fees++
+	}
+	if b.DamageFeeDue > 0 {
+		fees++
+	}
+
+	switch {
+	case fees == 0:
+		return PaymentTypeRental
+	case b.RentalDue > 0 || fees > 1:
+		return PaymentTypeCombined
+	case b.LateFeeDue > 0:
+		return PaymentTypeLateFee
+	default:
+		return PaymentTypeDamageFee
+	}
+}
+
+type WriteOffRentalRequest struct {
+	// Alasan penghapusan piutang, dicatat pada jurnal
+	Notes string `json:"notes" binding:"required"`
+}
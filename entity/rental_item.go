@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"final-project/utils/money"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/gofrs/uuid/v5"
 )
@@ -14,18 +15,19 @@ const (
 
 type RentalItem struct {
 	BaseEntity
-	RentalID          uuid.UUID `gorm:"type:uuid;not null" json:"rental_id"`
-	ToyID             uuid.UUID `gorm:"type:uuid;not null" json:"toy_id"`
-	Quantity          int       `gorm:"not null;default:1" json:"quantity"`
-	PricePerUnit      float64   `gorm:"type:decimal(10,2);not null" json:"price_per_unit"`
-	ConditionBefore   string    `gorm:"size:50;not null;check:condition_before IN ('new', 'excellent', 'good', 'fair', 'poor')" json:"condition_before"`
-	ConditionAfter    string    `gorm:"size:50;check:condition_after IN ('new', 'excellent', 'good', 'fair', 'poor', 'damaged', 'lost')" json:"condition_after"`
-	DamageDescription string    `gorm:"type:text" json:"damage_description"`
-	DamageFee         float64   `gorm:"type:decimal(10,2)" json:"damage_fee"`
-	Status            string    `gorm:"size:50;not null;default:rented;check:status IN ('rented', 'returned', 'damaged', 'lost')" json:"status"`
+	RentalID          uuid.UUID   `gorm:"type:uuid;not null" json:"rental_id"`
+	ToyID             uuid.UUID   `gorm:"type:uuid;not null" json:"toy_id"`
+	Quantity          int         `gorm:"not null;default:1" json:"quantity"`
+	PricePerUnit      money.Money `gorm:"type:decimal(10,2);not null" json:"price_per_unit"`
+	ConditionBefore   string      `gorm:"size:50;not null;check:condition_before IN ('new', 'excellent', 'good', 'fair', 'poor')" json:"condition_before"`
+	ConditionAfter    string      `gorm:"size:50;check:condition_after IN ('new', 'excellent', 'good', 'fair', 'poor', 'damaged', 'lost')" json:"condition_after"`
+	DamageDescription string      `gorm:"type:text" json:"damage_description"`
+	DamageFee         money.Money `gorm:"type:decimal(10,2)" json:"damage_fee"`
+	Status            string      `gorm:"size:50;not null;default:rented;check:status IN ('rented', 'returned', 'damaged', 'lost')" json:"status"`
 
-	Rental Rental `gorm:"foreignKey:RentalID" json:"-"`
-	Toy    Toy    `gorm:"foreignKey:ToyID" json:"toy"`
+	Rental Rental           `gorm:"foreignKey:RentalID" json:"-"`
+	Toy    Toy              `gorm:"foreignKey:ToyID" json:"toy"`
+	Units  []RentalItemUnit `gorm:"foreignKey:RentalItemID" json:"units,omitempty"`
 }
 
 func (*RentalItem) TableName() string {
@@ -51,7 +53,7 @@ func (ri *RentalItem) Validate() []string {
 		),
 		validation.Field(&ri.PricePerUnit,
 			validation.Required.Error("Harga per unit wajib diisi"),
-			validation.Min(0.0).Error("Harga per unit tidak boleh negatif"),
+			validation.Min(money.Money(0)).Error("Harga per unit tidak boleh negatif"),
 		),
 		validation.Field(&ri.ConditionBefore,
 			validation.Required.Error("Kondisi awal mainan wajib diisi"),
@@ -69,7 +71,7 @@ func (ri *RentalItem) Validate() []string {
 		),
 		validation.Field(&ri.DamageFee,
 			validation.When(isDamageFeeRequired, validation.Required.Error("Biaya kerusakan wajib diisi ketika mainan rusak")),
-			validation.When(ri.DamageFee > 0, validation.Min(0.0).Error("Biaya kerusakan tidak boleh negatif")),
+			validation.When(ri.DamageFee > 0, validation.Min(money.Money(0)).Error("Biaya kerusakan tidak boleh negatif")),
 		),
 		validation.Field(&ri.Status,
 			validation.Required.Error("Status wajib diisi"),
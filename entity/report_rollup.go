@@ -0,0 +1,164 @@
+package entity
+
+import (
+	"final-project/utils/money"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	ReportRollupRunRunning   = "running"
+	ReportRollupRunCompleted = "completed"
+	ReportRollupRunFailed    = "failed"
+)
+
+// ReportDailyRental adalah ringkasan rental per hari, cabang pengambilan, pelanggan dan status.
+// Tanggalnya adalah tanggal rental dalam zona waktu laporan, sama dengan tanggal pada parameter laporan.
+type ReportDailyRental struct {
+	ID               int64       `gorm:"primaryKey" json:"-"`
+	Date             time.Time   `gorm:"type:date;not null;index" json:"date"`
+	BranchID         *uuid.UUID  `gorm:"type:uuid;index" json:"branch_id"`
+	UserID           uuid.UUID   `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status           string      `gorm:"size:50;not null" json:"status"`
+	RentalCount      int         `gorm:"not null" json:"rental_count"`
+	RentalRevenue    money.Money `gorm:"type:decimal(14,2);not null" json:"rental_revenue"`
+	LateFeeRevenue   money.Money `gorm:"type:decimal(14,2);not null" json:"late_fee_revenue"`
+	DamageFeeRevenue money.Money `gorm:"type:decimal(14,2);not null" json:"damage_fee_revenue"`
+	DepositAmount    money.Money `gorm:"type:decimal(14,2);not null" json:"deposit_amount"`
+	NetRevenue       money.Money `gorm:"type:decimal(14,2);not null;default:0" json:"net_revenue"`
+	TaxAmount        money.Money `gorm:"type:decimal(14,2);not null;default:0" json:"tax_amount"`
+	GrossRevenue     money.Money `gorm:"type:decimal(14,2);not null;default:0" json:"gross_revenue"`
+	LateFeeCount     int         `gorm:"not null" json:"late_fee_count"`
+	DamageFeeCount   int         `gorm:"not null" json:"damage_fee_count"`
+	FirstRentalAt    time.Time   `gorm:"not null" json:"first_rental_at"`
+	LastRentalAt     time.Time   `gorm:"not null" json:"last_rental_at"`
+}
+
+func (*ReportDailyRental) TableName() string {
+	return "report_daily_rentals"
+}
+
+// ReportDailyToy adalah ringkasan item rental per hari, cabang dan mainan. Durasi rental yang belum
+// dikembalikan bergantung pada tanggal hari ini, sehingga yang disimpan adalah selisihnya terhadap
+// jumlah hari sejak tanggal rollup dan durasinya dihitung ulang saat laporan dibaca.
+type ReportDailyToy struct {
+	ID                  int64       `gorm:"primaryKey" json:"-"`
+	Date                time.Time   `gorm:"type:date;not null;index" json:"date"`
+	BranchID            *uuid.UUID  `gorm:"type:uuid;index" json:"branch_id"`
+	ToyID               uuid.UUID   `gorm:"type:uuid;not null;index" json:"toy_id"`
+	RentalCount         int         `gorm:"not null" json:"rental_count"`
+	ItemCount           int         `gorm:"not null" json:"item_count"`
+	Revenue             money.Money `gorm:"type:decimal(14,2);not null" json:"revenue"`
+	ReturnedDurationSum float64     `gorm:"not null" json:"returned_duration_sum"`
+	OpenItemCount       int         `gorm:"not null" json:"open_item_count"`
+	OpenDurationOffset  float64     `gorm:"not null" json:"open_duration_offset"`
+}
+
+func (*ReportDailyToy) TableName() string {
+	return "report_daily_toys"
+}
+
+// ReportDailyCategory adalah ringkasan item rental per hari, cabang dan kategori mainan. Item tanpa
+// kategori disimpan dengan CategoryID kosong. Pendapatan item dibagi rata ke semua kategorinya.
+type ReportDailyCategory struct {
+	ID                  int64      `gorm:"primaryKey" json:"-"`
+	Date                time.Time  `gorm:"type:date;not null;index" json:"date"`
+	BranchID            *uuid.UUID `gorm:"type:uuid;index" json:"branch_id"`
+	CategoryID          *uuid.UUID `gorm:"type:uuid;index" json:"category_id"`
+	RentalCount         int        `gorm:"not null" json:"rental_count"`
+	ItemCount           int        `gorm:"not null" json:"item_count"`
+	Quantity            int        `gorm:"not null" json:"quantity"`
+	DamagedQuantity     int        `gorm:"not null" json:"damaged_quantity"`
+	Revenue             float64    `gorm:"type:decimal(16,4);not null" json:"revenue"`
+	ReturnedDurationSum float64    `gorm:"not null" json:"returned_duration_sum"`
+	OpenItemCount       int        `gorm:"not null" json:"open_item_count"`
+	OpenDurationOffset  float64    `gorm:"not null" json:"open_duration_offset"`
+}
+
+func (*ReportDailyCategory) TableName() string {
+	return "report_daily_categories"
+}
+
+// ReportRollupRun adalah riwayat pembangunan ulang rollup laporan. Laporan baru membaca rollup setelah
+// ada pembangunan ulang yang selesai, sebelumnya semua laporan dihitung langsung dari transaksi.
+type ReportRollupRun struct {
+	BaseEntity
+	Status     string     `gorm:"size:20;not null;check:status IN ('running', 'completed', 'failed')" json:"status"`
+	StartedAt  time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DayCount   int        `gorm:"not null;default:0" json:"day_count"`
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+}
+
+func (*ReportRollupRun) TableName() string {
+	return "report_rollup_runs"
+}
+
+// ReportRollupDirtyDay menandai hari yang rollup-nya gagal diperbarui setelah rental berubah. Tanda
+// dihapus saat hari tersebut berhasil dihitung ulang, paling lambat pada pembangunan ulang berikutnya.
+type ReportRollupDirtyDay struct {
+	Date     time.Time `gorm:"type:date;primaryKey" json:"date"`
+	MarkedAt time.Time `gorm:"not null" json:"marked_at"`
+}
+
+func (*ReportRollupDirtyDay) TableName() string {
+	return "report_rollup_dirty_days"
+}
+
+// ReportRollupRange memecah periode laporan menjadi hari yang sudah lewat, yang dibaca dari rollup, dan
+// bagian hari ini yang dihitung langsung dari transaksi
+type ReportRollupRange struct {
+	StartDay  time.Time // tanggal rollup pertama, lihat ReportDay
+	EndDay    time.Time // tanggal rollup terakhir, sebelum StartDay bila tidak ada
+	LiveStart time.Time
+	LiveEnd   time.Time
+}
+
+// ReportDay adalah tanggal rollup untuk waktu t, yaitu tanggal kalender t dalam zona waktu laporan
+// yang disimpan sebagai pukul 00.00 UTC seperti nilai kolom date
+func ReportDay(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ReportDayStart adalah awal tanggal rollup day dalam zona waktu laporan
+func ReportDayStart(day time.Time, location *time.Location) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, location)
+}
+
+// SplitRollupRange memecah periode laporan pada awal hari ini dalam zona waktu laporan. Rollup hanya
+// dipakai bila periode terdiri dari hari penuh seperti hasil parameter laporan, yaitu mulai pukul
+// 00.00.00 dan berakhir pukul 23.59.59 dalam zona waktu tersebut.
+func SplitRollupRange(startDate, endDate time.Time, now time.Time, location *time.Location) (ReportRollupRange, bool) {
+	startDay := ReportDay(startDate, location)
+	endDay := ReportDay(endDate, location)
+	if !startDate.Equal(ReportDayStart(startDay, location)) || !endDate.Equal(ReportDayStart(endDay, location).Add(24*time.Hour-time.Second)) {
+		return ReportRollupRange{}, false
+	}
+
+	today := ReportDay(now, location)
+	rollupRange := ReportRollupRange{
+		StartDay:  startDay,
+		EndDay:    endDay,
+		LiveStart: ReportDayStart(today, location),
+		LiveEnd:   endDate,
+	}
+	if !endDay.Before(today) {
+		rollupRange.EndDay = today.AddDate(0, 0, -1)
+	}
+	if startDate.After(rollupRange.LiveStart) {
+		rollupRange.LiveStart = startDate
+	}
+	return rollupRange, true
+}
+
+// HasRollup bernilai true bila ada hari yang dibaca dari rollup
+func (r ReportRollupRange) HasRollup() bool {
+	return !r.EndDay.Before(r.StartDay)
+}
+
+// HasLive bernilai true bila periode mencakup hari ini atau sesudahnya
+func (r ReportRollupRange) HasLive() bool {
+	return !r.LiveEnd.Before(r.LiveStart)
+}
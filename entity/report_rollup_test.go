@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplitRollupRange(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// 03.00 WIB tanggal 10 Maret masih tanggal 9 Maret dalam UTC
+	now := time.Date(2026, 3, 10, 3, 0, 0, 0, jakarta)
+	startDate := time.Date(2026, 3, 1, 0, 0, 0, 0, jakarta)
+	endDate := time.Date(2026, 3, 10, 23, 59, 59, 0, jakarta)
+
+	period, ok := SplitRollupRange(startDate, endDate, now, jakarta)
+	if !ok {
+		t.Fatal("periode hari penuh tidak dipecah")
+	}
+	if !period.StartDay.Equal(day(2026, 3, 1)) || !period.EndDay.Equal(day(2026, 3, 9)) {
+		t.Errorf("rollup %v - %v, ingin 2026-03-01 - 2026-03-09", period.StartDay, period.EndDay)
+	}
+	if want := time.Date(2026, 3, 10, 0, 0, 0, 0, jakarta); !period.LiveStart.Equal(want) || !period.LiveEnd.Equal(endDate) {
+		t.Errorf("transaksi langsung %v - %v, ingin %v - %v", period.LiveStart, period.LiveEnd, want, endDate)
+	}
+
+	// Setiap rental masuk tepat satu bagian: tanggal rollup-nya sampai EndDay atau waktunya sejak LiveStart
+	rentals := []struct {
+		name       string
+		rentalDate time.Time
+		wantDay    time.Time
+		wantLive   bool
+	}{
+		{"kemarin malam WIB", time.Date(2026, 3, 9, 23, 0, 0, 0, jakarta), day(2026, 3, 9), false},
+		{"hari ini dini hari WIB", time.Date(2026, 3, 10, 2, 0, 0, 0, jakarta), day(2026, 3, 10), true},
+		{"tengah malam WIB", time.Date(2026, 3, 10, 0, 0, 0, 0, jakarta), day(2026, 3, 10), true},
+	}
+	for _, tt := range rentals {
+		t.Run(tt.name, func(t *testing.T) {
+			rentalDay := ReportDay(tt.rentalDate, jakarta)
+			if !rentalDay.Equal(tt.wantDay) {
+				t.Errorf("tanggal rollup %v, ingin %v", rentalDay, tt.wantDay)
+			}
+
+			inRollup := !rentalDay.After(period.EndDay)
+			inLive := !tt.rentalDate.Before(period.LiveStart) && !tt.rentalDate.After(period.LiveEnd)
+			if inRollup == inLive {
+				t.Errorf("rollup %v, transaksi langsung %v, ingin tepat salah satu", inRollup, inLive)
+			}
+			if inLive != tt.wantLive {
+				t.Errorf("transaksi langsung %v, ingin %v", inLive, tt.wantLive)
+			}
+		})
+	}
+}
+
+func TestSplitRollupRangePartialDay(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2026, 3, 10, 3, 0, 0, 0, jakarta)
+
+	// Tengah malam UTC bukan awal hari dalam zona waktu laporan
+	startDate := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, 3, 9, 23, 59, 59, 0, time.UTC)
+	if _, ok := SplitRollupRange(startDate, endDate, now, jakarta); ok {
+		t.Error("periode yang tidak mulai pukul 00.00 zona waktu laporan dibaca dari rollup")
+	}
+}
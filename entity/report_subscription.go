@@ -0,0 +1,248 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/cron"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	ReportTypeSales        = "sales"
+	ReportTypePopularToys  = "popular_toys"
+	ReportTypeTopCustomers = "top_customers"
+	ReportTypeRentalStatus = "rental_status"
+	ReportTypeCategories   = "categories"
+	ReportTypeUtilization  = "utilization"
+	ReportTypeDamage       = "damage"
+	ReportTypeInventory    = "inventory"
+	ReportTypeDashboard    = "dashboard"
+	ReportTypeOverdue      = "overdue"
+)
+
+// Periode laporan terjadwal dihitung mundur dari tanggal pengiriman, sehingga laporan Senin pagi
+// dengan last_week berisi Senin sampai Minggu sebelumnya.
+const (
+	ReportPeriodYesterday  = "yesterday"
+	ReportPeriodLast7Days  = "last_7_days"
+	ReportPeriodLastWeek   = "last_week"
+	ReportPeriodLast30Days = "last_30_days"
+	ReportPeriodLastMonth  = "last_month"
+)
+
+const (
+	ReportDeliveryStatusSent   = "sent"
+	ReportDeliveryStatusFailed = "failed"
+)
+
+var (
+	ErrReportScheduleNoRun = errors.New("jadwal laporan tidak pernah jatuh dalam lima tahun ke depan")
+)
+
+// ReportParameters adalah parameter laporan yang disimpan bersama langganan. Kolom yang tidak
+// dipakai jenis laporannya diabaikan.
+type ReportParameters struct {
+	// yesterday, last_7_days (default), last_week, last_30_days atau last_month
+	Period       string `json:"period"`
+	GroupBy      string `json:"group_by,omitempty"`
+	Basis        string `json:"basis,omitempty"`
+	Limit        int    `json:"limit,omitempty"`
+	IdleDays     int    `json:"idle_days,omitempty"`
+	MinIncidents int    `json:"min_incidents,omitempty"`
+	CategoryID   string `json:"category_id,omitempty"`
+	Condition    string `json:"condition,omitempty"`
+	// Isi id untuk format angka dan tanggal Indonesia
+	Locale string `json:"locale,omitempty"`
+}
+
+// Range menghitung rentang tanggal laporan untuk pengiriman pada waktu at. Tanggal dihitung dalam
+// zona waktu at, yaitu zona waktu laporan, sama seperti tanggal dari query laporan.
+func (p ReportParameters) Range(at time.Time) (startDate, endDate time.Time) {
+	today := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	lastDay := today.AddDate(0, 0, -1)
+
+	switch p.Period {
+	case ReportPeriodYesterday:
+		startDate = lastDay
+	case ReportPeriodLastWeek:
+		// Senin minggu lalu, time.Weekday dimulai dari Minggu
+		weekday := (int(today.Weekday()) + 6) % 7
+		startDate = today.AddDate(0, 0, -weekday-7)
+		lastDay = startDate.AddDate(0, 0, 6)
+	case ReportPeriodLast30Days:
+		startDate = today.AddDate(0, 0, -30)
+	case ReportPeriodLastMonth:
+		startDate = time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, at.Location())
+		lastDay = time.Date(today.Year(), today.Month(), 0, 0, 0, 0, 0, at.Location())
+	default:
+		startDate = today.AddDate(0, 0, -7)
+	}
+
+	return startDate, lastDay.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+}
+
+// ReportSubscription mengirim laporan bisnis ke email penerima sesuai jadwal cron. Jadwal dihitung
+// dalam zona waktu laporan (REPORT_TIMEZONE).
+type ReportSubscription struct {
+	BaseEntity
+	Name       string           `gorm:"size:100;not null" json:"name"`
+	ReportType string           `gorm:"size:50;not null" json:"report_type"`
+	Parameters ReportParameters `gorm:"type:jsonb;serializer:json;not null" json:"parameters"`
+	// Lima kolom cron: menit jam tanggal bulan hari, misalnya "0 7 * * 1" untuk Senin pukul 07.00
+	Schedule string `gorm:"size:100;not null" json:"schedule"`
+	// Dipisah koma
+	Recipients string     `gorm:"type:text;not null" json:"recipients"`
+	Format     string     `gorm:"size:10;not null;check:format IN ('csv', 'xlsx')" json:"format"`
+	BranchID   *uuid.UUID `gorm:"type:uuid;index" json:"branch_id"` // kosong berarti semua cabang
+	IsActive   bool       `gorm:"not null;default:true" json:"is_active"`
+	NextRunAt  *time.Time `gorm:"index" json:"next_run_at"`
+	LastRunAt  *time.Time `json:"last_run_at"`
+	CreatedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+}
+
+func (*ReportSubscription) TableName() string {
+	return "report_subscriptions"
+}
+
+// RecipientList memecah kolom Recipients menjadi daftar alamat email
+func (s *ReportSubscription) RecipientList() []string {
+	var recipients []string
+	for _, recipient := range strings.Split(s.Recipients, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+func (s *ReportSubscription) Validate() []string {
+	err := validation.ValidateStruct(s,
+		validation.Field(&s.Name,
+			validation.Required.Error("Nama langganan wajib diisi"),
+			validation.RuneLength(3, 100).Error("Nama langganan harus antara 3-100 karakter"),
+		),
+		validation.Field(&s.ReportType,
+			validation.Required.Error("Jenis laporan wajib diisi"),
+			validation.In(ReportTypeSales, ReportTypePopularToys, ReportTypeTopCustomers, ReportTypeRentalStatus,
+				ReportTypeCategories, ReportTypeUtilization, ReportTypeDamage, ReportTypeInventory,
+				ReportTypeDashboard, ReportTypeOverdue).
+				Error("Jenis laporan harus salah satu dari: sales, popular_toys, top_customers, rental_status, categories, utilization, damage, inventory, dashboard, atau overdue"),
+		),
+		validation.Field(&s.Schedule,
+			validation.Required.Error("Jadwal wajib diisi"),
+			validation.By(func(value interface{}) error {
+				_, err := cron.Parse(value.(string))
+				return err
+			}),
+		),
+		validation.Field(&s.Recipients,
+			validation.Required.Error("Penerima wajib diisi"),
+			validation.By(func(interface{}) error {
+				recipients := s.RecipientList()
+				if len(recipients) > 20 {
+					return errors.New("penerima maksimal 20 alamat email")
+				}
+				for _, recipient := range recipients {
+					if err := is.EmailFormat.Validate(recipient); err != nil {
+						return errors.New("format email penerima tidak valid: " + recipient)
+					}
+				}
+				return nil
+			}),
+		),
+		validation.Field(&s.Format,
+			validation.Required.Error("Format wajib diisi"),
+			validation.In("csv", "xlsx").Error("Format harus csv atau xlsx"),
+		),
+		validation.Field(&s.Parameters),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
+
+func (p ReportParameters) Validate() error {
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Period,
+			validation.In(ReportPeriodYesterday, ReportPeriodLast7Days, ReportPeriodLastWeek, ReportPeriodLast30Days, ReportPeriodLastMonth).
+				Error("Periode harus salah satu dari: yesterday, last_7_days, last_week, last_30_days, atau last_month"),
+		),
+		validation.Field(&p.GroupBy,
+			validation.In("day", "week", "month").Error("Pengelompokan harus day, week atau month"),
+		),
+		validation.Field(&p.Basis,
+			validation.In(SalesBasisAccrual, SalesBasisCash).Error("Basis laporan harus accrual atau cash"),
+		),
+		validation.Field(&p.Limit,
+			validation.Min(0).Error("Limit tidak boleh negatif"),
+		),
+		validation.Field(&p.IdleDays,
+			validation.Min(0).Error("Batas hari menganggur tidak boleh negatif"),
+		),
+		validation.Field(&p.MinIncidents,
+			validation.Min(0).Error("Jumlah insiden minimal tidak boleh negatif"),
+		),
+		validation.Field(&p.CategoryID,
+			is.UUID.Error("Format ID kategori tidak valid"),
+		),
+		validation.Field(&p.Condition,
+			validation.By(func(value interface{}) error {
+				if condition := value.(string); condition != "" {
+					if _, ok := ConditionRank[condition]; !ok {
+						return errors.New("kondisi tidak valid: " + condition)
+					}
+				}
+				return nil
+			}),
+		),
+	)
+}
+
+type ReportSubscriptionRequest struct {
+	Name       string           `json:"name" binding:"required"`
+	ReportType string           `json:"report_type" binding:"required"`
+	Parameters ReportParameters `json:"parameters"`
+	Schedule   string           `json:"schedule" binding:"required"`
+	Recipients []string         `json:"recipients" binding:"required"`
+	// csv (default) atau xlsx
+	Format   string     `json:"format"`
+	BranchID *uuid.UUID `json:"branch_id"`
+	IsActive *bool      `json:"is_active"` // default true
+}
+
+// ReportDelivery adalah riwayat satu kali pengiriman laporan langganan
+type ReportDelivery struct {
+	BaseEntity
+	SubscriptionID uuid.UUID  `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	ReportType     string     `gorm:"size:50;not null" json:"report_type"`
+	PeriodStart    time.Time  `gorm:"not null" json:"period_start"`
+	PeriodEnd      time.Time  `gorm:"not null" json:"period_end"`
+	Recipients     string     `gorm:"type:text;not null" json:"recipients"`
+	Filename       string     `gorm:"size:255" json:"filename"`
+	SizeBytes      int        `gorm:"not null;default:0" json:"size_bytes"`
+	Status         string     `gorm:"size:20;not null;check:status IN ('sent', 'failed')" json:"status"`
+	Error          string     `gorm:"type:text" json:"error,omitempty"`
+	Manual         bool       `gorm:"not null;default:false" json:"manual"` // dikirim lewat endpoint kirim sekarang
+	ScheduledAt    time.Time  `gorm:"not null" json:"scheduled_at"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+func (*ReportDelivery) TableName() string {
+	return "report_deliveries"
+}
@@ -0,0 +1,135 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	PriceLineTax         = "tax"
+	PriceLineTaxRounding = "tax_rounding"
+)
+
+const TaxInvoicePrefix = "INV"
+
+var ErrTaxInvoiceNotFound = errors.New("faktur pajak belum terbit untuk pembayaran ini")
+
+// TaxPolicy adalah tarif PPN dalam persen. Harga inklusif sudah termasuk PPN,
+// harga eksklusif ditambah PPN saat ditagihkan.
+type TaxPolicy struct {
+	Rate      float64 `json:"rate"`
+	Inclusive bool    `json:"inclusive"`
+}
+
+// Apply menghitung nilai tagihan (termasuk PPN) dan PPN dari harga yang tercatat
+func (t TaxPolicy) Apply(amount money.Money) (gross money.Money, tax money.Money) {
+	if t.Rate <= 0 || amount == 0 {
+		return amount, 0
+	}
+
+	if t.Inclusive {
+		rate := money.BasisPoints(t.Rate)
+		return amount, amount.MulDiv(rate, 10000+rate)
+	}
+
+	tax = amount.Percent(t.Rate)
+	return amount + tax, tax
+}
+
+// Net menurunkan harga inklusif menjadi DPP
+func (t TaxPolicy) Net(amount money.Money) money.Money {
+	if t.Rate <= 0 || !t.Inclusive {
+		return amount
+	}
+	return amount.MulDiv(10000, 10000+money.BasisPoints(t.Rate))
+}
+
+func (t TaxPolicy) Label() string {
+	return "PPN " + strconv.FormatFloat(t.Rate, 'f', -1, 64) + "%"
+}
+
+// TaxPortion mengambil bagian PPN secara proporsional dari sebagian tagihan
+func TaxPortion(due, gross, tax money.Money) money.Money {
+	if due >= gross || gross == 0 {
+		return tax
+	}
+	return tax.Prorate(due, gross)
+}
+
+// SplitTaxLines memisahkan rincian harga menjadi DPP dan satu baris PPN dengan total tetap gross.
+// Harga inklusif diturunkan ke DPP per unit, selisih pembulatannya dicatat di baris tersendiri.
+func (t TaxPolicy) SplitTaxLines(lines []PriceLine, gross money.Money, tax money.Money) []PriceLine {
+	if tax == 0 {
+		return lines
+	}
+
+	result := make([]PriceLine, 0, len(lines)+2)
+	for _, line := range lines {
+		if t.Inclusive {
+			line.UnitPrice = t.Net(line.UnitPrice)
+			line.Amount = line.UnitPrice.Mul(line.Quantity)
+		}
+		result = append(result, line)
+	}
+
+	result = append(result, PriceLine{
+		Kind:        PriceLineTax,
+		Description: t.Label(),
+		Quantity:    1,
+		UnitPrice:   tax,
+		Amount:      tax,
+	})
+
+	if rounding := gross - SumPriceLines(result); rounding != 0 {
+		result = append(result, PriceLine{
+			Kind:        PriceLineTaxRounding,
+			Description: "Pembulatan",
+			Quantity:    1,
+			UnitPrice:   rounding,
+			Amount:      rounding,
+		})
+	}
+	return result
+}
+
+// TaxPolicy mengembalikan tarif PPN yang berlaku saat rental dibuat
+func (r *Rental) TaxPolicy() TaxPolicy {
+	return TaxPolicy{Rate: r.TaxRate, Inclusive: r.TaxInclusive}
+}
+
+// TaxInvoiceSequence menyimpan nomor terakhir faktur per tahun agar penomoran berurutan tanpa loncat
+type TaxInvoiceSequence struct {
+	Year       int `gorm:"primaryKey;autoIncrement:false" json:"year"`
+	LastNumber int `gorm:"not null" json:"last_number"`
+}
+
+func (*TaxInvoiceSequence) TableName() string {
+	return "tax_invoice_sequences"
+}
+
+func FormatTaxInvoiceNumber(year int, number int) string {
+	return fmt.Sprintf("%s/%d/%06d", TaxInvoicePrefix, year, number)
+}
+
+// TaxInvoice adalah faktur untuk satu pembayaran lunas dengan DPP dan PPN terpisah
+type TaxInvoice struct {
+	Number        string      `json:"number"`
+	IssuedAt      time.Time   `json:"issued_at"`
+	PaymentID     uuid.UUID   `json:"payment_id"`
+	OrderID       string      `json:"order_id"`
+	RentalID      uuid.UUID   `json:"rental_id"`
+	UserID        uuid.UUID   `json:"user_id"`
+	CustomerName  string      `json:"customer_name"`
+	CustomerEmail string      `json:"customer_email"`
+	PaymentMethod string      `json:"payment_method"`
+	TaxRate       float64     `json:"tax_rate"`
+	Lines         []PriceLine `json:"lines"`
+	NetAmount     money.Money `json:"net_amount"`
+	TaxAmount     money.Money `json:"tax_amount"`
+	GrossAmount   money.Money `json:"gross_amount"`
+}
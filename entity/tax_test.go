@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"final-project/utils/money"
+	"testing"
+)
+
+func TestTaxPolicyApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    TaxPolicy
+		amount    money.Money
+		wantGross money.Money
+		wantTax   money.Money
+	}{
+		{"tanpa PPN", TaxPolicy{}, 100000, 100000, 0},
+		{"tarif negatif diabaikan", TaxPolicy{Rate: -11}, 100000, 100000, 0},
+		{"nominal nol", TaxPolicy{Rate: 11}, 0, 0, 0},
+		{"eksklusif", TaxPolicy{Rate: 11}, 100000, 111000, 11000},
+		{"eksklusif dengan pembulatan", TaxPolicy{Rate: 11}, 12345, 13703, 1358},
+		{"eksklusif negatif", TaxPolicy{Rate: 11}, -12345, -13703, -1358},
+		{"inklusif", TaxPolicy{Rate: 11, Inclusive: true}, 111000, 111000, 11000},
+		{"inklusif dengan pembulatan", TaxPolicy{Rate: 11, Inclusive: true}, 9999, 9999, 991},
+		{"inklusif tarif desimal", TaxPolicy{Rate: 11.5, Inclusive: true}, 50000, 50000, 5157},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gross, tax := tt.policy.Apply(tt.amount)
+			if gross != tt.wantGross || tax != tt.wantTax {
+				t.Errorf("Apply(%d) = (%d, %d), ingin (%d, %d)", tt.amount, gross, tax, tt.wantGross, tt.wantTax)
+			}
+		})
+	}
+}
+
+func TestTaxPolicyNet(t *testing.T) {
+	tests := []struct {
+		policy TaxPolicy
+		amount money.Money
+		want   money.Money
+	}{
+		{TaxPolicy{Rate: 11, Inclusive: true}, 111000, 100000},
+		{TaxPolicy{Rate: 11, Inclusive: true}, 9999, 9008},
+		{TaxPolicy{Rate: 11}, 100000, 100000},
+		{TaxPolicy{Inclusive: true}, 100000, 100000},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.Net(tt.amount); got != tt.want {
+			t.Errorf("%+v.Net(%d) = %d, ingin %d", tt.policy, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestTaxPortion(t *testing.T) {
+	tests := []struct {
+		name            string
+		due, gross, tax money.Money
+		want            money.Money
+	}{
+		{"lunas penuh", 111000, 111000, 11000, 11000},
+		{"melebihi tagihan", 200000, 111000, 11000, 11000},
+		{"sebagian", 55500, 111000, 11000, 5500},
+		{"tagihan nol", 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TaxPortion(tt.due, tt.gross, tt.tax); got != tt.want {
+				t.Errorf("TaxPortion(%d, %d, %d) = %d, ingin %d", tt.due, tt.gross, tt.tax, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTaxLines(t *testing.T) {
+	lines := []PriceLine{
+		{Kind: PriceLineRental, Description: "Mainan A - 3 hari", Quantity: 3, UnitPrice: 33333, Amount: 99999},
+		{Kind: PriceLineRental, Description: "Mainan B - 3 hari", Quantity: 1, UnitPrice: 12345, Amount: 12345},
+		{Kind: PriceLinePromotion, Description: "Promo", Quantity: 1, UnitPrice: -7777, Amount: -7777},
+	}
+	subtotal := SumPriceLines(lines)
+
+	tests := []struct {
+		name   string
+		policy TaxPolicy
+	}{
+		{"tanpa PPN", TaxPolicy{}},
+		{"eksklusif", TaxPolicy{Rate: 11}},
+		{"inklusif", TaxPolicy{Rate: 11, Inclusive: true}},
+		{"inklusif tarif desimal", TaxPolicy{Rate: 11.5, Inclusive: true}},
+		{"eksklusif tarif desimal", TaxPolicy{Rate: 12.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gross, tax := tt.policy.Apply(subtotal)
+			result := tt.policy.SplitTaxLines(lines, gross, tax)
+
+			if total := SumPriceLines(result); total != gross {
+				t.Errorf("jumlah rincian %d, ingin %d", total, gross)
+			}
+
+			var taxLine money.Money
+			for _, line := range result {
+				if line.UnitPrice.Mul(line.Quantity) != line.Amount {
+					t.Errorf("rincian %q: %d x %d != %d", line.Description, line.UnitPrice, line.Quantity, line.Amount)
+				}
+				if line.Kind == PriceLineTax {
+					taxLine += line.Amount
+				}
+			}
+			if taxLine != tax {
+				t.Errorf("baris PPN %d, ingin %d", taxLine, tax)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"final-project/utils/money"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"regexp"
 )
@@ -15,26 +16,38 @@ const (
 
 type Toy struct {
 	BaseEntity
-	Name              string  `gorm:"size:255;not null" json:"name"`
-	Description       string  `gorm:"type:text" json:"description"`
-	AgeRecommendation string  `gorm:"size:50" json:"age_recommendation"`
-	Condition         string  `gorm:"size:50;not null;check:condition IN ('new', 'excellent', 'good', 'fair', 'poor')" json:"condition"`
-	RentalPrice       float64 `gorm:"type:decimal(10,2);not null" json:"rental_price"`
-	LateFeePerDay     float64 `gorm:"type:decimal(10,2);not null" json:"late_fee_per_day"`
-	ReplacementPrice  float64 `gorm:"type:decimal(10,2);not null" json:"replacement_price"`
-	IsAvailable       bool    `gorm:"default:true" json:"is_available"`
-	Stock             int     `gorm:"not null" json:"stock"`
-	PrimaryImage      string  `gorm:"type:text" json:"primary_image"`
+	Name              string      `gorm:"size:255;not null" json:"name"`
+	Description       string      `gorm:"type:text" json:"description"`
+	AgeRecommendation string      `gorm:"size:50" json:"age_recommendation"`
+	Condition         string      `gorm:"size:50;not null;check:condition IN ('new', 'excellent', 'good', 'fair', 'poor')" json:"condition"`
+	RentalPrice       money.Money `gorm:"type:decimal(10,2);not null" json:"rental_price"`
+	WeeklyPrice       money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"weekly_price"`  // 0 berarti tanpa tarif mingguan
+	MonthlyPrice      money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"monthly_price"` // 0 berarti tanpa tarif bulanan
+	MinRentalDays     int         `gorm:"not null;default:1" json:"min_rental_days"`
+	LateFeePerDay     money.Money `gorm:"type:decimal(10,2);not null" json:"late_fee_per_day"`
+	ReplacementPrice  money.Money `gorm:"type:decimal(10,2);not null" json:"replacement_price"`
+	DepositAmount     money.Money `gorm:"type:decimal(10,2);not null;default:0" json:"deposit_amount"` // deposit per unit, 0 berarti tanpa deposit
+	IsAvailable       bool        `gorm:"default:true" json:"is_available"`
+	Stock             int         `gorm:"not null" json:"stock"` // jumlah unit tersedia, diturunkan dari toy_units
+	PrimaryImage      string      `gorm:"type:text" json:"primary_image"`
 
 	Categories  []ToyCategory `gorm:"many2many:toy_toy_categories" json:"categories"`
 	Images      []ToyImage    `gorm:"many2many:toy_toy_images" json:"images"`
 	RentalItems []RentalItem  `gorm:"foreignKey:ToyID" json:"-"`
+	Units       []ToyUnit     `gorm:"foreignKey:ToyID" json:"-"`
 }
 
 func (*Toy) TableName() string {
 	return "toys"
 }
 
+// ToyFilter menyaring katalog mainan. Dengan BranchID hanya mainan yang punya unit tersedia
+// di cabang tersebut yang ditampilkan dan stock berisi jumlah unit di cabang itu.
+type ToyFilter struct {
+	CategoryIDs []string
+	BranchID    string
+}
+
 func (t *Toy) Validate() []string {
 	err := validation.ValidateStruct(t,
 		validation.Field(&t.Categories,
@@ -59,18 +72,29 @@ func (t *Toy) Validate() []string {
 		),
 		validation.Field(&t.RentalPrice,
 			validation.Required.Error("Harga rental wajib diisi"),
-			validation.Min(0.0).Error("Harga rental tidak boleh negatif"),
+			validation.Min(money.Money(0)).Error("Harga rental tidak boleh negatif"),
+		),
+		validation.Field(&t.WeeklyPrice,
+			validation.Min(money.Money(0)).Error("Harga mingguan tidak boleh negatif"),
+		),
+		validation.Field(&t.MonthlyPrice,
+			validation.Min(money.Money(0)).Error("Harga bulanan tidak boleh negatif"),
+		),
+		validation.Field(&t.MinRentalDays,
+			validation.Min(0).Error("Minimal hari sewa tidak boleh negatif"),
 		),
 		validation.Field(&t.LateFeePerDay,
 			validation.Required.Error("Biaya keterlambatan per hari wajib diisi"),
-			validation.Min(0.0).Error("Biaya keterlambatan tidak boleh negatif"),
+			validation.Min(money.Money(0)).Error("Biaya keterlambatan tidak boleh negatif"),
 		),
 		validation.Field(&t.ReplacementPrice,
 			validation.Required.Error("Harga penggantian wajib diisi"),
-			validation.Min(0.0).Error("Harga penggantian tidak boleh negatif"),
+			validation.Min(money.Money(0)).Error("Harga penggantian tidak boleh negatif"),
+		),
+		validation.Field(&t.DepositAmount,
+			validation.Min(money.Money(0)).Error("Deposit tidak boleh negatif"),
 		),
 		validation.Field(&t.Stock,
-			validation.Required.Error("Stok wajib diisi"),
 			validation.Min(0).Error("Stok tidak boleh negatif"),
 		),
 	)
@@ -92,31 +116,38 @@ func (t *Toy) Validate() []string {
 }
 
 type ToyRequest struct {
-	Name              string   `json:"name" binding:"required"`
-	Description       string   `json:"description"`
-	AgeRecommendation string   `json:"age_recommendation"`
-	Condition         string   `json:"condition" binding:"required"`
-	RentalPrice       float64  `json:"rental_price" binding:"required"`
-	LateFeePerDay     float64  `json:"late_fee_per_day" binding:"required"`
-	ReplacementPrice  float64  `json:"replacement_price" binding:"required"`
-	IsAvailable       bool     `json:"is_available"`
-	Stock             int      `json:"stock" binding:"required"`
-	CategoryIDs       []string `json:"category_ids" binding:"required"`
-	ImageIDs          []string `json:"image_ids" binding:"required"`
-	PrimaryImageID    string   `json:"primary_image_id"`
+	Name              string      `json:"name" binding:"required"`
+	Description       string      `json:"description"`
+	AgeRecommendation string      `json:"age_recommendation"`
+	Condition         string      `json:"condition" binding:"required"`
+	RentalPrice       money.Money `json:"rental_price" binding:"required"`
+	WeeklyPrice       money.Money `json:"weekly_price"`
+	MonthlyPrice      money.Money `json:"monthly_price"`
+	MinRentalDays     int         `json:"min_rental_days"`
+	LateFeePerDay     money.Money `json:"late_fee_per_day" binding:"required"`
+	ReplacementPrice  money.Money `json:"replacement_price" binding:"required"`
+	DepositAmount     money.Money `json:"deposit_amount"`
+	IsAvailable       bool        `json:"is_available"`
+	Stock             int         `json:"stock" binding:"required"` // jumlah unit awal yang didaftarkan
+	CategoryIDs       []string    `json:"category_ids" binding:"required"`
+	ImageIDs          []string    `json:"image_ids" binding:"required"`
+	PrimaryImageID    string      `json:"primary_image_id"`
 }
 
 type ToyUpdateRequest struct {
-	Name              string   `json:"name" binding:"required"`
-	Description       string   `json:"description"`
-	AgeRecommendation string   `json:"age_recommendation"`
-	Condition         string   `json:"condition" binding:"required"`
-	RentalPrice       float64  `json:"rental_price" binding:"required"`
-	LateFeePerDay     float64  `json:"late_fee_per_day" binding:"required"`
-	ReplacementPrice  float64  `json:"replacement_price" binding:"required"`
-	IsAvailable       bool     `json:"is_available"`
-	Stock             int      `json:"stock" binding:"required"`
-	CategoryIDs       []string `json:"category_ids" binding:"required"`
-	ImageIDs          []string `json:"image_ids" binding:"required"`
-	PrimaryImageID    string   `json:"primary_image_id"`
+	Name              string      `json:"name" binding:"required"`
+	Description       string      `json:"description"`
+	AgeRecommendation string      `json:"age_recommendation"`
+	Condition         string      `json:"condition" binding:"required"`
+	RentalPrice       money.Money `json:"rental_price" binding:"required"`
+	WeeklyPrice       money.Money `json:"weekly_price"`
+	MonthlyPrice      money.Money `json:"monthly_price"`
+	MinRentalDays     int         `json:"min_rental_days"`
+	LateFeePerDay     money.Money `json:"late_fee_per_day" binding:"required"`
+	ReplacementPrice  money.Money `json:"replacement_price" binding:"required"`
+	DepositAmount     money.Money `json:"deposit_amount"`
+	IsAvailable       bool        `json:"is_available"`
+	CategoryIDs       []string    `json:"category_ids" binding:"required"`
+	ImageIDs          []string    `json:"image_ids" binding:"required"`
+	PrimaryImageID    string      `json:"primary_image_id"`
 }
@@ -1,28 +1,75 @@
 package entity
 
-import validation "github.com/go-ozzo/ozzo-validation/v4"
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+var (
+	ErrCategoryHasToys      = errors.New("kategori masih memiliki mainan, pindahkan ke kategori lain terlebih dahulu")
+	ErrCategoryHasChildren  = errors.New("kategori masih memiliki sub kategori, pindahkan ke kategori lain terlebih dahulu")
+	ErrCategoryInvalidMove  = errors.New("kategori tidak dapat dipindahkan ke dirinya sendiri atau ke sub kategorinya")
+	ErrCategorySlugConflict = errors.New("slug kategori sudah digunakan")
+)
 
 type ToyCategory struct {
 	BaseEntity
-	Name        string `gorm:"size:100;not null" json:"name"`
-	Description string `gorm:"type:text" json:"description"`
+	ParentID    *uuid.UUID `gorm:"type:uuid;index" json:"parent_id"`
+	Name        string     `gorm:"size:100;not null" json:"name"`
+	Slug        string     `gorm:"size:120;uniqueIndex:idx_toy_categories_slug,where:deleted_at IS NULL" json:"slug"`
+	Description string     `gorm:"type:text" json:"description"`
+	SortOrder   int        `gorm:"not null;default:0" json:"sort_order"`
 
-	Toys []Toy `gorm:"many2many:toy_toy_categories" json:"-"`
+	Parent   *ToyCategory  `gorm:"foreignKey:ParentID" json:"-" swaggerignore:"true"`
+	Children []ToyCategory `gorm:"foreignKey:ParentID" json:"children,omitempty" swaggerignore:"true"`
+	Toys     []Toy         `gorm:"many2many:toy_toy_categories" json:"-"`
 }
 
 func (*ToyCategory) TableName() string {
 	return "toy_categories"
 }
 
+// CategorySlug membuat kandidat slug ke-n dari slug nama kategori. Slug yang terlalu pendek, misalnya dari
+// nama yang hanya berisi simbol atau huruf non latin, diberi awalan "kategori". Kandidat kedua dan
+// seterusnya diberi akhiran angka untuk nama yang sama dengan kategori lain.
+func CategorySlug(base string, n int) string {
+	if len(base) < 3 {
+		base = strings.TrimSuffix("kategori-"+base, "-")
+	}
+
+	suffix := ""
+	if n > 1 {
+		suffix = "-" + strconv.Itoa(n)
+	}
+	if len(base)+len(suffix) > 120 {
+		base = strings.TrimRight(base[:120-len(suffix)], "-")
+	}
+	return base + suffix
+}
+
 func (c *ToyCategory) Validate() []string {
 	err := validation.ValidateStruct(c,
 		validation.Field(&c.Name,
 			validation.Required.Error("Nama kategori wajib diisi"),
-			validation.RuneLength(3, 25).Error("Nama kategori harus antara 3-25 karakter"),
+			validation.RuneLength(3, 100).Error("Nama kategori harus antara 3-100 karakter"),
+		),
+		validation.Field(&c.Slug,
+			validation.When(c.Slug != "",
+				validation.RuneLength(3, 120).Error("Slug harus antara 3-120 karakter"),
+				validation.Match(regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)).Error("Slug hanya boleh berisi huruf kecil, angka dan tanda hubung"),
+			),
 		),
 		validation.Field(&c.Description,
 			validation.When(c.Description != "", validation.RuneLength(10, 5000).Error("Deskripsi harus antara 10-5000 karakter")),
 		),
+		validation.Field(&c.SortOrder,
+			validation.Min(0).Error("Urutan tidak boleh negatif"),
+		),
 	)
 
 	if err == nil {
@@ -40,3 +87,21 @@ func (c *ToyCategory) Validate() []string {
 
 	return errorMessages
 }
+
+type ToyCategoryRequest struct {
+	ParentID    *uuid.UUID `json:"parent_id"`
+	Name        string     `json:"name" binding:"required"`
+	Slug        string     `json:"slug"`
+	Description string     `json:"description"`
+	SortOrder   int        `json:"sort_order"`
+}
+
+type ToyCategoryTree struct {
+	ID          uuid.UUID          `json:"id"`
+	ParentID    *uuid.UUID         `json:"parent_id"`
+	Name        string             `json:"name"`
+	Slug        string             `json:"slug"`
+	Description string             `json:"description"`
+	SortOrder   int                `json:"sort_order"`
+	Children    []*ToyCategoryTree `json:"children"`
+}
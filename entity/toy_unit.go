@@ -0,0 +1,185 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	ToyUnitStatusAvailable   = "available"
+	ToyUnitStatusRented      = "rented"
+	ToyUnitStatusMaintenance = "maintenance"
+	ToyUnitStatusRetired     = "retired"
+	ToyUnitStatusLost        = "lost"
+)
+
+const (
+	ConditionDamaged = "damaged"
+	ConditionLost    = "lost"
+)
+
+const (
+	ToyUnitEventRegistered       = "registered"
+	ToyUnitEventRented           = "rented"
+	ToyUnitEventReturned         = "returned"
+	ToyUnitEventStatusChanged    = "status_changed"
+	ToyUnitEventConditionChanged = "condition_changed"
+	ToyUnitEventMaintenance      = "maintenance"
+)
+
+var (
+	ErrInsufficientUnits  = errors.New("unit mainan yang tersedia tidak mencukupi")
+	ErrUnitRented         = errors.New("unit mainan sedang disewa dan tidak dapat diubah")
+	ErrSerialNumberExists = errors.New("nomor seri unit sudah digunakan")
+)
+
+// ConditionRank memberi nilai urut kondisi, semakin besar semakin baik
+var ConditionRank = map[string]int{
+	ConditionNew:       5,
+	ConditionExcellent: 4,
+	ConditionGood:      3,
+	ConditionFair:      2,
+	ConditionPoor:      1,
+}
+
+type ToyUnit struct {
+	BaseEntity
+	ToyID        uuid.UUID `gorm:"type:uuid;not null;index" json:"toy_id"`
+	SerialNumber string    `gorm:"size:100;not null;uniqueIndex:idx_toy_units_serial_number,where:deleted_at IS NULL" json:"serial_number"`
+	Barcode      string    `gorm:"size:100;index" json:"barcode"`
+	Condition    string    `gorm:"size:50;not null;check:condition IN ('new', 'excellent', 'good', 'fair', 'poor', 'damaged')" json:"condition"`
+	Status       string    `gorm:"size:50;not null;default:available;check:status IN ('available', 'rented', 'maintenance', 'retired', 'lost')" json:"status"`
+	Notes        string    `gorm:"type:text" json:"notes"`
+	// BranchID adalah lokasi unit. Selama TransferID terisi unit masih dalam perjalanan ke cabang ini.
+	BranchID   *uuid.UUID `gorm:"type:uuid;index" json:"branch_id"`
+	TransferID *uuid.UUID `gorm:"type:uuid;index" json:"transfer_id,omitempty"`
+
+	Toy Toy `gorm:"foreignKey:ToyID" json:"-" swaggerignore:"true"`
+}
+
+func (*ToyUnit) TableName() string {
+	return "toy_units"
+}
+
+// ToyUnitStateAfterReturn menentukan status dan kondisi unit berdasarkan kondisi saat dikembalikan.
+// Unit yang kembali selalu masuk antrean perawatan sebelum dapat disewakan lagi.
+// Kondisi kosong berarti kondisi unit tidak berubah.
+func ToyUnitStateAfterReturn(conditionAfter string) (status string, condition string) {
+	if conditionAfter == ConditionLost {
+		return ToyUnitStatusLost, ""
+	}
+	return ToyUnitStatusMaintenance, conditionAfter
+}
+
+func (u *ToyUnit) Validate() []string {
+	err := validation.ValidateStruct(u,
+		validation.Field(&u.SerialNumber,
+			validation.Required.Error("Nomor seri wajib diisi"),
+			validation.RuneLength(3, 100).Error("Nomor seri harus antara 3-100 karakter"),
+		),
+		validation.Field(&u.Barcode,
+			validation.RuneLength(0, 100).Error("Barcode maksimal 100 karakter"),
+		),
+		validation.Field(&u.Condition,
+			validation.Required.Error("Kondisi unit wajib diisi"),
+			validation.In(ConditionNew, ConditionExcellent, ConditionGood, ConditionFair, ConditionPoor, ConditionDamaged).
+				Error("Kondisi harus salah satu dari: new, excellent, good, fair, poor, atau damaged"),
+		),
+		validation.Field(&u.Status,
+			validation.Required.Error("Status unit wajib diisi"),
+			validation.In(ToyUnitStatusAvailable, ToyUnitStatusRented, ToyUnitStatusMaintenance, ToyUnitStatusRetired, ToyUnitStatusLost).
+				Error("Status harus salah satu dari: available, rented, maintenance, retired, atau lost"),
+		),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
+
+// RentalItemUnit mencatat unit fisik yang diserahkan pada sebuah item rental
+type RentalItemUnit struct {
+	BaseEntity
+	RentalItemID      uuid.UUID   `gorm:"type:uuid;not null;index" json:"rental_item_id"`
+	ToyUnitID         uuid.UUID   `gorm:"type:uuid;not null;index" json:"toy_unit_id"`
+	ConditionBefore   string      `gorm:"size:50;not null" json:"condition_before"`
+	ConditionAfter    string      `gorm:"size:50" json:"condition_after"`
+	DamageDescription string      `gorm:"type:text" json:"damage_description"`
+	DamageFee         money.Money `gorm:"type:decimal(10,2)" json:"damage_fee"`
+	ReturnedAt        *time.Time  `json:"returned_at"`
+
+	ToyUnit ToyUnit `gorm:"foreignKey:ToyUnitID" json:"toy_unit"`
+}
+
+func (*RentalItemUnit) TableName() string {
+	return "rental_item_units"
+}
+
+// ToyUnitLog adalah jejak perubahan status dan kondisi sebuah unit
+type ToyUnitLog struct {
+	BaseEntity
+	ToyUnitID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"toy_unit_id"`
+	RentalItemID  *uuid.UUID `gorm:"type:uuid" json:"rental_item_id"`
+	Event         string     `gorm:"size:50;not null" json:"event"`
+	StatusFrom    string     `gorm:"size:50" json:"status_from"`
+	StatusTo      string     `gorm:"size:50" json:"status_to"`
+	ConditionFrom string     `gorm:"size:50" json:"condition_from"`
+	ConditionTo   string     `gorm:"size:50" json:"condition_to"`
+	Notes         string     `gorm:"type:text" json:"notes"`
+	LoggedAt      time.Time  `gorm:"not null" json:"logged_at"`
+}
+
+func (*ToyUnitLog) TableName() string {
+	return "toy_unit_logs"
+}
+
+type RegisterToyUnitsRequest struct {
+	Quantity      int      `json:"quantity"`
+	SerialNumbers []string `json:"serial_numbers"`
+	Condition     string   `json:"condition"`
+	Notes         string   `json:"notes"`
+	// Cabang tempat unit disimpan, default cabang utama
+	BranchID *uuid.UUID `json:"branch_id"`
+}
+
+type UpdateToyUnitRequest struct {
+	Barcode   string `json:"barcode"`
+	Condition string `json:"condition" binding:"required"`
+	Status    string `json:"status" binding:"required"`
+	Notes     string `json:"notes"`
+}
+
+type ToyUnitRentalHistory struct {
+	RentalID          uuid.UUID   `json:"rental_id"`
+	RentalItemID      uuid.UUID   `json:"rental_item_id"`
+	UserID            uuid.UUID   `json:"user_id"`
+	CustomerName      string      `json:"customer_name"`
+	RentalStatus      string      `json:"rental_status"`
+	RentalDate        time.Time   `json:"rental_date"`
+	ReturnedAt        *time.Time  `json:"returned_at"`
+	ConditionBefore   string      `json:"condition_before"`
+	ConditionAfter    string      `json:"condition_after"`
+	DamageDescription string      `json:"damage_description"`
+	DamageFee         money.Money `json:"damage_fee"`
+}
+
+type ToyUnitHistory struct {
+	Unit    ToyUnit                `json:"unit"`
+	Rentals []ToyUnitRentalHistory `json:"rentals"`
+	Logs    []ToyUnitLog           `json:"logs"`
+}
@@ -3,7 +3,7 @@ package entity
 import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
-	_ "github.com/gofrs/uuid/v5"
+	"github.com/gofrs/uuid/v5"
 	"regexp"
 )
 
@@ -22,6 +22,8 @@ type User struct {
 	Address     string `gorm:"type:text" json:"address"`
 	IsActive    bool   `gorm:"default:true" json:"is_active"`
 	Role        string `gorm:"size:20;not null;default:customer;check:role IN ('admin', 'customer')" json:"role"`
+	// BranchID membatasi admin pada satu cabang, kosong berarti admin pusat
+	BranchID *uuid.UUID `gorm:"type:uuid;index" json:"branch_id"`
 
 	Rentals    []Rental    `gorm:"foreignKey:UserID" json:"-"`
 	UserTokens []UserToken `gorm:"foreignKey:UserID" json:"-"`
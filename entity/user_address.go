@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+var ErrAddressNotFound = errors.New("alamat tidak ditemukan")
+
+// UserAddress adalah alamat pada buku alamat pelanggan yang dipakai untuk antar jemput mainan
+type UserAddress struct {
+	BaseEntity
+	UserID        uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
+	Label         string    `gorm:"size:50;not null" json:"label"` // contoh: Rumah, Kantor
+	RecipientName string    `gorm:"size:100;not null" json:"recipient_name"`
+	PhoneNumber   string    `gorm:"size:20;not null" json:"phone_number"`
+	Street        string    `gorm:"type:text;not null" json:"street"`
+	City          string    `gorm:"size:100;not null" json:"city"`
+	PostalCode    string    `gorm:"size:10;not null;index" json:"postal_code"`
+	Latitude      *float64  `gorm:"type:decimal(9,6)" json:"latitude"`
+	Longitude     *float64  `gorm:"type:decimal(9,6)" json:"longitude"`
+	Notes         string    `gorm:"type:text" json:"notes"` // patokan untuk kurir
+	IsDefault     bool      `gorm:"not null;default:false" json:"is_default"`
+}
+
+func (*UserAddress) TableName() string {
+	return "user_addresses"
+}
+
+func (a *UserAddress) Validate() []string {
+	err := validation.ValidateStruct(a,
+		validation.Field(&a.Label,
+			validation.Required.Error("Label alamat wajib diisi"),
+			validation.RuneLength(1, 50).Error("Label alamat maksimal 50 karakter"),
+		),
+		validation.Field(&a.RecipientName,
+			validation.Required.Error("Nama penerima wajib diisi"),
+			validation.RuneLength(3, 100).Error("Nama penerima harus antara 3-100 karakter"),
+		),
+		validation.Field(&a.PhoneNumber,
+			validation.Required.Error("Nomor telepon penerima wajib diisi"),
+			validation.Match(regexp.MustCompile(`^[0-9+\-\s]{8,20}$`)).Error("Nomor telepon harus 8-20 karakter berisi angka, +, - dan spasi"),
+		),
+		validation.Field(&a.Street,
+			validation.Required.Error("Alamat wajib diisi"),
+			validation.RuneLength(5, 1000).Error("Alamat harus antara 5-1000 karakter"),
+		),
+		validation.Field(&a.City,
+			validation.Required.Error("Kota wajib diisi"),
+			validation.RuneLength(2, 100).Error("Kota harus antara 2-100 karakter"),
+		),
+		validation.Field(&a.PostalCode,
+			validation.Required.Error("Kode pos wajib diisi"),
+			validation.Match(regexp.MustCompile(`^[0-9]{5}$`)).Error("Kode pos harus 5 digit angka"),
+		),
+		validation.Field(&a.Latitude,
+			validation.When(a.Longitude != nil, validation.NotNil.Error("Latitude wajib diisi bersama longitude")),
+			validation.Min(-90.0).Error("Latitude harus antara -90 dan 90"),
+			validation.Max(90.0).Error("Latitude harus antara -90 dan 90"),
+		),
+		validation.Field(&a.Longitude,
+			validation.When(a.Latitude != nil, validation.NotNil.Error("Longitude wajib diisi bersama latitude")),
+			validation.Min(-180.0).Error("Longitude harus antara -180 dan 180"),
+			validation.Max(180.0).Error("Longitude harus antara -180 dan 180"),
+		),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
+
+// FullAddress menggabungkan alamat menjadi satu baris untuk catatan tugas kurir
+func (a *UserAddress) FullAddress() string {
+	return strings.Join([]string{a.Street, a.City, a.PostalCode}, ", ")
+}
+
+type UserAddressRequest struct {
+	Label         string   `json:"label" binding:"required"`
+	RecipientName string   `json:"recipient_name" binding:"required"`
+	PhoneNumber   string   `json:"phone_number" binding:"required"`
+	Street        string   `json:"street" binding:"required"`
+	City          string   `json:"city" binding:"required"`
+	PostalCode    string   `json:"postal_code" binding:"required"`
+	Latitude      *float64 `json:"latitude"`
+	Longitude     *float64 `json:"longitude"`
+	Notes         string   `json:"notes"`
+	// Jadikan alamat utama, alamat pertama selalu menjadi alamat utama
+	IsDefault bool `json:"is_default"`
+}
@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"errors"
+	"final-project/utils/money"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	WalletEntryCredit = "credit"
+	WalletEntryDebit  = "debit"
+)
+
+const (
+	WalletReasonDepositRefund = "deposit_refund"
+	WalletReasonOverpayment   = "overpayment"
+	WalletReasonGoodwill      = "goodwill"
+	WalletReasonRentalPayment = "rental_payment"
+	WalletReasonAdjustment    = "adjustment"
+)
+
+const (
+	PaymentMethodWallet = "wallet"
+	PriceLineWallet     = "wallet"
+)
+
+var ErrInsufficientWalletBalance = errors.New("saldo dompet tidak mencukupi")
+
+// Wallet menyimpan saldo dompet pengguna. Saldo hanya berubah bersama penambahan WalletEntry
+// di dalam transaksi yang mengunci baris dompet.
+type Wallet struct {
+	BaseEntity
+	UserID  uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	Balance money.Money `gorm:"type:decimal(12,2);not null;default:0;check:balance >= 0" json:"balance"`
+}
+
+func (*Wallet) TableName() string {
+	return "wallets"
+}
+
+// WalletEntry adalah catatan mutasi dompet yang tidak pernah diubah atau dihapus
+type WalletEntry struct {
+	BaseEntity
+	WalletID     uuid.UUID   `gorm:"type:uuid;not null;index" json:"wallet_id"`
+	UserID       uuid.UUID   `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type         string      `gorm:"size:10;not null;check:type IN ('credit', 'debit')" json:"type"`
+	Amount       money.Money `gorm:"type:decimal(12,2);not null;check:amount > 0" json:"amount"`
+	BalanceAfter money.Money `gorm:"type:decimal(12,2);not null" json:"balance_after"`
+	Reason       string      `gorm:"size:50;not null;check:reason IN ('deposit_refund', 'overpayment', 'goodwill', 'rental_payment', 'adjustment')" json:"reason"`
+	RentalID     *uuid.UUID  `gorm:"type:uuid;index" json:"rental_id,omitempty"`
+	PaymentID    *uuid.UUID  `gorm:"type:uuid;index" json:"payment_id,omitempty"`
+	Notes        string      `gorm:"type:text" json:"notes"`
+	CreatedBy    *uuid.UUID  `gorm:"type:uuid" json:"created_by,omitempty"` // admin yang membuat penyesuaian
+}
+
+func (*WalletEntry) TableName() string {
+	return "wallet_entries"
+}
+
+// SignedAmount mengembalikan nominal bertanda, negatif untuk debit
+func (e *WalletEntry) SignedAmount() money.Money {
+	if e.Type == WalletEntryDebit {
+		return -e.Amount
+	}
+	return e.Amount
+}
+
+type WalletAdjustmentRequest struct {
+	Type   string      `json:"type" binding:"required"`
+	Amount money.Money `json:"amount" binding:"required"`
+	// Alasan penyesuaian: goodwill atau adjustment
+	Reason string `json:"reason" binding:"required"`
+	Notes  string `json:"notes" binding:"required"`
+}
+
+func (r *WalletAdjustmentRequest) Validate() []string {
+	err := validation.ValidateStruct(r,
+		validation.Field(&r.Type,
+			validation.Required.Error("Jenis mutasi wajib diisi"),
+			validation.In(WalletEntryCredit, WalletEntryDebit).Error("Jenis mutasi harus credit atau debit"),
+		),
+		validation.Field(&r.Amount,
+			validation.Required.Error("Nominal wajib diisi"),
+			validation.Min(money.Money(1)).Error("Nominal minimal 1"),
+		),
+		validation.Field(&r.Reason,
+			validation.Required.Error("Alasan wajib diisi"),
+			validation.In(WalletReasonGoodwill, WalletReasonAdjustment).Error("Alasan harus goodwill atau adjustment"),
+		),
+		validation.Field(&r.Notes,
+			validation.Required.Error("Catatan wajib diisi"),
+		),
+	)
+
+	if err == nil {
+		return nil
+	}
+
+	var errorMessages []string
+	if validationErrors, ok := err.(validation.Errors); ok {
+		for _, fieldErr := range validationErrors {
+			errorMessages = append(errorMessages, fieldErr.Error())
+		}
+	} else {
+		errorMessages = append(errorMessages, err.Error())
+	}
+
+	return errorMessages
+}
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"final-project/config"
 	_ "final-project/docs"
+	"final-project/repository"
+	"final-project/service"
+	"final-project/utils/cache"
+	"final-project/utils/cron"
 	"final-project/utils/helpers"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
+	_ "time/tzdata"
 )
 
 // @title           ToyRental API
@@ -39,13 +47,82 @@ func main() {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Slug untuk kategori yang dibuat sebelum kategori memiliki slug
+	if filled, err := repository.NewToyCategoryRepository(db.DB).BackfillSlugs(context.Background()); err != nil {
+		log.Fatalf("Failed to backfill category slugs: %v", err)
+	} else if filled > 0 {
+		log.Printf("Backfilled %d category slugs", filled)
+	}
+
+	// Daftarkan unit untuk mainan lama berdasarkan kolom stock
+	if created, err := repository.NewToyUnitRepository(db.DB).BackfillFromStock(context.Background()); err != nil {
+		log.Fatalf("Failed to backfill toy units: %v", err)
+	} else if created > 0 {
+		log.Printf("Backfilled %d toy units from stock", created)
+	}
+
+	// Kebijakan biaya awal untuk database yang belum memilikinya
+	if seeded, err := repository.NewFeePolicyRepository(db.DB).SeedDefault(context.Background()); err != nil {
+		log.Fatalf("Failed to seed fee policy: %v", err)
+	} else if seeded {
+		log.Println("Seeded default fee policy")
+	}
+
+	// Cabang utama untuk unit dan rental dari sebelum multi cabang
+	branchRepo := repository.NewBranchRepository(db.DB)
+	if seeded, err := branchRepo.SeedDefault(context.Background()); err != nil {
+		log.Fatalf("Failed to seed default branch: %v", err)
+	} else if seeded {
+		log.Println("Seeded default branch")
+	}
+	if placed, err := branchRepo.BackfillLocations(context.Background()); err != nil {
+		log.Fatalf("Failed to backfill branch locations: %v", err)
+	} else if placed > 0 {
+		log.Printf("Placed %d toy units in the default branch", placed)
+	}
+
+	// Cache laporan dipakai bersama route dan scheduler
+	reportCache := cache.NewTTL(time.Duration(cfg.ReportCacheTTLSeconds) * time.Second)
+
 	// Setup routes
-	r := setupRoutes(cfg, db.DB)
+	r := setupRoutes(cfg, db.DB, reportCache)
 	srv := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
 		Handler: r,
 	}
 
+	// Scheduler laporan email dan pembangunan ulang rollup laporan berjalan di proses server yang sama
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	var schedulerDone sync.WaitGroup
+	location := reportLocation(cfg)
+	reportRollupRepo := repository.NewReportRollupRepository(db.DB, location)
+	if cfg.ReportSchedulerEnabled {
+		businessReportSvc := service.NewBusinessReportService(repository.NewBusinessReportRepository(db.DB, location), reportRollupRepo, reportCache, location)
+		reportSubscriptionSvc := newReportSubscriptionService(cfg, db.DB, businessReportSvc, branchRepo)
+
+		schedulerDone.Add(1)
+		go func() {
+			defer schedulerDone.Done()
+			reportSubscriptionSvc.StartScheduler(schedulerCtx, time.Minute)
+		}()
+		log.Println("Report scheduler started")
+	}
+
+	if cfg.ReportRollupSchedule != "" {
+		schedule, err := cron.Parse(cfg.ReportRollupSchedule)
+		if err != nil {
+			log.Fatalf("Invalid report rollup schedule: %v", err)
+		}
+		reportRollupSvc := service.NewReportRollupService(reportRollupRepo, reportCache, cfg.ReportRollupWindowDays, location)
+
+		schedulerDone.Add(1)
+		go func() {
+			defer schedulerDone.Done()
+			reportRollupSvc.StartRebuildScheduler(schedulerCtx, schedule)
+		}()
+		log.Println("Report rollup scheduler started")
+	}
+
 	// Buat channel untuk menangkap signal interupsi
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -62,6 +139,11 @@ func main() {
 	<-quit
 	log.Println("Shutting down server...")
 
+	// Tunggu pengiriman laporan dan pembangunan ulang rollup yang sedang berjalan selesai sebelum
+	// koneksi database ditutup
+	stopScheduler()
+	schedulerDone.Wait()
+
 	// Tutup koneksi database
 	db.CloseConnection()
 
@@ -0,0 +1,364 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IBranchRepository interface {
+	IBaseRepository[entity.Branch]
+	FindActive(ctx context.Context) ([]entity.Branch, error)
+	FindByCode(ctx context.Context, code string) (entity.Branch, error)
+	FindDefault(ctx context.Context) (entity.Branch, error)
+	CountUnits(ctx context.Context, branchID string) (int64, error)
+	CountAvailableUnits(ctx context.Context, branchID string, toyID string) (int64, error)
+	FindToyAvailability(ctx context.Context, toyID string) ([]entity.ToyBranchStock, error)
+	FindTransfers(ctx context.Context, filter entity.BranchTransferFilter, limit int, offset int) ([]entity.BranchTransfer, int64, error)
+	FindTransferByID(ctx context.Context, id string) (entity.BranchTransfer, error)
+	CreateTransfer(ctx context.Context, transfer *entity.BranchTransfer, unitIDs []uuid.UUID) error
+	ReceiveTransfer(ctx context.Context, transfer *entity.BranchTransfer) error
+	CancelTransfer(ctx context.Context, transfer *entity.BranchTransfer) error
+	SeedDefault(ctx context.Context) (bool, error)
+	BackfillLocations(ctx context.Context) (int64, error)
+}
+
+type BranchRepository struct {
+	BaseRepository[entity.Branch]
+}
+
+func NewBranchRepository(db *gorm.DB) IBranchRepository {
+	return &BranchRepository{
+		BaseRepository: BaseRepository[entity.Branch]{DB: db},
+	}
+}
+
+func (r *BranchRepository) FindAll(ctx context.Context, limit int, offset int) ([]entity.Branch, int64, error) {
+	var branches []entity.Branch
+	if err := r.DB.WithContext(ctx).Order("code ASC").
+		Limit(limit).Offset(offset).Find(&branches).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalData int64
+	if err := r.DB.WithContext(ctx).Model(&entity.Branch{}).Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+	return branches, totalData, nil
+}
+
+func (r *BranchRepository) UpdateById(ctx context.Context, id string, branch *entity.Branch) error {
+	return r.DB.WithContext(ctx).Model(&entity.Branch{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"code":          branch.Code,
+		"name":          branch.Name,
+		"address":       branch.Address,
+		"city":          branch.City,
+		"phone_number":  branch.PhoneNumber,
+		"opening_hours": branch.OpeningHours,
+		"latitude":      branch.Latitude,
+		"longitude":     branch.Longitude,
+		"is_active":     branch.IsActive,
+	}).Error
+}
+
+func (r *BranchRepository) FindActive(ctx context.Context) ([]entity.Branch, error) {
+	var branches []entity.Branch
+	if err := r.DB.WithContext(ctx).Where("is_active = ?", true).
+		Order("code ASC").Find(&branches).Error; err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+func (r *BranchRepository) FindByCode(ctx context.Context, code string) (entity.Branch, error) {
+	var branch entity.Branch
+	if err := r.DB.WithContext(ctx).Where("code = ?", code).First(&branch).Error; err != nil {
+		return branch, err
+	}
+	return branch, nil
+}
+
+func (r *BranchRepository) FindDefault(ctx context.Context) (entity.Branch, error) {
+	return r.FindByCode(ctx, entity.DefaultBranchCode)
+}
+
+// CountUnits menghitung unit yang masih tercatat di cabang, termasuk yang sedang dikirim ke cabang ini
+func (r *BranchRepository) CountUnits(ctx context.Context, branchID string) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&entity.ToyUnit{}).
+		Where("branch_id = ? AND status NOT IN ?", branchID, []string{entity.ToyUnitStatusRetired, entity.ToyUnitStatusLost}).
+		Count(&count).Error
+	return count, err
+}
+
+// CountAvailableUnits menghitung unit sebuah mainan yang siap disewakan di cabang
+func (r *BranchRepository) CountAvailableUnits(ctx context.Context, branchID string, toyID string) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&entity.ToyUnit{}).
+		Where("branch_id = ? AND toy_id = ? AND status = ? AND transfer_id IS NULL", branchID, toyID, entity.ToyUnitStatusAvailable).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *BranchRepository) FindToyAvailability(ctx context.Context, toyID string) ([]entity.ToyBranchStock, error) {
+	var items []entity.ToyBranchStock
+
+	query := `
+		SELECT
+			b.id AS branch_id,
+			b.code AS branch_code,
+			b.name AS branch_name,
+			COUNT(u.id) FILTER (WHERE u.status = ? AND u.transfer_id IS NULL) AS available,
+			COUNT(u.id) FILTER (WHERE u.transfer_id IS NOT NULL) AS in_transit
+		FROM
+			branches b
+		LEFT JOIN
+			toy_units u ON u.branch_id = b.id AND u.toy_id = ? AND u.deleted_at IS NULL
+		WHERE
+			b.deleted_at IS NULL
+			AND b.is_active = TRUE
+		GROUP BY
+			b.id, b.code, b.name
+		ORDER BY
+			b.code ASC
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, entity.ToyUnitStatusAvailable, toyID).Scan(&items).Error
+	return items, err
+}
+
+func (r *BranchRepository) FindTransfers(ctx context.Context, filter entity.BranchTransferFilter, limit int, offset int) ([]entity.BranchTransfer, int64, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.BranchTransfer{})
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.BranchID != "" {
+		query = query.Where("from_branch_id = ? OR to_branch_id = ?", filter.BranchID, filter.BranchID)
+	}
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var transfers []entity.BranchTransfer
+	if err := query.Preload("FromBranch").Preload("ToBranch").
+		Order("shipped_at DESC").
+		Limit(limit).Offset(offset).Find(&transfers).Error; err != nil {
+		return nil, 0, err
+	}
+	return transfers, totalData, nil
+}
+
+func (r *BranchRepository) FindTransferByID(ctx context.Context, id string) (entity.BranchTransfer, error) {
+	var transfer entity.BranchTransfer
+	if err := r.DB.WithContext(ctx).
+		Preload("FromBranch").
+		Preload("ToBranch").
+		Preload("Units").
+		Preload("Units.ToyUnit").
+		Where("id = ?", id).
+		First(&transfer).Error; err != nil {
+		return transfer, err
+	}
+	return transfer, nil
+}
+
+// CreateTransfer mengirim unit yang tersedia di cabang asal. Unit langsung tercatat di cabang tujuan
+// dengan transfer_id terisi sehingga tidak ikut dialokasikan ke rental maupun dihitung sebagai stok.
+func (r *BranchRepository) CreateTransfer(ctx context.Context, transfer *entity.BranchTransfer, unitIDs []uuid.UUID) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var units []entity.ToyUnit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", unitIDs).
+			Find(&units).Error; err != nil {
+			return err
+		}
+
+		if len(units) != len(unitIDs) {
+			return entity.ErrUnitNotAtBranch
+		}
+		for _, unit := range units {
+			if unit.BranchID == nil || *unit.BranchID != transfer.FromBranchID ||
+				unit.TransferID != nil || unit.Status != entity.ToyUnitStatusAvailable {
+				return entity.ErrUnitNotAtBranch
+			}
+		}
+
+		if err := tx.Omit("FromBranch", "ToBranch", "Units").Create(transfer).Error; err != nil {
+			return err
+		}
+
+		toyIDs := make(map[uuid.UUID]bool)
+		for _, unit := range units {
+			if err := tx.Omit("ToyUnit").Create(&entity.BranchTransferUnit{
+				BranchTransferID: transfer.ID,
+				ToyUnitID:        unit.ID,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Model(&entity.ToyUnit{}).Where("id = ?", unit.ID).Updates(map[string]interface{}{
+				"branch_id":   transfer.ToBranchID,
+				"transfer_id": transfer.ID,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := createTransferLog(tx, unit, "Dikirim ke cabang lain"); err != nil {
+				return err
+			}
+			toyIDs[unit.ToyID] = true
+		}
+
+		for toyID := range toyIDs {
+			if err := syncToyStock(tx, toyID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReceiveTransfer menandai transfer diterima sehingga unitnya dapat disewakan di cabang tujuan
+func (r *BranchRepository) ReceiveTransfer(ctx context.Context, transfer *entity.BranchTransfer) error {
+	return r.closeTransfer(ctx, transfer, map[string]interface{}{
+		"status":      entity.BranchTransferStatusReceived,
+		"received_at": transfer.ReceivedAt,
+		"received_by": transfer.ReceivedBy,
+	}, transfer.ToBranchID, "Diterima di cabang tujuan")
+}
+
+// CancelTransfer mengembalikan unit yang belum diterima ke cabang asal
+func (r *BranchRepository) CancelTransfer(ctx context.Context, transfer *entity.BranchTransfer) error {
+	return r.closeTransfer(ctx, transfer, map[string]interface{}{
+		"status":       entity.BranchTransferStatusCancelled,
+		"cancelled_at": transfer.CancelledAt,
+	}, transfer.FromBranchID, "Transfer dibatalkan, kembali ke cabang asal")
+}
+
+func (r *BranchRepository) closeTransfer(ctx context.Context, transfer *entity.BranchTransfer, updates map[string]interface{}, branchID uuid.UUID, notes string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.BranchTransfer{}).
+			Where("id = ? AND status = ?", transfer.ID, entity.BranchTransferStatusInTransit).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrTransferNotInTransit
+		}
+
+		var units []entity.ToyUnit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("transfer_id = ?", transfer.ID).
+			Find(&units).Error; err != nil {
+			return err
+		}
+
+		toyIDs := make(map[uuid.UUID]bool)
+		for _, unit := range units {
+			if err := tx.Model(&entity.ToyUnit{}).Where("id = ?", unit.ID).Updates(map[string]interface{}{
+				"branch_id":   branchID,
+				"transfer_id": nil,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := createTransferLog(tx, unit, notes); err != nil {
+				return err
+			}
+			toyIDs[unit.ToyID] = true
+		}
+
+		for toyID := range toyIDs {
+			if err := syncToyStock(tx, toyID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func createTransferLog(tx *gorm.DB, unit entity.ToyUnit, notes string) error {
+	return tx.Create(&entity.ToyUnitLog{
+		ToyUnitID:     unit.ID,
+		Event:         entity.ToyUnitEventTransferred,
+		StatusFrom:    unit.Status,
+		StatusTo:      unit.Status,
+		ConditionFrom: unit.Condition,
+		ConditionTo:   unit.Condition,
+		Notes:         notes,
+		LoggedAt:      time.Now(),
+	}).Error
+}
+
+// SeedDefault membuat cabang utama jika belum ada cabang sama sekali
+func (r *BranchRepository) SeedDefault(ctx context.Context) (bool, error) {
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&entity.Branch{}).Unscoped().Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	branch := entity.Branch{
+		Code:     entity.DefaultBranchCode,
+		Name:     "Cabang Utama",
+		Address:  "Alamat cabang utama belum diatur",
+		IsActive: true,
+	}
+	if err := r.DB.WithContext(ctx).Create(&branch).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// BackfillLocations menempatkan unit dan rental dari sebelum multi cabang di cabang utama
+func (r *BranchRepository) BackfillLocations(ctx context.Context) (int64, error) {
+	branch, err := r.FindDefault(ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var updated int64
+	err = r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.ToyUnit{}).Unscoped().
+			Where("branch_id IS NULL").
+			Update("branch_id", branch.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		updated = result.RowsAffected
+
+		return tx.Model(&entity.Rental{}).Unscoped().
+			Where("pickup_branch_id IS NULL").
+			Updates(map[string]interface{}{
+				"pickup_branch_id": branch.ID,
+				"return_branch_id": gorm.Expr("COALESCE(return_branch_id, ?)", branch.ID),
+			}).Error
+	})
+	return updated, err
+}
+
+// defaultBranchID mengembalikan cabang utama untuk unit baru, nil bila cabang utama belum ada
+func defaultBranchID(tx *gorm.DB) (*uuid.UUID, error) {
+	var branch entity.Branch
+	if err := tx.Where("code = ?", entity.DefaultBranchCode).First(&branch).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &branch.ID, nil
+}
@@ -3,137 +3,313 @@ package repository
 import (
 	"context"
 	"final-project/entity"
+	"final-project/utils/money"
+	"strings"
 	"time"
 
-	_ "github.com/gofrs/uuid/v5"
+	"github.com/gofrs/uuid/v5"
 	"gorm.io/gorm"
 )
 
 type IBusinessReportRepository interface {
-	GetSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]entity.SalesReportItem, error)
-	GetPopularToys(ctx context.Context, startDate, endDate time.Time, limit int) ([]entity.PopularToyItem, error)
-	GetTopCustomers(ctx context.Context, startDate, endDate time.Time, limit int) ([]entity.TopCustomerItem, error)
-	GetRentalStatusCount(ctx context.Context, startDate, endDate time.Time) ([]entity.RentalStatusItem, error)
+	GetSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string, branchID string) ([]entity.SalesReportItem, error)
+	GetCashSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string, branchID string) ([]entity.CashSalesReportItem, error)
+	GetPopularToys(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.PopularToyItem, error)
+	GetTopCustomers(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.TopCustomerItem, error)
+	GetCohortActivity(ctx context.Context, startDate, endDate time.Time, months int, branchID string) ([]entity.CohortActivity, error)
+	GetCustomerValues(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.CustomerValueItem, error)
+	GetCustomerRetentionSummary(ctx context.Context, startDate, endDate time.Time, branchID string) (entity.CustomerRetentionSummary, error)
+	GetRentalStatusCount(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.RentalStatusItem, error)
+	GetCategoryPerformance(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.CategorySummary, error)
+	GetToyUtilization(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.ToyUtilizationItem, error)
+	GetCategoryUtilization(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.CategoryUtilizationItem, error)
+	GetIdleToys(ctx context.Context, startDate, endDate time.Time, idleDays int, branchID string) ([]entity.ToyUtilizationItem, error)
+	GetKPISummary(ctx context.Context, startDate, endDate time.Time, branchID string) (entity.KPISummary, error)
+	GetUtilizationRate(ctx context.Context, startDate, endDate time.Time, branchID string) (float64, error)
+	GetDamageSummary(ctx context.Context, startDate, endDate time.Time, branchID string) (entity.DamageSummary, error)
+	GetDamageByToy(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.DamageToyItem, error)
+	GetDamageByCategory(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.DamageCategoryItem, error)
+	GetDamageByCustomer(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.DamageCustomerItem, error)
+	GetDamageFeeRentals(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.DamageFeeRental, error)
+	GetInventoryStatus(ctx context.Context, filter entity.InventoryStatusFilter, branchID string) ([]entity.InventoryStatusItem, error)
+	GetOverdueRentals(ctx context.Context, asOf time.Time, branchID string) ([]entity.OverdueRentalItem, error)
 }
 
 type BusinessReportRepository struct {
-	DB *gorm.DB
+	DB       *gorm.DB
+	timeZone string
 }
 
-func NewBusinessReportRepository(db *gorm.DB) IBusinessReportRepository {
+// NewBusinessReportRepository membuat repository laporan. Penjualan per tanggal dikelompokkan dalam
+// zona waktu location, sama dengan tanggal rollup.
+func NewBusinessReportRepository(db *gorm.DB, location *time.Location) IBusinessReportRepository {
 	return &BusinessReportRepository{
-		DB: db,
+		DB:       db,
+		timeZone: sqlTimeZone(location),
 	}
 }
 
-func (r *BusinessReportRepository) GetSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]entity.SalesReportItem, error) {
+func (r *BusinessReportRepository) GetSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string, branchID string) ([]entity.SalesReportItem, error) {
 	var items []entity.SalesReportItem
-	var query string
+	branchClause, branchArgs := rentalBranchFilter("r.", branchID)
 
+	rentalDate := "(r.rental_date AT TIME ZONE " + r.timeZone + ")"
+	dateExpr := "TO_CHAR(" + rentalDate + ", 'YYYY-MM-DD')"
 	switch groupBy {
-	case "day":
-		query = `
+	case "week":
+		dateExpr = "TO_CHAR(DATE_TRUNC('week', " + rentalDate + "), 'YYYY-MM-DD')"
+	case "month":
+		dateExpr = "TO_CHAR(" + rentalDate + ", 'YYYY-MM')"
+	}
+
+	query := `
+		SELECT 
+			` + dateExpr + ` as date,
+			COUNT(r.id) as rental_count,
+			SUM(r.total_rental_price) as rental_revenue,
+			SUM(COALESCE(r.late_fee, 0)) as late_fee_revenue,
+			SUM(COALESCE(r.damage_fee, 0)) as damage_fee_revenue,
+			SUM(r.total_rental_price + COALESCE(r.late_fee, 0) + COALESCE(r.damage_fee, 0)) as total_revenue,
+			SUM(COALESCE(r.deposit_amount, 0)) as deposit_amount,
+			COUNT(DISTINCT r.id) as transaction_count
+		FROM 
+			rentals r
+		WHERE 
+			r.rental_date BETWEEN ? AND ?
+			AND r.deleted_at IS NULL` + branchClause + `
+		GROUP BY 
+			` + dateExpr + `
+		ORDER BY 
+			date ASC
+	`
+
+	args := append([]interface{}{startDate, endDate}, branchArgs...)
+	if err := r.DB.WithContext(ctx).Raw(query, args...).Scan(&items).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.applySalesTax(ctx, items, dateExpr, startDate, endDate, branchID); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// applySalesTax memecah pendapatan setiap periode menjadi DPP dan PPN. Perhitungannya per komponen tagihan
+// setiap rental dengan entity.TaxPolicy agar pembulatannya sama persis dengan sisa tagihan dan jurnal.
+func (r *BusinessReportRepository) applySalesTax(ctx context.Context, items []entity.SalesReportItem, dateExpr string, startDate, endDate time.Time, branchID string) error {
+	branchClause, branchArgs := rentalBranchFilter("r.", branchID)
+
+	var rentals []struct {
+		Date             string
+		TotalRentalPrice money.Money
+		LateFee          money.Money
+		DamageFee        money.Money
+		TaxRate          float64
+		TaxInclusive     bool
+	}
+
+	query := `
+		SELECT
+			` + dateExpr + ` as date,
+			r.total_rental_price,
+			COALESCE(r.late_fee, 0) as late_fee,
+			COALESCE(r.damage_fee, 0) as damage_fee,
+			r.tax_rate,
+			r.tax_inclusive
+		FROM
+			rentals r
+		WHERE
+			r.rental_date BETWEEN ? AND ?
+			AND r.deleted_at IS NULL` + branchClause + `
+	`
+	args := append([]interface{}{startDate, endDate}, branchArgs...)
+	if err := r.DB.WithContext(ctx).Raw(query, args...).Scan(&rentals).Error; err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(items))
+	for i := range items {
+		index[items[i].Date] = i
+	}
+
+	for _, rental := range rentals {
+		i, ok := index[rental.Date]
+		if !ok {
+			continue
+		}
+
+		tax := entity.TaxPolicy{Rate: rental.TaxRate, Inclusive: rental.TaxInclusive}
+		for _, component := range []money.Money{rental.TotalRentalPrice, rental.LateFee, rental.DamageFee} {
+			gross, componentTax := tax.Apply(component)
+			items[i].NetRevenue += gross - componentTax
+			items[i].TaxAmount += componentTax
+			items[i].GrossRevenue += gross
+		}
+	}
+	return nil
+}
+
+// GetCashSalesReport mengelompokkan kas masuk menurut waktu transaksi, jenis dan metode pembayaran.
+// Pembayaran yang kemudian di-refund tetap tercatat pada tanggal lunasnya dan refund-nya menjadi
+// pengurang sebesar kas yang keluar pada jurnal refund di tanggal jurnal tersebut, sehingga laporan
+// periode lama tidak berubah. Deposit dan PPN pada refund sebagian dihitung proporsional. Pengembalian deposit
+// lewat payment gateway ikut mengurangi kas pada pembayaran yang menagih deposit tersebut.
+func (r *BusinessReportRepository) GetCashSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string, branchID string) ([]entity.CashSalesReportItem, error) {
+	var items []entity.CashSalesReportItem
+
+	movedAt := "(moved_at AT TIME ZONE " + r.timeZone + ")"
+	dateExpr := "TO_CHAR(" + movedAt + ", 'YYYY-MM-DD')"
+	switch groupBy {
+	case "week":
+		dateExpr = "TO_CHAR(DATE_TRUNC('week', " + movedAt + "), 'YYYY-MM-DD')"
+	case "month":
+		dateExpr = "TO_CHAR(" + movedAt + ", 'YYYY-MM')"
+	}
+
+	branchClause := ""
+	if branchID != "" {
+		branchClause = " AND r.pickup_branch_id = @branch_id"
+	}
+
+	query := `
+		WITH payment_lines AS (
 			SELECT 
-				TO_CHAR(r.rental_date, 'YYYY-MM-DD') as date,
-				COUNT(r.id) as rental_count,
-				SUM(r.total_rental_price) as rental_revenue,
-				SUM(COALESCE(r.late_fee, 0)) as late_fee_revenue,
-				SUM(COALESCE(r.damage_fee, 0)) as damage_fee_revenue,
-				SUM(r.total_rental_price + COALESCE(r.late_fee, 0) + COALESCE(r.damage_fee, 0)) as total_revenue,
-				COUNT(DISTINCT r.id) as transaction_count
+				pi.payment_id,
+				SUM(CASE WHEN pi.kind = @deposit_kind THEN pi.amount ELSE 0 END) AS deposit_amount,
+				SUM(CASE WHEN pi.kind IN @tax_kinds THEN pi.amount ELSE 0 END) AS tax_amount
 			FROM 
-				rentals r
+				payment_items pi
 			WHERE 
-				r.rental_date BETWEEN ? AND ?
-				AND r.deleted_at IS NULL
+				pi.deleted_at IS NULL
 			GROUP BY 
-				TO_CHAR(r.rental_date, 'YYYY-MM-DD')
-			ORDER BY 
-				date ASC
-		`
-	case "week":
-		query = `
+				pi.payment_id
+		),
+		cash_payments AS (
 			SELECT 
-				TO_CHAR(DATE_TRUNC('week', r.rental_date), 'YYYY-MM-DD') as date,
-				COUNT(r.id) as rental_count,
-				SUM(r.total_rental_price) as rental_revenue,
-				SUM(COALESCE(r.late_fee, 0)) as late_fee_revenue,
-				SUM(COALESCE(r.damage_fee, 0)) as damage_fee_revenue,
-				SUM(r.total_rental_price + COALESCE(r.late_fee, 0) + COALESCE(r.damage_fee, 0)) as total_revenue,
-				COUNT(DISTINCT r.id) as transaction_count
+				p.id,
+				p.rental_id,
+				p.payment_type,
+				COALESCE(NULLIF(p.payment_method, ''), 'unknown') AS payment_method,
+				p.transaction_time,
+				p.gross_amount,
+				COALESCE(pl.deposit_amount, 0) AS deposit_amount,
+				COALESCE(pl.tax_amount, 0) AS tax_amount
 			FROM 
-				rentals r
+				payments p
+			JOIN 
+				rentals r ON p.rental_id = r.id
+			LEFT JOIN 
+				payment_lines pl ON p.id = pl.payment_id
 			WHERE 
-				r.rental_date BETWEEN ? AND ?
-				AND r.deleted_at IS NULL
-			GROUP BY 
-				TO_CHAR(DATE_TRUNC('week', r.rental_date), 'YYYY-MM-DD')
-			ORDER BY 
-				date ASC
-		`
-	case "month":
-		query = `
+				p.deleted_at IS NULL
+				AND p.transaction_status IN @paid_statuses
+				AND COALESCE(p.payment_method, '') <> @wallet_method
+				AND p.transaction_time IS NOT NULL
+				AND p.transaction_time <= @end_date` + branchClause + `
+		),
+		cash_movements AS (
 			SELECT 
-				TO_CHAR(r.rental_date, 'YYYY-MM') as date,
-				COUNT(r.id) as rental_count,
-				SUM(r.total_rental_price) as rental_revenue,
-				SUM(COALESCE(r.late_fee, 0)) as late_fee_revenue,
-				SUM(COALESCE(r.damage_fee, 0)) as damage_fee_revenue,
-				SUM(r.total_rental_price + COALESCE(r.late_fee, 0) + COALESCE(r.damage_fee, 0)) as total_revenue,
-				COUNT(DISTINCT r.id) as transaction_count
+				cp.transaction_time AS moved_at,
+				cp.payment_type,
+				cp.payment_method,
+				1 AS direction,
+				cp.gross_amount,
+				cp.deposit_amount,
+				cp.tax_amount
 			FROM 
-				rentals r
+				cash_payments cp
 			WHERE 
-				r.rental_date BETWEEN ? AND ?
-				AND r.deleted_at IS NULL
-			GROUP BY 
-				TO_CHAR(r.rental_date, 'YYYY-MM')
-			ORDER BY 
-				date ASC
-		`
-	default:
-		query = `
-			SELECT 
-				TO_CHAR(r.rental_date, 'YYYY-MM-DD') as date,
-				COUNT(r.id) as rental_count,
-				SUM(r.total_rental_price) as rental_revenue,
-				SUM(COALESCE(r.late_fee, 0)) as late_fee_revenue,
-				SUM(COALESCE(r.damage_fee, 0)) as damage_fee_revenue,
-				SUM(r.total_rental_price + COALESCE(r.late_fee, 0) + COALESCE(r.damage_fee, 0)) as total_revenue,
-				COUNT(DISTINCT r.id) as transaction_count
+				cp.transaction_time BETWEEN @start_date AND @end_date
+			UNION ALL
+			SELECT 
+				j.entry_date AS moved_at,
+				cp.payment_type,
+				cp.payment_method,
+				-1 AS direction,
+				jl.credit AS gross_amount,
+				ROUND(cp.deposit_amount * jl.credit / NULLIF(cp.gross_amount, 0)) AS deposit_amount,
+				ROUND(cp.tax_amount * jl.credit / NULLIF(cp.gross_amount, 0)) AS tax_amount
 			FROM 
-				rentals r
+				journal_entries j
+			JOIN 
+				journal_lines jl ON j.id = jl.journal_entry_id AND jl.account_code = @cash_account AND jl.deleted_at IS NULL
+			JOIN 
+				cash_payments cp ON j.source_id = cp.id
 			WHERE 
-				r.rental_date BETWEEN ? AND ?
-				AND r.deleted_at IS NULL
-			GROUP BY 
-				TO_CHAR(r.rental_date, 'YYYY-MM-DD')
-			ORDER BY 
-				date ASC
-		`
+				j.event = @refund_event
+				AND j.deleted_at IS NULL
+				AND j.entry_date BETWEEN @start_date AND @end_date
+			UNION ALL
+			SELECT 
+				j.entry_date AS moved_at,
+				dp.payment_type,
+				dp.payment_method,
+				-1 AS direction,
+				jl.credit AS gross_amount,
+				jl.credit AS deposit_amount,
+				0 AS tax_amount
+			FROM 
+				journal_entries j
+			JOIN 
+				journal_lines jl ON j.id = jl.journal_entry_id AND jl.account_code = @cash_account AND jl.deleted_at IS NULL
+			JOIN (
+				SELECT DISTINCT ON (rental_id) 
+					rental_id,
+					payment_type,
+					payment_method
+				FROM 
+					cash_payments
+				WHERE 
+					deposit_amount > 0
+				ORDER BY 
+					rental_id, transaction_time ASC
+			) dp ON j.source_id = dp.rental_id
+			WHERE 
+				j.event = @deposit_refund_event
+				AND j.deleted_at IS NULL
+				AND j.entry_date BETWEEN @start_date AND @end_date
+		)
+		SELECT 
+			` + dateExpr + ` AS date,
+			payment_type,
+			payment_method,
+			COUNT(*) FILTER (WHERE direction = 1) AS transaction_count,
+			COALESCE(SUM(gross_amount) FILTER (WHERE direction = 1), 0) AS received_amount,
+			COALESCE(SUM(gross_amount) FILTER (WHERE direction = -1), 0) AS refunded_amount,
+			SUM(direction * deposit_amount) AS deposit_amount,
+			SUM(direction * tax_amount) AS tax_amount,
+			SUM(direction * (gross_amount - deposit_amount - tax_amount)) AS net_revenue
+		FROM 
+			cash_movements
+		GROUP BY 
+			1, 2, 3
+		ORDER BY 
+			date ASC, payment_type ASC, payment_method ASC
+	`
+
+	args := reportArgs(startDate, endDate, branchID)
+	args["deposit_kind"] = entity.PriceLineDeposit
+	args["tax_kinds"] = []string{entity.PriceLineTax, entity.PriceLineTaxRounding}
+	args["paid_statuses"] = []string{
+		entity.TransactionStatusSettlement,
+		entity.TransactionStatusCapture,
+		entity.TransactionStatusRefund,
+		entity.TransactionStatusPartialRefund,
 	}
+	args["wallet_method"] = entity.PaymentMethodWallet
+	args["refund_event"] = entity.JournalEventPaymentRefunded
+	args["deposit_refund_event"] = entity.JournalEventDepositRefunded
+	args["cash_account"] = entity.AccountCashClearing
 
-	err := r.DB.WithContext(ctx).Raw(query, startDate, endDate).Scan(&items).Error
+	err := r.DB.WithContext(ctx).Raw(query, args).Scan(&items).Error
 	return items, err
 }
 
-func (r *BusinessReportRepository) GetPopularToys(ctx context.Context, startDate, endDate time.Time, limit int) ([]entity.PopularToyItem, error) {
+func (r *BusinessReportRepository) GetPopularToys(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.PopularToyItem, error) {
 	var items []entity.PopularToyItem
+	branchClause, branchArgs := rentalBranchFilter("r.", branchID)
 
 	query := `
-		WITH category_names AS (
-			SELECT 
-				tc.toy_id,
-				STRING_AGG(c.name, ', ') AS category_names
-			FROM 
-				toy_toy_categories tc
-			JOIN 
-				toy_categories c ON tc.toy_category_id = c.id
-			WHERE 
-				c.deleted_at IS NULL
-			GROUP BY 
-				tc.toy_id
-		)
 		SELECT 
 			t.id AS toy_id,
 			t.name AS toy_name,
@@ -147,15 +323,11 @@ func (r *BusinessReportRepository) GetPopularToys(ctx context.Context, startDate
 			rental_items ri ON t.id = ri.toy_id
 		JOIN 
 			rentals r ON ri.rental_id = r.id
-		LEFT JOIN 
-			toy_toy_categories tc ON t.id = tc.toy_id
-		LEFT JOIN 
-			toy_categories c ON tc.toy_category_id = c.id
 		WHERE 
 			r.rental_date BETWEEN ? AND ?
 			AND r.deleted_at IS NULL 
 			AND ri.deleted_at IS NULL
-			AND t.deleted_at IS NULL
+			AND t.deleted_at IS NULL` + branchClause + `
 		GROUP BY 
 			t.id, t.name, t.primary_image
 		ORDER BY 
@@ -163,12 +335,14 @@ func (r *BusinessReportRepository) GetPopularToys(ctx context.Context, startDate
 		LIMIT ?
 	`
 
-	err := r.DB.WithContext(ctx).Raw(query, startDate, endDate, limit).Scan(&items).Error
+	args := append([]interface{}{startDate, endDate}, branchArgs...)
+	err := r.DB.WithContext(ctx).Raw(query, append(args, limit)...).Scan(&items).Error
 	return items, err
 }
 
-func (r *BusinessReportRepository) GetTopCustomers(ctx context.Context, startDate, endDate time.Time, limit int) ([]entity.TopCustomerItem, error) {
+func (r *BusinessReportRepository) GetTopCustomers(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.TopCustomerItem, error) {
 	var items []entity.TopCustomerItem
+	branchClause, branchArgs := rentalBranchFilter("r.", branchID)
 
 	query := `
 		SELECT 
@@ -190,7 +364,7 @@ func (r *BusinessReportRepository) GetTopCustomers(ctx context.Context, startDat
 		WHERE 
 			r.rental_date BETWEEN ? AND ?
 			AND r.deleted_at IS NULL
-			AND u.deleted_at IS NULL
+			AND u.deleted_at IS NULL` + branchClause + `
 		GROUP BY 
 			u.id, u.full_name, u.email, u.phone_number
 		ORDER BY 
@@ -198,18 +372,174 @@ func (r *BusinessReportRepository) GetTopCustomers(ctx context.Context, startDat
 		LIMIT ?
 	`
 
-	err := r.DB.WithContext(ctx).Raw(query, startDate, endDate, limit).Scan(&items).Error
+	args := append([]interface{}{startDate, endDate}, branchArgs...)
+	err := r.DB.WithContext(ctx).Raw(query, append(args, limit)...).Scan(&items).Error
+	return items, err
+}
+
+// GetCohortActivity menghitung pelanggan aktif per bulan sejak rental pertama untuk kohort yang rental
+// pertamanya jatuh di periode laporan. Aktivitas setelah periode tetap dihitung sampai bulan ke-months.
+func (r *BusinessReportRepository) GetCohortActivity(ctx context.Context, startDate, endDate time.Time, months int, branchID string) ([]entity.CohortActivity, error) {
+	var items []entity.CohortActivity
+
+	query := customerRentalsQuery(branchID) + `,
+		cohorts AS (
+			SELECT 
+				user_id,
+				DATE_TRUNC('month', MIN(rental_date)) AS cohort_month
+			FROM 
+				customer_rentals
+			GROUP BY 
+				user_id
+			HAVING 
+				MIN(rental_date) BETWEEN @start_date AND @end_date
+		),
+		cohort_sizes AS (
+			SELECT 
+				cohort_month,
+				COUNT(*) AS cohort_size
+			FROM 
+				cohorts
+			GROUP BY 
+				cohort_month
+		),
+		activity AS (
+			SELECT DISTINCT 
+				c.user_id,
+				c.cohort_month,
+				(EXTRACT(YEAR FROM AGE(DATE_TRUNC('month', cr.rental_date), c.cohort_month)) * 12 +
+					EXTRACT(MONTH FROM AGE(DATE_TRUNC('month', cr.rental_date), c.cohort_month)))::int AS month_offset
+			FROM 
+				cohorts c
+			JOIN 
+				customer_rentals cr ON c.user_id = cr.user_id
+		)
+		SELECT 
+			TO_CHAR(a.cohort_month, 'YYYY-MM') AS cohort_month,
+			cs.cohort_size,
+			a.month_offset,
+			COUNT(*) AS active_customers
+		FROM 
+			activity a
+		JOIN 
+			cohort_sizes cs ON a.cohort_month = cs.cohort_month
+		WHERE 
+			a.month_offset <= @months
+		GROUP BY 
+			a.cohort_month, cs.cohort_size, a.month_offset
+		ORDER BY 
+			a.cohort_month ASC, a.month_offset ASC
+	`
+
+	args := reportArgs(startDate, endDate, branchID)
+	args["months"] = months
+	err := r.DB.WithContext(ctx).Raw(query, args).Scan(&items).Error
+	return items, err
+}
+
+func (r *BusinessReportRepository) GetCustomerValues(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.CustomerValueItem, error) {
+	var items []entity.CustomerValueItem
+
+	query := customerRentalsQuery(branchID) + customerLifetimeQuery + `
+		SELECT 
+			cl.user_id,
+			u.full_name,
+			u.email,
+			cl.first_rental_date,
+			cl.last_rental_date,
+			cl.rental_count,
+			cl.lifetime_value,
+			cl.total_gap_days / NULLIF(cl.gap_count, 0) AS average_days_between_rentals
+		FROM 
+			customer_lifetimes cl
+		JOIN 
+			users u ON cl.user_id = u.id
+		WHERE 
+			u.deleted_at IS NULL
+		ORDER BY 
+			cl.lifetime_value DESC, cl.rental_count DESC
+		LIMIT @limit
+	`
+
+	args := reportArgs(startDate, endDate, branchID)
+	args["limit"] = limit
+	err := r.DB.WithContext(ctx).Raw(query, args).Scan(&items).Error
 	return items, err
 }
 
-func (r *BusinessReportRepository) GetRentalStatusCount(ctx context.Context, startDate, endDate time.Time) ([]entity.RentalStatusItem, error) {
+func (r *BusinessReportRepository) GetCustomerRetentionSummary(ctx context.Context, startDate, endDate time.Time, branchID string) (entity.CustomerRetentionSummary, error) {
+	var summary entity.CustomerRetentionSummary
+
+	query := customerRentalsQuery(branchID) + customerLifetimeQuery + `
+		SELECT 
+			COUNT(*) AS customer_count,
+			COUNT(*) FILTER (WHERE rental_count > 1) AS repeat_customer_count,
+			COALESCE(COUNT(*) FILTER (WHERE rental_count > 1) * 100.0 / NULLIF(COUNT(*), 0), 0) AS repeat_rate,
+			COALESCE(SUM(total_gap_days) / NULLIF(SUM(gap_count), 0), 0) AS average_days_between_rentals,
+			COALESCE(ROUND(AVG(lifetime_value), 2), 0) AS average_lifetime_value
+		FROM 
+			customer_lifetimes
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&summary).Error
+	return summary, err
+}
+
+// customerRentalsQuery menyusun CTE customer_rentals, yaitu rental yang benar-benar berjalan (bukan pending
+// atau batal) beserta jarak hari dari rental sebelumnya milik pelanggan yang sama
+func customerRentalsQuery(branchID string) string {
+	branchClause := ""
+	if branchID != "" {
+		branchClause = " AND r.pickup_branch_id = @branch_id"
+	}
+
+	return `
+		WITH customer_rentals AS (
+			SELECT 
+				r.user_id,
+				r.rental_date,
+				r.total_rental_price + COALESCE(r.late_fee, 0) + COALESCE(r.damage_fee, 0) AS amount,
+				EXTRACT(EPOCH FROM (r.rental_date - LAG(r.rental_date) OVER (PARTITION BY r.user_id ORDER BY r.rental_date))) / 86400 AS gap_days
+			FROM 
+				rentals r
+			WHERE 
+				r.deleted_at IS NULL
+				AND r.status IN ('active', 'overdue', 'completed')` + branchClause + `
+		)`
+}
+
+// customerLifetimeQuery melanjutkan customerRentalsQuery dengan nilai seumur hidup pelanggan yang
+// rental pertamanya jatuh di periode laporan
+const customerLifetimeQuery = `,
+		customer_lifetimes AS (
+			SELECT 
+				user_id,
+				MIN(rental_date) AS first_rental_date,
+				MAX(rental_date) AS last_rental_date,
+				COUNT(*) AS rental_count,
+				SUM(amount) AS lifetime_value,
+				COALESCE(SUM(gap_days), 0) AS total_gap_days,
+				COUNT(gap_days) AS gap_count
+			FROM 
+				customer_rentals
+			GROUP BY 
+				user_id
+			HAVING 
+				MIN(rental_date) BETWEEN @start_date AND @end_date
+		)`
+
+func (r *BusinessReportRepository) GetRentalStatusCount(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.RentalStatusItem, error) {
 	var items []entity.RentalStatusItem
 	var totalCount int64
+	branchClause, branchArgs := rentalBranchFilter("", branchID)
 
-	if err := r.DB.WithContext(ctx).Model(&entity.Rental{}).
+	countQuery := r.DB.WithContext(ctx).Model(&entity.Rental{}).
 		Where("rental_date BETWEEN ? AND ?", startDate, endDate).
-		Where("deleted_at IS NULL").
-		Count(&totalCount).Error; err != nil {
+		Where("deleted_at IS NULL")
+	if branchID != "" {
+		countQuery = countQuery.Where("pickup_branch_id = ?", branchID)
+	}
+	if err := countQuery.Count(&totalCount).Error; err != nil {
 		return nil, err
 	}
 
@@ -221,14 +551,15 @@ func (r *BusinessReportRepository) GetRentalStatusCount(ctx context.Context, sta
 			rentals
 		WHERE 
 			rental_date BETWEEN ? AND ?
-			AND deleted_at IS NULL
+			AND deleted_at IS NULL` + branchClause + `
 		GROUP BY 
 			status
 		ORDER BY 
 			count DESC
 	`
 
-	err := r.DB.WithContext(ctx).Raw(query, startDate, endDate).Scan(&items).Error
+	args := append([]interface{}{startDate, endDate}, branchArgs...)
+	err := r.DB.WithContext(ctx).Raw(query, args...).Scan(&items).Error
 	if err != nil {
 		return nil, err
 	}
@@ -243,3 +574,714 @@ func (r *BusinessReportRepository) GetRentalStatusCount(ctx context.Context, sta
 
 	return items, nil
 }
+
+// GetCategoryPerformance menghitung kinerja setiap kategori. Setiap item rental dipecah ke semua kategori
+// mainannya dan pendapatannya dibagi dengan jumlah kategori tersebut agar tidak terhitung ganda.
+func (r *BusinessReportRepository) GetCategoryPerformance(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.CategorySummary, error) {
+	var items []entity.CategorySummary
+	branchClause, branchArgs := rentalBranchFilter("r.", branchID)
+
+	query := `
+		WITH toy_category_names AS (
+			SELECT 
+				tc.toy_id,
+				c.id AS category_id,
+				c.name
+			FROM 
+				toy_toy_categories tc
+			JOIN 
+				toy_categories c ON tc.toy_category_id = c.id
+			WHERE 
+				c.deleted_at IS NULL
+		),
+		item_categories AS (
+			SELECT 
+				ri.rental_id,
+				ri.quantity,
+				ri.price_per_unit * ri.quantity AS revenue,
+				(ri.status IN ('damaged', 'lost') OR ri.condition_after IN ('damaged', 'lost')) AS is_damaged,
+				EXTRACT(DAY FROM (COALESCE(r.actual_return_date, CURRENT_DATE) - r.rental_date)) + 1 AS duration,
+				tcn.category_id,
+				COALESCE(tcn.name, 'Tanpa Kategori') AS name,
+				COUNT(*) OVER (PARTITION BY ri.id) AS category_count
+			FROM 
+				rental_items ri
+			JOIN 
+				rentals r ON ri.rental_id = r.id
+			LEFT JOIN 
+				toy_category_names tcn ON ri.toy_id = tcn.toy_id
+			WHERE 
+				r.rental_date BETWEEN ? AND ?
+				AND r.deleted_at IS NULL
+				AND ri.deleted_at IS NULL` + branchClause + `
+		)
+		SELECT 
+			category_id,
+			name,
+			COUNT(DISTINCT rental_id) AS rental_count,
+			ROUND(SUM(revenue / category_count), 2) AS revenue,
+			AVG(duration) AS average_duration,
+			COALESCE(SUM(CASE WHEN is_damaged THEN quantity ELSE 0 END) * 100.0 / NULLIF(SUM(quantity), 0), 0) AS damage_rate
+		FROM 
+			item_categories
+		GROUP BY 
+			category_id, name
+		ORDER BY 
+			revenue DESC, rental_count DESC
+	`
+
+	args := append([]interface{}{startDate, endDate}, branchArgs...)
+	if err := r.DB.WithContext(ctx).Raw(query, args...).Scan(&items).Error; err != nil {
+		return nil, err
+	}
+
+	var totalRevenue money.Money
+	for _, item := range items {
+		totalRevenue += item.Revenue
+	}
+	for i := range items {
+		if totalRevenue > 0 {
+			items[i].Percentage = items[i].Revenue.Float() / totalRevenue.Float() * 100
+		}
+	}
+	return items, nil
+}
+
+func (r *BusinessReportRepository) GetToyUtilization(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.ToyUtilizationItem, error) {
+	var items []entity.ToyUtilizationItem
+
+	query := toyUtilizationQuery(branchID) + `
+		SELECT 
+			*
+		FROM 
+			toy_utilization
+		ORDER BY 
+			utilization_rate DESC, revenue DESC
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&items).Error
+	return items, err
+}
+
+func (r *BusinessReportRepository) GetCategoryUtilization(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.CategoryUtilizationItem, error) {
+	var items []entity.CategoryUtilizationItem
+
+	query := toyUtilizationQuery(branchID) + `
+		SELECT 
+			c.id AS category_id,
+			COALESCE(c.name, 'Tanpa Kategori') AS name,
+			COUNT(tu.toy_id) AS toy_count,
+			SUM(tu.unit_count) AS unit_count,
+			SUM(tu.rented_unit_days) AS rented_unit_days,
+			SUM(tu.available_unit_days) AS available_unit_days,
+			COALESCE(SUM(tu.rented_unit_days) * 100 / NULLIF(SUM(tu.available_unit_days), 0), 0) AS utilization_rate,
+			SUM(tu.revenue) AS revenue,
+			COALESCE(ROUND(SUM(tu.revenue) / NULLIF(SUM(tu.unit_count), 0), 2), 0) AS revenue_per_unit
+		FROM 
+			toy_utilization tu
+		LEFT JOIN (
+			SELECT 
+				tc.toy_id,
+				c.id,
+				c.name
+			FROM 
+				toy_toy_categories tc
+			JOIN 
+				toy_categories c ON tc.toy_category_id = c.id
+			WHERE 
+				c.deleted_at IS NULL
+		) c ON tu.toy_id = c.toy_id
+		GROUP BY 
+			c.id, c.name
+		ORDER BY 
+			utilization_rate DESC, revenue DESC
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&items).Error
+	return items, err
+}
+
+// GetIdleToys mengambil mainan yang masih punya unit tetapi tidak disewa lebih dari idleDays hari.
+// Mainan yang belum pernah disewa dihitung sejak mainan ditambahkan.
+func (r *BusinessReportRepository) GetIdleToys(ctx context.Context, startDate, endDate time.Time, idleDays int, branchID string) ([]entity.ToyUtilizationItem, error) {
+	var items []entity.ToyUtilizationItem
+
+	query := toyUtilizationQuery(branchID) + `
+		SELECT 
+			tu.*
+		FROM 
+			toy_utilization tu
+		JOIN 
+			toys t ON tu.toy_id = t.id
+		WHERE 
+			tu.unit_count > 0
+			AND COALESCE(tu.last_rental_date, t.created_at) < NOW() - make_interval(days => @idle_days)
+		ORDER BY 
+			tu.last_rental_date ASC NULLS FIRST, t.created_at ASC
+	`
+
+	args := reportArgs(startDate, endDate, branchID)
+	args["idle_days"] = idleDays
+	err := r.DB.WithContext(ctx).Raw(query, args).Scan(&items).Error
+	return items, err
+}
+
+// GetUtilizationRate menghitung utilisasi seluruh armada: total hari-unit tersewa dibagi hari-unit tersedia
+func (r *BusinessReportRepository) GetUtilizationRate(ctx context.Context, startDate, endDate time.Time, branchID string) (float64, error) {
+	var rate float64
+
+	query := toyUtilizationQuery(branchID) + `
+		SELECT 
+			COALESCE(SUM(rented_unit_days) * 100 / NULLIF(SUM(available_unit_days), 0), 0)
+		FROM 
+			toy_utilization
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&rate).Error
+	return rate, err
+}
+
+// toyUtilizationQuery menyusun CTE toy_utilization. Hari-unit tersewa dihitung dari irisan masa sewa
+// dengan periode laporan, sedangkan hari-unit tersedia adalah jumlah unit aktif dikali panjang periode.
+// Pendapatan mengikuti tanggal rental seperti laporan lainnya.
+func toyUtilizationQuery(branchID string) string {
+	unitBranchClause, rentalBranchClause := "", ""
+	if branchID != "" {
+		unitBranchClause = " AND u.branch_id = @branch_id"
+		rentalBranchClause = " AND r.pickup_branch_id = @branch_id"
+	}
+
+	return `
+		WITH period AS (
+			SELECT EXTRACT(EPOCH FROM (CAST(@end_date AS timestamptz) - CAST(@start_date AS timestamptz))) / 86400 AS days
+		),
+		unit_counts AS (
+			SELECT 
+				u.toy_id,
+				COUNT(*) AS unit_count
+			FROM 
+				toy_units u
+			WHERE 
+				u.deleted_at IS NULL
+				AND u.status NOT IN ('retired', 'lost')` + unitBranchClause + `
+			GROUP BY 
+				u.toy_id
+		),
+		rental_periods AS (
+			SELECT 
+				ri.toy_id,
+				ri.quantity,
+				ri.price_per_unit * ri.quantity AS revenue,
+				r.rental_date,
+				COALESCE(r.actual_return_date, GREATEST(r.expected_return_date, NOW())) AS return_date
+			FROM 
+				rental_items ri
+			JOIN 
+				rentals r ON ri.rental_id = r.id
+			WHERE 
+				r.status IN ('active', 'overdue', 'completed')
+				AND r.deleted_at IS NULL
+				AND ri.deleted_at IS NULL` + rentalBranchClause + `
+		),
+		rented_days AS (
+			SELECT 
+				toy_id,
+				SUM(quantity * GREATEST(EXTRACT(EPOCH FROM (
+					LEAST(return_date, CAST(@end_date AS timestamptz)) - GREATEST(rental_date, CAST(@start_date AS timestamptz))
+				)) / 86400, 0)) AS rented_unit_days,
+				SUM(CASE WHEN rental_date BETWEEN @start_date AND @end_date THEN revenue ELSE 0 END) AS revenue,
+				MAX(rental_date) AS last_rental_date
+			FROM 
+				rental_periods
+			GROUP BY 
+				toy_id
+		),
+		toy_utilization AS (
+			SELECT 
+				t.id AS toy_id,
+				t.name AS toy_name,
+				t.primary_image AS image_url,
+				COALESCE(uc.unit_count, 0) AS unit_count,
+				COALESCE(rd.rented_unit_days, 0) AS rented_unit_days,
+				COALESCE(uc.unit_count, 0) * p.days AS available_unit_days,
+				COALESCE(rd.rented_unit_days * 100 / NULLIF(uc.unit_count * p.days, 0), 0) AS utilization_rate,
+				COALESCE(rd.revenue, 0) AS revenue,
+				COALESCE(ROUND(rd.revenue / NULLIF(uc.unit_count, 0), 2), 0) AS revenue_per_unit,
+				rd.last_rental_date,
+				EXTRACT(DAY FROM (NOW() - rd.last_rental_date))::int AS days_since_last_rental
+			FROM 
+				toys t
+			CROSS JOIN 
+				period p
+			LEFT JOIN 
+				unit_counts uc ON t.id = uc.toy_id
+			LEFT JOIN 
+				rented_days rd ON t.id = rd.toy_id
+			WHERE 
+				t.deleted_at IS NULL
+		)`
+}
+
+// reportArgs adalah argumen bernama untuk query laporan yang memakai @start_date, @end_date dan @branch_id
+func reportArgs(startDate, endDate time.Time, branchID string) map[string]interface{} {
+	return map[string]interface{}{
+		"start_date": startDate,
+		"end_date":   endDate,
+		"branch_id":  branchID,
+	}
+}
+
+// GetKPISummary menghitung indikator utama dashboard. Pendapatan dan jumlah rental mengikuti laporan
+// penjualan, sedangkan rental aktif dan terlambat adalah posisi pada akhir periode.
+func (r *BusinessReportRepository) GetKPISummary(ctx context.Context, startDate, endDate time.Time, branchID string) (entity.KPISummary, error) {
+	var summary entity.KPISummary
+
+	branchClause := ""
+	if branchID != "" {
+		branchClause = " AND r.pickup_branch_id = @branch_id"
+	}
+
+	query := `
+		WITH period_rentals AS (
+			SELECT 
+				r.*
+			FROM 
+				rentals r
+			WHERE 
+				r.deleted_at IS NULL` + branchClause + `
+		),
+		first_rentals AS (
+			SELECT 
+				user_id
+			FROM 
+				period_rentals
+			WHERE 
+				status <> 'cancelled'
+			GROUP BY 
+				user_id
+			HAVING 
+				MIN(rental_date) BETWEEN @start_date AND @end_date
+		)
+		SELECT 
+			COALESCE(SUM(total_rental_price + COALESCE(late_fee, 0) + COALESCE(damage_fee, 0))
+				FILTER (WHERE rental_date BETWEEN @start_date AND @end_date), 0) AS revenue,
+			COUNT(*) FILTER (WHERE rental_date BETWEEN @start_date AND @end_date) AS rental_count,
+			COUNT(*) FILTER (WHERE status IN ('active', 'overdue', 'completed')
+				AND rental_date <= @end_date
+				AND (actual_return_date IS NULL OR actual_return_date > @end_date)) AS active_rentals,
+			COUNT(*) FILTER (WHERE status IN ('active', 'overdue', 'completed')
+				AND expected_return_date < @end_date
+				AND (actual_return_date IS NULL OR actual_return_date > @end_date)) AS overdue_count,
+			COALESCE(ROUND(SUM(total_rental_price + COALESCE(late_fee, 0) + COALESCE(damage_fee, 0))
+				FILTER (WHERE rental_date BETWEEN @start_date AND @end_date)
+				/ NULLIF(COUNT(*) FILTER (WHERE rental_date BETWEEN @start_date AND @end_date), 0), 2), 0) AS average_basket,
+			(SELECT COUNT(*) FROM first_rentals) AS new_customers
+		FROM 
+			period_rentals
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&summary).Error
+	return summary, err
+}
+
+// GetDamageSummary menghitung insiden pada rental yang dikembalikan dalam periode. Biaya diisi oleh service.
+func (r *BusinessReportRepository) GetDamageSummary(ctx context.Context, startDate, endDate time.Time, branchID string) (entity.DamageSummary, error) {
+	var summary entity.DamageSummary
+
+	query := returnedItemsQuery(branchID) + `
+		SELECT 
+			COUNT(DISTINCT rental_id) AS returned_rentals,
+			COUNT(DISTINCT rental_id) FILTER (WHERE is_damaged OR is_lost) AS incident_rentals,
+			COALESCE(COUNT(DISTINCT rental_id) FILTER (WHERE is_damaged OR is_lost) * 100.0 / NULLIF(COUNT(DISTINCT rental_id), 0), 0) AS damage_rate,
+			COALESCE(SUM(quantity) FILTER (WHERE is_damaged), 0) AS damaged_units,
+			COALESCE(SUM(quantity) FILTER (WHERE is_lost), 0) AS lost_units
+		FROM 
+			returned_items
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&summary).Error
+	return summary, err
+}
+
+func (r *BusinessReportRepository) GetDamageByToy(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.DamageToyItem, error) {
+	var items []entity.DamageToyItem
+
+	query := returnedItemsQuery(branchID) + `
+		SELECT 
+			t.id AS toy_id,
+			t.name AS toy_name,
+			SUM(ri.quantity) AS returned_units,
+			COALESCE(SUM(ri.quantity) FILTER (WHERE ri.is_damaged), 0) AS damaged_units,
+			COALESCE(SUM(ri.quantity) FILTER (WHERE ri.is_lost), 0) AS lost_units,
+			COALESCE(SUM(ri.quantity) FILTER (WHERE ri.is_damaged OR ri.is_lost) * 100.0 / NULLIF(SUM(ri.quantity), 0), 0) AS incident_rate,
+			COALESCE(SUM(ri.damage_fee), 0) AS fees_assessed
+		FROM 
+			returned_items ri
+		JOIN 
+			toys t ON ri.toy_id = t.id
+		GROUP BY 
+			t.id, t.name
+		ORDER BY 
+			SUM(ri.quantity) FILTER (WHERE ri.is_damaged OR ri.is_lost) DESC NULLS LAST, incident_rate DESC, t.name ASC
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&items).Error
+	return items, err
+}
+
+func (r *BusinessReportRepository) GetDamageByCategory(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.DamageCategoryItem, error) {
+	var items []entity.DamageCategoryItem
+
+	query := returnedItemsQuery(branchID) + `,
+		item_categories AS (
+			SELECT 
+				ri.*,
+				c.id AS category_id,
+				COALESCE(c.name, 'Tanpa Kategori') AS name,
+				COUNT(*) OVER (PARTITION BY ri.id) AS category_count
+			FROM 
+				returned_items ri
+			LEFT JOIN (
+				SELECT 
+					tc.toy_id,
+					c.id,
+					c.name
+				FROM 
+					toy_toy_categories tc
+				JOIN 
+					toy_categories c ON tc.toy_category_id = c.id
+				WHERE 
+					c.deleted_at IS NULL
+			) c ON ri.toy_id = c.toy_id
+		)
+		SELECT 
+			category_id,
+			name,
+			SUM(quantity) AS returned_units,
+			COALESCE(SUM(quantity) FILTER (WHERE is_damaged), 0) AS damaged_units,
+			COALESCE(SUM(quantity) FILTER (WHERE is_lost), 0) AS lost_units,
+			COALESCE(SUM(quantity) FILTER (WHERE is_damaged OR is_lost) * 100.0 / NULLIF(SUM(quantity), 0), 0) AS incident_rate,
+			COALESCE(ROUND(SUM(damage_fee / category_count), 2), 0) AS fees_assessed
+		FROM 
+			item_categories
+		GROUP BY 
+			category_id, name
+		ORDER BY 
+			incident_rate DESC, name ASC
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&items).Error
+	return items, err
+}
+
+// GetDamageByCustomer mengambil pelanggan yang punya insiden dalam periode. Biaya diisi oleh service.
+func (r *BusinessReportRepository) GetDamageByCustomer(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.DamageCustomerItem, error) {
+	var items []entity.DamageCustomerItem
+
+	query := returnedItemsQuery(branchID) + `
+		SELECT 
+			u.id AS user_id,
+			u.full_name,
+			u.email,
+			COUNT(DISTINCT ri.rental_id) AS returned_rentals,
+			COUNT(DISTINCT ri.rental_id) FILTER (WHERE ri.is_damaged OR ri.is_lost) AS incident_count,
+			COALESCE(SUM(ri.quantity) FILTER (WHERE ri.is_damaged), 0) AS damaged_units,
+			COALESCE(SUM(ri.quantity) FILTER (WHERE ri.is_lost), 0) AS lost_units
+		FROM 
+			returned_items ri
+		JOIN 
+			users u ON ri.user_id = u.id
+		GROUP BY 
+			u.id, u.full_name, u.email
+		HAVING 
+			COUNT(*) FILTER (WHERE ri.is_damaged OR ri.is_lost) > 0
+		ORDER BY 
+			incident_count DESC, SUM(ri.quantity) FILTER (WHERE ri.is_damaged OR ri.is_lost) DESC
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, reportArgs(startDate, endDate, branchID)).Scan(&items).Error
+	return items, err
+}
+
+// GetDamageFeeRentals mengambil rental berbiaya kerusakan beserta jumlah yang sudah dibayar, dihitung
+// sama seperti PaymentRepository.SumPaidByRentalID
+func (r *BusinessReportRepository) GetDamageFeeRentals(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.DamageFeeRental, error) {
+	var items []entity.DamageFeeRental
+
+	branchClause := ""
+	if branchID != "" {
+		branchClause = " AND r.pickup_branch_id = @branch_id"
+	}
+
+	query := `
+		SELECT 
+			r.id AS rental_id,
+			r.user_id,
+			r.total_rental_price,
+			COALESCE(r.late_fee, 0) AS late_fee,
+			r.damage_fee,
+			r.tax_rate,
+			r.tax_inclusive,
+			r.deposit_applied,
+			COALESCE((
+				SELECT SUM(
+					CASE
+						WHEN EXISTS (SELECT 1 FROM payment_items pi WHERE pi.payment_id = p.id AND pi.deleted_at IS NULL)
+						THEN (
+							SELECT COALESCE(SUM(pi.amount), 0)
+							FROM payment_items pi
+							WHERE pi.payment_id = p.id AND pi.deleted_at IS NULL AND pi.kind <> @deposit_kind
+						)
+						ELSE p.gross_amount
+					END
+				)
+				FROM payments p
+				WHERE p.rental_id = r.id
+					AND p.transaction_status IN @paid_statuses
+					AND p.deleted_at IS NULL
+			), 0) - COALESCE((
+				SELECT SUM(w.amount)
+				FROM wallet_entries w
+				WHERE w.rental_id = r.id AND w.reason = @overpayment_reason AND w.deleted_at IS NULL
+			), 0) AS paid
+		FROM 
+			rentals r
+		WHERE 
+			r.actual_return_date BETWEEN @start_date AND @end_date
+			AND r.deleted_at IS NULL
+			AND r.damage_fee > 0` + branchClause + `
+	`
+
+	args := reportArgs(startDate, endDate, branchID)
+	args["deposit_kind"] = entity.PriceLineDeposit
+	args["paid_statuses"] = []string{
+		entity.TransactionStatusCapture,
+		entity.TransactionStatusSettlement,
+		entity.TransactionStatusPartialRefund,
+	}
+	args["overpayment_reason"] = entity.WalletReasonOverpayment
+
+	err := r.DB.WithContext(ctx).Raw(query, args).Scan(&items).Error
+	return items, err
+}
+
+// returnedItemsQuery menyusun CTE returned_items, yaitu item dari rental yang dikembalikan dalam periode
+// beserta penanda rusak atau hilang. Item hilang tidak dihitung lagi sebagai rusak.
+func returnedItemsQuery(branchID string) string {
+	branchClause := ""
+	if branchID != "" {
+		branchClause = " AND r.pickup_branch_id = @branch_id"
+	}
+
+	return `
+		WITH returned_items AS (
+			SELECT 
+				ri.id,
+				ri.rental_id,
+				ri.toy_id,
+				r.user_id,
+				ri.quantity,
+				COALESCE(ri.damage_fee, 0) AS damage_fee,
+				(ri.status = 'lost' OR COALESCE(ri.condition_after, '') = 'lost') AS is_lost,
+				(ri.status = 'damaged' OR COALESCE(ri.condition_after, '') = 'damaged')
+					AND NOT (ri.status = 'lost' OR COALESCE(ri.condition_after, '') = 'lost') AS is_damaged
+			FROM 
+				rental_items ri
+			JOIN 
+				rentals r ON ri.rental_id = r.id
+			WHERE 
+				r.actual_return_date BETWEEN @start_date AND @end_date
+				AND r.deleted_at IS NULL
+				AND ri.deleted_at IS NULL` + branchClause + `
+		)`
+}
+
+// GetInventoryStatus menghitung posisi stok setiap mainan. Jumlah unit diambil dari toy_units sesuai
+// cabang lokasinya, sedangkan jumlah yang sedang disewa dari rental_items yang belum dikembalikan.
+func (r *BusinessReportRepository) GetInventoryStatus(ctx context.Context, filter entity.InventoryStatusFilter, branchID string) ([]entity.InventoryStatusItem, error) {
+	var rows []struct {
+		ToyID           uuid.UUID
+		ToyName         string
+		ImageURL        string
+		CategoryNames   string
+		CurrentStock    int
+		TotalStock      int
+		RentedCount     int
+		AvailableCount  int
+		DamagedCount    int
+		LostCount       int
+		Condition       string
+		ReplacementCost money.Money
+	}
+
+	unitBranchClause, unitBranchArgs := "", []interface{}{}
+	if branchID != "" {
+		unitBranchClause = " AND u.branch_id = ?"
+		unitBranchArgs = append(unitBranchArgs, branchID)
+	}
+	rentalBranchClause, rentalBranchArgs := rentalBranchFilter("r.", branchID)
+
+	query := `
+		WITH category_names AS (
+			SELECT 
+				tc.toy_id,
+				STRING_AGG(c.name, ', ' ORDER BY c.name) AS category_names
+			FROM 
+				toy_toy_categories tc
+			JOIN 
+				toy_categories c ON tc.toy_category_id = c.id
+			WHERE 
+				c.deleted_at IS NULL
+			GROUP BY 
+				tc.toy_id
+		),
+		unit_counts AS (
+			SELECT 
+				u.toy_id,
+				COUNT(CASE WHEN u.status IN ('available', 'maintenance') AND u.transfer_id IS NULL THEN 1 END) AS current_stock,
+				COUNT(CASE WHEN u.status <> 'retired' THEN 1 END) AS total_stock,
+				COUNT(CASE WHEN u.status = 'available' AND u.transfer_id IS NULL THEN 1 END) AS available_count,
+				COUNT(CASE WHEN u.condition = 'damaged' AND u.status NOT IN ('retired', 'lost') THEN 1 END) AS damaged_count,
+				COUNT(CASE WHEN u.status = 'lost' THEN 1 END) AS lost_count
+			FROM 
+				toy_units u
+			WHERE 
+				u.deleted_at IS NULL` + unitBranchClause + `
+			GROUP BY 
+				u.toy_id
+		),
+		rented_counts AS (
+			SELECT 
+				ri.toy_id,
+				SUM(ri.quantity) AS rented_count
+			FROM 
+				rental_items ri
+			JOIN 
+				rentals r ON ri.rental_id = r.id
+			WHERE 
+				ri.status = 'rented'
+				AND ri.deleted_at IS NULL
+				AND r.deleted_at IS NULL
+				AND r.status IN ('active', 'overdue')` + rentalBranchClause + `
+			GROUP BY 
+				ri.toy_id
+		)
+		SELECT 
+			t.id AS toy_id,
+			t.name AS toy_name,
+			t.primary_image AS image_url,
+			COALESCE(cn.category_names, '') AS category_names,
+			COALESCE(uc.current_stock, 0) AS current_stock,
+			COALESCE(uc.total_stock, 0) AS total_stock,
+			COALESCE(rc.rented_count, 0) AS rented_count,
+			COALESCE(uc.available_count, 0) AS available_count,
+			COALESCE(uc.damaged_count, 0) AS damaged_count,
+			COALESCE(uc.lost_count, 0) AS lost_count,
+			t.condition,
+			t.replacement_price * (COALESCE(uc.damaged_count, 0) + COALESCE(uc.lost_count, 0)) AS replacement_cost
+		FROM 
+			toys t
+		LEFT JOIN 
+			category_names cn ON t.id = cn.toy_id
+		LEFT JOIN 
+			unit_counts uc ON t.id = uc.toy_id
+		LEFT JOIN 
+			rented_counts rc ON t.id = rc.toy_id
+		WHERE 
+			t.deleted_at IS NULL`
+
+	args := append(unitBranchArgs, rentalBranchArgs...)
+	if filter.CategoryID != "" {
+		query += `
+			AND EXISTS (
+				SELECT 1 FROM toy_toy_categories tc
+				WHERE tc.toy_id = t.id AND tc.toy_category_id = ?
+			)`
+		args = append(args, filter.CategoryID)
+	}
+	if filter.Condition != "" {
+		query += `
+			AND t.condition = ?`
+		args = append(args, filter.Condition)
+	}
+	query += `
+		ORDER BY 
+			replacement_cost DESC, t.name ASC
+	`
+
+	if err := r.DB.WithContext(ctx).Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]entity.InventoryStatusItem, 0, len(rows))
+	for _, row := range rows {
+		categories := []string{}
+		if row.CategoryNames != "" {
+			categories = strings.Split(row.CategoryNames, ", ")
+		}
+		items = append(items, entity.InventoryStatusItem{
+			ToyID:           row.ToyID,
+			ToyName:         row.ToyName,
+			ImageURL:        row.ImageURL,
+			Categories:      categories,
+			CurrentStock:    row.CurrentStock,
+			TotalStock:      row.TotalStock,
+			RentedCount:     row.RentedCount,
+			AvailableCount:  row.AvailableCount,
+			DamagedCount:    row.DamagedCount,
+			LostCount:       row.LostCount,
+			Condition:       row.Condition,
+			ReplacementCost: row.ReplacementCost,
+		})
+	}
+	return items, nil
+}
+
+// GetOverdueRentals mengambil rental yang belum dikembalikan padahal tanggal kembalinya sudah lewat
+// pada waktu asOf, diurutkan dari yang paling lama terlambat.
+func (r *BusinessReportRepository) GetOverdueRentals(ctx context.Context, asOf time.Time, branchID string) ([]entity.OverdueRentalItem, error) {
+	var items []entity.OverdueRentalItem
+
+	branchClause, branchArgs := rentalBranchFilter("r.", branchID)
+
+	query := `
+		SELECT 
+			r.id AS rental_id,
+			r.user_id,
+			u.full_name,
+			u.email,
+			COALESCE(u.phone_number, '') AS phone_number,
+			r.rental_date,
+			r.expected_return_date,
+			DATE(?) - DATE(r.expected_return_date) AS days_overdue,
+			COALESCE(SUM(ri.quantity), 0) AS unit_count,
+			r.total_rental_price
+		FROM 
+			rentals r
+		JOIN 
+			users u ON r.user_id = u.id
+		LEFT JOIN 
+			rental_items ri ON ri.rental_id = r.id AND ri.deleted_at IS NULL
+		WHERE 
+			r.deleted_at IS NULL
+			AND r.status IN ('active', 'overdue')
+			AND r.actual_return_date IS NULL
+			AND r.expected_return_date < ?` + branchClause + `
+		GROUP BY 
+			r.id, u.full_name, u.email, u.phone_number
+		ORDER BY 
+			r.expected_return_date ASC
+	`
+
+	args := append([]interface{}{asOf, asOf}, branchArgs...)
+	err := r.DB.WithContext(ctx).Raw(query, args...).Scan(&items).Error
+	return items, err
+}
+
+// rentalBranchFilter membatasi laporan pada rental yang diambil di cabang tertentu
+func rentalBranchFilter(alias string, branchID string) (string, []interface{}) {
+	if branchID == "" {
+		return "", nil
+	}
+	return " AND " + alias + "pickup_branch_id = ?", []interface{}{branchID}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+
+	"gorm.io/gorm"
+)
+
+type IDeliverySlotRepository interface {
+	IBaseRepository[entity.DeliverySlot]
+	FindFiltered(ctx context.Context, filter entity.DeliverySlotFilter, activeOnly bool) ([]entity.DeliverySlot, error)
+	FindByWindow(ctx context.Context, slot entity.DeliverySlot) (entity.DeliverySlot, error)
+	CountBooked(ctx context.Context, slotID string) (int64, error)
+}
+
+type DeliverySlotRepository struct {
+	BaseRepository[entity.DeliverySlot]
+}
+
+func NewDeliverySlotRepository(db *gorm.DB) IDeliverySlotRepository {
+	return &DeliverySlotRepository{
+		BaseRepository: BaseRepository[entity.DeliverySlot]{DB: db},
+	}
+}
+
+func (r *DeliverySlotRepository) FindById(ctx context.Context, id string) (entity.DeliverySlot, error) {
+	var slot entity.DeliverySlot
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).First(&slot).Error; err != nil {
+		return slot, err
+	}
+
+	booked, err := r.CountBooked(ctx, id)
+	if err != nil {
+		return slot, err
+	}
+	slot.Booked = booked
+	return slot, nil
+}
+
+// FindFiltered mengambil jadwal beserta jumlah tugas yang sudah dipesan pada tiap jadwal
+func (r *DeliverySlotRepository) FindFiltered(ctx context.Context, filter entity.DeliverySlotFilter, activeOnly bool) ([]entity.DeliverySlot, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.DeliverySlot{})
+	if filter.BranchID != "" {
+		query = query.Where("branch_id = ?", filter.BranchID)
+	}
+	if filter.Date != "" {
+		query = query.Where("date = ?", filter.Date)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var slots []entity.DeliverySlot
+	if err := query.Order("date ASC, start_time ASC").Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	if len(slots) == 0 {
+		return slots, nil
+	}
+
+	slotIDs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		slotIDs = append(slotIDs, slot.ID.String())
+	}
+
+	var counts []struct {
+		SlotID string
+		Booked int64
+	}
+	if err := r.DB.WithContext(ctx).Model(&entity.DeliveryTask{}).
+		Select("slot_id, COUNT(*) AS booked").
+		Where("slot_id IN ? AND status <> ?", slotIDs, entity.DeliveryTaskStatusCancelled).
+		Group("slot_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	booked := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		booked[count.SlotID] = count.Booked
+	}
+	for i := range slots {
+		slots[i].Booked = booked[slots[i].ID.String()]
+	}
+	return slots, nil
+}
+
+// FindByWindow mencari jadwal lain pada cabang, tanggal dan jam mulai yang sama
+func (r *DeliverySlotRepository) FindByWindow(ctx context.Context, slot entity.DeliverySlot) (entity.DeliverySlot, error) {
+	var existing entity.DeliverySlot
+	err := r.DB.WithContext(ctx).
+		Where("branch_id = ? AND date = ? AND start_time = ?", slot.BranchID, slot.Date.Format(entity.HolidayDateFormat), slot.StartTime).
+		First(&existing).Error
+	return existing, err
+}
+
+// CountBooked menghitung tugas antar jemput yang belum dibatalkan pada jadwal
+func (r *DeliverySlotRepository) CountBooked(ctx context.Context, slotID string) (int64, error) {
+	return countBookedTasks(r.DB.WithContext(ctx), slotID)
+}
+
+func (r *DeliverySlotRepository) UpdateById(ctx context.Context, id string, slot *entity.DeliverySlot) error {
+	return r.DB.WithContext(ctx).Model(&entity.DeliverySlot{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"date":       slot.Date,
+		"start_time": slot.StartTime,
+		"end_time":   slot.EndTime,
+		"capacity":   slot.Capacity,
+		"is_active":  slot.IsActive,
+	}).Error
+}
+
+func countBookedTasks(tx *gorm.DB, slotID interface{}) (int64, error) {
+	var count int64
+	err := tx.Model(&entity.DeliveryTask{}).
+		Where("slot_id = ? AND status <> ?", slotID, entity.DeliveryTaskStatusCancelled).
+		Count(&count).Error
+	return count, err
+}
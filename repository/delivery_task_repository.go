@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IDeliveryTaskRepository interface {
+	IBaseRepository[entity.DeliveryTask]
+	FindFiltered(ctx context.Context, filter entity.DeliveryTaskFilter, limit int, offset int) ([]entity.DeliveryTask, int64, error)
+	UpdateStatus(ctx context.Context, task *entity.DeliveryTask, fromStatus string) error
+	AssignCourier(ctx context.Context, id string, courierID *uuid.UUID) error
+}
+
+type DeliveryTaskRepository struct {
+	BaseRepository[entity.DeliveryTask]
+}
+
+func NewDeliveryTaskRepository(db *gorm.DB) IDeliveryTaskRepository {
+	return &DeliveryTaskRepository{
+		BaseRepository: BaseRepository[entity.DeliveryTask]{DB: db},
+	}
+}
+
+func (r *DeliveryTaskRepository) FindById(ctx context.Context, id string) (entity.DeliveryTask, error) {
+	var task entity.DeliveryTask
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).Preload("Slot").First(&task).Error; err != nil {
+		return task, err
+	}
+	return task, nil
+}
+
+// FindFiltered mengambil daftar tugas kurir diurutkan sesuai jadwal antar jemput
+func (r *DeliveryTaskRepository) FindFiltered(ctx context.Context, filter entity.DeliveryTaskFilter, limit int, offset int) ([]entity.DeliveryTask, int64, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.DeliveryTask{}).
+		Joins("JOIN delivery_slots ON delivery_slots.id = delivery_tasks.slot_id")
+
+	if filter.Date != "" {
+		query = query.Where("delivery_slots.date = ?", filter.Date)
+	}
+	if filter.Status != "" {
+		query = query.Where("delivery_tasks.status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("delivery_tasks.type = ?", filter.Type)
+	}
+	if filter.CourierID != "" {
+		query = query.Where("delivery_tasks.courier_id = ?", filter.CourierID)
+	}
+	if filter.BranchID != "" {
+		query = query.Where("delivery_tasks.branch_id = ?", filter.BranchID)
+	}
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []entity.DeliveryTask
+	if err := query.Preload("Slot").
+		Order("delivery_slots.date ASC, delivery_slots.start_time ASC, delivery_tasks.created_at ASC").
+		Limit(limit).Offset(offset).
+		Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	return tasks, totalData, nil
+}
+
+// UpdateStatus mengubah status tugas hanya bila statusnya belum diubah oleh permintaan lain
+func (r *DeliveryTaskRepository) UpdateStatus(ctx context.Context, task *entity.DeliveryTask, fromStatus string) error {
+	result := r.DB.WithContext(ctx).Model(&entity.DeliveryTask{}).
+		Where("id = ? AND status = ?", task.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":       task.Status,
+			"completed_at": task.CompletedAt,
+			"notes":        task.Notes,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entity.ErrInvalidDeliveryTaskState
+	}
+	return nil
+}
+
+func (r *DeliveryTaskRepository) AssignCourier(ctx context.Context, id string, courierID *uuid.UUID) error {
+	return r.DB.WithContext(ctx).Model(&entity.DeliveryTask{}).Where("id = ?", id).
+		Update("courier_id", courierID).Error
+}
+
+// bookDeliveryTasks menyimpan tugas antar jemput rental. Jadwal dikunci agar kuota tidak terlampaui
+// oleh rental lain yang dibuat bersamaan.
+func bookDeliveryTasks(tx *gorm.DB, rental *entity.Rental) error {
+	for i := range rental.DeliveryTasks {
+		task := &rental.DeliveryTasks[i]
+		task.RentalID = rental.ID
+
+		var slot entity.DeliverySlot
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND is_active = ?", task.SlotID, true).
+			First(&slot).Error; err != nil {
+			return entity.ErrDeliverySlotNotFound
+		}
+
+		booked, err := countBookedTasks(tx, slot.ID)
+		if err != nil {
+			return err
+		}
+		if booked >= int64(slot.Capacity) {
+			return entity.ErrDeliverySlotFull
+		}
+
+		if err := tx.Omit("Slot").Create(task).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
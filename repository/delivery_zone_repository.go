@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+
+	"gorm.io/gorm"
+)
+
+type IDeliveryZoneRepository interface {
+	IBaseRepository[entity.DeliveryZone]
+	FindByBranch(ctx context.Context, branchID string, activeOnly bool) ([]entity.DeliveryZone, error)
+}
+
+type DeliveryZoneRepository struct {
+	BaseRepository[entity.DeliveryZone]
+}
+
+func NewDeliveryZoneRepository(db *gorm.DB) IDeliveryZoneRepository {
+	return &DeliveryZoneRepository{
+		BaseRepository: BaseRepository[entity.DeliveryZone]{DB: db},
+	}
+}
+
+// FindByBranch mengambil zona cabang, diurutkan dari ongkos termurah. Tanpa branchID semua zona diambil.
+func (r *DeliveryZoneRepository) FindByBranch(ctx context.Context, branchID string, activeOnly bool) ([]entity.DeliveryZone, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.DeliveryZone{})
+	if branchID != "" {
+		query = query.Where("branch_id = ?", branchID)
+	}
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var zones []entity.DeliveryZone
+	if err := query.Order("fee ASC, name ASC").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+func (r *DeliveryZoneRepository) UpdateById(ctx context.Context, id string, zone *entity.DeliveryZone) error {
+	return r.DB.WithContext(ctx).Model(&entity.DeliveryZone{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"branch_id":       zone.BranchID,
+		"name":            zone.Name,
+		"postal_codes":    zone.PostalCodes,
+		"min_distance_km": zone.MinDistanceKm,
+		"max_distance_km": zone.MaxDistanceKm,
+		"fee":             zone.Fee,
+		"is_active":       zone.IsActive,
+	}).Error
+}
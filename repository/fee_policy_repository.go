@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IFeePolicyRepository interface {
+	IBaseRepository[entity.FeePolicy]
+	FindActive(ctx context.Context) (entity.FeePolicy, error)
+	CreateVersion(ctx context.Context, policy *entity.FeePolicy, activate bool) error
+	Activate(ctx context.Context, id string) error
+	SeedDefault(ctx context.Context) (bool, error)
+}
+
+type FeePolicyRepository struct {
+	BaseRepository[entity.FeePolicy]
+}
+
+func NewFeePolicyRepository(db *gorm.DB) IFeePolicyRepository {
+	return &FeePolicyRepository{
+		BaseRepository: BaseRepository[entity.FeePolicy]{DB: db},
+	}
+}
+
+func (r *FeePolicyRepository) FindAll(ctx context.Context, limit int, offset int) ([]entity.FeePolicy, int64, error) {
+	var policies []entity.FeePolicy
+	if err := r.DB.WithContext(ctx).Preload("Rules").Order("version DESC").
+		Limit(limit).Offset(offset).Find(&policies).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalData int64
+	if err := r.DB.WithContext(ctx).Model(&entity.FeePolicy{}).Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+	return policies, totalData, nil
+}
+
+func (r *FeePolicyRepository) FindById(ctx context.Context, id string) (entity.FeePolicy, error) {
+	var policy entity.FeePolicy
+	if err := r.DB.WithContext(ctx).Preload("Rules").Where("id = ?", id).First(&policy).Error; err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+func (r *FeePolicyRepository) FindActive(ctx context.Context) (entity.FeePolicy, error) {
+	var policy entity.FeePolicy
+	if err := r.DB.WithContext(ctx).Preload("Rules").Where("is_active = ?", true).
+		Order("version DESC").First(&policy).Error; err != nil {
+		return policy, err
+	}
+	return policy, nil
+}
+
+// CreateVersion menyimpan kebijakan sebagai versi berikutnya
+func (r *FeePolicyRepository) CreateVersion(ctx context.Context, policy *entity.FeePolicy, activate bool) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Kunci tabel agar dua admin tidak mendapatkan nomor versi yang sama
+		if err := tx.Exec("LOCK TABLE fee_policies IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&entity.FeePolicy{}).Unscoped().
+			Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		policy.Version = latest + 1
+		policy.IsActive = false
+		if err := tx.Create(policy).Error; err != nil {
+			return err
+		}
+
+		if !activate {
+			return nil
+		}
+		return activatePolicy(tx, policy)
+	})
+}
+
+func (r *FeePolicyRepository) Activate(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var policy entity.FeePolicy
+		if err := tx.Where("id = ?", id).First(&policy).Error; err != nil {
+			return err
+		}
+		return activatePolicy(tx, &policy)
+	})
+}
+
+// SeedDefault membuat kebijakan awal jika belum ada kebijakan sama sekali
+func (r *FeePolicyRepository) SeedDefault(ctx context.Context) (bool, error) {
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&entity.FeePolicy{}).Unscoped().Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	now := time.Now()
+	policy := entity.DefaultFeePolicy()
+	policy.ActivatedAt = &now
+	if err := r.DB.WithContext(ctx).Create(&policy).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func activatePolicy(tx *gorm.DB, policy *entity.FeePolicy) error {
+	if err := tx.Model(&entity.FeePolicy{}).Where("is_active = ? AND id <> ?", true, policy.ID).
+		Update("is_active", false).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	policy.IsActive = true
+	policy.ActivatedAt = &now
+	return tx.Model(&entity.FeePolicy{}).Where("id = ?", policy.ID).Updates(map[string]interface{}{
+		"is_active":    true,
+		"activated_at": now,
+	}).Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IHolidayRepository interface {
+	IBaseRepository[entity.Holiday]
+	FindBetween(ctx context.Context, from time.Time, to time.Time) ([]entity.Holiday, error)
+	FindByDate(ctx context.Context, date time.Time) (entity.Holiday, error)
+}
+
+type HolidayRepository struct {
+	BaseRepository[entity.Holiday]
+}
+
+func NewHolidayRepository(db *gorm.DB) IHolidayRepository {
+	return &HolidayRepository{
+		BaseRepository: BaseRepository[entity.Holiday]{DB: db},
+	}
+}
+
+func (r *HolidayRepository) FindAll(ctx context.Context, limit int, offset int) ([]entity.Holiday, int64, error) {
+	var holidays []entity.Holiday
+	if err := r.DB.WithContext(ctx).Order("date ASC").Limit(limit).Offset(offset).Find(&holidays).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalData int64
+	if err := r.DB.WithContext(ctx).Model(&entity.Holiday{}).Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+	return holidays, totalData, nil
+}
+
+func (r *HolidayRepository) FindBetween(ctx context.Context, from time.Time, to time.Time) ([]entity.Holiday, error) {
+	var holidays []entity.Holiday
+	if err := r.DB.WithContext(ctx).
+		Where("date BETWEEN ? AND ?", from.Format(entity.HolidayDateFormat), to.Format(entity.HolidayDateFormat)).
+		Order("date ASC").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (r *HolidayRepository) FindByDate(ctx context.Context, date time.Time) (entity.Holiday, error) {
+	var holiday entity.Holiday
+	if err := r.DB.WithContext(ctx).Where("date = ?", date.Format(entity.HolidayDateFormat)).
+		First(&holiday).Error; err != nil {
+		return holiday, err
+	}
+	return holiday, nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+	"final-project/utils/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IJournalRepository sengaja tidak menyediakan ubah dan hapus, koreksi dicatat sebagai jurnal baru
+type IJournalRepository interface {
+	Post(ctx context.Context, entry *entity.JournalEntry) error
+	FindEntries(ctx context.Context, filter entity.JournalFilter, limit int, offset int) ([]entity.JournalEntry, int64, error)
+	GetTrialBalance(ctx context.Context, filter entity.JournalFilter) ([]entity.TrialBalanceItem, error)
+	FindExportRows(ctx context.Context, filter entity.JournalFilter) ([]entity.JournalExportRow, error)
+}
+
+type JournalRepository struct {
+	DB *gorm.DB
+}
+
+func NewJournalRepository(db *gorm.DB) IJournalRepository {
+	return &JournalRepository{
+		DB: db,
+	}
+}
+
+func (r *JournalRepository) Post(ctx context.Context, entry *entity.JournalEntry) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return postJournal(tx, entry)
+	})
+}
+
+func (r *JournalRepository) FindEntries(ctx context.Context, filter entity.JournalFilter, limit int, offset int) ([]entity.JournalEntry, int64, error) {
+	query := applyJournalFilter(r.DB.WithContext(ctx).Model(&entity.JournalEntry{}), filter)
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []entity.JournalEntry
+	if err := query.Preload("Lines").
+		Order("entry_date DESC").
+		Limit(limit).Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, totalData, nil
+}
+
+// GetTrialBalance menjumlahkan debit dan kredit per akun. Akun tanpa mutasi tetap ditampilkan.
+func (r *JournalRepository) GetTrialBalance(ctx context.Context, filter entity.JournalFilter) ([]entity.TrialBalanceItem, error) {
+	var totals []struct {
+		AccountCode string
+		Debit       money.Money
+		Credit      money.Money
+	}
+
+	query := applyJournalFilter(r.DB.WithContext(ctx).Model(&entity.JournalEntry{}), filter).
+		Joins("JOIN journal_lines jl ON jl.journal_entry_id = journal_entries.id AND jl.deleted_at IS NULL").
+		Select("jl.account_code, COALESCE(SUM(jl.debit), 0) AS debit, COALESCE(SUM(jl.credit), 0) AS credit").
+		Group("jl.account_code")
+	if err := query.Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]entity.TrialBalanceItem, 0, len(entity.ChartOfAccounts))
+	for _, account := range entity.ChartOfAccounts {
+		item := entity.TrialBalanceItem{
+			AccountCode: account.Code,
+			AccountName: account.Name,
+			AccountType: account.Type,
+		}
+		for _, total := range totals {
+			if total.AccountCode == account.Code {
+				item.Debit = total.Debit
+				item.Credit = total.Credit
+			}
+		}
+
+		item.Balance = item.Credit - item.Debit
+		if account.DebitNormal() {
+			item.Balance = item.Debit - item.Credit
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (r *JournalRepository) FindExportRows(ctx context.Context, filter entity.JournalFilter) ([]entity.JournalExportRow, error) {
+	var rows []entity.JournalExportRow
+
+	query := applyJournalFilter(r.DB.WithContext(ctx).Model(&entity.JournalEntry{}), filter).
+		Joins("JOIN journal_lines jl ON jl.journal_entry_id = journal_entries.id AND jl.deleted_at IS NULL").
+		Select(`journal_entries.entry_date, journal_entries.id AS journal_entry_id, journal_entries.event,
+			journal_entries.description, journal_entries.rental_id, jl.account_code, jl.debit, jl.credit`).
+		Order("journal_entries.entry_date ASC, journal_entries.id ASC, jl.debit DESC")
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func applyJournalFilter(query *gorm.DB, filter entity.JournalFilter) *gorm.DB {
+	if filter.StartDate != nil {
+		query = query.Where("journal_entries.entry_date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("journal_entries.entry_date <= ?", *filter.EndDate)
+	}
+	if filter.Event != "" {
+		query = query.Where("journal_entries.event = ?", filter.Event)
+	}
+	if filter.RentalID != "" {
+		query = query.Where("journal_entries.rental_id = ?", filter.RentalID)
+	}
+	return query
+}
+
+// postJournal menyimpan jurnal di dalam transaksi pemanggilnya. Jurnal kosong dilewati dan jurnal
+// untuk kejadian yang sudah dicatat diabaikan sehingga aman dipanggil ulang.
+func postJournal(tx *gorm.DB, entry *entity.JournalEntry) error {
+	if entry.IsEmpty() {
+		return nil
+	}
+	if !entry.IsBalanced() {
+		return entity.ErrUnbalancedJournal
+	}
+
+	lines := entry.Lines
+	result := tx.Omit("Lines").
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "event"}, {Name: "source_id"}}, DoNothing: true}).
+		Create(entry)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil
+	}
+
+	for i := range lines {
+		lines[i].JournalEntryID = entry.ID
+	}
+	entry.Lines = lines
+	return tx.Create(&entry.Lines).Error
+}
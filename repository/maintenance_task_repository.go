@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IMaintenanceTaskRepository interface {
+	IBaseRepository[entity.MaintenanceTask]
+	FindAllFiltered(ctx context.Context, filter entity.MaintenanceTaskFilter, limit int, offset int) ([]entity.MaintenanceTask, int64, error)
+	FindOpenByUnitID(ctx context.Context, unitID string) (entity.MaintenanceTask, error)
+	StartTask(ctx context.Context, task *entity.MaintenanceTask) error
+	CompleteTask(ctx context.Context, task *entity.MaintenanceTask, condition string, followUp *entity.MaintenanceTask) error
+}
+
+type MaintenanceTaskRepository struct {
+	BaseRepository[entity.MaintenanceTask]
+}
+
+func NewMaintenanceTaskRepository(db *gorm.DB) IMaintenanceTaskRepository {
+	return &MaintenanceTaskRepository{
+		BaseRepository: BaseRepository[entity.MaintenanceTask]{DB: db},
+	}
+}
+
+func (r *MaintenanceTaskRepository) FindById(ctx context.Context, id string) (entity.MaintenanceTask, error) {
+	var task entity.MaintenanceTask
+	if err := r.DB.WithContext(ctx).Preload("ToyUnit").Where("id = ?", id).First(&task).Error; err != nil {
+		return task, err
+	}
+	return task, nil
+}
+
+func (r *MaintenanceTaskRepository) FindAllFiltered(ctx context.Context, filter entity.MaintenanceTaskFilter, limit int, offset int) ([]entity.MaintenanceTask, int64, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.MaintenanceTask{})
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	} else {
+		query = query.Where("status <> ?", entity.MaintenanceStatusCompleted)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.ToyID != "" {
+		query = query.Where("toy_id = ?", filter.ToyID)
+	}
+	if filter.BranchID != "" {
+		query = query.Where("toy_unit_id IN (?)", r.DB.Model(&entity.ToyUnit{}).Select("id").Where("branch_id = ?", filter.BranchID))
+	}
+	if filter.Overdue {
+		query = query.Where("status <> ? AND due_at < ?", entity.MaintenanceStatusCompleted, time.Now())
+	}
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tasks []entity.MaintenanceTask
+	if err := query.Preload("ToyUnit").Order("due_at ASC").
+		Limit(limit).Offset(offset).Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	return tasks, totalData, nil
+}
+
+func (r *MaintenanceTaskRepository) FindOpenByUnitID(ctx context.Context, unitID string) (entity.MaintenanceTask, error) {
+	var task entity.MaintenanceTask
+	if err := r.DB.WithContext(ctx).
+		Where("toy_unit_id = ? AND status <> ?", unitID, entity.MaintenanceStatusCompleted).
+		First(&task).Error; err != nil {
+		return task, err
+	}
+	return task, nil
+}
+
+func (r *MaintenanceTaskRepository) StartTask(ctx context.Context, task *entity.MaintenanceTask) error {
+	return r.DB.WithContext(ctx).Model(&entity.MaintenanceTask{}).
+		Where("id = ? AND status = ?", task.ID, entity.MaintenanceStatusPending).
+		Updates(map[string]interface{}{
+			"status":     entity.MaintenanceStatusInProgress,
+			"started_at": task.StartedAt,
+			"started_by": task.StartedBy,
+			"notes":      task.Notes,
+		}).Error
+}
+
+// CompleteTask menutup tugas perawatan dan memperbarui unitnya sesuai hasil.
+// Unit yang dilepas kembali tersedia sehingga stok mainan dihitung ulang.
+func (r *MaintenanceTaskRepository) CompleteTask(ctx context.Context, task *entity.MaintenanceTask, condition string, followUp *entity.MaintenanceTask) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var unit entity.ToyUnit
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", task.ToyUnitID).First(&unit).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&entity.MaintenanceTask{}).
+			Where("id = ? AND status <> ?", task.ID, entity.MaintenanceStatusCompleted).
+			Updates(map[string]interface{}{
+				"status":       entity.MaintenanceStatusCompleted,
+				"outcome":      task.Outcome,
+				"started_at":   task.StartedAt,
+				"started_by":   task.StartedBy,
+				"completed_at": task.CompletedAt,
+				"completed_by": task.CompletedBy,
+				"notes":        task.Notes,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entity.ErrMaintenanceTaskClosed
+		}
+
+		statusTo := entity.ToyUnitStatusMaintenance
+		switch task.Outcome {
+		case entity.MaintenanceOutcomeReleased:
+			statusTo = entity.ToyUnitStatusAvailable
+		case entity.MaintenanceOutcomeWrittenOff:
+			statusTo = entity.ToyUnitStatusRetired
+		}
+		if condition == "" {
+			condition = unit.Condition
+		}
+
+		if err := tx.Model(&entity.ToyUnit{}).Where("id = ?", unit.ID).Updates(map[string]interface{}{
+			"status":    statusTo,
+			"condition": condition,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&entity.ToyUnitLog{
+			ToyUnitID:     unit.ID,
+			RentalItemID:  task.RentalItemID,
+			Event:         entity.ToyUnitEventMaintenance,
+			StatusFrom:    unit.Status,
+			StatusTo:      statusTo,
+			ConditionFrom: unit.Condition,
+			ConditionTo:   condition,
+			Notes:         task.Type + ": " + task.Outcome,
+			LoggedAt:      time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		if followUp != nil {
+			if err := tx.Omit("ToyUnit").Create(followUp).Error; err != nil {
+				return err
+			}
+		}
+
+		return syncToyStock(tx, unit.ToyID)
+	})
+}
@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"final-project/entity"
+	"final-project/utils/money"
 	"github.com/gofrs/uuid/v5"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 type IPaymentRepository interface {
@@ -13,6 +16,10 @@ type IPaymentRepository interface {
 	FindByRentalID(ctx context.Context, rentalID string) ([]entity.Payment, error)
 	UpdateByID(ctx context.Context, id string, payment *entity.Payment) error
 	SavePaymentWithMetadata(ctx context.Context, payment *entity.Payment) error
+	SumPaidByRentalID(ctx context.Context, rentalID string) (money.Money, error)
+	PayFromWallet(ctx context.Context, payment *entity.Payment, userID uuid.UUID, lines []entity.PriceLine) (money.Money, error)
+	FindByIdWithRental(ctx context.Context, id string) (entity.Payment, error)
+	AssignTaxInvoiceNumber(ctx context.Context, payment *entity.Payment) error
 }
 
 type PaymentRepository struct {
@@ -28,7 +35,7 @@ func NewPaymentRepository(db *gorm.DB) IPaymentRepository {
 func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID string) (entity.Payment, error) {
 	var payment entity.Payment
 
-	if err := r.DB.WithContext(ctx).Where("order_id = ?", orderID).First(&payment).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("Items").Where("order_id = ?", orderID).First(&payment).Error; err != nil {
 		return entity.Payment{}, err
 	}
 
@@ -38,7 +45,7 @@ func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID string) (
 func (r *PaymentRepository) FindByRentalID(ctx context.Context, rentalID string) ([]entity.Payment, error) {
 	var payments []entity.Payment
 
-	if err := r.DB.WithContext(ctx).Where("rental_id = ?", rentalID).Find(&payments).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("Items").Where("rental_id = ?", rentalID).Find(&payments).Error; err != nil {
 		return nil, err
 	}
 
@@ -63,3 +70,162 @@ func (r *PaymentRepository) UpdateByID(ctx context.Context, id string, payment *
 func (r *PaymentRepository) SavePaymentWithMetadata(ctx context.Context, payment *entity.Payment) error {
 	return r.DB.WithContext(ctx).Create(payment).Error
 }
+
+// SumPaidByRentalID menjumlahkan pembayaran yang sudah masuk untuk sebuah rental, tanpa deposit.
+// Pembayaran yang dikembalikan sebagian tetap dihitung karena yang dikembalikan hanya depositnya.
+func (r *PaymentRepository) SumPaidByRentalID(ctx context.Context, rentalID string) (money.Money, error) {
+	var paid money.Money
+
+	query := `
+		SELECT COALESCE(SUM(
+			CASE
+				WHEN EXISTS (SELECT 1 FROM payment_items pi WHERE pi.payment_id = p.id AND pi.deleted_at IS NULL)
+				THEN (
+					SELECT COALESCE(SUM(pi.amount), 0)
+					FROM payment_items pi
+					WHERE pi.payment_id = p.id AND pi.deleted_at IS NULL AND pi.kind <> ?
+				)
+				ELSE p.gross_amount
+			END
+		), 0)
+		FROM payments p
+		WHERE p.rental_id = ?
+			AND p.transaction_status IN (?, ?, ?)
+			AND p.deleted_at IS NULL
+	`
+
+	// Kelebihan bayar yang sudah dikembalikan ke dompet tidak lagi dihitung sebagai pembayaran rental
+	refundedQuery := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM wallet_entries
+		WHERE rental_id = ? AND reason = ? AND deleted_at IS NULL
+	`
+
+	var refunded money.Money
+	if err := r.DB.WithContext(ctx).Raw(refundedQuery, rentalID, entity.WalletReasonOverpayment).
+		Scan(&refunded).Error; err != nil {
+		return 0, err
+	}
+
+	if err := r.DB.WithContext(ctx).Raw(query, entity.PriceLineDeposit, rentalID,
+		entity.TransactionStatusCapture, entity.TransactionStatusSettlement, entity.TransactionStatusPartialRefund).
+		Scan(&paid).Error; err != nil {
+		return 0, err
+	}
+	return paid - refunded, nil
+}
+
+// PayFromWallet memotong saldo dompet sebanyak mungkin untuk rincian tagihan dan mencatatnya sebagai
+// pembayaran lunas. Saldo dibaca setelah dompet dikunci sehingga tidak bisa dipakai dua kali.
+func (r *PaymentRepository) PayFromWallet(ctx context.Context, payment *entity.Payment, userID uuid.UUID, lines []entity.PriceLine) (money.Money, error) {
+	var amount money.Money
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entry := &entity.WalletEntry{
+			UserID:   userID,
+			Type:     entity.WalletEntryDebit,
+			Reason:   entity.WalletReasonRentalPayment,
+			RentalID: &payment.RentalID,
+			Notes:    "Pembayaran rental dengan saldo dompet",
+		}
+
+		wallet, err := lockWallet(tx, entry)
+		if err != nil {
+			return err
+		}
+
+		total := entity.SumPriceLines(lines)
+		amount = money.Min(wallet.Balance, total)
+		if amount <= 0 {
+			amount = 0
+			return nil
+		}
+
+		// Bila saldo menutup seluruh tagihan rinciannya disimpan utuh, selain itu cukup satu baris potongan saldo
+		if amount >= total {
+			payment.SetItems(lines)
+		} else {
+			payment.SetItems([]entity.PriceLine{{
+				Kind:        entity.PriceLineWallet,
+				Description: "Dibayar dengan saldo dompet",
+				Quantity:    1,
+				UnitPrice:   amount,
+				Amount:      amount,
+			}})
+		}
+
+		now := time.Now()
+		payment.TransactionTime = &now
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+
+		entry.Amount = amount
+		entry.PaymentID = &payment.ID
+		return postWalletEntry(tx, entry)
+	})
+
+	return amount, err
+}
+
+func (r *PaymentRepository) FindByIdWithRental(ctx context.Context, id string) (entity.Payment, error) {
+	var payment entity.Payment
+
+	if err := r.DB.WithContext(ctx).
+		Preload("Items").
+		Preload("Rental").
+		Preload("Rental.User").
+		Where("id = ?", id).
+		First(&payment).Error; err != nil {
+		return entity.Payment{}, err
+	}
+
+	return payment, nil
+}
+
+// AssignTaxInvoiceNumber memberi nomor faktur berikutnya pada tahun berjalan. Urutan nomor dikunci per tahun
+// sehingga tidak ada nomor ganda atau loncat, dan pembayaran yang sudah bernomor tidak diberi nomor baru.
+func (r *PaymentRepository) AssignTaxInvoiceNumber(ctx context.Context, payment *entity.Payment) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current entity.Payment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", payment.ID).First(&current).Error; err != nil {
+			return err
+		}
+		if current.TaxInvoiceNumber != nil {
+			payment.TaxInvoiceNumber = current.TaxInvoiceNumber
+			payment.TaxInvoiceDate = current.TaxInvoiceDate
+			return nil
+		}
+
+		now := time.Now()
+		if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "year"}}, DoNothing: true}).
+			Create(&entity.TaxInvoiceSequence{Year: now.Year()}).Error; err != nil {
+			return err
+		}
+
+		var sequence entity.TaxInvoiceSequence
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("year = ?", now.Year()).First(&sequence).Error; err != nil {
+			return err
+		}
+
+		sequence.LastNumber++
+		if err := tx.Model(&entity.TaxInvoiceSequence{}).Where("year = ?", sequence.Year).
+			Update("last_number", sequence.LastNumber).Error; err != nil {
+			return err
+		}
+
+		number := entity.FormatTaxInvoiceNumber(sequence.Year, sequence.LastNumber)
+		if err := tx.Model(&entity.Payment{}).Where("id = ?", payment.ID).Updates(map[string]interface{}{
+			"tax_invoice_number": number,
+			"tax_invoice_date":   now,
+		}).Error; err != nil {
+			return err
+		}
+
+		payment.TaxInvoiceNumber = &number
+		payment.TaxInvoiceDate = &now
+		return nil
+	})
+}
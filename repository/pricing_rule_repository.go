@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+
+	"gorm.io/gorm"
+)
+
+type IPricingRuleRepository interface {
+	IBaseRepository[entity.PricingRule]
+	FindActive(ctx context.Context) ([]entity.PricingRule, error)
+}
+
+type PricingRuleRepository struct {
+	BaseRepository[entity.PricingRule]
+}
+
+func NewPricingRuleRepository(db *gorm.DB) IPricingRuleRepository {
+	return &PricingRuleRepository{
+		BaseRepository: BaseRepository[entity.PricingRule]{DB: db},
+	}
+}
+
+func (r *PricingRuleRepository) UpdateById(ctx context.Context, id string, rule *entity.PricingRule) error {
+	return r.DB.WithContext(ctx).Model(&entity.PricingRule{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":      rule.Name,
+		"type":      rule.Type,
+		"percent":   rule.Percent,
+		"min_days":  rule.MinDays,
+		"is_active": rule.IsActive,
+	}).Error
+}
+
+func (r *PricingRuleRepository) FindActive(ctx context.Context) ([]entity.PricingRule, error) {
+	var rules []entity.PricingRule
+	if err := r.DB.WithContext(ctx).Where("is_active = ?", true).
+		Order("type ASC, min_days ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
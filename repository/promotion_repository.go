@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IPromotionRepository interface {
+	IBaseRepository[entity.Promotion]
+	FindByCode(ctx context.Context, code string) (entity.Promotion, error)
+	CountRedemptions(ctx context.Context, promotionID string, userID string) (int64, error)
+	FindRedemptions(ctx context.Context, promotionID string, limit int, offset int) ([]entity.PromotionRedemption, int64, error)
+	GetUsageReport(ctx context.Context, startDate, endDate time.Time) ([]entity.PromotionUsageItem, error)
+}
+
+type PromotionRepository struct {
+	BaseRepository[entity.Promotion]
+}
+
+func NewPromotionRepository(db *gorm.DB) IPromotionRepository {
+	return &PromotionRepository{
+		BaseRepository: BaseRepository[entity.Promotion]{DB: db},
+	}
+}
+
+func (r *PromotionRepository) Insert(ctx context.Context, promotion *entity.Promotion) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Categories", "Toys").Create(promotion).Error; err != nil {
+			return err
+		}
+
+		return replacePromotionScope(tx, promotion.ID.String(), promotion)
+	})
+}
+
+func (r *PromotionRepository) UpdateById(ctx context.Context, id string, promotion *entity.Promotion) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Promotion{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"code":             promotion.Code,
+			"name":             promotion.Name,
+			"description":      promotion.Description,
+			"discount_type":    promotion.DiscountType,
+			"discount_value":   promotion.DiscountValue,
+			"min_order_amount": promotion.MinOrderAmount,
+			"max_discount":     promotion.MaxDiscount,
+			"starts_at":        promotion.StartsAt,
+			"ends_at":          promotion.EndsAt,
+			"usage_limit":      promotion.UsageLimit,
+			"per_user_limit":   promotion.PerUserLimit,
+			"is_active":        promotion.IsActive,
+		}).Error; err != nil {
+			return err
+		}
+
+		return replacePromotionScope(tx, id, promotion)
+	})
+}
+
+func (r *PromotionRepository) FindAll(ctx context.Context, limit int, offset int) ([]entity.Promotion, int64, error) {
+	var promotions []entity.Promotion
+	if err := r.DB.WithContext(ctx).
+		Preload("Categories").
+		Preload("Toys").
+		Order("created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&promotions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalData int64
+	if err := r.DB.WithContext(ctx).Model(&entity.Promotion{}).Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+	return promotions, totalData, nil
+}
+
+func (r *PromotionRepository) FindById(ctx context.Context, id string) (entity.Promotion, error) {
+	var promotion entity.Promotion
+	if err := r.DB.WithContext(ctx).
+		Preload("Categories").
+		Preload("Toys").
+		Where("id = ?", id).
+		First(&promotion).Error; err != nil {
+		return promotion, err
+	}
+	return promotion, nil
+}
+
+func (r *PromotionRepository) FindByCode(ctx context.Context, code string) (entity.Promotion, error) {
+	var promotion entity.Promotion
+	if err := r.DB.WithContext(ctx).
+		Preload("Categories").
+		Preload("Toys").
+		Where("code = ?", code).
+		First(&promotion).Error; err != nil {
+		return promotion, err
+	}
+	return promotion, nil
+}
+
+// CountRedemptions menghitung pemakaian promo yang masih berlaku, seluruhnya atau hanya milik satu
+// pengguna bila userID diisi
+func (r *PromotionRepository) CountRedemptions(ctx context.Context, promotionID string, userID string) (int64, error) {
+	query := activeRedemptions(r.DB.WithContext(ctx), promotionID)
+	if userID != "" {
+		query = query.Where("promotion_redemptions.user_id = ?", userID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *PromotionRepository) FindRedemptions(ctx context.Context, promotionID string, limit int, offset int) ([]entity.PromotionRedemption, int64, error) {
+	var redemptions []entity.PromotionRedemption
+	if err := r.DB.WithContext(ctx).
+		Where("promotion_id = ?", promotionID).
+		Order("redeemed_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&redemptions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalData int64
+	if err := r.DB.WithContext(ctx).Model(&entity.PromotionRedemption{}).
+		Where("promotion_id = ?", promotionID).
+		Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+	return redemptions, totalData, nil
+}
+
+func (r *PromotionRepository) GetUsageReport(ctx context.Context, startDate, endDate time.Time) ([]entity.PromotionUsageItem, error) {
+	var items []entity.PromotionUsageItem
+
+	query := `
+		SELECT 
+			p.id as promotion_id,
+			p.code,
+			p.name,
+			p.usage_limit,
+			COUNT(pr.id) as redemption_count,
+			COUNT(DISTINCT pr.user_id) as unique_users,
+			COALESCE(SUM(pr.discount_amount), 0) as total_discount,
+			COALESCE(SUM(r.total_rental_price), 0) as rental_revenue,
+			COUNT(r.id) FILTER (WHERE r.payment_status = 'paid') as paid_rental_count,
+			MAX(pr.redeemed_at) as last_redemption_at
+		FROM 
+			promotions p
+		LEFT JOIN 
+			promotion_redemptions pr ON pr.promotion_id = p.id
+			AND pr.deleted_at IS NULL
+			AND pr.redeemed_at BETWEEN ? AND ?
+		LEFT JOIN 
+			rentals r ON r.id = pr.rental_id AND r.deleted_at IS NULL
+		WHERE 
+			p.deleted_at IS NULL
+		GROUP BY 
+			p.id, p.code, p.name, p.usage_limit
+		ORDER BY 
+			total_discount DESC, p.code ASC
+	`
+
+	if err := r.DB.WithContext(ctx).Raw(query, startDate, endDate).Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// replacePromotionScope mengganti daftar kategori dan mainan yang dicakup promo
+func replacePromotionScope(tx *gorm.DB, promotionID string, promotion *entity.Promotion) error {
+	if err := tx.Exec("DELETE FROM promotion_categories WHERE promotion_id = ?", promotionID).Error; err != nil {
+		return err
+	}
+
+	for _, category := range promotion.Categories {
+		if err := tx.Exec("INSERT INTO promotion_categories (promotion_id, toy_category_id) VALUES (?, ?)",
+			promotionID, category.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Exec("DELETE FROM promotion_toys WHERE promotion_id = ?", promotionID).Error; err != nil {
+		return err
+	}
+
+	for _, toy := range promotion.Toys {
+		if err := tx.Exec("INSERT INTO promotion_toys (promotion_id, toy_id) VALUES (?, ?)",
+			promotionID, toy.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// activeRedemptions memilih pemakaian promo yang masih mengurangi kuota. Pemakaian pada rental yang
+// dibatalkan, dihapus atau pembayarannya gagal/kedaluwarsa dilepas kembali, sedangkan rental yang belum
+// dibayar tetap dihitung agar kuota tidak terlampaui oleh rental yang dibuat bersamaan.
+func activeRedemptions(db *gorm.DB, promotionID string) *gorm.DB {
+	return db.Model(&entity.PromotionRedemption{}).
+		Joins("JOIN rentals ON rentals.id = promotion_redemptions.rental_id AND rentals.deleted_at IS NULL").
+		Where("promotion_redemptions.promotion_id = ?", promotionID).
+		Where("rentals.status <> ?", entity.RentalStatusCancelled).
+		Where("rentals.payment_status NOT IN ?", []string{entity.PaymentStatusFailed, entity.PaymentStatusExpired})
+}
+
+// redeemPromotion mencatat pemakaian promo dalam transaksi pembuatan rental. Baris promo dikunci
+// agar kuota global dan per pengguna tetap terjaga saat beberapa rental dibuat bersamaan.
+func redeemPromotion(tx *gorm.DB, rental *entity.Rental) error {
+	var promotion entity.Promotion
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", rental.PromotionID).
+		First(&promotion).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if !promotion.IsRunning(now) {
+		return entity.ErrPromotionInactive
+	}
+
+	if promotion.UsageLimit > 0 {
+		var used int64
+		if err := activeRedemptions(tx, promotion.ID.String()).Count(&used).Error; err != nil {
+			return err
+		}
+		if used >= int64(promotion.UsageLimit) {
+			return entity.ErrPromotionUsageExceeded
+		}
+	}
+
+	if promotion.PerUserLimit > 0 {
+		var used int64
+		if err := activeRedemptions(tx, promotion.ID.String()).
+			Where("promotion_redemptions.user_id = ?", rental.UserID).
+			Count(&used).Error; err != nil {
+			return err
+		}
+		if used >= int64(promotion.PerUserLimit) {
+			return entity.ErrPromotionUserLimit
+		}
+	}
+
+	return tx.Create(&entity.PromotionRedemption{
+		PromotionID:    promotion.ID,
+		RentalID:       rental.ID,
+		UserID:         rental.UserID,
+		DiscountAmount: rental.DiscountAmount,
+		RedeemedAt:     now,
+	}).Error
+}
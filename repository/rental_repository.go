@@ -3,20 +3,24 @@ package repository
 import (
 	"context"
 	"final-project/entity"
+	"final-project/utils/money"
 	"fmt"
+	"github.com/gofrs/uuid/v5"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
 type IRentalRepository interface {
 	IBaseRepository[entity.Rental]
-	UpdateToyStock(ctx context.Context, toyID string, quantity int) error
-	ReturnRental(ctx context.Context, rental *entity.Rental) error
-	UpdateRentalItem(ctx context.Context, rentalItem *entity.RentalItem) error
+	ReturnRental(ctx context.Context, rental *entity.Rental, returnedItems []*entity.RentalItem, turnaround entity.MaintenanceTurnaround) error
+	FindAllByBranch(ctx context.Context, branchID string, limit int, offset int) ([]entity.Rental, int64, error)
 	UpdatePaymentStatus(ctx context.Context, rentalID string, status string) error
 	UpdateStatus(ctx context.Context, rentalID string, status string) error
-	ExtendRental(ctx context.Context, rental *entity.Rental, newExpectedReturnDate time.Time, additionalCost float64, notes string) error
-	RollbackExtension(ctx context.Context, rentalID string, oldExpectedReturnDate time.Time, oldPrice float64) error
+	ExtendRental(ctx context.Context, rental *entity.Rental, newExpectedReturnDate time.Time, additionalCost money.Money, notes string) error
+	RollbackExtension(ctx context.Context, rentalID string, oldExpectedReturnDate time.Time, oldPrice money.Money) error
+	UpdateDepositStatus(ctx context.Context, rentalID string, fromStatus string, toStatus string) (bool, error)
+	WriteOff(ctx context.Context, rental *entity.Rental, notes string) error
 }
 
 type RentalRepository struct {
@@ -34,6 +38,11 @@ func (r *RentalRepository) FindById(ctx context.Context, id string) (entity.Rent
 	err := r.DB.WithContext(ctx).Where("id = ?", id).
 		Preload("RentalItems").
 		Preload("RentalItems.Toy").
+		Preload("RentalItems.Units").
+		Preload("RentalItems.Units.ToyUnit").
+		Preload("PriceLines").
+		Preload("DeliveryTasks").
+		Preload("DeliveryTasks.Slot").
 		First(&model).Error
 
 	return model, err
@@ -41,18 +50,77 @@ func (r *RentalRepository) FindById(ctx context.Context, id string) (entity.Rent
 
 func (r *RentalRepository) Insert(ctx context.Context, model *entity.Rental) error {
 	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Omit("RentalItems").Create(model).Error; err != nil {
+		if err := tx.Omit("RentalItems", "DeliveryTasks").Create(model).Error; err != nil {
+			return err
+		}
+
+		if model.PromotionID != nil {
+			if err := redeemPromotion(tx, model); err != nil {
+				return err
+			}
+		}
+
+		if err := bookDeliveryTasks(tx, model); err != nil {
 			return err
 		}
 
 		for i := range model.RentalItems {
-			model.RentalItems[i].RentalID = model.ID
-			if err := tx.Create(&model.RentalItems[i]).Error; err != nil {
+			rentalItem := &model.RentalItems[i]
+			rentalItem.RentalID = model.ID
+
+			// Unit diambil dari cabang pengambilan, unit yang masih dalam perjalanan tidak ikut dialokasikan
+			unitQuery := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("toy_id = ? AND status = ? AND transfer_id IS NULL", rentalItem.ToyID, entity.ToyUnitStatusAvailable)
+			if model.PickupBranchID != nil {
+				unitQuery = unitQuery.Where("branch_id = ?", model.PickupBranchID)
+			}
+
+			var units []entity.ToyUnit
+			if err := unitQuery.
+				Order("serial_number ASC").
+				Limit(rentalItem.Quantity).
+				Find(&units).Error; err != nil {
 				return err
 			}
 
-			if err := tx.Model(&entity.Toy{}).Where("id = ?", model.RentalItems[i].ToyID).
-				UpdateColumn("stock", gorm.Expr("stock - ?", model.RentalItems[i].Quantity)).Error; err != nil {
+			if len(units) < rentalItem.Quantity {
+				return entity.ErrInsufficientUnits
+			}
+
+			if err := tx.Omit("Units").Create(rentalItem).Error; err != nil {
+				return err
+			}
+
+			for _, unit := range units {
+				itemUnit := entity.RentalItemUnit{
+					RentalItemID:    rentalItem.ID,
+					ToyUnitID:       unit.ID,
+					ConditionBefore: unit.Condition,
+				}
+				if err := tx.Omit("ToyUnit").Create(&itemUnit).Error; err != nil {
+					return err
+				}
+
+				if err := tx.Model(&entity.ToyUnit{}).Where("id = ?", unit.ID).
+					Update("status", entity.ToyUnitStatusRented).Error; err != nil {
+					return err
+				}
+
+				if err := tx.Create(&entity.ToyUnitLog{
+					ToyUnitID:     unit.ID,
+					RentalItemID:  &rentalItem.ID,
+					Event:         entity.ToyUnitEventRented,
+					StatusFrom:    unit.Status,
+					StatusTo:      entity.ToyUnitStatusRented,
+					ConditionFrom: unit.Condition,
+					ConditionTo:   unit.Condition,
+					LoggedAt:      time.Now(),
+				}).Error; err != nil {
+					return err
+				}
+			}
+
+			if err := syncToyStock(tx, rentalItem.ToyID); err != nil {
 				return err
 			}
 		}
@@ -60,41 +128,162 @@ func (r *RentalRepository) Insert(ctx context.Context, model *entity.Rental) err
 	})
 }
 
-func (r *RentalRepository) UpdateToyStock(ctx context.Context, toyID string, quantity int) error {
-	return r.DB.WithContext(ctx).Model(&entity.Toy{}).Where("id = ?", toyID).
-		UpdateColumn("stock", gorm.Expr("stock - ?", quantity)).Error
-}
-
-func (r *RentalRepository) ReturnRental(ctx context.Context, rental *entity.Rental) error {
+// ReturnRental menyimpan pengembalian rental beserta item yang dikembalikan dalam satu transaksi,
+// sehingga unit tidak masuk antrean perawatan bila pengembaliannya gagal tersimpan.
+func (r *RentalRepository) ReturnRental(ctx context.Context, rental *entity.Rental, returnedItems []*entity.RentalItem, turnaround entity.MaintenanceTurnaround) error {
 	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, rentalItem := range returnedItems {
+			if err := returnRentalItem(tx, rentalItem, rental.ReturnBranchID, turnaround); err != nil {
+				return err
+			}
+		}
+
 		if err := tx.Model(rental).
-			Select("status", "actual_return_date", "late_fee", "damage_fee", "total_amount").
+			Select("status", "payment_status", "actual_return_date", "late_fee", "damage_fee", "total_amount", "fee_policy_id", "fee_policy_version",
+				"deposit_status", "deposit_applied", "deposit_refunded", "deposit_settled_at", "return_branch_id").
 			Updates(rental).Error; err != nil {
 			return err
 		}
 
-		return nil
+		// Sisa deposit yang dipilih sebagai kredit langsung masuk ke dompet pengguna
+		if rental.DepositStatus == entity.DepositStatusCredited && rental.DepositRefunded > 0 {
+			if err := postWalletEntry(tx, &entity.WalletEntry{
+				UserID:   rental.UserID,
+				Type:     entity.WalletEntryCredit,
+				Amount:   rental.DepositRefunded,
+				Reason:   entity.WalletReasonDepositRefund,
+				RentalID: &rental.ID,
+				Notes:    "Sisa deposit rental",
+			}); err != nil {
+				return err
+			}
+		}
+
+		return postJournal(tx, entity.NewReturnJournal(*rental))
 	})
 }
 
-func (r *RentalRepository) UpdateRentalItem(ctx context.Context, rentalItem *entity.RentalItem) error {
+// WriteOff menghapus sisa piutang rental yang sudah dikembalikan dan mencatat bebannya di jurnal
+func (r *RentalRepository) WriteOff(ctx context.Context, rental *entity.Rental, notes string) error {
 	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(rentalItem).
-			Select("condition_after", "damage_description", "damage_fee", "status").
-			Updates(rentalItem).Error; err != nil {
+		if err := tx.Model(rental).
+			Select("status", "written_off_amount", "written_off_at").
+			Updates(rental).Error; err != nil {
 			return err
 		}
 
-		if rentalItem.Status == "returned" {
-			if err := tx.Model(&entity.Toy{}).
-				Where("id = ?", rentalItem.ToyID).
-				UpdateColumn("stock", gorm.Expr("stock + ?", rentalItem.Quantity)).Error; err != nil {
+		return postJournal(tx, entity.NewWriteOffJournal(*rental, rental.WrittenOffAmount, notes))
+	})
+}
+
+// returnRentalItem menyimpan hasil pengembalian item beserta kondisi setiap unitnya.
+// Item dari rental sebelum pelacakan unit (tanpa ToyUnitID) didaftarkan sebagai unit baru.
+// Unit yang kembali masuk antrean perawatan di cabang pengembalian dan stok baru bertambah setelah tugasnya selesai.
+func returnRentalItem(tx *gorm.DB, rentalItem *entity.RentalItem, branchID *uuid.UUID, turnaround entity.MaintenanceTurnaround) error {
+	if err := tx.Model(rentalItem).
+		Select("condition_after", "damage_description", "damage_fee", "status").
+		Updates(rentalItem).Error; err != nil {
+		return err
+	}
+
+	for i := range rentalItem.Units {
+		itemUnit := &rentalItem.Units[i]
+		statusFrom := itemUnit.ToyUnit.Status
+		conditionFrom := itemUnit.ToyUnit.Condition
+
+		if itemUnit.ToyUnitID == uuid.Nil {
+			units, err := newToyUnits(tx, rentalItem.ToyID, branchID, itemUnit.ConditionBefore, 1)
+			if err != nil {
+				return err
+			}
+			units[0].Status = entity.ToyUnitStatusRented
+
+			if err := createToyUnits(tx, units, "Registrasi unit dari rental sebelum pelacakan unit"); err != nil {
 				return err
 			}
+
+			itemUnit.ToyUnit = units[0]
+			itemUnit.ToyUnitID = units[0].ID
+			itemUnit.RentalItemID = rentalItem.ID
+			if err := tx.Omit("ToyUnit").Create(itemUnit).Error; err != nil {
+				return err
+			}
+
+			statusFrom = entity.ToyUnitStatusRented
+			conditionFrom = itemUnit.ConditionBefore
+		} else if err := tx.Model(itemUnit).
+			Select("condition_after", "damage_description", "damage_fee", "returned_at").
+			Updates(itemUnit).Error; err != nil {
+			return err
 		}
 
-		return nil
-	})
+		statusTo, conditionTo := entity.ToyUnitStateAfterReturn(itemUnit.ConditionAfter)
+		if conditionTo == "" {
+			conditionTo = conditionFrom
+		}
+
+		unitUpdates := map[string]interface{}{
+			"status":    statusTo,
+			"condition": conditionTo,
+		}
+		if branchID != nil {
+			unitUpdates["branch_id"] = branchID
+		}
+		if err := tx.Model(&entity.ToyUnit{}).Where("id = ?", itemUnit.ToyUnitID).Updates(unitUpdates).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&entity.ToyUnitLog{
+			ToyUnitID:     itemUnit.ToyUnitID,
+			RentalItemID:  &rentalItem.ID,
+			Event:         entity.ToyUnitEventReturned,
+			StatusFrom:    statusFrom,
+			StatusTo:      statusTo,
+			ConditionFrom: conditionFrom,
+			ConditionTo:   conditionTo,
+			Notes:         itemUnit.DamageDescription,
+			LoggedAt:      time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		if statusTo != entity.ToyUnitStatusMaintenance {
+			continue
+		}
+
+		taskType := entity.MaintenanceTypeForCondition(conditionTo)
+		if err := tx.Omit("ToyUnit").Create(&entity.MaintenanceTask{
+			ToyUnitID:    itemUnit.ToyUnitID,
+			ToyID:        rentalItem.ToyID,
+			RentalItemID: &rentalItem.ID,
+			Type:         taskType,
+			Status:       entity.MaintenanceStatusPending,
+			DueAt:        turnaround.DueAt(taskType, time.Now()),
+			Notes:        itemUnit.DamageDescription,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return syncToyStock(tx, rentalItem.ToyID)
+}
+
+// FindAllByBranch mengambil rental yang diambil atau dikembalikan di sebuah cabang
+func (r *RentalRepository) FindAllByBranch(ctx context.Context, branchID string, limit int, offset int) ([]entity.Rental, int64, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.Rental{}).
+		Where("pickup_branch_id = ? OR return_branch_id = ?", branchID, branchID)
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rentals []entity.Rental
+	if err := query.Order("rental_date DESC").
+		Limit(limit).Offset(offset).Find(&rentals).Error; err != nil {
+		return nil, 0, err
+	}
+	return rentals, totalData, nil
 }
 
 func (r *RentalRepository) UpdatePaymentStatus(ctx context.Context, rentalID string, status string) error {
@@ -102,12 +291,20 @@ func (r *RentalRepository) UpdatePaymentStatus(ctx context.Context, rentalID str
 		Update("payment_status", status).Error
 }
 
+// UpdateDepositStatus mengubah status deposit hanya bila status saat ini masih fromStatus
+func (r *RentalRepository) UpdateDepositStatus(ctx context.Context, rentalID string, fromStatus string, toStatus string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&entity.Rental{}).
+		Where("id = ? AND deposit_status = ?", rentalID, fromStatus).
+		Update("deposit_status", toStatus)
+	return result.RowsAffected > 0, result.Error
+}
+
 func (r *RentalRepository) UpdateStatus(ctx context.Context, rentalID string, status string) error {
 	return r.DB.WithContext(ctx).Model(&entity.Rental{}).Where("id = ?", rentalID).
 		Update("status", status).Error
 }
 
-func (r *RentalRepository) ExtendRental(ctx context.Context, rental *entity.Rental, newExpectedReturnDate time.Time, additionalCost float64, notes string) error {
+func (r *RentalRepository) ExtendRental(ctx context.Context, rental *entity.Rental, newExpectedReturnDate time.Time, additionalCost money.Money, notes string) error {
 	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Perbarui tanggal pengembalian yang diharapkan, total harga rental, dan catatan
 		fmt.Println("Additional Cost:", additionalCost)
@@ -130,7 +327,7 @@ func (r *RentalRepository) ExtendRental(ctx context.Context, rental *entity.Rent
 	})
 }
 
-func (r *RentalRepository) RollbackExtension(ctx context.Context, rentalID string, oldExpectedReturnDate time.Time, oldPrice float64) error {
+func (r *RentalRepository) RollbackExtension(ctx context.Context, rentalID string, oldExpectedReturnDate time.Time, oldPrice money.Money) error {
 	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Model(&entity.Rental{}).
 			Where("id = ?", rentalID).
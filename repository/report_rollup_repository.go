@@ -0,0 +1,654 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/utils/money"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IReportRollupRepository menyimpan dan membaca rollup harian laporan bisnis. Rollup sebuah hari selalu
+// dihitung ulang utuh dari transaksinya, sehingga pembaruan dari event dan pembangunan ulang malam hari
+// memakai query yang sama.
+type IReportRollupRepository interface {
+	RefreshDay(ctx context.Context, day time.Time) error
+	FindRentalDay(ctx context.Context, rentalID string) (time.Time, error)
+	FindDays(ctx context.Context, from time.Time) ([]time.Time, error)
+	MarkDirty(ctx context.Context, day time.Time) error
+	FindDirtyDays(ctx context.Context) ([]time.Time, error)
+	InsertRun(ctx context.Context, run *entity.ReportRollupRun) error
+	FinishRun(ctx context.Context, run *entity.ReportRollupRun) error
+	HasCompletedRun(ctx context.Context) (bool, error)
+	GetSalesReport(ctx context.Context, startDay, endDay time.Time, groupBy string, branchID string) ([]entity.SalesReportItem, error)
+	GetRentalStatusCount(ctx context.Context, startDay, endDay time.Time, branchID string) ([]entity.RentalStatusItem, error)
+	GetPopularToys(ctx context.Context, period entity.ReportRollupRange, limit int, branchID string) ([]entity.PopularToyItem, error)
+	GetTopCustomers(ctx context.Context, period entity.ReportRollupRange, limit int, branchID string) ([]entity.TopCustomerItem, error)
+	GetCategoryPerformance(ctx context.Context, period entity.ReportRollupRange, branchID string) ([]entity.CategorySummary, error)
+}
+
+type ReportRollupRepository struct {
+	DB       *gorm.DB
+	location *time.Location
+	dayExpr  string // tanggal rollup sebuah rental (alias r) dalam zona waktu laporan
+}
+
+// NewReportRollupRepository membuat repository rollup. location adalah zona waktu laporan yang menentukan
+// tanggal rollup setiap rental.
+func NewReportRollupRepository(db *gorm.DB, location *time.Location) IReportRollupRepository {
+	return &ReportRollupRepository{
+		DB:       db,
+		location: location,
+		dayExpr:  "(r.rental_date AT TIME ZONE " + sqlTimeZone(location) + ")::date",
+	}
+}
+
+// RefreshDay menghitung ulang seluruh rollup satu hari di dalam satu transaksi. Advisory lock per hari
+// mencegah dua pembaruan bersamaan menggandakan baris rollup.
+func (r *ReportRollupRepository) RefreshDay(ctx context.Context, day time.Time) error {
+	day = day.UTC().Truncate(24 * time.Hour)
+	scope := " AND r.rental_date >= @day_start AND r.rental_date < @day_end"
+	args := map[string]interface{}{
+		"day_start": entity.ReportDayStart(day, r.location),
+		"day_end":   entity.ReportDayStart(day.AddDate(0, 0, 1), r.location),
+	}
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "report_rollup:"+day.Format("2006-01-02")).Error; err != nil {
+			return err
+		}
+
+		for _, table := range []string{"report_daily_rentals", "report_daily_toys", "report_daily_categories"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE date = ?", day).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO report_daily_rentals (date, branch_id, user_id, status, rental_count, rental_revenue,
+				late_fee_revenue, damage_fee_revenue, deposit_amount, late_fee_count, damage_fee_count,
+				first_rental_at, last_rental_at)
+			`+dailyRentalsQuery(scope, r.dayExpr), args).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO report_daily_toys (date, branch_id, toy_id, rental_count, item_count, revenue,
+				returned_duration_sum, open_item_count, open_duration_offset)
+			`+dailyToysQuery(scope, r.dayExpr), args).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO report_daily_categories (date, branch_id, category_id, rental_count, item_count, quantity,
+				damaged_quantity, revenue, returned_duration_sum, open_item_count, open_duration_offset)
+			`+dailyCategoriesQuery(scope, r.dayExpr), args).Error; err != nil {
+			return err
+		}
+
+		// Hari yang sudah dihitung ulang tidak perlu lagi ikut pembangunan ulang berikutnya
+		if err := tx.Where("date = ?", day).Delete(&entity.ReportRollupDirtyDay{}).Error; err != nil {
+			return err
+		}
+
+		return refreshDailyTax(tx, day, scope, args)
+	})
+}
+
+// refreshDailyTax mengisi DPP dan PPN rollup rental. Perhitungannya per komponen tagihan setiap rental
+// dengan entity.TaxPolicy, sama seperti laporan penjualan yang dihitung langsung.
+func refreshDailyTax(tx *gorm.DB, day time.Time, scope string, args map[string]interface{}) error {
+	var rentals []struct {
+		BranchID         *uuid.UUID
+		UserID           uuid.UUID
+		Status           string
+		TotalRentalPrice money.Money
+		LateFee          money.Money
+		DamageFee        money.Money
+		TaxRate          float64
+		TaxInclusive     bool
+	}
+
+	query := `
+		SELECT
+			r.pickup_branch_id AS branch_id,
+			r.user_id,
+			r.status,
+			r.total_rental_price,
+			COALESCE(r.late_fee, 0) AS late_fee,
+			COALESCE(r.damage_fee, 0) AS damage_fee,
+			r.tax_rate,
+			r.tax_inclusive
+		FROM
+			rentals r
+		WHERE
+			r.deleted_at IS NULL` + scope + `
+	`
+	if err := tx.Raw(query, args).Scan(&rentals).Error; err != nil {
+		return err
+	}
+
+	type rollupKey struct {
+		BranchID uuid.UUID
+		UserID   uuid.UUID
+		Status   string
+	}
+	type rollupTax struct {
+		BranchID *uuid.UUID
+		Net      money.Money
+		Tax      money.Money
+		Gross    money.Money
+	}
+
+	taxes := make(map[rollupKey]*rollupTax)
+	var keys []rollupKey
+	for _, rental := range rentals {
+		key := rollupKey{UserID: rental.UserID, Status: rental.Status}
+		if rental.BranchID != nil {
+			key.BranchID = *rental.BranchID
+		}
+
+		total, ok := taxes[key]
+		if !ok {
+			total = &rollupTax{BranchID: rental.BranchID}
+			taxes[key] = total
+			keys = append(keys, key)
+		}
+
+		tax := entity.TaxPolicy{Rate: rental.TaxRate, Inclusive: rental.TaxInclusive}
+		for _, component := range []money.Money{rental.TotalRentalPrice, rental.LateFee, rental.DamageFee} {
+			gross, componentTax := tax.Apply(component)
+			total.Net += gross - componentTax
+			total.Tax += componentTax
+			total.Gross += gross
+		}
+	}
+
+	for _, key := range keys {
+		total := taxes[key]
+		err := tx.Model(&entity.ReportDailyRental{}).
+			Where("date = ? AND branch_id IS NOT DISTINCT FROM ? AND user_id = ? AND status = ?", day, total.BranchID, key.UserID, key.Status).
+			Updates(map[string]interface{}{
+				"net_revenue":   total.Net,
+				"tax_amount":    total.Tax,
+				"gross_revenue": total.Gross,
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindRentalDay mengambil tanggal rollup sebuah rental, termasuk rental yang sudah dihapus
+func (r *ReportRollupRepository) FindRentalDay(ctx context.Context, rentalID string) (time.Time, error) {
+	var rental entity.Rental
+	err := r.DB.WithContext(ctx).Unscoped().Select("rental_date").Where("id = ?", rentalID).First(&rental).Error
+	if err != nil {
+		return time.Time{}, err
+	}
+	return entity.ReportDay(rental.RentalDate, r.location), nil
+}
+
+// FindDays mengambil hari sejak from yang memiliki rental atau rollup, termasuk rollup dari rental yang
+// sudah dihapus agar ikut dibersihkan saat pembangunan ulang. from kosong berarti semua hari.
+func (r *ReportRollupRepository) FindDays(ctx context.Context, from time.Time) ([]time.Time, error) {
+	var days []string
+	query := `
+		SELECT
+			TO_CHAR(rollup_day, 'YYYY-MM-DD')
+		FROM (
+			SELECT ` + r.dayExpr + ` AS rollup_day FROM rentals r WHERE r.deleted_at IS NULL
+			UNION
+			SELECT date FROM report_daily_rentals
+			UNION
+			SELECT date FROM report_daily_toys
+			UNION
+			SELECT date FROM report_daily_categories
+		) days
+		WHERE
+			rollup_day >= ?
+		ORDER BY
+			1
+	`
+	if err := r.DB.WithContext(ctx).Raw(query, from.UTC().Truncate(24*time.Hour)).Scan(&days).Error; err != nil {
+		return nil, err
+	}
+	return parseRollupDays(days)
+}
+
+// MarkDirty menandai hari yang rollup-nya harus dihitung ulang pada pembangunan ulang berikutnya
+func (r *ReportRollupRepository) MarkDirty(ctx context.Context, day time.Time) error {
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.ReportRollupDirtyDay{
+		Date:     day.UTC().Truncate(24 * time.Hour),
+		MarkedAt: time.Now(),
+	}).Error
+}
+
+func (r *ReportRollupRepository) FindDirtyDays(ctx context.Context) ([]time.Time, error) {
+	var days []string
+	if err := r.DB.WithContext(ctx).Model(&entity.ReportRollupDirtyDay{}).
+		Select("TO_CHAR(date, 'YYYY-MM-DD')").
+		Order("date").
+		Scan(&days).Error; err != nil {
+		return nil, err
+	}
+	return parseRollupDays(days)
+}
+
+func parseRollupDays(days []string) ([]time.Time, error) {
+	result := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		parsed, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, parsed)
+	}
+	return result, nil
+}
+
+func (r *ReportRollupRepository) InsertRun(ctx context.Context, run *entity.ReportRollupRun) error {
+	return r.DB.WithContext(ctx).Create(run).Error
+}
+
+func (r *ReportRollupRepository) FinishRun(ctx context.Context, run *entity.ReportRollupRun) error {
+	return r.DB.WithContext(ctx).Model(&entity.ReportRollupRun{}).Where("id = ?", run.ID).
+		Select("status", "finished_at", "day_count", "error").
+		Updates(run).Error
+}
+
+func (r *ReportRollupRepository) HasCompletedRun(ctx context.Context) (bool, error) {
+	var run entity.ReportRollupRun
+	err := r.DB.WithContext(ctx).Select("id").Where("status = ?", entity.ReportRollupRunCompleted).First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *ReportRollupRepository) GetSalesReport(ctx context.Context, startDay, endDay time.Time, groupBy string, branchID string) ([]entity.SalesReportItem, error) {
+	var items []entity.SalesReportItem
+
+	dateExpr := "TO_CHAR(d.date, 'YYYY-MM-DD')"
+	switch groupBy {
+	case "week":
+		dateExpr = "TO_CHAR(DATE_TRUNC('week', d.date), 'YYYY-MM-DD')"
+	case "month":
+		dateExpr = "TO_CHAR(d.date, 'YYYY-MM')"
+	}
+
+	branchClause, branchArgs := rollupBranchFilter(branchID)
+	query := `
+		SELECT 
+			` + dateExpr + ` AS date,
+			SUM(d.rental_count) AS rental_count,
+			SUM(d.rental_revenue) AS rental_revenue,
+			SUM(d.late_fee_revenue) AS late_fee_revenue,
+			SUM(d.damage_fee_revenue) AS damage_fee_revenue,
+			SUM(d.rental_revenue + d.late_fee_revenue + d.damage_fee_revenue) AS total_revenue,
+			SUM(d.net_revenue) AS net_revenue,
+			SUM(d.tax_amount) AS tax_amount,
+			SUM(d.gross_revenue) AS gross_revenue,
+			SUM(d.deposit_amount) AS deposit_amount,
+			SUM(d.rental_count) AS transaction_count
+		FROM 
+			report_daily_rentals d
+		WHERE 
+			d.date BETWEEN ? AND ?` + branchClause + `
+		GROUP BY 
+			` + dateExpr + `
+		ORDER BY 
+			date ASC
+	`
+
+	args := append([]interface{}{startDay, endDay}, branchArgs...)
+	err := r.DB.WithContext(ctx).Raw(query, args...).Scan(&items).Error
+	return items, err
+}
+
+func (r *ReportRollupRepository) GetRentalStatusCount(ctx context.Context, startDay, endDay time.Time, branchID string) ([]entity.RentalStatusItem, error) {
+	var items []entity.RentalStatusItem
+	branchClause, branchArgs := rollupBranchFilter(branchID)
+
+	query := `
+		SELECT 
+			d.status,
+			SUM(d.rental_count) AS count
+		FROM 
+			report_daily_rentals d
+		WHERE 
+			d.date BETWEEN ? AND ?` + branchClause + `
+		GROUP BY 
+			d.status
+		ORDER BY 
+			count DESC
+	`
+
+	args := append([]interface{}{startDay, endDay}, branchArgs...)
+	err := r.DB.WithContext(ctx).Raw(query, args...).Scan(&items).Error
+	return items, err
+}
+
+// GetPopularToys menggabungkan rollup hari yang sudah lewat dengan item rental hari ini sebelum diurutkan,
+// sehingga batas jumlah mainan berlaku untuk seluruh periode
+func (r *ReportRollupRepository) GetPopularToys(ctx context.Context, period entity.ReportRollupRange, limit int, branchID string) ([]entity.PopularToyItem, error) {
+	var items []entity.PopularToyItem
+	rollupClause, liveScope := rollupScopes(branchID)
+
+	query := `
+		WITH daily AS (
+			SELECT 
+				d.toy_id,
+				d.rental_count,
+				d.item_count,
+				d.revenue,
+				` + rollupDurationExpr + ` AS duration_sum
+			FROM 
+				report_daily_toys d
+			WHERE 
+				d.date BETWEEN @start_day AND @end_day` + rollupClause + `
+			UNION ALL
+			SELECT 
+				d.toy_id,
+				d.rental_count,
+				d.item_count,
+				d.revenue,
+				` + rollupDurationExpr + ` AS duration_sum
+			FROM (` + dailyToysQuery(liveScope, r.dayExpr) + `) d
+		)
+		SELECT 
+			t.id AS toy_id,
+			t.name AS toy_name,
+			t.primary_image AS image_url,
+			SUM(daily.rental_count) AS rental_count,
+			SUM(daily.duration_sum) / NULLIF(SUM(daily.item_count), 0) AS average_duration,
+			SUM(daily.revenue) AS revenue
+		FROM 
+			daily
+		JOIN 
+			toys t ON daily.toy_id = t.id
+		WHERE 
+			t.deleted_at IS NULL
+		GROUP BY 
+			t.id, t.name, t.primary_image
+		ORDER BY 
+			rental_count DESC, revenue DESC
+		LIMIT @limit
+	`
+
+	args := rollupArgs(period, branchID)
+	args["limit"] = limit
+	err := r.DB.WithContext(ctx).Raw(query, args).Scan(&items).Error
+	return items, err
+}
+
+func (r *ReportRollupRepository) GetTopCustomers(ctx context.Context, period entity.ReportRollupRange, limit int, branchID string) ([]entity.TopCustomerItem, error) {
+	var items []entity.TopCustomerItem
+	rollupClause, liveScope := rollupScopes(branchID)
+
+	columns := `
+				d.user_id,
+				d.rental_count,
+				d.rental_revenue,
+				d.late_fee_revenue,
+				d.damage_fee_revenue,
+				d.late_fee_count,
+				d.damage_fee_count,
+				d.first_rental_at,
+				d.last_rental_at`
+
+	query := `
+		WITH daily AS (
+			SELECT ` + columns + `
+			FROM 
+				report_daily_rentals d
+			WHERE 
+				d.date BETWEEN @start_day AND @end_day` + rollupClause + `
+			UNION ALL
+			SELECT ` + columns + `
+			FROM (` + dailyRentalsQuery(liveScope, r.dayExpr) + `) d
+		)
+		SELECT 
+			u.id AS user_id,
+			u.full_name,
+			u.email,
+			u.phone_number,
+			SUM(daily.rental_count) AS rental_count,
+			SUM(daily.rental_revenue + daily.late_fee_revenue + daily.damage_fee_revenue) AS total_spent,
+			SUM(daily.rental_revenue) / NULLIF(SUM(daily.rental_count), 0) AS average_rental_value,
+			SUM(daily.late_fee_count) AS late_fee_count,
+			SUM(daily.damage_fee_count) AS damage_fee_count,
+			MIN(daily.first_rental_at) AS first_rental_date,
+			MAX(daily.last_rental_at) AS last_rental_date
+		FROM 
+			daily
+		JOIN 
+			users u ON daily.user_id = u.id
+		WHERE 
+			u.deleted_at IS NULL
+		GROUP BY 
+			u.id, u.full_name, u.email, u.phone_number
+		ORDER BY 
+			total_spent DESC, rental_count DESC
+		LIMIT @limit
+	`
+
+	args := rollupArgs(period, branchID)
+	args["limit"] = limit
+	err := r.DB.WithContext(ctx).Raw(query, args).Scan(&items).Error
+	return items, err
+}
+
+// GetCategoryPerformance membaca kinerja kategori dari rollup. Nama kategori diambil saat laporan dibaca,
+// sedangkan pembagian item ke kategorinya mengikuti keadaan saat rollup hari itu dihitung.
+func (r *ReportRollupRepository) GetCategoryPerformance(ctx context.Context, period entity.ReportRollupRange, branchID string) ([]entity.CategorySummary, error) {
+	var items []entity.CategorySummary
+	rollupClause, liveScope := rollupScopes(branchID)
+
+	columns := `
+				d.category_id,
+				d.rental_count,
+				d.item_count,
+				d.quantity,
+				d.damaged_quantity,
+				d.revenue,
+				` + rollupDurationExpr + ` AS duration_sum`
+
+	query := `
+		WITH daily AS (
+			SELECT ` + columns + `
+			FROM 
+				report_daily_categories d
+			WHERE 
+				d.date BETWEEN @start_day AND @end_day` + rollupClause + `
+			UNION ALL
+			SELECT ` + columns + `
+			FROM (` + dailyCategoriesQuery(liveScope, r.dayExpr) + `) d
+		)
+		SELECT 
+			daily.category_id,
+			COALESCE(c.name, 'Tanpa Kategori') AS name,
+			SUM(daily.rental_count) AS rental_count,
+			ROUND(SUM(daily.revenue), 2) AS revenue,
+			SUM(daily.duration_sum) / NULLIF(SUM(daily.item_count), 0) AS average_duration,
+			COALESCE(SUM(daily.damaged_quantity) * 100.0 / NULLIF(SUM(daily.quantity), 0), 0) AS damage_rate
+		FROM 
+			daily
+		LEFT JOIN 
+			toy_categories c ON daily.category_id = c.id
+		GROUP BY 
+			daily.category_id, c.name
+		ORDER BY 
+			revenue DESC, rental_count DESC
+	`
+
+	if err := r.DB.WithContext(ctx).Raw(query, rollupArgs(period, branchID)).Scan(&items).Error; err != nil {
+		return nil, err
+	}
+
+	var totalRevenue money.Money
+	for _, item := range items {
+		totalRevenue += item.Revenue
+	}
+	for i := range items {
+		if totalRevenue > 0 {
+			items[i].Percentage = items[i].Revenue.Float() / totalRevenue.Float() * 100
+		}
+	}
+	return items, nil
+}
+
+// rollupDurationExpr adalah total durasi item sebuah baris rollup (alias d) per hari ini. Item yang
+// belum kembali bertambah satu hari setiap hari sejak tanggal rollup.
+const rollupDurationExpr = "d.returned_duration_sum + d.open_duration_offset + d.open_item_count * (CURRENT_DATE - d.date)"
+
+// openDurationOffsetExpr adalah selisih durasi item yang belum kembali, dihitung seperti laporan langsung
+// dengan COALESCE(actual_return_date, CURRENT_DATE), terhadap jumlah hari sejak tanggal rollup
+func openDurationOffsetExpr(dayExpr string) string {
+	return "EXTRACT(DAY FROM (CURRENT_DATE - r.rental_date)) + 1 - (CURRENT_DATE - " + dayExpr + ")"
+}
+
+// dailyRentalsQuery menghitung baris report_daily_rentals dari rental yang memenuhi scope
+func dailyRentalsQuery(scope string, dayExpr string) string {
+	return `
+		SELECT 
+			` + dayExpr + ` AS date,
+			r.pickup_branch_id AS branch_id,
+			r.user_id,
+			r.status,
+			COUNT(*) AS rental_count,
+			SUM(r.total_rental_price) AS rental_revenue,
+			SUM(COALESCE(r.late_fee, 0)) AS late_fee_revenue,
+			SUM(COALESCE(r.damage_fee, 0)) AS damage_fee_revenue,
+			SUM(COALESCE(r.deposit_amount, 0)) AS deposit_amount,
+			COUNT(CASE WHEN r.late_fee > 0 THEN 1 END) AS late_fee_count,
+			COUNT(CASE WHEN r.damage_fee > 0 THEN 1 END) AS damage_fee_count,
+			MIN(r.rental_date) AS first_rental_at,
+			MAX(r.rental_date) AS last_rental_at
+		FROM 
+			rentals r
+		WHERE 
+			r.deleted_at IS NULL` + scope + `
+		GROUP BY 
+			` + dayExpr + `, r.pickup_branch_id, r.user_id, r.status`
+}
+
+// dailyToysQuery menghitung baris report_daily_toys dari item rental yang memenuhi scope
+func dailyToysQuery(scope string, dayExpr string) string {
+	return `
+		SELECT 
+			` + dayExpr + ` AS date,
+			r.pickup_branch_id AS branch_id,
+			ri.toy_id,
+			COUNT(DISTINCT ri.rental_id) AS rental_count,
+			COUNT(*) AS item_count,
+			SUM(ri.price_per_unit * ri.quantity) AS revenue,
+			COALESCE(SUM(EXTRACT(DAY FROM (r.actual_return_date - r.rental_date)) + 1) FILTER (WHERE r.actual_return_date IS NOT NULL), 0) AS returned_duration_sum,
+			COUNT(*) FILTER (WHERE r.actual_return_date IS NULL) AS open_item_count,
+			COALESCE(SUM(` + openDurationOffsetExpr(dayExpr) + `) FILTER (WHERE r.actual_return_date IS NULL), 0) AS open_duration_offset
+		FROM 
+			rental_items ri
+		JOIN 
+			rentals r ON ri.rental_id = r.id
+		WHERE 
+			r.deleted_at IS NULL
+			AND ri.deleted_at IS NULL` + scope + `
+		GROUP BY 
+			` + dayExpr + `, r.pickup_branch_id, ri.toy_id`
+}
+
+// dailyCategoriesQuery menghitung baris report_daily_categories dari item rental yang memenuhi scope.
+// Setiap item dipecah ke semua kategori mainannya seperti GetCategoryPerformance.
+func dailyCategoriesQuery(scope string, dayExpr string) string {
+	return `
+		WITH toy_category_ids AS (
+			SELECT 
+				tc.toy_id,
+				c.id AS category_id
+			FROM 
+				toy_toy_categories tc
+			JOIN 
+				toy_categories c ON tc.toy_category_id = c.id
+			WHERE 
+				c.deleted_at IS NULL
+		),
+		item_categories AS (
+			SELECT 
+				` + dayExpr + ` AS date,
+				r.pickup_branch_id AS branch_id,
+				ri.rental_id,
+				ri.quantity,
+				ri.price_per_unit * ri.quantity AS revenue,
+				(ri.status IN ('damaged', 'lost') OR ri.condition_after IN ('damaged', 'lost')) AS is_damaged,
+				r.actual_return_date IS NULL AS is_open,
+				CASE WHEN r.actual_return_date IS NOT NULL THEN EXTRACT(DAY FROM (r.actual_return_date - r.rental_date)) + 1 ELSE 0 END AS returned_duration,
+				CASE WHEN r.actual_return_date IS NULL THEN ` + openDurationOffsetExpr(dayExpr) + ` ELSE 0 END AS open_duration_offset,
+				tci.category_id,
+				COUNT(*) OVER (PARTITION BY ri.id) AS category_count
+			FROM 
+				rental_items ri
+			JOIN 
+				rentals r ON ri.rental_id = r.id
+			LEFT JOIN 
+				toy_category_ids tci ON ri.toy_id = tci.toy_id
+			WHERE 
+				r.deleted_at IS NULL
+				AND ri.deleted_at IS NULL` + scope + `
+		)
+		SELECT 
+			date,
+			branch_id,
+			category_id,
+			COUNT(DISTINCT rental_id) AS rental_count,
+			COUNT(*) AS item_count,
+			SUM(quantity) AS quantity,
+			SUM(CASE WHEN is_damaged THEN quantity ELSE 0 END) AS damaged_quantity,
+			SUM(revenue / category_count) AS revenue,
+			SUM(returned_duration) AS returned_duration_sum,
+			COUNT(*) FILTER (WHERE is_open) AS open_item_count,
+			SUM(open_duration_offset) AS open_duration_offset
+		FROM 
+			item_categories
+		GROUP BY 
+			date, branch_id, category_id`
+}
+
+// rollupScopes membatasi bagian rollup (alias d) dan bagian hari ini (alias r) pada cabang laporan.
+// Bagian hari ini memakai @live_start dan @live_end.
+func rollupScopes(branchID string) (rollupClause string, liveScope string) {
+	liveScope = " AND r.rental_date BETWEEN @live_start AND @live_end"
+	if branchID != "" {
+		rollupClause = " AND d.branch_id = @branch_id"
+		liveScope += " AND r.pickup_branch_id = @branch_id"
+	}
+	return rollupClause, liveScope
+}
+
+func rollupBranchFilter(branchID string) (string, []interface{}) {
+	if branchID == "" {
+		return "", nil
+	}
+	return " AND d.branch_id = ?", []interface{}{branchID}
+}
+
+// rollupArgs adalah argumen bernama untuk query yang menggabungkan rollup dengan transaksi hari ini
+func rollupArgs(period entity.ReportRollupRange, branchID string) map[string]interface{} {
+	return map[string]interface{}{
+		"start_day":  period.StartDay,
+		"end_day":    period.EndDay,
+		"live_start": period.LiveStart,
+		"live_end":   period.LiveEnd,
+		"branch_id":  branchID,
+	}
+}
+
+// sqlTimeZone adalah nama zona waktu location sebagai literal SQL untuk AT TIME ZONE
+func sqlTimeZone(location *time.Location) string {
+	return "'" + strings.ReplaceAll(location.String(), "'", "''") + "'"
+}
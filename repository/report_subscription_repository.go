@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IReportSubscriptionRepository interface {
+	IBaseRepository[entity.ReportSubscription]
+	FindByBranch(ctx context.Context, branchID string, limit int, offset int) ([]entity.ReportSubscription, int64, error)
+	FindDue(ctx context.Context, now time.Time, limit int) ([]entity.ReportSubscription, error)
+	ClaimRun(ctx context.Context, id string, scheduledAt time.Time, nextRunAt *time.Time) (bool, error)
+	InsertDelivery(ctx context.Context, delivery *entity.ReportDelivery) error
+	FindDeliveries(ctx context.Context, subscriptionID string, limit int, offset int) ([]entity.ReportDelivery, int64, error)
+}
+
+type ReportSubscriptionRepository struct {
+	BaseRepository[entity.ReportSubscription]
+}
+
+func NewReportSubscriptionRepository(db *gorm.DB) IReportSubscriptionRepository {
+	return &ReportSubscriptionRepository{
+		BaseRepository: BaseRepository[entity.ReportSubscription]{DB: db},
+	}
+}
+
+// UpdateById menyimpan kolom yang bisa diubah admin, termasuk nilai kosong seperti is_active false.
+// Parameters diperbarui lewat struct agar serializer JSON-nya tetap dipakai.
+func (r *ReportSubscriptionRepository) UpdateById(ctx context.Context, id string, subscription *entity.ReportSubscription) error {
+	return r.DB.WithContext(ctx).Model(&entity.ReportSubscription{}).Where("id = ?", id).
+		Select("name", "report_type", "parameters", "schedule", "recipients", "format", "branch_id", "is_active", "next_run_at").
+		Updates(subscription).Error
+}
+
+// FindByBranch mengambil langganan terbaru lebih dulu. Tanpa branchID semua langganan diambil.
+func (r *ReportSubscriptionRepository) FindByBranch(ctx context.Context, branchID string, limit int, offset int) ([]entity.ReportSubscription, int64, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.ReportSubscription{})
+	if branchID != "" {
+		query = query.Where("branch_id = ?", branchID)
+	}
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var subscriptions []entity.ReportSubscription
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&subscriptions).Error; err != nil {
+		return nil, 0, err
+	}
+	return subscriptions, totalData, nil
+}
+
+// FindDue mengambil langganan aktif yang jadwalnya sudah tiba, yang paling lama tertunda lebih dulu
+func (r *ReportSubscriptionRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]entity.ReportSubscription, error) {
+	var subscriptions []entity.ReportSubscription
+	err := r.DB.WithContext(ctx).
+		Where("is_active = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// ClaimRun memajukan jadwal hanya bila next_run_at masih sama dengan scheduledAt, sehingga bila ada
+// beberapa proses server hanya satu yang mengirim laporan untuk jadwal yang sama.
+func (r *ReportSubscriptionRepository) ClaimRun(ctx context.Context, id string, scheduledAt time.Time, nextRunAt *time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&entity.ReportSubscription{}).
+		Where("id = ? AND next_run_at = ?", id, scheduledAt).
+		Updates(map[string]interface{}{
+			"next_run_at": nextRunAt,
+			"last_run_at": scheduledAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *ReportSubscriptionRepository) InsertDelivery(ctx context.Context, delivery *entity.ReportDelivery) error {
+	return r.DB.WithContext(ctx).Create(delivery).Error
+}
+
+func (r *ReportSubscriptionRepository) FindDeliveries(ctx context.Context, subscriptionID string, limit int, offset int) ([]entity.ReportDelivery, int64, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.ReportDelivery{}).Where("subscription_id = ?", subscriptionID)
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []entity.ReportDelivery
+	if err := query.Order("scheduled_at DESC, created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, totalData, nil
+}
@@ -1,12 +1,23 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"final-project/entity"
+	"final-project/utils/helpers"
 	"gorm.io/gorm"
 )
 
 type IToyCategoryRepository interface {
 	IBaseRepository[entity.ToyCategory]
+	FindAllOrdered(ctx context.Context) ([]entity.ToyCategory, error)
+	FindBySlug(ctx context.Context, slug string) (entity.ToyCategory, error)
+	AvailableSlug(ctx context.Context, id string, name string) (string, error)
+	BackfillSlugs(ctx context.Context) (int64, error)
+	FindDescendantIDs(ctx context.Context, id string) ([]string, error)
+	CountToys(ctx context.Context, id string) (int64, error)
+	CountChildren(ctx context.Context, id string) (int64, error)
+	ReassignAndDelete(ctx context.Context, id string, targetID string) error
 }
 
 type ToyCategoryRepository struct {
@@ -18,3 +29,154 @@ func NewToyCategoryRepository(db *gorm.DB) IToyCategoryRepository {
 		BaseRepository: &BaseRepository[entity.ToyCategory]{DB: db},
 	}
 }
+
+func (r *ToyCategoryRepository) FindAll(ctx context.Context, limit int, offset int) ([]entity.ToyCategory, int64, error) {
+	var entities []entity.ToyCategory
+	if err := r.DB.WithContext(ctx).
+		Order("sort_order ASC, name ASC").
+		Limit(limit).Offset(offset).
+		Find(&entities).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalData int64
+	if err := r.DB.WithContext(ctx).Model(new(entity.ToyCategory)).Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+	return entities, totalData, nil
+}
+
+func (r *ToyCategoryRepository) UpdateById(ctx context.Context, id string, category *entity.ToyCategory) error {
+	return r.DB.WithContext(ctx).Model(&entity.ToyCategory{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"parent_id":   category.ParentID,
+		"name":        category.Name,
+		"slug":        category.Slug,
+		"description": category.Description,
+		"sort_order":  category.SortOrder,
+	}).Error
+}
+
+func (r *ToyCategoryRepository) FindAllOrdered(ctx context.Context) ([]entity.ToyCategory, error) {
+	var categories []entity.ToyCategory
+	if err := r.DB.WithContext(ctx).Order("sort_order ASC, name ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *ToyCategoryRepository) FindBySlug(ctx context.Context, slug string) (entity.ToyCategory, error) {
+	var category entity.ToyCategory
+	if err := r.DB.WithContext(ctx).Where("slug = ?", slug).First(&category).Error; err != nil {
+		return category, err
+	}
+	return category, nil
+}
+
+// AvailableSlug membuat slug dari nama kategori yang belum dipakai kategori lain selain id
+func (r *ToyCategoryRepository) AvailableSlug(ctx context.Context, id string, name string) (string, error) {
+	return availableCategorySlug(r.DB.WithContext(ctx), id, name)
+}
+
+// BackfillSlugs mengisi slug kategori yang dibuat sebelum kategori memiliki slug
+func (r *ToyCategoryRepository) BackfillSlugs(ctx context.Context) (int64, error) {
+	var filled int64
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var categories []entity.ToyCategory
+		if err := tx.Where("slug IS NULL OR slug = ''").Order("created_at ASC").Find(&categories).Error; err != nil {
+			return err
+		}
+
+		for _, category := range categories {
+			slug, err := availableCategorySlug(tx, category.ID.String(), category.Name)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&entity.ToyCategory{}).Where("id = ?", category.ID).Update("slug", slug).Error; err != nil {
+				return err
+			}
+			filled++
+		}
+		return nil
+	})
+	return filled, err
+}
+
+func availableCategorySlug(tx *gorm.DB, id string, name string) (string, error) {
+	base := helpers.Slugify(name)
+	for n := 1; ; n++ {
+		slug := entity.CategorySlug(base, n)
+
+		var existing entity.ToyCategory
+		err := tx.Select("id").Where("slug = ?", slug).First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return slug, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if existing.ID.String() == id {
+			return slug, nil
+		}
+	}
+}
+
+// FindDescendantIDs mengembalikan ID kategori beserta seluruh turunannya
+func (r *ToyCategoryRepository) FindDescendantIDs(ctx context.Context, id string) ([]string, error) {
+	var ids []string
+
+	query := `
+		WITH RECURSIVE category_tree AS (
+			SELECT id FROM toy_categories WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id FROM toy_categories c
+			JOIN category_tree ct ON c.parent_id = ct.id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT id FROM category_tree
+	`
+
+	if err := r.DB.WithContext(ctx).Raw(query, id).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *ToyCategoryRepository) CountToys(ctx context.Context, id string) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Table("toy_toy_categories ttc").
+		Joins("JOIN toys t ON t.id = ttc.toy_id AND t.deleted_at IS NULL").
+		Where("ttc.toy_category_id = ?", id).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ToyCategoryRepository) CountChildren(ctx context.Context, id string) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&entity.ToyCategory{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// ReassignAndDelete memindahkan mainan dan sub kategori ke kategori tujuan lalu menghapus kategori
+func (r *ToyCategoryRepository) ReassignAndDelete(ctx context.Context, id string, targetID string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO toy_toy_categories (toy_id, toy_category_id)
+			SELECT toy_id, ? FROM toy_toy_categories
+			WHERE toy_category_id = ?
+			AND toy_id NOT IN (SELECT toy_id FROM toy_toy_categories WHERE toy_category_id = ?)
+		`, targetID, id, targetID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM toy_toy_categories WHERE toy_category_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.ToyCategory{}).Where("parent_id = ?", id).
+			Update("parent_id", targetID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.ToyCategory{}, "id = ?", id).Error
+	})
+}
@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"final-project/entity"
+	"github.com/gofrs/uuid/v5"
 	"gorm.io/gorm"
 )
 
 type IToyRepository interface {
 	IBaseRepository[entity.Toy]
+	FindAllFiltered(ctx context.Context, filter entity.ToyFilter, limit int, offset int) ([]entity.Toy, int64, error)
 }
 
 type ToyRepository struct {
@@ -28,10 +30,13 @@ func (r *ToyRepository) Insert(ctx context.Context, toy *entity.Toy) error {
 			AgeRecommendation: toy.AgeRecommendation,
 			Condition:         toy.Condition,
 			RentalPrice:       toy.RentalPrice,
+			WeeklyPrice:       toy.WeeklyPrice,
+			MonthlyPrice:      toy.MonthlyPrice,
+			MinRentalDays:     toy.MinRentalDays,
 			LateFeePerDay:     toy.LateFeePerDay,
 			ReplacementPrice:  toy.ReplacementPrice,
+			DepositAmount:     toy.DepositAmount,
 			IsAvailable:       toy.IsAvailable,
-			Stock:             toy.Stock,
 			PrimaryImage:      toy.PrimaryImage,
 		}
 
@@ -39,6 +44,25 @@ func (r *ToyRepository) Insert(ctx context.Context, toy *entity.Toy) error {
 			return err
 		}
 
+		// Unit awal disimpan di cabang utama, pindahkan lewat transfer antar cabang bila perlu
+		branchID, err := defaultBranchID(tx)
+		if err != nil {
+			return err
+		}
+
+		units, err := newToyUnits(tx, toyWithoutRelations.ID, branchID, toy.Condition, toy.Stock)
+		if err != nil {
+			return err
+		}
+
+		if err := createToyUnits(tx, units, "Registrasi unit awal"); err != nil {
+			return err
+		}
+
+		if err := syncToyStock(tx, toyWithoutRelations.ID); err != nil {
+			return err
+		}
+
 		for _, category := range toy.Categories {
 			if err := tx.Exec("INSERT INTO toy_toy_categories (toy_id, toy_category_id) VALUES (?, ?)",
 				toyWithoutRelations.ID, category.ID).Error; err != nil {
@@ -67,10 +91,13 @@ func (r *ToyRepository) UpdateById(ctx context.Context, id string, toy *entity.T
 			"age_recommendation": toy.AgeRecommendation,
 			"condition":          toy.Condition,
 			"rental_price":       toy.RentalPrice,
+			"weekly_price":       toy.WeeklyPrice,
+			"monthly_price":      toy.MonthlyPrice,
+			"min_rental_days":    toy.MinRentalDays,
 			"late_fee_per_day":   toy.LateFeePerDay,
 			"replacement_price":  toy.ReplacementPrice,
+			"deposit_amount":     toy.DepositAmount,
 			"is_available":       toy.IsAvailable,
-			"stock":              toy.Stock,
 			"primary_image":      toy.PrimaryImage,
 		}).Error; err != nil {
 			return err
@@ -130,3 +157,67 @@ func (r *ToyRepository) FindById(ctx context.Context, id string) (entity.Toy, er
 	}
 	return toy, nil
 }
+
+// FindAllFiltered mengambil katalog mainan sesuai filter kategori dan cabang
+func (r *ToyRepository) FindAllFiltered(ctx context.Context, filter entity.ToyFilter, limit int, offset int) ([]entity.Toy, int64, error) {
+	query := r.DB.WithContext(ctx).Model(&entity.Toy{})
+
+	if len(filter.CategoryIDs) > 0 {
+		subQuery := r.DB.Table("toy_toy_categories").Select("toy_id").Where("toy_category_id IN ?", filter.CategoryIDs)
+		query = query.Where("id IN (?)", subQuery)
+	}
+	if filter.BranchID != "" {
+		query = query.Where("id IN (?)", r.branchStockQuery(filter.BranchID).Select("toy_id"))
+	}
+
+	var totalData int64
+	if err := query.Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entities []entity.Toy
+	if err := query.
+		Preload("Categories").
+		Preload("Images").
+		Limit(limit).Offset(offset).
+		Find(&entities).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.BranchID == "" || len(entities) == 0 {
+		return entities, totalData, nil
+	}
+
+	// Stok yang ditampilkan adalah unit tersedia di cabang yang dipilih
+	toyIDs := make([]uuid.UUID, len(entities))
+	for i := range entities {
+		toyIDs[i] = entities[i].ID
+	}
+
+	var stocks []struct {
+		ToyID uuid.UUID
+		Stock int
+	}
+	if err := r.branchStockQuery(filter.BranchID).WithContext(ctx).
+		Select("toy_id, COUNT(*) AS stock").
+		Where("toy_id IN ?", toyIDs).
+		Group("toy_id").
+		Scan(&stocks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	stockByToy := make(map[uuid.UUID]int, len(stocks))
+	for _, stock := range stocks {
+		stockByToy[stock.ToyID] = stock.Stock
+	}
+	for i := range entities {
+		entities[i].Stock = stockByToy[entities[i].ID]
+	}
+	return entities, totalData, nil
+}
+
+// branchStockQuery memilih unit yang siap disewakan di sebuah cabang
+func (r *ToyRepository) branchStockQuery(branchID string) *gorm.DB {
+	return r.DB.Model(&entity.ToyUnit{}).
+		Where("branch_id = ? AND status = ? AND transfer_id IS NULL", branchID, entity.ToyUnitStatusAvailable)
+}
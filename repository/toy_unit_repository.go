@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type IToyUnitRepository interface {
+	IBaseRepository[entity.ToyUnit]
+	FindByToyID(ctx context.Context, toyID string, status string, branchID string) ([]entity.ToyUnit, error)
+	FindBySerialNumber(ctx context.Context, serialNumber string) (entity.ToyUnit, error)
+	RegisterUnits(ctx context.Context, toyID uuid.UUID, units []entity.ToyUnit) ([]entity.ToyUnit, error)
+	GenerateUnits(ctx context.Context, toyID uuid.UUID, branchID *uuid.UUID, condition string, quantity int) ([]entity.ToyUnit, error)
+	UpdateUnit(ctx context.Context, unit *entity.ToyUnit, log *entity.ToyUnitLog) error
+	FindRentalHistory(ctx context.Context, unitID string) ([]entity.ToyUnitRentalHistory, error)
+	FindLogs(ctx context.Context, unitID string) ([]entity.ToyUnitLog, error)
+	BackfillFromStock(ctx context.Context) (int64, error)
+}
+
+type ToyUnitRepository struct {
+	BaseRepository[entity.ToyUnit]
+}
+
+func NewToyUnitRepository(db *gorm.DB) IToyUnitRepository {
+	return &ToyUnitRepository{
+		BaseRepository: BaseRepository[entity.ToyUnit]{DB: db},
+	}
+}
+
+func (r *ToyUnitRepository) FindByToyID(ctx context.Context, toyID string, status string, branchID string) ([]entity.ToyUnit, error) {
+	var units []entity.ToyUnit
+
+	query := r.DB.WithContext(ctx).Where("toy_id = ?", toyID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if branchID != "" {
+		query = query.Where("branch_id = ?", branchID)
+	}
+
+	if err := query.Order("serial_number ASC").Find(&units).Error; err != nil {
+		return nil, err
+	}
+	return units, nil
+}
+
+func (r *ToyUnitRepository) FindBySerialNumber(ctx context.Context, serialNumber string) (entity.ToyUnit, error) {
+	var unit entity.ToyUnit
+	if err := r.DB.WithContext(ctx).Where("serial_number = ?", serialNumber).First(&unit).Error; err != nil {
+		return unit, err
+	}
+	return unit, nil
+}
+
+func (r *ToyUnitRepository) RegisterUnits(ctx context.Context, toyID uuid.UUID, units []entity.ToyUnit) ([]entity.ToyUnit, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createToyUnits(tx, units, "Registrasi unit baru"); err != nil {
+			return err
+		}
+
+		return syncToyStock(tx, toyID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return units, nil
+}
+
+// GenerateUnits mendaftarkan sejumlah unit baru dengan nomor seri otomatis
+func (r *ToyUnitRepository) GenerateUnits(ctx context.Context, toyID uuid.UUID, branchID *uuid.UUID, condition string, quantity int) ([]entity.ToyUnit, error) {
+	var units []entity.ToyUnit
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		generated, err := newToyUnits(tx, toyID, branchID, condition, quantity)
+		if err != nil {
+			return err
+		}
+
+		if err := createToyUnits(tx, generated, "Registrasi unit baru"); err != nil {
+			return err
+		}
+
+		units = generated
+		return syncToyStock(tx, toyID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return units, nil
+}
+
+func (r *ToyUnitRepository) UpdateUnit(ctx context.Context, unit *entity.ToyUnit, log *entity.ToyUnitLog) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.ToyUnit{}).Where("id = ?", unit.ID).Updates(map[string]interface{}{
+			"barcode":   unit.Barcode,
+			"condition": unit.Condition,
+			"status":    unit.Status,
+			"notes":     unit.Notes,
+		}).Error; err != nil {
+			return err
+		}
+
+		if log != nil {
+			if err := tx.Create(log).Error; err != nil {
+				return err
+			}
+		}
+
+		return syncToyStock(tx, unit.ToyID)
+	})
+}
+
+func (r *ToyUnitRepository) FindRentalHistory(ctx context.Context, unitID string) ([]entity.ToyUnitRentalHistory, error) {
+	var items []entity.ToyUnitRentalHistory
+
+	query := `
+		SELECT
+			r.id AS rental_id,
+			ri.id AS rental_item_id,
+			r.user_id,
+			u.full_name AS customer_name,
+			r.status AS rental_status,
+			r.rental_date,
+			riu.returned_at,
+			riu.condition_before,
+			riu.condition_after,
+			riu.damage_description,
+			COALESCE(riu.damage_fee, 0) AS damage_fee
+		FROM
+			rental_item_units riu
+		JOIN
+			rental_items ri ON ri.id = riu.rental_item_id
+		JOIN
+			rentals r ON r.id = ri.rental_id
+		LEFT JOIN
+			users u ON u.id = r.user_id
+		WHERE
+			riu.toy_unit_id = ?
+			AND riu.deleted_at IS NULL
+			AND r.deleted_at IS NULL
+		ORDER BY
+			r.rental_date DESC
+	`
+
+	err := r.DB.WithContext(ctx).Raw(query, unitID).Scan(&items).Error
+	return items, err
+}
+
+func (r *ToyUnitRepository) FindLogs(ctx context.Context, unitID string) ([]entity.ToyUnitLog, error) {
+	var logs []entity.ToyUnitLog
+	if err := r.DB.WithContext(ctx).Where("toy_unit_id = ?", unitID).
+		Order("logged_at DESC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// BackfillFromStock mendaftarkan unit untuk mainan lama yang belum memiliki unit berdasarkan kolom stock
+func (r *ToyUnitRepository) BackfillFromStock(ctx context.Context) (int64, error) {
+	query := `
+		INSERT INTO toy_units (id, toy_id, serial_number, condition, status, created_at, updated_at)
+		SELECT
+			gen_random_uuid(),
+			t.id,
+			UPPER(RIGHT(REPLACE(t.id::text, '-', ''), 8)) || '-' || LPAD(g::text, 4, '0'),
+			t.condition,
+			'available',
+			NOW(),
+			NOW()
+		FROM
+			toys t
+		CROSS JOIN LATERAL
+			generate_series(1, t.stock) g
+		WHERE
+			t.deleted_at IS NULL
+			AND t.stock > 0
+			AND NOT EXISTS (SELECT 1 FROM toy_units u WHERE u.toy_id = t.id)
+	`
+
+	result := r.DB.WithContext(ctx).Exec(query)
+	return result.RowsAffected, result.Error
+}
+
+// syncToyStock menghitung ulang kolom stock dari jumlah unit yang berstatus available di semua cabang.
+// Unit yang masih dalam perjalanan antar cabang belum dihitung.
+func syncToyStock(tx *gorm.DB, toyID interface{}) error {
+	return tx.Exec(`
+		UPDATE toys SET stock = (
+			SELECT COUNT(*) FROM toy_units
+			WHERE toy_id = ? AND status = ? AND transfer_id IS NULL AND deleted_at IS NULL
+		) WHERE id = ?
+	`, toyID, entity.ToyUnitStatusAvailable, toyID).Error
+}
+
+func serialPrefix(toyID uuid.UUID) string {
+	hex := strings.ReplaceAll(toyID.String(), "-", "")
+	return strings.ToUpper(hex[len(hex)-8:])
+}
+
+// newToyUnits menyiapkan unit baru dengan nomor seri berurutan untuk sebuah mainan di sebuah cabang
+func newToyUnits(tx *gorm.DB, toyID uuid.UUID, branchID *uuid.UUID, condition string, quantity int) ([]entity.ToyUnit, error) {
+	var existing int64
+	if err := tx.Unscoped().Model(&entity.ToyUnit{}).Where("toy_id = ?", toyID).Count(&existing).Error; err != nil {
+		return nil, err
+	}
+
+	units := make([]entity.ToyUnit, 0, quantity)
+	for i := 1; i <= quantity; i++ {
+		units = append(units, entity.ToyUnit{
+			ToyID:        toyID,
+			SerialNumber: fmt.Sprintf("%s-%04d", serialPrefix(toyID), int(existing)+i),
+			Condition:    condition,
+			Status:       entity.ToyUnitStatusAvailable,
+			BranchID:     branchID,
+		})
+	}
+	return units, nil
+}
+
+func createToyUnits(tx *gorm.DB, units []entity.ToyUnit, notes string) error {
+	for i := range units {
+		if err := tx.Create(&units[i]).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&entity.ToyUnitLog{
+			ToyUnitID:   units[i].ID,
+			Event:       entity.ToyUnitEventRegistered,
+			StatusTo:    units[i].Status,
+			ConditionTo: units[i].Condition,
+			Notes:       notes,
+			LoggedAt:    time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
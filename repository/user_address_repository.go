@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+
+	"gorm.io/gorm"
+)
+
+type IUserAddressRepository interface {
+	IBaseRepository[entity.UserAddress]
+	FindByUserID(ctx context.Context, userID string) ([]entity.UserAddress, error)
+	FindByUserAndID(ctx context.Context, userID string, id string) (entity.UserAddress, error)
+	CountByUserID(ctx context.Context, userID string) (int64, error)
+}
+
+type UserAddressRepository struct {
+	BaseRepository[entity.UserAddress]
+}
+
+func NewUserAddressRepository(db *gorm.DB) IUserAddressRepository {
+	return &UserAddressRepository{
+		BaseRepository: BaseRepository[entity.UserAddress]{DB: db},
+	}
+}
+
+func (r *UserAddressRepository) FindByUserID(ctx context.Context, userID string) ([]entity.UserAddress, error) {
+	var addresses []entity.UserAddress
+	if err := r.DB.WithContext(ctx).Where("user_id = ?", userID).
+		Order("is_default DESC, created_at ASC").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (r *UserAddressRepository) FindByUserAndID(ctx context.Context, userID string, id string) (entity.UserAddress, error) {
+	var address entity.UserAddress
+	if err := r.DB.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&address).Error; err != nil {
+		return address, err
+	}
+	return address, nil
+}
+
+func (r *UserAddressRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&entity.UserAddress{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Insert menyimpan alamat baru, alamat utama lama dilepas bila alamat baru dijadikan alamat utama
+func (r *UserAddressRepository) Insert(ctx context.Context, address *entity.UserAddress) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address); err != nil {
+				return err
+			}
+		}
+		return tx.Create(address).Error
+	})
+}
+
+func (r *UserAddressRepository) UpdateById(ctx context.Context, id string, address *entity.UserAddress) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := clearDefaultAddress(tx, address); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&entity.UserAddress{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"label":          address.Label,
+			"recipient_name": address.RecipientName,
+			"phone_number":   address.PhoneNumber,
+			"street":         address.Street,
+			"city":           address.City,
+			"postal_code":    address.PostalCode,
+			"latitude":       address.Latitude,
+			"longitude":      address.Longitude,
+			"notes":          address.Notes,
+			"is_default":     address.IsDefault,
+		}).Error
+	})
+}
+
+// DeleteById menghapus alamat, bila alamat utama yang dihapus alamat tertua berikutnya menjadi alamat utama
+func (r *UserAddressRepository) DeleteById(ctx context.Context, id string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var address entity.UserAddress
+		if err := tx.Where("id = ?", id).First(&address).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&address).Error; err != nil {
+			return err
+		}
+
+		if !address.IsDefault {
+			return nil
+		}
+
+		var next entity.UserAddress
+		err := tx.Where("user_id = ?", address.UserID).Order("created_at ASC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_default", true).Error
+	})
+}
+
+func clearDefaultAddress(tx *gorm.DB, address *entity.UserAddress) error {
+	return tx.Model(&entity.UserAddress{}).
+		Where("user_id = ? AND id <> ? AND is_default = ?", address.UserID, address.ID, true).
+		Update("is_default", false).Error
+}
@@ -3,12 +3,14 @@ package repository
 import (
 	"context"
 	"final-project/entity"
+	"github.com/gofrs/uuid/v5"
 	"gorm.io/gorm"
 )
 
 type IUserRepository interface {
 	IBaseRepository[entity.User]
 	FindByEmailOrUsername(ctx context.Context, email string) (*entity.User, error)
+	UpdateBranch(ctx context.Context, id string, branchID *uuid.UUID) error
 }
 
 type UserRepository struct {
@@ -41,3 +43,7 @@ func (r *UserRepository) FindByEmailOrUsername(ctx context.Context, emailOrUsern
 	}
 	return &user, nil
 }
+
+func (r *UserRepository) UpdateBranch(ctx context.Context, id string, branchID *uuid.UUID) error {
+	return r.DB.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).Update("branch_id", branchID).Error
+}
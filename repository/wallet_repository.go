@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"final-project/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// IWalletRepository sengaja tidak menyediakan ubah dan hapus karena mutasi dompet bersifat append-only
+type IWalletRepository interface {
+	FindByUserID(ctx context.Context, userID string) (entity.Wallet, error)
+	FindEntries(ctx context.Context, userID string, limit int, offset int) ([]entity.WalletEntry, int64, error)
+	Post(ctx context.Context, entry *entity.WalletEntry) error
+}
+
+type WalletRepository struct {
+	DB *gorm.DB
+}
+
+func NewWalletRepository(db *gorm.DB) IWalletRepository {
+	return &WalletRepository{
+		DB: db,
+	}
+}
+
+func (r *WalletRepository) FindByUserID(ctx context.Context, userID string) (entity.Wallet, error) {
+	var wallet entity.Wallet
+	if err := r.DB.WithContext(ctx).Where("user_id = ?", userID).First(&wallet).Error; err != nil {
+		return wallet, err
+	}
+	return wallet, nil
+}
+
+func (r *WalletRepository) FindEntries(ctx context.Context, userID string, limit int, offset int) ([]entity.WalletEntry, int64, error) {
+	var entries []entity.WalletEntry
+	if err := r.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var totalData int64
+	if err := r.DB.WithContext(ctx).Model(&entity.WalletEntry{}).
+		Where("user_id = ?", userID).
+		Count(&totalData).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, totalData, nil
+}
+
+func (r *WalletRepository) Post(ctx context.Context, entry *entity.WalletEntry) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return postWalletEntry(tx, entry)
+	})
+}
+
+// lockWallet mengunci dompet pengguna, membuatnya lebih dulu bila belum ada
+func lockWallet(tx *gorm.DB, entry *entity.WalletEntry) (entity.Wallet, error) {
+	if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).
+		Create(&entity.Wallet{UserID: entry.UserID}).Error; err != nil {
+		return entity.Wallet{}, err
+	}
+
+	var wallet entity.Wallet
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", entry.UserID).
+		First(&wallet).Error; err != nil {
+		return wallet, err
+	}
+	return wallet, nil
+}
+
+// postWalletEntry menambahkan mutasi, memperbarui saldo dan mencatat jurnalnya dalam transaksi yang sama.
+// Mutasi dengan payment dan alasan yang sama hanya dicatat sekali.
+func postWalletEntry(tx *gorm.DB, entry *entity.WalletEntry) error {
+	wallet, err := lockWallet(tx, entry)
+	if err != nil {
+		return err
+	}
+
+	if entry.PaymentID != nil {
+		var existing int64
+		if err := tx.Model(&entity.WalletEntry{}).
+			Where("payment_id = ? AND reason = ?", entry.PaymentID, entry.Reason).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return nil
+		}
+	}
+
+	balance := wallet.Balance + entry.SignedAmount()
+	if balance < 0 {
+		return entity.ErrInsufficientWalletBalance
+	}
+
+	if err := tx.Model(&entity.Wallet{}).Where("id = ?", wallet.ID).
+		Update("balance", balance).Error; err != nil {
+		return err
+	}
+
+	entry.WalletID = wallet.ID
+	entry.BalanceAfter = balance
+	if err := tx.Create(entry).Error; err != nil {
+		return err
+	}
+	return postJournal(tx, entity.NewWalletJournal(*entry))
+}
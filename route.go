@@ -4,17 +4,23 @@ import (
 	"final-project/config"
 	"final-project/controller"
 	_ "final-project/docs"
+	"final-project/entity"
 	"final-project/middleware"
 	"final-project/repository"
 	"final-project/service"
+	"final-project/utils/cache"
 	"final-project/utils/helpers"
+	"final-project/utils/mailer"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
+	"time"
 )
 
-func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
+// setupRoutes menyusun seluruh route. reportCache dipakai bersama scheduler di main agar pembangunan
+// ulang rollup ikut mengosongkan cache laporan yang disajikan route.
+func setupRoutes(cfg *config.Config, db *gorm.DB, reportCache *cache.TTL) *gin.Engine {
 	if cfg.IsProd {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -29,9 +35,12 @@ func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
 	userTokenRepo := repository.NewUserTokenRepository(db)
 	userTokenSvc := service.NewTokenService(userTokenRepo, *jwtHelper)
 
+	// Branch
+	branchRepo := repository.NewBranchRepository(db)
+
 	// Users
 	userRepo := repository.NewUserRepository(db)
-	userSvc := service.NewUserService(userRepo, userTokenRepo, *jwtHelper)
+	userSvc := service.NewUserService(userRepo, userTokenRepo, *jwtHelper, branchRepo)
 	userController := controller.NewUserController(userSvc, userTokenSvc)
 
 	// Toy category
@@ -49,22 +58,96 @@ func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
 	toySvc := service.NewToyService(toyRepo, toyImageRepo, toyCategoryRepo)
 	toyController := controller.NewToyController(toySvc)
 
+	branchSvc := service.NewBranchService(branchRepo, toyRepo)
+	branchController := controller.NewBranchController(branchSvc)
+
+	// Maintenance
+	turnaround := entity.MaintenanceTurnaround{
+		Cleaning: time.Duration(cfg.CleaningTurnaroundHours) * time.Hour,
+		Repair:   time.Duration(cfg.RepairTurnaroundHours) * time.Hour,
+	}
+	maintenanceRepo := repository.NewMaintenanceTaskRepository(db)
+	maintenanceSvc := service.NewMaintenanceTaskService(maintenanceRepo, turnaround)
+	maintenanceController := controller.NewMaintenanceTaskController(maintenanceSvc)
+
+	// Pajak
+	tax := entity.TaxPolicy{
+		Rate:      cfg.PPNRate,
+		Inclusive: cfg.PPNInclusive,
+	}
+
+	// Toy unit
+	toyUnitRepo := repository.NewToyUnitRepository(db)
+	toyUnitSvc := service.NewToyUnitService(toyUnitRepo, toyRepo, maintenanceRepo, branchRepo)
+	toyUnitController := controller.NewToyUnitController(toyUnitSvc)
+
 	// Rental
 	rentalRepo := repository.NewRentalRepository(db)
 
+	// Accounting
+	journalRepo := repository.NewJournalRepository(db)
+	journalSvc := service.NewJournalService(journalRepo)
+	journalController := controller.NewJournalController(journalSvc)
+
+	// Wallet
+	walletRepo := repository.NewWalletRepository(db)
+	walletSvc := service.NewWalletService(walletRepo, userRepo)
+	walletController := controller.NewWalletController(walletSvc)
+
+	// Rollup laporan, diperbarui oleh rental dan pembayaran
+	location := reportLocation(cfg)
+	reportRollupRepo := repository.NewReportRollupRepository(db, location)
+	reportRollupSvc := service.NewReportRollupService(reportRollupRepo, reportCache, cfg.ReportRollupWindowDays, location)
+
 	// Payment
 	paymentRepo := repository.NewPaymentRepository(db)
 	midtransSvc := service.NewMidtransService(cfg)
-	paymentSvc := service.NewPaymentService(paymentRepo, rentalRepo, midtransSvc)
-	paymentController := controller.NewPaymentController(paymentSvc)
+	paymentSvc := service.NewPaymentService(paymentRepo, rentalRepo, walletRepo, journalRepo, midtransSvc, reportRollupSvc)
+
+	// Fee policy
+	feePolicyRepo := repository.NewFeePolicyRepository(db)
+	feePolicySvc := service.NewFeePolicyService(feePolicyRepo, toyCategoryRepo)
+	feePolicyController := controller.NewFeePolicyController(feePolicySvc)
+
+	// Pricing
+	pricingRuleRepo := repository.NewPricingRuleRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	pricingSvc := service.NewPricingService(pricingRuleRepo, holidayRepo, toyRepo)
+	pricingController := controller.NewPricingController(pricingSvc)
+
+	// Promotion
+	promotionRepo := repository.NewPromotionRepository(db)
+	promotionSvc := service.NewPromotionService(promotionRepo, toyCategoryRepo, toyRepo)
+	promotionController := controller.NewPromotionController(promotionSvc)
+
+	// Address book
+	userAddressRepo := repository.NewUserAddressRepository(db)
+	userAddressSvc := service.NewUserAddressService(userAddressRepo)
+	userAddressController := controller.NewUserAddressController(userAddressSvc)
 
-	rentalSvc := service.NewRentalService(rentalRepo, userRepo, toyRepo, paymentSvc)
+	// Delivery
+	deliveryTaskRepo := repository.NewDeliveryTaskRepository(db)
+	deliveryZoneRepo := repository.NewDeliveryZoneRepository(db)
+	deliverySlotRepo := repository.NewDeliverySlotRepository(db)
+	deliverySvc := service.NewDeliveryService(deliveryTaskRepo, deliveryZoneRepo, deliverySlotRepo, userAddressRepo, branchRepo, userRepo)
+	deliveryController := controller.NewDeliveryController(deliverySvc)
+
+	rentalSvc := service.NewRentalService(rentalRepo, userRepo, toyRepo, paymentSvc, feePolicySvc, pricingSvc, promotionSvc, branchRepo, deliverySvc, turnaround, tax, reportRollupSvc)
 	rentalController := controller.NewRentalController(rentalSvc)
+	paymentController := controller.NewPaymentController(paymentSvc, rentalSvc)
+
+	// Document
+	documentSvc := service.NewDocumentService(rentalRepo, paymentRepo, userRepo, paymentSvc)
+	documentController := controller.NewDocumentController(documentSvc, rentalSvc, paymentSvc)
 
 	// Report
-	businessReportRepo := repository.NewBusinessReportRepository(db)
-	businessReportSvc := service.NewBusinessReportService(businessReportRepo)
-	businessReportController := controller.NewBusinessReportController(businessReportSvc)
+	businessReportRepo := repository.NewBusinessReportRepository(db, location)
+	businessReportSvc := service.NewBusinessReportService(businessReportRepo, reportRollupRepo, reportCache, location)
+	businessReportController := controller.NewBusinessReportController(businessReportSvc, location)
+
+	// Report subscription
+	reportSubscriptionSvc := newReportSubscriptionService(cfg, db, businessReportSvc, branchRepo)
+	reportSubscriptionController := controller.NewReportSubscriptionController(reportSubscriptionSvc)
 
 	// Middleware
 	authMiddleware := middleware.NewAuthMiddleware(*jwtHelper, userTokenSvc)
@@ -83,6 +166,7 @@ func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		toyCategory := public.Group("/toy")
 		{
 			toyCategory.GET("/category", toyCategoryController.FindAll)
+			toyCategory.GET("/category/tree", toyCategoryController.Tree)
 			toyCategory.GET("/category/:id", toyCategoryController.FinById)
 		}
 
@@ -97,6 +181,20 @@ func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		{
 			toy.GET("", toyController.FindAll)
 			toy.GET("/:id", toyController.FinById)
+			toy.GET("/:id/availability", branchController.ToyAvailability)
+		}
+
+		// Branch routes
+		branch := public.Group("/branch")
+		{
+			branch.GET("", branchController.FindAll)
+			branch.GET("/:id", branchController.FinById)
+		}
+
+		// Delivery routes
+		delivery := public.Group("/delivery")
+		{
+			delivery.GET("/slot", deliveryController.FindSlots)
 		}
 
 		// Payment routes
@@ -123,8 +221,29 @@ func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		rental := protected.Group("/rental")
 		{
 			rental.POST("", rentalController.Insert)
+			rental.POST("/quote", rentalController.Quote)
 			rental.PUT("/:id", rentalController.UpdateById)
 			rental.GET("/:id", rentalController.FinById)
+			rental.GET("/:id/balance", rentalController.Balance)
+			rental.GET("/:id/invoice.pdf", documentController.RentalInvoice)
+			rental.POST("/:id/settle", rentalController.Settle)
+		}
+
+		// Address book routes
+		address := protected.Group("/address")
+		{
+			address.GET("", userAddressController.FindAll)
+			address.GET("/:id", userAddressController.FinById)
+			address.POST("", userAddressController.Insert)
+			address.PUT("/:id", userAddressController.UpdateById)
+			address.DELETE("/:id", userAddressController.DeleteById)
+		}
+
+		// Wallet routes
+		wallet := protected.Group("/wallet")
+		{
+			wallet.GET("", walletController.MyWallet)
+			wallet.GET("/entries", walletController.MyEntries)
 		}
 
 		// Payment routes
@@ -132,6 +251,8 @@ func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		{
 			payment.POST("", paymentController.CreatePayment)
 			payment.GET("/:id", paymentController.GetPaymentByID)
+			payment.GET("/:id/tax-invoice", paymentController.GetTaxInvoice)
+			payment.GET("/:id/receipt.pdf", documentController.PaymentReceipt)
 			payment.GET("/rental/:rental_id", paymentController.GetPaymentsByRentalID)
 		}
 	}
@@ -145,6 +266,7 @@ func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		{
 			auth.GET("/users", userController.FindAll)
 			auth.GET("/user/:id", userController.FinById)
+			auth.PUT("/user/:id/branch", userController.AssignBranch)
 		}
 
 		// Admin toy category routes
@@ -170,22 +292,184 @@ func setupRoutes(cfg *config.Config, db *gorm.DB) *gin.Engine {
 			toy.DELETE("/:id", toyController.DeleteById)
 		}
 
+		// Admin toy unit routes
+		toyUnit := admin.Group("/toy")
+		{
+			toyUnit.GET("/:id/units", toyUnitController.FindByToyID)
+			toyUnit.POST("/:id/units", toyUnitController.Register)
+			toyUnit.PUT("/unit/:id", toyUnitController.UpdateById)
+			toyUnit.GET("/unit/:id/history", toyUnitController.History)
+		}
+
+		// Admin branch routes
+		branch := admin.Group("/branch")
+		{
+			branch.POST("", branchController.Insert)
+			branch.PUT("/:id", branchController.UpdateById)
+			branch.DELETE("/:id", branchController.DeleteById)
+		}
+
+		// Admin branch transfer routes
+		branchTransfer := admin.Group("/branch-transfer")
+		{
+			branchTransfer.GET("", branchController.FindTransfers)
+			branchTransfer.GET("/:id", branchController.FindTransferById)
+			branchTransfer.POST("", branchController.CreateTransfer)
+			branchTransfer.PUT("/:id/receive", branchController.ReceiveTransfer)
+			branchTransfer.PUT("/:id/cancel", branchController.CancelTransfer)
+		}
+
+		// Admin delivery routes
+		delivery := admin.Group("/delivery")
+		{
+			delivery.GET("/zone", deliveryController.FindZones)
+			delivery.POST("/zone", deliveryController.InsertZone)
+			delivery.PUT("/zone/:id", deliveryController.UpdateZone)
+			delivery.DELETE("/zone/:id", deliveryController.DeleteZone)
+			delivery.POST("/slot", deliveryController.InsertSlot)
+			delivery.PUT("/slot/:id", deliveryController.UpdateSlot)
+			delivery.DELETE("/slot/:id", deliveryController.DeleteSlot)
+			delivery.GET("/task", deliveryController.FindTasks)
+			delivery.GET("/task/mine", deliveryController.MyTasks)
+			delivery.GET("/task/:id", deliveryController.FindTaskById)
+			delivery.PUT("/task/:id/assign", deliveryController.AssignCourier)
+			delivery.PUT("/task/:id/status", deliveryController.UpdateTaskStatus)
+		}
+
+		// Admin maintenance routes
+		maintenance := admin.Group("/maintenance")
+		{
+			maintenance.GET("", maintenanceController.FindAll)
+			maintenance.GET("/:id", maintenanceController.FinById)
+			maintenance.PUT("/:id/start", maintenanceController.Start)
+			maintenance.PUT("/:id/complete", maintenanceController.Complete)
+		}
+
+		// Admin fee policy routes
+		feePolicy := admin.Group("/fee-policy")
+		{
+			feePolicy.GET("", feePolicyController.FindAll)
+			feePolicy.GET("/active", feePolicyController.FindActive)
+			feePolicy.GET("/:id", feePolicyController.FinById)
+			feePolicy.POST("", feePolicyController.Insert)
+			feePolicy.PUT("/:id/activate", feePolicyController.Activate)
+		}
+
+		// Admin pricing routes
+		pricing := admin.Group("/pricing")
+		{
+			pricing.GET("/rule", pricingController.FindAllRules)
+			pricing.POST("/rule", pricingController.InsertRule)
+			pricing.PUT("/rule/:id", pricingController.UpdateRule)
+			pricing.DELETE("/rule/:id", pricingController.DeleteRule)
+			pricing.GET("/holiday", pricingController.FindAllHolidays)
+			pricing.POST("/holiday", pricingController.InsertHoliday)
+			pricing.DELETE("/holiday/:id", pricingController.DeleteHoliday)
+		}
+
+		// Admin wallet routes
+		walletAdmin := admin.Group("/wallet/user")
+		{
+			walletAdmin.GET("/:user_id", walletController.FindByUserID)
+			walletAdmin.GET("/:user_id/entries", walletController.FindEntriesByUserID)
+			walletAdmin.POST("/:user_id/adjust", walletController.Adjust)
+		}
+
+		// Admin promotion routes
+		promotion := admin.Group("/promotion")
+		{
+			promotion.GET("", promotionController.FindAll)
+			promotion.GET("/report", promotionController.UsageReport)
+			promotion.GET("/:id", promotionController.FindById)
+			promotion.GET("/:id/redemptions", promotionController.FindRedemptions)
+			promotion.POST("", promotionController.Insert)
+			promotion.PUT("/:id", promotionController.UpdateById)
+			promotion.DELETE("/:id", promotionController.DeleteById)
+		}
+
 		// Admin rental routes
 		rental := admin.Group("/rental")
 		{
 			rental.GET("", rentalController.FindAll)
 			rental.PUT("/:id/return", rentalController.ReturnRental)
+			rental.PUT("/:id/deposit/refund", rentalController.RefundDeposit)
+			rental.PUT("/:id/write-off", rentalController.WriteOff)
+		}
+
+		// Admin accounting routes
+		accounting := admin.Group("/accounting")
+		{
+			accounting.GET("/accounts", journalController.FindAccounts)
+			accounting.GET("/journal", journalController.FindEntries)
+			accounting.GET("/journal/export", journalController.Export)
+			accounting.GET("/trial-balance", journalController.TrialBalance)
 		}
 
 		// Admin report routes
 		report := admin.Group("/business-report")
 		{
+			report.GET("/dashboard", businessReportController.GetDashboard)
 			report.GET("/sales", businessReportController.GetSalesReport)
 			report.GET("/popular-toys", businessReportController.GetPopularToysReport)
 			report.GET("/customers", businessReportController.GetTopCustomersReport)
+			report.GET("/customer-value", businessReportController.GetCustomerValueReport)
+			report.GET("/cohorts", businessReportController.GetCohortReport)
 			report.GET("/rental-status", businessReportController.GetRentalStatusReport)
+			report.GET("/categories", businessReportController.GetCategoryReport)
+			report.GET("/utilization", businessReportController.GetUtilizationReport)
+			report.GET("/damage", businessReportController.GetDamageReport)
+			report.GET("/inventory", businessReportController.GetInventoryReport)
+			report.GET("/overdue", businessReportController.GetOverdueReport)
+		}
+
+		// Admin report subscription routes
+		reportSubscription := admin.Group("/report-subscription")
+		{
+			reportSubscription.GET("", reportSubscriptionController.FindAll)
+			reportSubscription.GET("/:id", reportSubscriptionController.FindById)
+			reportSubscription.GET("/:id/deliveries", reportSubscriptionController.FindDeliveries)
+			reportSubscription.POST("", reportSubscriptionController.Insert)
+			reportSubscription.POST("/:id/send", reportSubscriptionController.SendNow)
+			reportSubscription.PUT("/:id", reportSubscriptionController.UpdateById)
+			reportSubscription.DELETE("/:id", reportSubscriptionController.DeleteById)
 		}
 	}
 
 	return r
 }
+
+// newReportSubscriptionService dipakai route untuk endpoint langganan dan main untuk scheduler,
+// sehingga keduanya memakai mailer dan zona waktu yang sama
+func newReportSubscriptionService(
+	cfg *config.Config,
+	db *gorm.DB,
+	businessReportSvc service.IBusinessReportService,
+	branchRepo repository.IBranchRepository,
+) service.IReportSubscriptionService {
+	var reportMailer mailer.Mailer
+	switch cfg.MailDriver {
+	case "smtp":
+		reportMailer = mailer.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	default:
+		reportMailer = mailer.NewFileMailer(cfg.MailDropDir, cfg.MailFrom)
+	}
+
+	return service.NewReportSubscriptionService(
+		repository.NewReportSubscriptionRepository(db),
+		branchRepo,
+		controller.NewReportRenderer(businessReportSvc),
+		reportMailer,
+		reportLocation(cfg),
+	)
+}
+
+// reportLocation adalah zona waktu tanggal laporan, rollup dan jadwalnya. Zona waktu yang tidak
+// dikenal diganti UTC karena namanya juga dipakai di query database.
+func reportLocation(cfg *config.Config) *time.Location {
+	location, err := time.LoadLocation(cfg.ReportTimezone)
+	if err != nil {
+		helpers.Logger.Errorf("Invalid report timezone %q, using UTC: %v", cfg.ReportTimezone, err)
+		return time.UTC
+	}
+	return location
+}
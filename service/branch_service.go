@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type IBranchService interface {
+	IBaseService[entity.Branch]
+	FindActive(ctx context.Context) ([]entity.Branch, error)
+	CreateBranch(ctx context.Context, req entity.BranchRequest) (*entity.Branch, error)
+	UpdateBranch(ctx context.Context, id string, req entity.BranchRequest) (*entity.Branch, error)
+	FindToyAvailability(ctx context.Context, toyID string) ([]entity.ToyBranchStock, error)
+	FindTransfers(ctx context.Context, filter entity.BranchTransferFilter, limit int, offset int) ([]entity.BranchTransfer, int64, error)
+	FindTransferByID(ctx context.Context, id string) (*entity.BranchTransfer, error)
+	CreateTransfer(ctx context.Context, req entity.CreateBranchTransferRequest, userID uuid.UUID) (*entity.BranchTransfer, error)
+	ReceiveTransfer(ctx context.Context, id string, userID uuid.UUID) (*entity.BranchTransfer, error)
+	CancelTransfer(ctx context.Context, id string) (*entity.BranchTransfer, error)
+}
+
+type BranchService struct {
+	BaseService[entity.Branch]
+	branchRepo repository.IBranchRepository
+	toyRepo    repository.IToyRepository
+}
+
+func NewBranchService(repo repository.IBranchRepository, toyRepo repository.IToyRepository) IBranchService {
+	return &BranchService{
+		BaseService: BaseService[entity.Branch]{repository: repo},
+		branchRepo:  repo,
+		toyRepo:     toyRepo,
+	}
+}
+
+func (s *BranchService) FindActive(ctx context.Context) ([]entity.Branch, error) {
+	return s.branchRepo.FindActive(ctx)
+}
+
+func (s *BranchService) CreateBranch(ctx context.Context, req entity.BranchRequest) (*entity.Branch, error) {
+	branch := &entity.Branch{IsActive: true}
+	applyBranchRequest(branch, req)
+
+	if errs := branch.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if _, err := s.branchRepo.FindByCode(ctx, branch.Code); err == nil {
+		return nil, entity.ErrBranchCodeExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.repository.Insert(ctx, branch); err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+func (s *BranchService) UpdateBranch(ctx context.Context, id string, req entity.BranchRequest) (*entity.Branch, error) {
+	branch, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previousCode := branch.Code
+	applyBranchRequest(&branch, req)
+
+	if previousCode == entity.DefaultBranchCode && branch.Code != previousCode {
+		return nil, entity.ErrDefaultBranchLocked
+	}
+
+	if errs := branch.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if existing, err := s.branchRepo.FindByCode(ctx, branch.Code); err == nil && existing.ID != branch.ID {
+		return nil, entity.ErrBranchCodeExists
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateById(ctx, id, &branch); err != nil {
+		return nil, err
+	}
+	return &branch, nil
+}
+
+// DeleteById hanya menghapus cabang yang sudah tidak memiliki unit aktif
+func (s *BranchService) DeleteById(ctx context.Context, id string) error {
+	branch, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if branch.Code == entity.DefaultBranchCode {
+		return entity.ErrDefaultBranchLocked
+	}
+
+	units, err := s.branchRepo.CountUnits(ctx, id)
+	if err != nil {
+		return err
+	}
+	if units > 0 {
+		return entity.ErrBranchHasUnits
+	}
+
+	return s.repository.DeleteById(ctx, id)
+}
+
+func (s *BranchService) FindToyAvailability(ctx context.Context, toyID string) ([]entity.ToyBranchStock, error) {
+	if _, err := s.toyRepo.FindById(ctx, toyID); err != nil {
+		return nil, err
+	}
+
+	return s.branchRepo.FindToyAvailability(ctx, toyID)
+}
+
+func (s *BranchService) FindTransfers(ctx context.Context, filter entity.BranchTransferFilter, limit int, offset int) ([]entity.BranchTransfer, int64, error) {
+	return s.branchRepo.FindTransfers(ctx, filter, limit, offset)
+}
+
+func (s *BranchService) FindTransferByID(ctx context.Context, id string) (*entity.BranchTransfer, error) {
+	transfer, err := s.branchRepo.FindTransferByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (s *BranchService) CreateTransfer(ctx context.Context, req entity.CreateBranchTransferRequest, userID uuid.UUID) (*entity.BranchTransfer, error) {
+	if req.FromBranchID == req.ToBranchID {
+		return nil, entity.ErrTransferSameBranch
+	}
+
+	if _, err := resolveBranch(ctx, s.branchRepo, &req.FromBranchID); err != nil {
+		return nil, err
+	}
+	if _, err := resolveBranch(ctx, s.branchRepo, &req.ToBranchID); err != nil {
+		return nil, err
+	}
+
+	unitIDs := make([]uuid.UUID, 0, len(req.ToyUnitIDs))
+	seen := make(map[uuid.UUID]bool, len(req.ToyUnitIDs))
+	for _, unitID := range req.ToyUnitIDs {
+		if unitID == uuid.Nil || seen[unitID] {
+			continue
+		}
+		seen[unitID] = true
+		unitIDs = append(unitIDs, unitID)
+	}
+	if len(unitIDs) == 0 {
+		return nil, entity.ErrTransferUnitsRequired
+	}
+
+	transfer := &entity.BranchTransfer{
+		FromBranchID: req.FromBranchID,
+		ToBranchID:   req.ToBranchID,
+		Status:       entity.BranchTransferStatusInTransit,
+		Notes:        strings.TrimSpace(req.Notes),
+		ShippedAt:    time.Now(),
+		CreatedBy:    &userID,
+	}
+	if err := s.branchRepo.CreateTransfer(ctx, transfer, unitIDs); err != nil {
+		return nil, err
+	}
+
+	return s.FindTransferByID(ctx, transfer.ID.String())
+}
+
+func (s *BranchService) ReceiveTransfer(ctx context.Context, id string, userID uuid.UUID) (*entity.BranchTransfer, error) {
+	transfer, err := s.branchRepo.FindTransferByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Status != entity.BranchTransferStatusInTransit {
+		return nil, entity.ErrTransferNotInTransit
+	}
+
+	now := time.Now()
+	transfer.ReceivedAt = &now
+	transfer.ReceivedBy = &userID
+	if err := s.branchRepo.ReceiveTransfer(ctx, &transfer); err != nil {
+		return nil, err
+	}
+
+	return s.FindTransferByID(ctx, id)
+}
+
+func (s *BranchService) CancelTransfer(ctx context.Context, id string) (*entity.BranchTransfer, error) {
+	transfer, err := s.branchRepo.FindTransferByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if transfer.Status != entity.BranchTransferStatusInTransit {
+		return nil, entity.ErrTransferNotInTransit
+	}
+
+	now := time.Now()
+	transfer.CancelledAt = &now
+	if err := s.branchRepo.CancelTransfer(ctx, &transfer); err != nil {
+		return nil, err
+	}
+
+	return s.FindTransferByID(ctx, id)
+}
+
+func applyBranchRequest(branch *entity.Branch, req entity.BranchRequest) {
+	branch.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	branch.Name = strings.TrimSpace(req.Name)
+	branch.Address = strings.TrimSpace(req.Address)
+	branch.City = strings.TrimSpace(req.City)
+	branch.PhoneNumber = strings.TrimSpace(req.PhoneNumber)
+	branch.OpeningHours = strings.TrimSpace(req.OpeningHours)
+	branch.Latitude = req.Latitude
+	branch.Longitude = req.Longitude
+	if req.IsActive != nil {
+		branch.IsActive = *req.IsActive
+	}
+}
+
+// resolveBranch mengambil cabang yang dipilih, atau cabang utama bila tidak dipilih. Cabang harus aktif.
+func resolveBranch(ctx context.Context, branchRepo repository.IBranchRepository, branchID *uuid.UUID) (entity.Branch, error) {
+	var (
+		branch entity.Branch
+		err    error
+	)
+	if branchID == nil {
+		branch, err = branchRepo.FindDefault(ctx)
+	} else {
+		branch, err = branchRepo.FindById(ctx, branchID.String())
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return branch, entity.ErrBranchNotFound
+		}
+		return branch, err
+	}
+
+	if !branch.IsActive {
+		return branch, entity.ErrBranchInactive
+	}
+	return branch, nil
+}
@@ -4,60 +4,545 @@ import (
 	"context"
 	"final-project/entity"
 	"final-project/repository"
+	"final-project/utils/cache"
+	"final-project/utils/helpers"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/gofrs/uuid/v5"
 )
 
+// dashboardTimeout membatasi total waktu seluruh query dashboard yang berjalan bersamaan
+const dashboardTimeout = 15 * time.Second
+
 type IBusinessReportService interface {
-	GetSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]entity.SalesReportItem, error)
-	GetPopularToysReport(ctx context.Context, startDate, endDate time.Time, limit int) ([]entity.PopularToyItem, error)
-	GetTopCustomersReport(ctx context.Context, startDate, endDate time.Time, limit int) ([]entity.TopCustomerItem, error)
-	GetRentalStatusReport(ctx context.Context, startDate, endDate time.Time) ([]entity.RentalStatusItem, error)
+	GetSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string, branchID string) ([]entity.SalesReportItem, error)
+	GetCashSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string, branchID string) ([]entity.CashSalesReportItem, error)
+	GetPopularToysReport(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.PopularToyItem, error)
+	GetTopCustomersReport(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.TopCustomerItem, error)
+	GetCohortReport(ctx context.Context, startDate, endDate time.Time, months int, branchID string) ([]entity.CohortItem, error)
+	GetCustomerValueReport(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) (*entity.CustomerValueReport, error)
+	GetRentalStatusReport(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.RentalStatusItem, error)
+	GetCategoryReport(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.CategorySummary, error)
+	GetUtilizationReport(ctx context.Context, startDate, endDate time.Time, idleDays int, branchID string) (*entity.UtilizationReport, error)
+	GetDashboard(ctx context.Context, startDate, endDate time.Time, branchID string) (*entity.DashboardReport, error)
+	GetDamageReport(ctx context.Context, startDate, endDate time.Time, minIncidents int, branchID string) (*entity.DamageReport, error)
+	GetInventoryReport(ctx context.Context, filter entity.InventoryStatusFilter, branchID string) ([]entity.InventoryStatusItem, error)
+	GetOverdueReport(ctx context.Context, asOf time.Time, branchID string) ([]entity.OverdueRentalItem, error)
 }
 
 type BusinessReportService struct {
-	reportRepo repository.IBusinessReportRepository
+	reportRepo  repository.IBusinessReportRepository
+	rollupRepo  repository.IReportRollupRepository
+	reportCache *cache.TTL
+	location    *time.Location
+	rollupReady atomic.Bool
 }
 
-func NewBusinessReportService(reportRepo repository.IBusinessReportRepository) IBusinessReportService {
+// NewBusinessReportService membuat layanan laporan. Laporan penjualan, mainan populer, pelanggan
+// teratas, status rental dan kategori dibaca dari rollup harian setelah rollup pertama kali dibangun
+// dan hasilnya disimpan di reportCache. reportCache boleh nil untuk mematikan cache. location adalah
+// zona waktu laporan yang menentukan batas hari ini antara rollup dan transaksi langsung.
+func NewBusinessReportService(reportRepo repository.IBusinessReportRepository, rollupRepo repository.IReportRollupRepository, reportCache *cache.TTL, location *time.Location) IBusinessReportService {
 	return &BusinessReportService{
-		reportRepo: reportRepo,
+		reportRepo:  reportRepo,
+		rollupRepo:  rollupRepo,
+		reportCache: reportCache,
+		location:    location,
 	}
 }
 
-func (s *BusinessReportService) GetSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string) ([]entity.SalesReportItem, error) {
-	validGroupBy := map[string]bool{
-		"day":   true,
-		"week":  true,
-		"month": true,
+var validGroupBy = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
+}
+
+func (s *BusinessReportService) GetSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string, branchID string) ([]entity.SalesReportItem, error) {
+	if !validGroupBy[groupBy] {
+		groupBy = "day"
 	}
 
+	key := reportCacheKey("sales", startDate, endDate, groupBy, branchID)
+	return cachedReport(s.reportCache, key, func() ([]entity.SalesReportItem, error) {
+		period, ok := s.rollupRange(ctx, startDate, endDate)
+		if !ok {
+			return s.reportRepo.GetSalesReport(ctx, startDate, endDate, groupBy, branchID)
+		}
+
+		var items []entity.SalesReportItem
+		if period.HasRollup() {
+			var err error
+			if items, err = s.rollupRepo.GetSalesReport(ctx, period.StartDay, period.EndDay, groupBy, branchID); err != nil {
+				return nil, err
+			}
+		}
+		if period.HasLive() {
+			live, err := s.reportRepo.GetSalesReport(ctx, period.LiveStart, period.LiveEnd, groupBy, branchID)
+			if err != nil {
+				return nil, err
+			}
+			items = mergeSalesItems(items, live)
+		}
+		return items, nil
+	})
+}
+
+// mergeSalesItems menambahkan penjualan hari ini ke hasil rollup. Periode mingguan atau bulanan yang
+// mencakup hari ini dijumlahkan, urutan tetap naik karena hari ini selalu periode terakhir.
+func mergeSalesItems(items, live []entity.SalesReportItem) []entity.SalesReportItem {
+	index := make(map[string]int, len(items))
+	for i := range items {
+		index[items[i].Date] = i
+	}
+
+	for _, item := range live {
+		i, ok := index[item.Date]
+		if !ok {
+			items = append(items, item)
+			continue
+		}
+
+		merged := &items[i]
+		merged.RentalCount += item.RentalCount
+		merged.RentalRevenue += item.RentalRevenue
+		merged.LateFeeRevenue += item.LateFeeRevenue
+		merged.DamageFeeRevenue += item.DamageFeeRevenue
+		merged.TotalRevenue += item.TotalRevenue
+		merged.NetRevenue += item.NetRevenue
+		merged.TaxAmount += item.TaxAmount
+		merged.GrossRevenue += item.GrossRevenue
+		merged.DepositAmount += item.DepositAmount
+		merged.TransactionCount += item.TransactionCount
+	}
+	return items
+}
+
+func (s *BusinessReportService) GetCashSalesReport(ctx context.Context, startDate, endDate time.Time, groupBy string, branchID string) ([]entity.CashSalesReportItem, error) {
 	if !validGroupBy[groupBy] {
 		groupBy = "day"
 	}
 
-	return s.reportRepo.GetSalesReport(ctx, startDate, endDate, groupBy)
+	return s.reportRepo.GetCashSalesReport(ctx, startDate, endDate, groupBy, branchID)
 }
 
-func (s *BusinessReportService) GetPopularToysReport(ctx context.Context, startDate, endDate time.Time, limit int) ([]entity.PopularToyItem, error) {
+func (s *BusinessReportService) GetPopularToysReport(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.PopularToyItem, error) {
 	if limit <= 0 {
 		limit = 10
 	} else if limit > 100 {
 		limit = 100
 	}
 
-	return s.reportRepo.GetPopularToys(ctx, startDate, endDate, limit)
+	key := reportCacheKey("popular_toys", startDate, endDate, limit, branchID)
+	return cachedReport(s.reportCache, key, func() ([]entity.PopularToyItem, error) {
+		if period, ok := s.rollupRange(ctx, startDate, endDate); ok {
+			return s.rollupRepo.GetPopularToys(ctx, period, limit, branchID)
+		}
+		return s.reportRepo.GetPopularToys(ctx, startDate, endDate, limit, branchID)
+	})
 }
 
-func (s *BusinessReportService) GetTopCustomersReport(ctx context.Context, startDate, endDate time.Time, limit int) ([]entity.TopCustomerItem, error) {
+func (s *BusinessReportService) GetTopCustomersReport(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) ([]entity.TopCustomerItem, error) {
 	if limit <= 0 {
 		limit = 10
 	} else if limit > 100 {
 		limit = 100
 	}
 
-	return s.reportRepo.GetTopCustomers(ctx, startDate, endDate, limit)
+	key := reportCacheKey("top_customers", startDate, endDate, limit, branchID)
+	return cachedReport(s.reportCache, key, func() ([]entity.TopCustomerItem, error) {
+		if period, ok := s.rollupRange(ctx, startDate, endDate); ok {
+			return s.rollupRepo.GetTopCustomers(ctx, period, limit, branchID)
+		}
+		return s.reportRepo.GetTopCustomers(ctx, startDate, endDate, limit, branchID)
+	})
+}
+
+// GetCohortReport menyusun retensi per kohort. Bulan yang belum berjalan tidak ditampilkan, bulan tanpa
+// pelanggan yang kembali tetap ditampilkan dengan nilai nol.
+func (s *BusinessReportService) GetCohortReport(ctx context.Context, startDate, endDate time.Time, months int, branchID string) ([]entity.CohortItem, error) {
+	if months <= 0 {
+		months = 12
+	} else if months > 36 {
+		months = 36
+	}
+
+	activities, err := s.reportRepo.GetCohortActivity(ctx, startDate, endDate, months, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	cohorts := []entity.CohortItem{}
+	index := make(map[string]int)
+	for _, activity := range activities {
+		i, ok := index[activity.CohortMonth]
+		if !ok {
+			cohortMonth, err := time.Parse("2006-01", activity.CohortMonth)
+			if err != nil {
+				return nil, err
+			}
+
+			elapsed := (now.Year()-cohortMonth.Year())*12 + int(now.Month()-cohortMonth.Month())
+			cohort := entity.CohortItem{
+				CohortMonth:   activity.CohortMonth,
+				CustomerCount: activity.CohortSize,
+			}
+			for offset := 0; offset <= months && offset <= elapsed; offset++ {
+				cohort.Retention = append(cohort.Retention, entity.CohortRetention{MonthOffset: offset})
+			}
+
+			i = len(cohorts)
+			index[activity.CohortMonth] = i
+			cohorts = append(cohorts, cohort)
+		}
+
+		cohort := &cohorts[i]
+		if activity.MonthOffset < len(cohort.Retention) && cohort.CustomerCount > 0 {
+			cohort.Retention[activity.MonthOffset].ActiveCustomers = activity.ActiveCustomers
+			cohort.Retention[activity.MonthOffset].RetentionRate = float64(activity.ActiveCustomers) / float64(cohort.CustomerCount) * 100
+		}
+	}
+
+	return cohorts, nil
+}
+
+func (s *BusinessReportService) GetCustomerValueReport(ctx context.Context, startDate, endDate time.Time, limit int, branchID string) (*entity.CustomerValueReport, error) {
+	if limit <= 0 {
+		limit = 10
+	} else if limit > 100 {
+		limit = 100
+	}
+
+	summary, err := s.reportRepo.GetCustomerRetentionSummary(ctx, startDate, endDate, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	customers, err := s.reportRepo.GetCustomerValues(ctx, startDate, endDate, limit, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.CustomerValueReport{
+		Summary:   summary,
+		Customers: customers,
+	}, nil
+}
+
+func (s *BusinessReportService) GetRentalStatusReport(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.RentalStatusItem, error) {
+	key := reportCacheKey("rental_status", startDate, endDate, branchID)
+	return cachedReport(s.reportCache, key, func() ([]entity.RentalStatusItem, error) {
+		period, ok := s.rollupRange(ctx, startDate, endDate)
+		if !ok {
+			return s.reportRepo.GetRentalStatusCount(ctx, startDate, endDate, branchID)
+		}
+
+		var items []entity.RentalStatusItem
+		if period.HasRollup() {
+			var err error
+			if items, err = s.rollupRepo.GetRentalStatusCount(ctx, period.StartDay, period.EndDay, branchID); err != nil {
+				return nil, err
+			}
+		}
+		if period.HasLive() {
+			live, err := s.reportRepo.GetRentalStatusCount(ctx, period.LiveStart, period.LiveEnd, branchID)
+			if err != nil {
+				return nil, err
+			}
+			items = mergeRentalStatusItems(items, live)
+		}
+		return items, nil
+	})
+}
+
+// mergeRentalStatusItems menambahkan jumlah rental hari ini ke hasil rollup lalu menghitung ulang
+// urutan dan persentasenya
+func mergeRentalStatusItems(items, live []entity.RentalStatusItem) []entity.RentalStatusItem {
+	index := make(map[string]int, len(items))
+	for i := range items {
+		index[items[i].Status] = i
+	}
+
+	for _, item := range live {
+		if i, ok := index[item.Status]; ok {
+			items[i].Count += item.Count
+		} else {
+			items = append(items, item)
+		}
+	}
+
+	var totalCount int64
+	for _, item := range items {
+		totalCount += int64(item.Count)
+	}
+	for i := range items {
+		items[i].PercentageTotal = 0
+		if totalCount > 0 {
+			items[i].PercentageTotal = float64(items[i].Count) / float64(totalCount) * 100
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Count > items[j].Count
+	})
+	return items
+}
+
+func (s *BusinessReportService) GetCategoryReport(ctx context.Context, startDate, endDate time.Time, branchID string) ([]entity.CategorySummary, error) {
+	key := reportCacheKey("categories", startDate, endDate, branchID)
+	return cachedReport(s.reportCache, key, func() ([]entity.CategorySummary, error) {
+		if period, ok := s.rollupRange(ctx, startDate, endDate); ok {
+			return s.rollupRepo.GetCategoryPerformance(ctx, period, branchID)
+		}
+		return s.reportRepo.GetCategoryPerformance(ctx, startDate, endDate, branchID)
+	})
+}
+
+// rollupRange menentukan apakah laporan dibaca dari rollup. Rollup dipakai untuk periode hari penuh
+// setelah pembangunan ulang pertama selesai, sebelum itu rollup belum lengkap.
+func (s *BusinessReportService) rollupRange(ctx context.Context, startDate, endDate time.Time) (entity.ReportRollupRange, bool) {
+	period, ok := entity.SplitRollupRange(startDate, endDate, time.Now(), s.location)
+	if !ok {
+		return period, false
+	}
+
+	if !s.rollupReady.Load() {
+		ready, err := s.rollupRepo.HasCompletedRun(ctx)
+		if err != nil {
+			helpers.Logger.Error("Gagal memeriksa rollup laporan: ", err)
+			return period, false
+		}
+		if !ready {
+			return period, false
+		}
+		s.rollupReady.Store(true)
+	}
+	return period, true
+}
+
+// cachedReport mengambil hasil laporan dari cache atau menghitungnya dengan load. Hasil dari cache
+// dipakai bersama oleh beberapa request sehingga tidak boleh diubah pemanggilnya.
+func cachedReport[T any](reportCache *cache.TTL, key string, load func() (T, error)) (T, error) {
+	if cached, ok := reportCache.Get(key); ok {
+		return cached.(T), nil
+	}
+
+	result, err := load()
+	if err != nil {
+		return result, err
+	}
+	reportCache.Set(key, result)
+	return result, nil
+}
+
+// reportCacheKey menyusun kunci cache dari nama laporan dan seluruh parameternya
+func reportCacheKey(report string, startDate, endDate time.Time, params ...interface{}) string {
+	return fmt.Sprintf("%s|%s|%s|%v", report, startDate.Format(time.RFC3339), endDate.Format(time.RFC3339), params)
+}
+
+func (s *BusinessReportService) GetUtilizationReport(ctx context.Context, startDate, endDate time.Time, idleDays int, branchID string) (*entity.UtilizationReport, error) {
+	if idleDays <= 0 {
+		idleDays = 30
+	}
+
+	toys, err := s.reportRepo.GetToyUtilization(ctx, startDate, endDate, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.reportRepo.GetCategoryUtilization(ctx, startDate, endDate, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	idleToys, err := s.reportRepo.GetIdleToys(ctx, startDate, endDate, idleDays, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.UtilizationReport{
+		Toys:       toys,
+		Categories: categories,
+		IdleToys:   idleToys,
+	}, nil
+}
+
+// GetDashboard menghitung KPI periode terpilih dan periode sebelumnya dengan panjang yang sama.
+// Seluruh query berjalan bersamaan dan dibatalkan begitu salah satunya gagal.
+func (s *BusinessReportService) GetDashboard(ctx context.Context, startDate, endDate time.Time, branchID string) (*entity.DashboardReport, error) {
+	days := int(endDate.Sub(startDate).Hours()/24) + 1
+	report := &entity.DashboardReport{
+		StartDate:         startDate,
+		EndDate:           endDate,
+		PreviousStartDate: startDate.AddDate(0, 0, -days),
+		PreviousEndDate:   startDate.Add(-time.Second),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dashboardTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	run := func(query func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := query(ctx); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var sales []entity.SalesReportItem
+	var currentRate, previousRate float64
+
+	run(func(ctx context.Context) (err error) {
+		report.Current, err = s.reportRepo.GetKPISummary(ctx, report.StartDate, report.EndDate, branchID)
+		return err
+	})
+	run(func(ctx context.Context) (err error) {
+		report.Previous, err = s.reportRepo.GetKPISummary(ctx, report.PreviousStartDate, report.PreviousEndDate, branchID)
+		return err
+	})
+	run(func(ctx context.Context) (err error) {
+		currentRate, err = s.reportRepo.GetUtilizationRate(ctx, report.StartDate, report.EndDate, branchID)
+		return err
+	})
+	run(func(ctx context.Context) (err error) {
+		previousRate, err = s.reportRepo.GetUtilizationRate(ctx, report.PreviousStartDate, report.PreviousEndDate, branchID)
+		return err
+	})
+	run(func(ctx context.Context) (err error) {
+		sales, err = s.GetSalesReport(ctx, report.StartDate, report.EndDate, "day", branchID)
+		return err
+	})
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	report.Current.UtilizationRate = currentRate
+	report.Previous.UtilizationRate = previousRate
+	report.Change = entity.KPIChange{
+		Revenue:         percentChange(report.Current.Revenue.Float(), report.Previous.Revenue.Float()),
+		RentalCount:     percentChange(float64(report.Current.RentalCount), float64(report.Previous.RentalCount)),
+		ActiveRentals:   percentChange(float64(report.Current.ActiveRentals), float64(report.Previous.ActiveRentals)),
+		OverdueCount:    percentChange(float64(report.Current.OverdueCount), float64(report.Previous.OverdueCount)),
+		AverageBasket:   percentChange(report.Current.AverageBasket.Float(), report.Previous.AverageBasket.Float()),
+		NewCustomers:    percentChange(float64(report.Current.NewCustomers), float64(report.Previous.NewCustomers)),
+		UtilizationRate: percentChange(currentRate, previousRate),
+	}
+
+	// Deret harian dilengkapi hari tanpa transaksi agar sparkline tidak melompat
+	byDate := make(map[string]entity.SalesReportItem, len(sales))
+	for _, item := range sales {
+		byDate[item.Date] = item
+	}
+	for day := 0; day < days; day++ {
+		date := startDate.AddDate(0, 0, day).Format("2006-01-02")
+		item := byDate[date]
+		report.Series = append(report.Series, entity.DashboardPoint{
+			Date:        date,
+			Revenue:     item.TotalRevenue,
+			RentalCount: item.RentalCount,
+		})
+	}
+
+	return report, nil
+}
+
+// percentChange menghitung perubahan terhadap nilai sebelumnya dalam persen
+func percentChange(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := (current - previous) / previous * 100
+	return &change
+}
+
+// GetDamageReport menyusun laporan kerusakan dan kehilangan. Biaya terkumpul per rental dihitung dengan
+// RentalBalance sehingga sama dengan sisa tagihan yang dilihat pelanggan. Pelanggan dengan insiden
+// minimal minIncidents kali ditandai.
+func (s *BusinessReportService) GetDamageReport(ctx context.Context, startDate, endDate time.Time, minIncidents int, branchID string) (*entity.DamageReport, error) {
+	if minIncidents <= 0 {
+		minIncidents = 2
+	}
+
+	summary, err := s.reportRepo.GetDamageSummary(ctx, startDate, endDate, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	toys, err := s.reportRepo.GetDamageByToy(ctx, startDate, endDate, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.reportRepo.GetDamageByCategory(ctx, startDate, endDate, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	customers, err := s.reportRepo.GetDamageByCustomer(ctx, startDate, endDate, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRentals, err := s.reportRepo.GetDamageFeeRentals(ctx, startDate, endDate, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[uuid.UUID]int, len(customers))
+	for i := range customers {
+		index[customers[i].UserID] = i
+		customers[i].Flagged = customers[i].IncidentCount >= minIncidents
+	}
+
+	for _, feeRental := range feeRentals {
+		rental := entity.Rental{
+			TotalRentalPrice: feeRental.TotalRentalPrice,
+			LateFee:          feeRental.LateFee,
+			DamageFee:        feeRental.DamageFee,
+			TaxRate:          feeRental.TaxRate,
+			TaxInclusive:     feeRental.TaxInclusive,
+			DepositApplied:   feeRental.DepositApplied,
+		}
+		balance := entity.NewRentalBalance(rental, feeRental.Paid)
+		collected := balance.DamageFee - balance.DamageFeeDue
+
+		summary.FeesCharged += balance.DamageFee
+		summary.FeesCollected += collected
+		if i, ok := index[feeRental.UserID]; ok {
+			customers[i].FeesCharged += balance.DamageFee
+			customers[i].FeesCollected += collected
+		}
+	}
+	summary.FeesOutstanding = summary.FeesCharged - summary.FeesCollected
+
+	return &entity.DamageReport{
+		Summary:    summary,
+		Toys:       toys,
+		Categories: categories,
+		Customers:  customers,
+	}, nil
+}
+
+func (s *BusinessReportService) GetInventoryReport(ctx context.Context, filter entity.InventoryStatusFilter, branchID string) ([]entity.InventoryStatusItem, error) {
+	return s.reportRepo.GetInventoryStatus(ctx, filter, branchID)
 }
 
-func (s *BusinessReportService) GetRentalStatusReport(ctx context.Context, startDate, endDate time.Time) ([]entity.RentalStatusItem, error) {
-	return s.reportRepo.GetRentalStatusCount(ctx, startDate, endDate)
+func (s *BusinessReportService) GetOverdueReport(ctx context.Context, asOf time.Time, branchID string) ([]entity.OverdueRentalItem, error) {
+	return s.reportRepo.GetOverdueRentals(ctx, asOf, branchID)
 }
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"final-project/entity"
+	"final-project/repository"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Rental dini hari WIB sudah tanggal hari ini dalam zona waktu laporan walaupun masih tanggal kemarin
+// dalam UTC. Laporan yang menggabungkan rollup dan transaksi hari ini harus menghitungnya tepat sekali.
+func TestGetSalesReportAcrossRollupSplit(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	location, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skip("zona waktu Asia/Jakarta tidak tersedia: ", err)
+	}
+
+	now := time.Now().In(location)
+	today := entity.ReportDayStart(entity.ReportDay(now, location), location)
+	yesterday := today.AddDate(0, 0, -1)
+
+	suffix := uuid.Must(uuid.NewV4()).String()[:8]
+	branch := entity.Branch{Code: "T" + suffix, Name: "Cabang Uji", Address: "Jl. Uji"}
+	if err := db.Create(&branch).Error; err != nil {
+		t.Fatal(err)
+	}
+	user := entity.User{Email: "rollup-" + suffix + "@example.com", Username: "rollup-" + suffix, Password: "rahasia", FullName: "Pelanggan Rollup"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	rentalDates := []time.Time{
+		yesterday.Add(23 * time.Hour), // dari rollup
+		today.Add(now.Sub(today) / 2), // dari transaksi hari ini, bisa masih tanggal kemarin dalam UTC
+	}
+	for _, rentalDate := range rentalDates {
+		rental := entity.Rental{
+			UserID:             user.ID,
+			Status:             entity.RentalStatusActive,
+			RentalDate:         rentalDate,
+			ExpectedReturnDate: rentalDate.AddDate(0, 0, 3),
+			TotalRentalPrice:   100000,
+			PaymentStatus:      entity.PaymentStatusPaid,
+			PickupBranchID:     &branch.ID,
+		}
+		if err := db.Create(&rental).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rollupRepo := repository.NewReportRollupRepository(db, location)
+	if _, err := NewReportRollupService(rollupRepo, nil, 0, location).Rebuild(ctx); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+
+	reportSvc := NewBusinessReportService(repository.NewBusinessReportRepository(db, location), rollupRepo, nil, location)
+	endDate := today.Add(24*time.Hour - time.Second)
+	items, err := reportSvc.GetSalesReport(ctx, yesterday, endDate, "day", branch.ID.String())
+	if err != nil {
+		t.Fatalf("GetSalesReport: %v", err)
+	}
+
+	want := map[string]int{
+		yesterday.Format("2006-01-02"): 1,
+		today.Format("2006-01-02"):     1,
+	}
+	got := make(map[string]int)
+	for _, item := range items {
+		got[item.Date] += item.RentalCount
+	}
+	if len(got) != len(want) || got[yesterday.Format("2006-01-02")] != 1 || got[today.Format("2006-01-02")] != 1 {
+		t.Errorf("jumlah rental per tanggal %v, ingin %v", got, want)
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"final-project/config"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB membuka database PostgreSQL dari TEST_DATABASE_URL. Setiap uji berjalan di dalam transaksi
+// yang dibatalkan setelah uji selesai, dan uji dilewati bila variabel tersebut tidak diisi.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL tidak diisi")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("gagal terhubung ke database uji: %v", err)
+	}
+	if err := (&config.Database{DB: db}).AutoMigrate(); err != nil {
+		t.Fatalf("gagal migrasi database uji: %v", err)
+	}
+
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
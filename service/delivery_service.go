@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type IDeliveryService interface {
+	IBaseService[entity.DeliveryTask]
+	FindZones(ctx context.Context, branchID string) ([]entity.DeliveryZone, error)
+	FindZoneByID(ctx context.Context, id string) (*entity.DeliveryZone, error)
+	CreateZone(ctx context.Context, req entity.DeliveryZoneRequest) (*entity.DeliveryZone, error)
+	UpdateZone(ctx context.Context, id string, req entity.DeliveryZoneRequest) (*entity.DeliveryZone, error)
+	DeleteZone(ctx context.Context, id string) error
+	FindSlots(ctx context.Context, filter entity.DeliverySlotFilter, activeOnly bool) ([]entity.DeliverySlot, error)
+	FindSlotByID(ctx context.Context, id string) (*entity.DeliverySlot, error)
+	CreateSlot(ctx context.Context, req entity.DeliverySlotRequest) (*entity.DeliverySlot, error)
+	UpdateSlot(ctx context.Context, id string, req entity.DeliverySlotRequest) (*entity.DeliverySlot, error)
+	DeleteSlot(ctx context.Context, id string) error
+	FindTasks(ctx context.Context, filter entity.DeliveryTaskFilter, limit int, offset int) ([]entity.DeliveryTask, int64, error)
+	AssignCourier(ctx context.Context, id string, courierID *uuid.UUID) (*entity.DeliveryTask, error)
+	UpdateTaskStatus(ctx context.Context, id string, req entity.UpdateDeliveryTaskStatusRequest) (*entity.DeliveryTask, error)
+	ApplyToQuote(ctx context.Context, quote *entity.PriceQuote, req entity.QuoteRentalRequest) error
+}
+
+type DeliveryService struct {
+	BaseService[entity.DeliveryTask]
+	taskRepo    repository.IDeliveryTaskRepository
+	zoneRepo    repository.IDeliveryZoneRepository
+	slotRepo    repository.IDeliverySlotRepository
+	addressRepo repository.IUserAddressRepository
+	branchRepo  repository.IBranchRepository
+	userRepo    repository.IUserRepository
+}
+
+func NewDeliveryService(
+	taskRepo repository.IDeliveryTaskRepository,
+	zoneRepo repository.IDeliveryZoneRepository,
+	slotRepo repository.IDeliverySlotRepository,
+	addressRepo repository.IUserAddressRepository,
+	branchRepo repository.IBranchRepository,
+	userRepo repository.IUserRepository,
+) IDeliveryService {
+	return &DeliveryService{
+		BaseService: BaseService[entity.DeliveryTask]{repository: taskRepo},
+		taskRepo:    taskRepo,
+		zoneRepo:    zoneRepo,
+		slotRepo:    slotRepo,
+		addressRepo: addressRepo,
+		branchRepo:  branchRepo,
+		userRepo:    userRepo,
+	}
+}
+
+func (s *DeliveryService) FindZones(ctx context.Context, branchID string) ([]entity.DeliveryZone, error) {
+	return s.zoneRepo.FindByBranch(ctx, branchID, false)
+}
+
+func (s *DeliveryService) FindZoneByID(ctx context.Context, id string) (*entity.DeliveryZone, error) {
+	zone, err := s.zoneRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+func (s *DeliveryService) CreateZone(ctx context.Context, req entity.DeliveryZoneRequest) (*entity.DeliveryZone, error) {
+	zone := &entity.DeliveryZone{IsActive: true}
+	applyDeliveryZoneRequest(zone, req)
+
+	if errs := zone.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if _, err := resolveBranch(ctx, s.branchRepo, &zone.BranchID); err != nil {
+		return nil, err
+	}
+
+	if err := s.zoneRepo.Insert(ctx, zone); err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+func (s *DeliveryService) UpdateZone(ctx context.Context, id string, req entity.DeliveryZoneRequest) (*entity.DeliveryZone, error) {
+	zone, err := s.zoneRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyDeliveryZoneRequest(&zone, req)
+
+	if errs := zone.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if _, err := resolveBranch(ctx, s.branchRepo, &zone.BranchID); err != nil {
+		return nil, err
+	}
+
+	if err := s.zoneRepo.UpdateById(ctx, id, &zone); err != nil {
+		return nil, err
+	}
+	return &zone, nil
+}
+
+func (s *DeliveryService) DeleteZone(ctx context.Context, id string) error {
+	if _, err := s.zoneRepo.FindById(ctx, id); err != nil {
+		return err
+	}
+	return s.zoneRepo.DeleteById(ctx, id)
+}
+
+func (s *DeliveryService) FindSlots(ctx context.Context, filter entity.DeliverySlotFilter, activeOnly bool) ([]entity.DeliverySlot, error) {
+	return s.slotRepo.FindFiltered(ctx, filter, activeOnly)
+}
+
+func (s *DeliveryService) FindSlotByID(ctx context.Context, id string) (*entity.DeliverySlot, error) {
+	slot, err := s.slotRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+func (s *DeliveryService) CreateSlot(ctx context.Context, req entity.DeliverySlotRequest) (*entity.DeliverySlot, error) {
+	slot := &entity.DeliverySlot{IsActive: true}
+	if err := applyDeliverySlotRequest(slot, req); err != nil {
+		return nil, err
+	}
+
+	if errs := slot.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if _, err := resolveBranch(ctx, s.branchRepo, &slot.BranchID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.slotRepo.FindByWindow(ctx, *slot); err == nil {
+		return nil, entity.ErrDeliverySlotExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.slotRepo.Insert(ctx, slot); err != nil {
+		return nil, err
+	}
+	return slot, nil
+}
+
+// UpdateSlot mengubah jadwal. Cabang tidak dapat dipindah, dan jadwal yang sudah dipesan tidak dapat
+// digeser waktunya maupun dikurangi kuotanya di bawah jumlah pesanan.
+func (s *DeliveryService) UpdateSlot(ctx context.Context, id string, req entity.DeliverySlotRequest) (*entity.DeliverySlot, error) {
+	slot, err := s.slotRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := slot
+	if err := applyDeliverySlotRequest(&slot, req); err != nil {
+		return nil, err
+	}
+	slot.BranchID = previous.BranchID
+
+	if errs := slot.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if slot.Booked > 0 {
+		moved := !slot.IsOn(previous.Date) || slot.StartTime != previous.StartTime || slot.EndTime != previous.EndTime
+		if moved || int64(slot.Capacity) < slot.Booked {
+			return nil, entity.ErrDeliverySlotInUse
+		}
+	}
+
+	if existing, err := s.slotRepo.FindByWindow(ctx, slot); err == nil && existing.ID != slot.ID {
+		return nil, entity.ErrDeliverySlotExists
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.slotRepo.UpdateById(ctx, id, &slot); err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// DeleteSlot hanya menghapus jadwal yang belum dipakai tugas, jadwal terpakai cukup dinonaktifkan
+func (s *DeliveryService) DeleteSlot(ctx context.Context, id string) error {
+	slot, err := s.slotRepo.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if slot.Booked > 0 {
+		return entity.ErrDeliverySlotInUse
+	}
+	return s.slotRepo.DeleteById(ctx, id)
+}
+
+func (s *DeliveryService) FindTasks(ctx context.Context, filter entity.DeliveryTaskFilter, limit int, offset int) ([]entity.DeliveryTask, int64, error) {
+	return s.taskRepo.FindFiltered(ctx, filter, limit, offset)
+}
+
+// AssignCourier menugaskan staf (pengguna admin) sebagai kurir, courierID nil melepas kurir dari tugas
+func (s *DeliveryService) AssignCourier(ctx context.Context, id string, courierID *uuid.UUID) (*entity.DeliveryTask, error) {
+	task, err := s.taskRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Status == entity.DeliveryTaskStatusCompleted || task.Status == entity.DeliveryTaskStatusCancelled {
+		return nil, entity.ErrInvalidDeliveryTaskState
+	}
+
+	if courierID != nil {
+		courier, err := s.userRepo.FindById(ctx, courierID.String())
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, entity.ErrCourierNotStaff
+			}
+			return nil, err
+		}
+		if courier.Role != entity.RoleAdmin {
+			return nil, entity.ErrCourierNotStaff
+		}
+	}
+
+	if err := s.taskRepo.AssignCourier(ctx, id, courierID); err != nil {
+		return nil, err
+	}
+
+	task.CourierID = courierID
+	return &task, nil
+}
+
+func (s *DeliveryService) UpdateTaskStatus(ctx context.Context, id string, req entity.UpdateDeliveryTaskStatusRequest) (*entity.DeliveryTask, error) {
+	task, err := s.taskRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.CanTransitionTo(req.Status) {
+		return nil, entity.ErrInvalidDeliveryTaskState
+	}
+
+	fromStatus := task.Status
+	task.Status = req.Status
+	if req.Status == entity.DeliveryTaskStatusCompleted {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+	if notes := strings.TrimSpace(req.Notes); notes != "" {
+		task.Notes = notes
+	}
+
+	if err := s.taskRepo.UpdateStatus(ctx, &task, fromStatus); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ApplyToQuote menambahkan ongkos antar dan jemput sebagai baris harga tersendiri lalu menyiapkan
+// tugas kurirnya. Ongkos dihitung setelah promo sehingga potongan promo tidak mengurangi ongkos.
+func (s *DeliveryService) ApplyToQuote(ctx context.Context, quote *entity.PriceQuote, req entity.QuoteRentalRequest) error {
+	options := req.DeliveryOptions
+	if err := options.Normalize(); err != nil {
+		return err
+	}
+
+	if options.DeliveryMethod == entity.DeliveryMethodSelfPickup && options.ReturnMethod == entity.ReturnMethodSelfReturn {
+		return nil
+	}
+
+	if options.AddressID == nil {
+		return entity.ErrDeliveryAddressRequired
+	}
+	address, err := s.addressRepo.FindByUserAndID(ctx, req.UserID.String(), options.AddressID.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ErrAddressNotFound
+		}
+		return err
+	}
+
+	pickupBranch, err := resolveBranch(ctx, s.branchRepo, req.PickupBranchID)
+	if err != nil {
+		return err
+	}
+	returnBranch := pickupBranch
+	if req.ReturnBranchID != nil {
+		if returnBranch, err = resolveBranch(ctx, s.branchRepo, req.ReturnBranchID); err != nil {
+			return err
+		}
+	}
+
+	if options.DeliveryMethod == entity.DeliveryMethodDelivery {
+		task, zone, err := s.planTask(ctx, entity.DeliveryTaskTypeDelivery, pickupBranch, address, options.DeliverySlotID, req.RentalDate)
+		if err != nil {
+			return err
+		}
+		quote.Lines = append(quote.Lines, entity.PriceLine{
+			Kind:        entity.PriceLineDelivery,
+			Description: "Ongkos antar - " + zone.Name,
+			Quantity:    1,
+			UnitPrice:   zone.Fee,
+			Amount:      zone.Fee,
+		})
+		quote.DeliveryFee += zone.Fee
+		quote.DeliveryTasks = append(quote.DeliveryTasks, task)
+	}
+
+	if options.ReturnMethod == entity.ReturnMethodPickup {
+		task, zone, err := s.planTask(ctx, entity.DeliveryTaskTypeReturnPickup, returnBranch, address, options.ReturnSlotID, req.ExpectedReturnDate)
+		if err != nil {
+			return err
+		}
+		quote.Lines = append(quote.Lines, entity.PriceLine{
+			Kind:        entity.PriceLineReturnPickup,
+			Description: "Ongkos jemput - " + zone.Name,
+			Quantity:    1,
+			UnitPrice:   zone.Fee,
+			Amount:      zone.Fee,
+		})
+		quote.DeliveryFee += zone.Fee
+		quote.DeliveryTasks = append(quote.DeliveryTasks, task)
+	}
+
+	quote.Total = entity.SumPriceLines(quote.Lines)
+	return nil
+}
+
+// planTask memeriksa jadwal dan zona cabang lalu menyusun tugas kurir dengan salinan alamat tujuan
+func (s *DeliveryService) planTask(ctx context.Context, taskType string, branch entity.Branch, address entity.UserAddress, slotID *uuid.UUID, date time.Time) (entity.DeliveryTask, entity.DeliveryZone, error) {
+	var task entity.DeliveryTask
+
+	if slotID == nil {
+		return task, entity.DeliveryZone{}, entity.ErrDeliverySlotRequired
+	}
+
+	slot, err := s.slotRepo.FindById(ctx, slotID.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return task, entity.DeliveryZone{}, entity.ErrDeliverySlotNotFound
+		}
+		return task, entity.DeliveryZone{}, err
+	}
+	if !slot.IsActive {
+		return task, entity.DeliveryZone{}, entity.ErrDeliverySlotNotFound
+	}
+	if slot.BranchID != branch.ID || !slot.IsOn(date) {
+		return task, entity.DeliveryZone{}, entity.ErrDeliverySlotMismatch
+	}
+	if slot.Remaining() <= 0 {
+		return task, entity.DeliveryZone{}, entity.ErrDeliverySlotFull
+	}
+
+	zone, err := s.findZone(ctx, branch, address)
+	if err != nil {
+		return task, entity.DeliveryZone{}, err
+	}
+
+	addressID := address.ID
+	task = entity.DeliveryTask{
+		BranchID:      branch.ID,
+		SlotID:        slot.ID,
+		Type:          taskType,
+		Status:        entity.DeliveryTaskStatusScheduled,
+		AddressID:     &addressID,
+		RecipientName: address.RecipientName,
+		PhoneNumber:   address.PhoneNumber,
+		Address:       address.FullAddress(),
+		AddressNotes:  address.Notes,
+		Fee:           zone.Fee,
+		Slot:          slot,
+	}
+	return task, zone, nil
+}
+
+// findZone memilih zona termurah yang mencakup kode pos alamat. Bila tidak ada, zona dicari dari
+// jarak alamat ke cabang selama keduanya memiliki koordinat.
+func (s *DeliveryService) findZone(ctx context.Context, branch entity.Branch, address entity.UserAddress) (entity.DeliveryZone, error) {
+	zones, err := s.zoneRepo.FindByBranch(ctx, branch.ID.String(), true)
+	if err != nil {
+		return entity.DeliveryZone{}, err
+	}
+
+	for _, zone := range zones {
+		if zone.CoversPostalCode(address.PostalCode) {
+			return zone, nil
+		}
+	}
+
+	if branch.Latitude == nil || branch.Longitude == nil || address.Latitude == nil || address.Longitude == nil {
+		return entity.DeliveryZone{}, entity.ErrOutsideDeliveryZone
+	}
+
+	distance := entity.DistanceKm(*branch.Latitude, *branch.Longitude, *address.Latitude, *address.Longitude)
+	for _, zone := range zones {
+		if zone.CoversDistance(distance) {
+			return zone, nil
+		}
+	}
+	return entity.DeliveryZone{}, entity.ErrOutsideDeliveryZone
+}
+
+func applyDeliveryZoneRequest(zone *entity.DeliveryZone, req entity.DeliveryZoneRequest) {
+	postalCodes := make([]string, 0, len(req.PostalCodes))
+	for _, code := range req.PostalCodes {
+		if code = strings.TrimSpace(code); code != "" {
+			postalCodes = append(postalCodes, code)
+		}
+	}
+
+	zone.BranchID = req.BranchID
+	zone.Name = strings.TrimSpace(req.Name)
+	zone.PostalCodes = strings.Join(postalCodes, ",")
+	zone.MinDistanceKm = req.MinDistanceKm
+	zone.MaxDistanceKm = req.MaxDistanceKm
+	zone.Fee = req.Fee
+	if req.IsActive != nil {
+		zone.IsActive = *req.IsActive
+	}
+}
+
+func applyDeliverySlotRequest(slot *entity.DeliverySlot, req entity.DeliverySlotRequest) error {
+	date, err := time.Parse(entity.HolidayDateFormat, req.Date)
+	if err != nil {
+		return errors.New("format tanggal jadwal harus YYYY-MM-DD")
+	}
+
+	slot.BranchID = req.BranchID
+	slot.Date = date
+	slot.StartTime = strings.TrimSpace(req.StartTime)
+	slot.EndTime = strings.TrimSpace(req.EndTime)
+	slot.Capacity = req.Capacity
+	if req.IsActive != nil {
+		slot.IsActive = *req.IsActive
+	}
+	return nil
+}
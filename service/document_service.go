@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"final-project/entity"
+	"final-project/repository"
+	"final-project/utils/money"
+	"final-project/utils/pdf"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const documentStoreName = "Toy Rental"
+
+type IDocumentService interface {
+	RentalInvoice(ctx context.Context, rentalID string) (*entity.DocumentFile, error)
+	PaymentReceipt(ctx context.Context, paymentID string) (*entity.DocumentFile, error)
+}
+
+type DocumentService struct {
+	rentalRepo  repository.IRentalRepository
+	paymentRepo repository.IPaymentRepository
+	userRepo    repository.IUserRepository
+	paymentSvc  IPaymentService
+}
+
+func NewDocumentService(
+	rentalRepo repository.IRentalRepository,
+	paymentRepo repository.IPaymentRepository,
+	userRepo repository.IUserRepository,
+	paymentSvc IPaymentService,
+) IDocumentService {
+	return &DocumentService{
+		rentalRepo:  rentalRepo,
+		paymentRepo: paymentRepo,
+		userRepo:    userRepo,
+		paymentSvc:  paymentSvc,
+	}
+}
+
+// RentalInvoice membuat invoice PDF rental berisi rincian sewa, denda, PPN, deposit dan pembayaran yang sudah masuk
+func (s *DocumentService) RentalInvoice(ctx context.Context, rentalID string) (*entity.DocumentFile, error) {
+	rental, err := s.rentalRepo.FindById(ctx, rentalID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindById(ctx, rental.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := s.paymentSvc.GetRentalBalance(ctx, rental)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.FindByRentalID(ctx, rentalID)
+	if err != nil {
+		return nil, err
+	}
+
+	number := "RNT-" + strings.ToUpper(rental.ID.String()[:8])
+	layout := newDocumentLayout("INVOICE")
+	layout.field("No. Invoice", number)
+	layout.field("Tanggal", formatDocumentDate(time.Now()))
+	layout.field("Pelanggan", user.FullName+" ("+user.Email+")")
+	layout.field("Periode sewa", formatDocumentDate(rental.RentalDate)+" - "+formatDocumentDate(rental.ExpectedReturnDate))
+	layout.field("Lama sewa", strconv.Itoa(max(countRentalDays(rental.RentalDate, rental.ExpectedReturnDate), 1))+" hari")
+	if rental.ActualReturnDate != nil {
+		layout.field("Dikembalikan", formatDocumentDate(*rental.ActualReturnDate))
+	}
+	layout.field("Status", rental.Status+" / "+rental.PaymentStatus)
+
+	lines := rentalPriceLines(rental)
+	if lateFee := rental.LateFee; lateFee > 0 {
+		lines = append(lines, entity.PriceLine{Kind: entity.PriceLineLateFee, Description: "Biaya Keterlambatan", Quantity: 1, UnitPrice: lateFee, Amount: lateFee})
+	}
+	if damageFee := rental.DamageFee; damageFee > 0 {
+		lines = append(lines, entity.PriceLine{Kind: entity.PriceLineDamageFee, Description: "Biaya Kerusakan", Quantity: 1, UnitPrice: damageFee, Amount: damageFee})
+	}
+	layout.items(lines)
+
+	tax := rental.TaxPolicy()
+	layout.total("Subtotal", entity.SumPriceLines(lines), false)
+	if balance.Tax > 0 {
+		label := tax.Label() + " (ditambahkan)"
+		if tax.Inclusive {
+			label = tax.Label() + " (termasuk dalam harga)"
+		}
+		layout.total(label, balance.Tax, false)
+	}
+	layout.total("Total Tagihan", balance.TotalCharged, true)
+	if rental.DepositAmount > 0 {
+		layout.total("Deposit ("+rental.DepositStatus+")", rental.DepositAmount, false)
+	}
+	layout.total("Sudah Dibayar", balance.Paid, false)
+	if balance.DepositApplied > 0 {
+		layout.total("Potongan Deposit", balance.DepositApplied, false)
+	}
+	if balance.WrittenOff > 0 {
+		layout.total("Dihapuskan", balance.WrittenOff, false)
+	}
+	layout.total("Sisa Tagihan", balance.Outstanding, true)
+
+	var settled []entity.Payment
+	for _, payment := range payments {
+		if isSettledPayment(payment) {
+			settled = append(settled, payment)
+		}
+	}
+	if len(settled) > 0 {
+		layout.section("Riwayat Pembayaran")
+		for _, payment := range settled {
+			layout.row(
+				formatDocumentDate(paymentDate(payment))+"  "+payment.OrderID+"  "+paymentMethodLabel(payment.PaymentMethod),
+				payment.GrossAmount.String(),
+			)
+		}
+	}
+
+	return &entity.DocumentFile{
+		Filename:    "invoice-" + number + ".pdf",
+		ContentType: "application/pdf",
+		UserID:      rental.UserID,
+		Content:     layout.doc.Bytes(),
+	}, nil
+}
+
+// PaymentReceipt membuat kwitansi PDF untuk pembayaran yang sudah lunas
+func (s *DocumentService) PaymentReceipt(ctx context.Context, paymentID string) (*entity.DocumentFile, error) {
+	payment, err := s.paymentRepo.FindByIdWithRental(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSettledPayment(payment) {
+		return nil, entity.ErrPaymentNotSettled
+	}
+
+	rental := payment.Rental
+	layout := newDocumentLayout("KWITANSI PEMBAYARAN")
+	layout.field("No. Pembayaran", payment.OrderID)
+	if payment.TaxInvoiceNumber != nil {
+		layout.field("No. Faktur", *payment.TaxInvoiceNumber)
+	}
+	layout.field("Tanggal Bayar", formatDocumentDate(paymentDate(payment)))
+	layout.field("Pelanggan", rental.User.FullName+" ("+rental.User.Email+")")
+	layout.field("Rental", "RNT-"+strings.ToUpper(rental.ID.String()[:8]))
+	layout.field("Periode sewa", formatDocumentDate(rental.RentalDate)+" - "+formatDocumentDate(rental.ExpectedReturnDate)+
+		" ("+strconv.Itoa(max(countRentalDays(rental.RentalDate, rental.ExpectedReturnDate), 1))+" hari)")
+	layout.field("Metode Pembayaran", paymentMethodLabel(payment.PaymentMethod))
+
+	lines := make([]entity.PriceLine, 0, len(payment.Items))
+	for _, item := range payment.Items {
+		lines = append(lines, item.PriceLine)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, entity.PriceLine{
+			Kind:        payment.PaymentType,
+			Description: "Pembayaran " + payment.PaymentType,
+			Quantity:    1,
+			UnitPrice:   payment.GrossAmount,
+			Amount:      payment.GrossAmount,
+		})
+	}
+	layout.items(lines)
+	layout.total("Total Dibayar", payment.GrossAmount, true)
+	layout.section("LUNAS")
+
+	return &entity.DocumentFile{
+		Filename:    "kwitansi-" + payment.OrderID + ".pdf",
+		ContentType: "application/pdf",
+		UserID:      rental.UserID,
+		Content:     layout.doc.Bytes(),
+	}, nil
+}
+
+func isSettledPayment(payment entity.Payment) bool {
+	return payment.TransactionStatus == entity.TransactionStatusSettlement ||
+		payment.TransactionStatus == entity.TransactionStatusCapture ||
+		payment.TransactionStatus == entity.TransactionStatusPartialRefund
+}
+
+func paymentDate(payment entity.Payment) time.Time {
+	if payment.TransactionTime != nil {
+		return *payment.TransactionTime
+	}
+	return payment.CreatedAt
+}
+
+func paymentMethodLabel(method string) string {
+	switch method {
+	case "":
+		return "-"
+	case entity.PaymentMethodWallet:
+		return "Saldo dompet"
+	default:
+		return strings.ReplaceAll(method, "_", " ")
+	}
+}
+
+var documentMonths = [...]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"}
+
+func formatDocumentDate(t time.Time) string {
+	return fmt.Sprintf("%02d %s %d", t.Day(), documentMonths[t.Month()-1], t.Year())
+}
+
+// documentLayout menyusun dokumen dari atas ke bawah dan berpindah halaman saat ruang habis
+type documentLayout struct {
+	doc *pdf.Document
+	y   float64
+}
+
+const (
+	documentMarginLeft  = 50.0
+	documentMarginRight = pdf.PageWidth - 50
+	documentMarginTop   = 50.0
+	documentMarginEnd   = pdf.PageHeight - 60
+	documentFontSize    = 10.0
+	documentLineHeight  = 16.0
+)
+
+func newDocumentLayout(title string) *documentLayout {
+	layout := &documentLayout{doc: pdf.New(), y: documentMarginTop}
+	layout.doc.Text(documentMarginLeft, layout.y, 18, true, title)
+	layout.doc.TextRight(documentMarginRight, layout.y+4, 12, true, documentStoreName)
+	layout.y += 36
+	return layout
+}
+
+func (l *documentLayout) advance(height float64) {
+	if l.y+height > documentMarginEnd {
+		l.doc.AddPage()
+		l.y = documentMarginTop
+	}
+}
+
+func (l *documentLayout) field(label, value string) {
+	l.advance(documentLineHeight)
+	l.doc.Text(documentMarginLeft, l.y, documentFontSize, true, label)
+	l.doc.Text(documentMarginLeft+110, l.y, documentFontSize, false, ": "+value)
+	l.y += documentLineHeight
+}
+
+func (l *documentLayout) section(title string) {
+	l.advance(documentLineHeight * 2)
+	l.y += documentLineHeight / 2
+	l.doc.Text(documentMarginLeft, l.y, 12, true, title)
+	l.y += documentLineHeight + 2
+}
+
+func (l *documentLayout) row(label, amount string) {
+	l.advance(documentLineHeight)
+	l.doc.Text(documentMarginLeft, l.y, documentFontSize, false, label)
+	l.doc.TextRight(documentMarginRight, l.y, documentFontSize, false, amount)
+	l.y += documentLineHeight
+}
+
+func (l *documentLayout) items(lines []entity.PriceLine) {
+	const qtyRight, priceRight = 360.0, 450.0
+
+	l.section("Rincian")
+	l.doc.Text(documentMarginLeft, l.y, documentFontSize, true, "Deskripsi")
+	l.doc.TextRight(qtyRight, l.y, documentFontSize, true, "Qty")
+	l.doc.TextRight(priceRight, l.y, documentFontSize, true, "Harga")
+	l.doc.TextRight(documentMarginRight, l.y, documentFontSize, true, "Jumlah")
+	l.y += documentLineHeight
+	l.doc.Line(documentMarginLeft, l.y-4, documentMarginRight, l.y-4)
+
+	for _, line := range lines {
+		l.advance(documentLineHeight)
+		l.doc.Text(documentMarginLeft, l.y, documentFontSize, false, line.ShortDescription())
+		l.doc.TextRight(qtyRight, l.y, documentFontSize, false, strconv.Itoa(line.Quantity))
+		l.doc.TextRight(priceRight, l.y, documentFontSize, false, line.UnitPrice.String())
+		l.doc.TextRight(documentMarginRight, l.y, documentFontSize, false, line.Amount.String())
+		l.y += documentLineHeight
+	}
+
+	l.doc.Line(documentMarginLeft, l.y-4, documentMarginRight, l.y-4)
+	l.y += 4
+}
+
+func (l *documentLayout) total(label string, amount money.Money, bold bool) {
+	l.advance(documentLineHeight)
+	l.doc.TextRight(450, l.y, documentFontSize, bold, label)
+	l.doc.TextRight(documentMarginRight, l.y, documentFontSize, bold, amount.String())
+	l.y += documentLineHeight
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+
+	"gorm.io/gorm"
+)
+
+type IFeePolicyService interface {
+	IBaseService[entity.FeePolicy]
+	FindActive(ctx context.Context) (*entity.FeePolicy, error)
+	CreateVersion(ctx context.Context, req entity.FeePolicyRequest) (*entity.FeePolicy, error)
+	Activate(ctx context.Context, id string) (*entity.FeePolicy, error)
+}
+
+type FeePolicyService struct {
+	BaseService[entity.FeePolicy]
+	policyRepo   repository.IFeePolicyRepository
+	categoryRepo repository.IToyCategoryRepository
+}
+
+func NewFeePolicyService(repo repository.IFeePolicyRepository, categoryRepo repository.IToyCategoryRepository) IFeePolicyService {
+	return &FeePolicyService{
+		BaseService:  BaseService[entity.FeePolicy]{repository: repo},
+		policyRepo:   repo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (s *FeePolicyService) FindActive(ctx context.Context) (*entity.FeePolicy, error) {
+	policy, err := s.policyRepo.FindActive(ctx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrNoActiveFeePolicy
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (s *FeePolicyService) CreateVersion(ctx context.Context, req entity.FeePolicyRequest) (*entity.FeePolicy, error) {
+	policy := &entity.FeePolicy{
+		Name:                 req.Name,
+		Description:          req.Description,
+		GracePeriodMinutes:   req.GracePeriodMinutes,
+		LateDayHours:         req.LateDayHours,
+		DailyLateFeeCap:      req.DailyLateFeeCap,
+		MaxLateFee:           req.MaxLateFee,
+		DamagePercent:        req.DamagePercent,
+		ConditionStepPercent: req.ConditionStepPercent,
+		LostPercent:          req.LostPercent,
+		MaxUnitDamagePercent: req.MaxUnitDamagePercent,
+		MaxDamageFee:         req.MaxDamageFee,
+	}
+
+	for _, ruleReq := range req.Rules {
+		if _, err := s.categoryRepo.FindById(ctx, ruleReq.CategoryID.String()); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("kategori " + ruleReq.CategoryID.String() + " tidak ditemukan")
+			}
+			return nil, err
+		}
+
+		policy.Rules = append(policy.Rules, entity.FeePolicyRule{
+			CategoryID:           ruleReq.CategoryID,
+			DamagePercent:        ruleReq.DamagePercent,
+			ConditionStepPercent: ruleReq.ConditionStepPercent,
+			LostPercent:          ruleReq.LostPercent,
+		})
+	}
+
+	if errs := policy.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if err := s.policyRepo.CreateVersion(ctx, policy, req.Activate); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (s *FeePolicyService) Activate(ctx context.Context, id string) (*entity.FeePolicy, error) {
+	if err := s.policyRepo.Activate(ctx, id); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policyRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
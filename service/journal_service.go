@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"final-project/entity"
+	"final-project/repository"
+)
+
+type IJournalService interface {
+	FindAccounts() []entity.Account
+	FindEntries(ctx context.Context, filter entity.JournalFilter, limit int, offset int) ([]entity.JournalEntry, int64, error)
+	GetTrialBalance(ctx context.Context, filter entity.JournalFilter) ([]entity.TrialBalanceItem, error)
+	ExportJournal(ctx context.Context, filter entity.JournalFilter) ([]entity.JournalExportRow, error)
+}
+
+type JournalService struct {
+	journalRepo repository.IJournalRepository
+}
+
+func NewJournalService(journalRepo repository.IJournalRepository) IJournalService {
+	return &JournalService{
+		journalRepo: journalRepo,
+	}
+}
+
+func (s *JournalService) FindAccounts() []entity.Account {
+	return entity.ChartOfAccounts
+}
+
+func (s *JournalService) FindEntries(ctx context.Context, filter entity.JournalFilter, limit int, offset int) ([]entity.JournalEntry, int64, error) {
+	return s.journalRepo.FindEntries(ctx, filter, limit, offset)
+}
+
+// GetTrialBalance mengembalikan neraca saldo. Jurnal selalu seimbang sehingga total debit sama dengan total kredit.
+func (s *JournalService) GetTrialBalance(ctx context.Context, filter entity.JournalFilter) ([]entity.TrialBalanceItem, error) {
+	return s.journalRepo.GetTrialBalance(ctx, filter)
+}
+
+func (s *JournalService) ExportJournal(ctx context.Context, filter entity.JournalFilter) ([]entity.JournalExportRow, error) {
+	return s.journalRepo.FindExportRows(ctx, filter)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type IMaintenanceTaskService interface {
+	IBaseService[entity.MaintenanceTask]
+	FindAllFiltered(ctx context.Context, filter entity.MaintenanceTaskFilter, limit int, offset int) ([]entity.MaintenanceTask, int64, error)
+	StartTask(ctx context.Context, id string, staffID uuid.UUID, req entity.StartMaintenanceTaskRequest) (*entity.MaintenanceTask, error)
+	CompleteTask(ctx context.Context, id string, staffID uuid.UUID, req entity.CompleteMaintenanceTaskRequest) (*entity.MaintenanceTask, error)
+}
+
+type MaintenanceTaskService struct {
+	BaseService[entity.MaintenanceTask]
+	taskRepo   repository.IMaintenanceTaskRepository
+	turnaround entity.MaintenanceTurnaround
+}
+
+func NewMaintenanceTaskService(repo repository.IMaintenanceTaskRepository, turnaround entity.MaintenanceTurnaround) IMaintenanceTaskService {
+	return &MaintenanceTaskService{
+		BaseService: BaseService[entity.MaintenanceTask]{repository: repo},
+		taskRepo:    repo,
+		turnaround:  turnaround,
+	}
+}
+
+func (s *MaintenanceTaskService) FindAllFiltered(ctx context.Context, filter entity.MaintenanceTaskFilter, limit int, offset int) ([]entity.MaintenanceTask, int64, error) {
+	return s.taskRepo.FindAllFiltered(ctx, filter, limit, offset)
+}
+
+func (s *MaintenanceTaskService) StartTask(ctx context.Context, id string, staffID uuid.UUID, req entity.StartMaintenanceTaskRequest) (*entity.MaintenanceTask, error) {
+	task, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch task.Status {
+	case entity.MaintenanceStatusCompleted:
+		return nil, entity.ErrMaintenanceTaskClosed
+	case entity.MaintenanceStatusInProgress:
+		return nil, errors.New("tugas perawatan sudah dikerjakan")
+	}
+
+	now := time.Now()
+	task.Status = entity.MaintenanceStatusInProgress
+	task.StartedAt = &now
+	task.StartedBy = &staffID
+	if req.Notes != "" {
+		task.Notes = req.Notes
+	}
+
+	if err := s.taskRepo.StartTask(ctx, &task); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (s *MaintenanceTaskService) CompleteTask(ctx context.Context, id string, staffID uuid.UUID, req entity.CompleteMaintenanceTaskRequest) (*entity.MaintenanceTask, error) {
+	task, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Status == entity.MaintenanceStatusCompleted {
+		return nil, entity.ErrMaintenanceTaskClosed
+	}
+
+	var followUp *entity.MaintenanceTask
+	condition := req.Condition
+
+	switch req.Outcome {
+	case entity.MaintenanceOutcomeReleased:
+		if _, ok := entity.ConditionRank[condition]; !ok {
+			return nil, errors.New("kondisi unit setelah perawatan wajib diisi: new, excellent, good, fair, atau poor")
+		}
+	case entity.MaintenanceOutcomeRepair:
+		if task.Type == entity.MaintenanceTypeRepair {
+			return nil, errors.New("tugas perbaikan tidak dapat dialihkan ke perbaikan lagi")
+		}
+		condition = entity.ConditionDamaged
+		followUp = &entity.MaintenanceTask{
+			ToyUnitID:    task.ToyUnitID,
+			ToyID:        task.ToyID,
+			RentalItemID: task.RentalItemID,
+			Type:         entity.MaintenanceTypeRepair,
+			Status:       entity.MaintenanceStatusPending,
+			DueAt:        s.turnaround.DueAt(entity.MaintenanceTypeRepair, time.Now()),
+			Notes:        req.Notes,
+		}
+	case entity.MaintenanceOutcomeWrittenOff:
+		condition = ""
+	default:
+		return nil, errors.New("hasil perawatan harus salah satu dari: released, repair, atau written_off")
+	}
+
+	now := time.Now()
+	if task.StartedAt == nil {
+		task.StartedAt = &now
+		task.StartedBy = &staffID
+	}
+	task.Status = entity.MaintenanceStatusCompleted
+	task.Outcome = req.Outcome
+	task.CompletedAt = &now
+	task.CompletedBy = &staffID
+	if req.Notes != "" {
+		task.Notes = req.Notes
+	}
+
+	if err := s.taskRepo.CompleteTask(ctx, &task, condition, followUp); err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
@@ -6,16 +6,19 @@ import (
 	"final-project/config"
 	"final-project/entity"
 	"final-project/utils/helpers"
-	"fmt"
+	"final-project/utils/money"
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/midtrans/midtrans-go/snap"
+	"net/http"
 	"time"
 )
 
 type IMidtransService interface {
 	CreateTransaction(ctx context.Context, payment *entity.Payment, rental *entity.Rental) (*entity.Payment, error)
 	VerifyPayment(ctx context.Context, notificationPayload map[string]interface{}) (*coreapi.TransactionStatusResponse, error)
+	RefundTransaction(ctx context.Context, orderID string, amount money.Money, reason string) error
+	ExpireTransaction(ctx context.Context, orderID string) error
 }
 
 type MidtransService struct {
@@ -55,45 +58,27 @@ func NewMidtransService(cfg *config.Config) IMidtransService {
 
 func (s *MidtransService) CreateTransaction(ctx context.Context, payment *entity.Payment, rental *entity.Rental) (*entity.Payment, error) {
 	var logger = helpers.Logger
-	var items []midtrans.ItemDetails
-
-	totalDayRent := rental.ExpectedReturnDate.Sub(rental.RentalDate).Hours() / 24
-
-	if rental.PaymentStatus != entity.PaymentStatusPaid {
-		for _, item := range rental.RentalItems {
-			toyName := fmt.Sprintf("Item %s", item.ToyID.String())
-			if item.Toy.Name != "" {
-				toyName = item.Toy.Name
-			}
-
-			itemDetail := midtrans.ItemDetails{
-				ID:    item.ToyID.String(),
-				Name:  toyName,
-				Price: int64(item.PricePerUnit) * int64(totalDayRent),
-				Qty:   int32(item.Quantity),
-			}
-			items = append(items, itemDetail)
-		}
-	}
 
-	if rental.LateFee > 0 {
-		lateFeeItem := midtrans.ItemDetails{
-			ID:    "late_fee",
-			Name:  "Biaya Keterlambatan",
-			Price: int64(rental.LateFee),
-			Qty:   1,
-		}
-		items = append(items, lateFeeItem)
+	// Rincian item dikirim 1:1 dari PaymentItem sehingga jumlahnya selalu sama dengan GrossAmount.
+	// Bila tetap berbeda transaksi ditolak di sini, bukan oleh Snap.
+	if len(payment.Items) > 0 && payment.ItemsTotal() != payment.GrossAmount {
+		logger.Error("Payment items total ", payment.ItemsTotal(), " does not match gross amount ", payment.GrossAmount)
+		return nil, entity.ErrPaymentItemsMismatch
 	}
 
-	if rental.DamageFee > 0 {
-		damageFeeItem := midtrans.ItemDetails{
-			ID:    "damage_fee",
-			Name:  "Biaya Kerusakan",
-			Price: int64(rental.DamageFee),
-			Qty:   1,
+	items := make([]midtrans.ItemDetails, 0, len(payment.Items))
+	for _, item := range payment.Items {
+		id := item.Kind
+		if item.ToyID != nil {
+			id = item.Kind + "-" + item.ToyID.String()[:8]
 		}
-		items = append(items, damageFeeItem)
+
+		items = append(items, midtrans.ItemDetails{
+			ID:    id,
+			Name:  item.ShortDescription(),
+			Price: item.UnitPrice.Int64(),
+			Qty:   int32(item.Quantity),
+		})
 	}
 
 	customerDetails := &midtrans.CustomerDetails{
@@ -114,7 +99,7 @@ func (s *MidtransService) CreateTransaction(ctx context.Context, payment *entity
 	snapReq := &snap.Request{
 		TransactionDetails: midtrans.TransactionDetails{
 			OrderID:  uniqueOrderID,
-			GrossAmt: int64(payment.GrossAmount),
+			GrossAmt: payment.GrossAmount.Int64(),
 		},
 		CustomerDetail: customerDetails,
 		Items:          &items,
@@ -170,3 +155,75 @@ func (s *MidtransService) VerifyPayment(ctx context.Context, notificationPayload
 
 	return txStatus, nil
 }
+
+// midtransTimeLayout adalah format waktu pada status transaksi Midtrans yang selalu dalam WIB
+const midtransTimeLayout = "2006-01-02 15:04:05"
+
+var midtransLocation = time.FixedZone("WIB", 7*60*60)
+
+// applyTransactionStatus menyalin status, waktu, metode dan nomor VA transaksi Midtrans ke payment
+func applyTransactionStatus(payment *entity.Payment, txStatus *coreapi.TransactionStatusResponse) {
+	payment.TransactionStatus = txStatus.TransactionStatus
+
+	if txStatus.TransactionTime != "" {
+		transactionTime, err := time.ParseInLocation(midtransTimeLayout, txStatus.TransactionTime, midtransLocation)
+		if err != nil {
+			helpers.Logger.Warn("Format waktu transaksi tidak dikenali: ", txStatus.TransactionTime)
+		} else {
+			payment.TransactionTime = &transactionTime
+		}
+	}
+
+	if txStatus.PaymentType != "" {
+		payment.PaymentMethod = txStatus.PaymentType
+	}
+
+	switch {
+	case len(txStatus.VaNumbers) > 0:
+		payment.VANumber = txStatus.VaNumbers[0].VANumber
+	case txStatus.PermataVaNumber != "":
+		payment.VANumber = txStatus.PermataVaNumber
+	}
+
+	if txStatus.FraudStatus != "" {
+		payment.FraudStatus = txStatus.FraudStatus
+	}
+}
+
+func (s *MidtransService) RefundTransaction(ctx context.Context, orderID string, amount money.Money, reason string) error {
+	var logger = helpers.Logger
+
+	// Refund key dibuat tetap per order agar permintaan ulang tidak mengembalikan dana dua kali
+	refundReq := &coreapi.RefundReq{
+		RefundKey: orderID + "-deposit",
+		Amount:    amount.Int64(),
+		Reason:    reason,
+	}
+
+	refundResp, err := s.coreAPIClient.RefundTransaction(orderID, refundReq)
+	if err != nil {
+		logger.Error("Error refunding transaction: ", err)
+		return errors.New("gagal mengembalikan dana: " + err.Error())
+	}
+
+	logger.Info("Refund berhasil: OrderID=", refundResp.OrderID, ", Status=", refundResp.TransactionStatus)
+	return nil
+}
+
+// ExpireTransaction menutup transaksi yang masih menunggu pembayaran. Transaksi Snap yang belum pernah
+// dibuka pelanggan belum tercatat di Midtrans sehingga tidak ada yang perlu ditutup.
+func (s *MidtransService) ExpireTransaction(ctx context.Context, orderID string) error {
+	var logger = helpers.Logger
+
+	expireResp, err := s.coreAPIClient.ExpireTransaction(orderID)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		logger.Error("Error expiring transaction: ", err)
+		return errors.New("gagal menutup transaksi: " + err.Error())
+	}
+
+	logger.Info("Transaksi ditutup: OrderID=", expireResp.OrderID, ", Status=", expireResp.TransactionStatus)
+	return nil
+}
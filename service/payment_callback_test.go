@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"final-project/entity"
+	"final-project/repository"
+	"final-project/utils/money"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/midtrans/midtrans-go/coreapi"
+)
+
+// fakeMidtrans mengembalikan status transaksi yang sudah ditentukan tanpa memanggil Midtrans
+type fakeMidtrans struct {
+	status *coreapi.TransactionStatusResponse
+}
+
+func (f *fakeMidtrans) CreateTransaction(ctx context.Context, payment *entity.Payment, rental *entity.Rental) (*entity.Payment, error) {
+	payment.OrderID = "RENTAL-" + uuid.Must(uuid.NewV4()).String()[:8]
+	return payment, nil
+}
+
+func (f *fakeMidtrans) VerifyPayment(ctx context.Context, notificationPayload map[string]interface{}) (*coreapi.TransactionStatusResponse, error) {
+	return f.status, nil
+}
+
+func (f *fakeMidtrans) RefundTransaction(ctx context.Context, orderID string, amount money.Money, reason string) error {
+	return nil
+}
+
+func (f *fakeMidtrans) ExpireTransaction(ctx context.Context, orderID string) error {
+	return nil
+}
+
+func TestApplyTransactionStatus(t *testing.T) {
+	payment := &entity.Payment{TransactionStatus: entity.TransactionStatusPending}
+	applyTransactionStatus(payment, &coreapi.TransactionStatusResponse{
+		TransactionStatus: entity.TransactionStatusSettlement,
+		TransactionTime:   "2026-03-10 06:30:00",
+		PaymentType:       "bank_transfer",
+		VaNumbers:         []coreapi.VANumber{{Bank: "bca", VANumber: "12345678901"}},
+		FraudStatus:       "accept",
+	})
+
+	if payment.TransactionStatus != entity.TransactionStatusSettlement {
+		t.Errorf("status %q, ingin %q", payment.TransactionStatus, entity.TransactionStatusSettlement)
+	}
+	// 06.30 WIB masih tanggal 9 Maret dalam UTC
+	if want := time.Date(2026, 3, 9, 23, 30, 0, 0, time.UTC); payment.TransactionTime == nil || !payment.TransactionTime.Equal(want) {
+		t.Errorf("waktu transaksi %v, ingin %v", payment.TransactionTime, want)
+	}
+	if payment.PaymentMethod != "bank_transfer" || payment.VANumber != "12345678901" || payment.FraudStatus != "accept" {
+		t.Errorf("metode %q, VA %q, fraud %q", payment.PaymentMethod, payment.VANumber, payment.FraudStatus)
+	}
+
+	// Waktu yang tidak bisa dibaca tidak menghapus waktu yang sudah tersimpan
+	applyTransactionStatus(payment, &coreapi.TransactionStatusResponse{TransactionStatus: entity.TransactionStatusSettlement, TransactionTime: "10/03/2026"})
+	if payment.TransactionTime == nil {
+		t.Error("waktu transaksi terhapus oleh format yang tidak dikenali")
+	}
+}
+
+func TestProcessPaymentCallbackCashSalesReport(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	user := entity.User{
+		Email:    "kas-" + uuid.Must(uuid.NewV4()).String()[:8] + "@example.com",
+		Username: "kas-" + uuid.Must(uuid.NewV4()).String()[:8],
+		Password: "rahasia",
+		FullName: "Pelanggan Kas",
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	rental := entity.Rental{
+		UserID:             user.ID,
+		Status:             entity.RentalStatusPending,
+		RentalDate:         time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC),
+		ExpectedReturnDate: time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC),
+		TotalRentalPrice:   111000,
+		PaymentStatus:      entity.PaymentStatusPending,
+		TaxRate:            11,
+		TaxInclusive:       true,
+	}
+	if err := db.Create(&rental).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Pembayaran gateway tersimpan tanpa waktu dan metode sampai notifikasi Midtrans diterima
+	payment := entity.Payment{
+		RentalID:          rental.ID,
+		OrderID:           "RENTAL-" + rental.ID.String()[:8],
+		PaymentType:       entity.PaymentTypeRental,
+		TransactionStatus: entity.TransactionStatusPending,
+	}
+	payment.SetItems([]entity.PriceLine{
+		{Kind: entity.PriceLineRental, Description: "Mainan A - 3 hari", Quantity: 1, UnitPrice: 100000, Amount: 100000},
+		{Kind: entity.PriceLineTax, Description: "PPN 11%", Quantity: 1, UnitPrice: 11000, Amount: 11000},
+	})
+	if err := db.Create(&payment).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	midtrans := &fakeMidtrans{status: &coreapi.TransactionStatusResponse{
+		OrderID:           payment.OrderID,
+		TransactionStatus: entity.TransactionStatusSettlement,
+		TransactionTime:   "2026-03-10 10:15:00",
+		PaymentType:       "bank_transfer",
+		VaNumbers:         []coreapi.VANumber{{Bank: "bca", VANumber: "12345678901"}},
+	}}
+	paymentSvc := NewPaymentService(
+		repository.NewPaymentRepository(db),
+		repository.NewRentalRepository(db),
+		repository.NewWalletRepository(db),
+		repository.NewJournalRepository(db),
+		midtrans,
+		NewReportRollupService(repository.NewReportRollupRepository(db, time.UTC), nil, 0, time.UTC),
+	)
+
+	notification := map[string]interface{}{"order_id": payment.OrderID, "transaction_status": entity.TransactionStatusSettlement}
+	if err := paymentSvc.ProcessPaymentCallback(ctx, notification); err != nil {
+		t.Fatalf("ProcessPaymentCallback: %v", err)
+	}
+
+	items, err := repository.NewBusinessReportRepository(db, time.UTC).GetCashSalesReport(ctx,
+		time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 23, 59, 59, 0, time.UTC), "day", "")
+	if err != nil {
+		t.Fatalf("GetCashSalesReport: %v", err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("laporan berisi %d baris, ingin 1: %+v", len(items), items)
+	}
+	item := items[0]
+	if item.Date != "2026-03-10" || item.PaymentMethod != "bank_transfer" || item.TransactionCount != 1 {
+		t.Errorf("tanggal %q, metode %q, jumlah transaksi %d", item.Date, item.PaymentMethod, item.TransactionCount)
+	}
+	if item.ReceivedAmount != 111000 || item.TaxAmount != 11000 || item.NetRevenue != 100000 {
+		t.Errorf("kas masuk %d, PPN %d, pendapatan bersih %d", item.ReceivedAmount, item.TaxAmount, item.NetRevenue)
+	}
+}
@@ -6,39 +6,58 @@ import (
 	"final-project/entity"
 	"final-project/repository"
 	"final-project/utils/helpers"
+	"final-project/utils/money"
+	"fmt"
 	"gorm.io/gorm"
+	"time"
 )
 
 type IPaymentService interface {
 	IBaseService[entity.Payment]
-	CreatePaymentForRental(ctx context.Context, rentalID string) (*entity.Payment, error)
+	CreatePaymentForRental(ctx context.Context, rentalID string, useWallet bool) (*entity.Payment, error)
 	CreatePaymentForExtension(ctx context.Context, rentalID string, metadata *entity.ExtensionMetadata) (*entity.Payment, error)
 	ProcessPaymentCallback(ctx context.Context, notification map[string]interface{}) error
 	GetPaymentByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error)
 	FindByRentalID(ctx context.Context, rentalID string) ([]entity.Payment, error)
+	GetRentalBalance(ctx context.Context, rental entity.Rental) (*entity.RentalBalance, error)
+	RefundDeposit(ctx context.Context, rental entity.Rental, amount money.Money) error
+	GetTaxInvoice(ctx context.Context, paymentID string) (*entity.TaxInvoice, error)
 }
 
 type PaymentService struct {
 	BaseService[entity.Payment]
 	paymentRepo     repository.IPaymentRepository
 	rentalRepo      repository.IRentalRepository
+	walletRepo      repository.IWalletRepository
+	journalRepo     repository.IJournalRepository
 	midtransService IMidtransService
+	rollupSvc       IReportRollupService
 }
 
 func NewPaymentService(
 	paymentRepo repository.IPaymentRepository,
 	rentalRepo repository.IRentalRepository,
+	walletRepo repository.IWalletRepository,
+	journalRepo repository.IJournalRepository,
 	midtransService IMidtransService,
+	rollupSvc IReportRollupService,
 ) IPaymentService {
 	return &PaymentService{
 		BaseService:     BaseService[entity.Payment]{repository: paymentRepo},
 		paymentRepo:     paymentRepo,
 		rentalRepo:      rentalRepo,
+		walletRepo:      walletRepo,
+		journalRepo:     journalRepo,
 		midtransService: midtransService,
+		rollupSvc:       rollupSvc,
 	}
 }
 
-func (s *PaymentService) CreatePaymentForRental(ctx context.Context, rentalID string) (*entity.Payment, error) {
+// CreatePaymentForRental menagih sisa tagihan rental. Bila useWallet diisi, saldo dompet dipakai
+// lebih dulu dan hanya kekurangannya yang diteruskan ke payment gateway. Pembayaran gateway yang masih
+// menunggu dikembalikan tanpa membuat transaksi baru selama nominalnya sama dengan tagihan saat ini,
+// sedangkan yang nominalnya sudah tidak sesuai, misalnya sebelum ada denda, ditutup lebih dulu.
+func (s *PaymentService) CreatePaymentForRental(ctx context.Context, rentalID string, useWallet bool) (*entity.Payment, error) {
 	var logger = helpers.Logger
 
 	rental, err := s.rentalRepo.FindById(ctx, rentalID)
@@ -49,33 +68,75 @@ func (s *PaymentService) CreatePaymentForRental(ctx context.Context, rentalID st
 		return nil, err
 	}
 
-	if rental.PaymentStatus == entity.PaymentStatusPaid {
-		return nil, errors.New("rental sudah dibayar")
+	balance, err := s.GetRentalBalance(ctx, rental)
+	if err != nil {
+		return nil, err
 	}
 
-	totalAmount := rental.TotalRentalPrice
-	if rental.LateFee > 0 {
-		totalAmount += rental.LateFee
+	// Hanya sisa tagihan yang ditagihkan sehingga denda setelah pengembalian bisa dibayar terpisah
+	lines := rentalPaymentLines(rental, *balance)
+
+	// Transaksi Snap kedua atau potongan dompet berulang akan menagih tagihan yang sama dua kali
+	payments, err := s.paymentRepo.FindByRentalID(ctx, rental.ID.String())
+	if err != nil {
+		return nil, err
 	}
-	if rental.DamageFee > 0 {
-		totalAmount += rental.DamageFee
+	for i := range payments {
+		pending := &payments[i]
+		if pending.TransactionStatus != entity.TransactionStatusPending || pending.PaymentType == entity.PaymentTypeExtension {
+			continue
+		}
+		if len(lines) > 0 && pending.GrossAmount == entity.SumPriceLines(lines) {
+			return pending, nil
+		}
+		if err := s.expirePayment(ctx, pending); err != nil {
+			return nil, err
+		}
 	}
 
-	paymentType := entity.PaymentTypeRental
-	if rental.LateFee > 0 && rental.DamageFee > 0 {
-		paymentType = entity.PaymentTypeCombined
-	} else if rental.LateFee > 0 {
-		paymentType = entity.PaymentTypeLateFee
-	} else if rental.DamageFee > 0 {
-		paymentType = entity.PaymentTypeDamageFee
+	if len(lines) == 0 {
+		return nil, entity.ErrNothingToSettle
+	}
+
+	if useWallet {
+		walletPayment := &entity.Payment{
+			RentalID:          rental.ID,
+			OrderID:           "WALLET-" + rental.ID.String()[:8] + "-" + time.Now().Format("060102150405"),
+			PaymentType:       balance.PaymentType(),
+			TransactionStatus: entity.TransactionStatusSettlement,
+			PaymentMethod:     entity.PaymentMethodWallet,
+		}
+
+		walletAmount, err := s.paymentRepo.PayFromWallet(ctx, walletPayment, rental.UserID, lines)
+		if err != nil {
+			return nil, err
+		}
+
+		if walletAmount > 0 {
+			if err := s.syncRentalAfterPayment(ctx, walletPayment, walletPayment.TransactionStatus); err != nil {
+				return nil, err
+			}
+
+			if walletAmount >= entity.SumPriceLines(lines) {
+				return walletPayment, nil
+			}
+
+			lines = append(lines, entity.PriceLine{
+				Kind:        entity.PriceLineWallet,
+				Description: "Dibayar dengan saldo dompet",
+				Quantity:    1,
+				UnitPrice:   -walletAmount,
+				Amount:      -walletAmount,
+			})
+		}
 	}
 
 	payment := &entity.Payment{
 		RentalID:          rental.ID,
-		PaymentType:       paymentType,
-		GrossAmount:       totalAmount,
+		PaymentType:       balance.PaymentType(),
 		TransactionStatus: entity.TransactionStatusPending,
 	}
+	payment.SetItems(lines)
 
 	payment, err = s.midtransService.CreateTransaction(ctx, payment, &rental)
 	if err != nil {
@@ -94,6 +155,17 @@ func (s *PaymentService) CreatePaymentForRental(ctx context.Context, rentalID st
 	return payment, nil
 }
 
+// expirePayment menutup pembayaran gateway yang nominalnya sudah tidak sesuai tagihan. Bila pelanggan
+// tetap membayarnya, kelebihan bayarnya dikembalikan ke dompet oleh syncRentalAfterPayment.
+func (s *PaymentService) expirePayment(ctx context.Context, payment *entity.Payment) error {
+	if err := s.midtransService.ExpireTransaction(ctx, payment.OrderID); err != nil {
+		return err
+	}
+
+	payment.TransactionStatus = entity.TransactionStatusExpire
+	return s.paymentRepo.UpdateByID(ctx, payment.ID.String(), payment)
+}
+
 func (s *PaymentService) CreatePaymentForExtension(ctx context.Context, rentalID string, metadata *entity.ExtensionMetadata) (*entity.Payment, error) {
 	rental, err := s.rentalRepo.FindById(ctx, rentalID)
 	if err != nil {
@@ -110,10 +182,25 @@ func (s *PaymentService) CreatePaymentForExtension(ctx context.Context, rentalID
 	payment := &entity.Payment{
 		RentalID:          rental.ID,
 		PaymentType:       entity.PaymentTypeExtension,
-		GrossAmount:       metadata.AdditionalCost,
 		TransactionStatus: entity.TransactionStatusPending,
 	}
 
+	lines := metadata.Lines
+	if len(lines) == 0 {
+		amount := metadata.AdditionalCost
+		lines = []entity.PriceLine{{
+			Kind:        entity.PaymentTypeExtension,
+			Description: fmt.Sprintf("Perpanjangan %d hari", metadata.AdditionalDays),
+			Quantity:    1,
+			UnitPrice:   amount,
+			Amount:      amount,
+		}}
+	}
+
+	// Perpanjangan menambah biaya rental sehingga dikenai PPN dengan tarif rentalnya
+	gross, tax := rental.TaxPolicy().Apply(entity.SumPriceLines(lines))
+	payment.SetItems(rental.TaxPolicy().SplitTaxLines(lines, gross, tax))
+
 	err = payment.SetExtensionMetadata(metadata)
 	if err != nil {
 		return nil, errors.New("gagal menyimpan metadata perpanjangan: " + err.Error())
@@ -142,7 +229,8 @@ func (s *PaymentService) ProcessPaymentCallback(ctx context.Context, notificatio
 		return err
 	}
 
-	payment.TransactionStatus = txStatus.TransactionStatus
+	// Waktu dan metode transaksi dipakai laporan penjualan berbasis kas
+	applyTransactionStatus(payment, txStatus)
 
 	if err := s.paymentRepo.UpdateByID(ctx, payment.ID.String(), payment); err != nil {
 		return err
@@ -156,7 +244,10 @@ func (s *PaymentService) ProcessPaymentCallback(ctx context.Context, notificatio
 
 		switch txStatus.TransactionStatus {
 		case "capture", "settlement":
-			return nil
+			if err := s.issueTaxInvoice(ctx, payment); err != nil {
+				return err
+			}
+			return s.journalRepo.Post(ctx, entity.NewPaymentJournal(*payment))
 		case "pending":
 			return nil
 		case "deny", "cancel", "expire", "failure":
@@ -165,37 +256,111 @@ func (s *PaymentService) ProcessPaymentCallback(ctx context.Context, notificatio
 			if err != nil {
 				return errors.New("gagal membatalkan perpanjangan: " + err.Error())
 			}
+			s.rollupSvc.RefreshRental(ctx, payment.RentalID.String())
 			return nil
 		}
 	} else {
-		var rentalPaymentStatus string
-		var rentalStatus string
+		return s.syncRentalAfterPayment(ctx, payment, txStatus.TransactionStatus)
+	}
 
-		switch txStatus.TransactionStatus {
-		case "capture", "settlement":
+	return nil
+}
+
+// syncRentalAfterPayment menyesuaikan status rental, deposit dan kelebihan bayar setelah status pembayaran berubah
+func (s *PaymentService) syncRentalAfterPayment(ctx context.Context, payment *entity.Payment, transactionStatus string) error {
+	rental, err := s.rentalRepo.FindById(ctx, payment.RentalID.String())
+	if err != nil {
+		return err
+	}
+
+	// Status rental ikut berubah, rollup laporan diperbarui walaupun langkah berikutnya gagal
+	defer s.rollupSvc.RefreshRental(ctx, rental.ID.String())
+
+	balance, err := s.GetRentalBalance(ctx, rental)
+	if err != nil {
+		return err
+	}
+
+	settled := transactionStatus == entity.TransactionStatusCapture || transactionStatus == entity.TransactionStatusSettlement
+
+	switch {
+	case settled:
+		if err := s.issueTaxInvoice(ctx, payment); err != nil {
+			return err
+		}
+		if err := s.journalRepo.Post(ctx, entity.NewPaymentJournal(*payment)); err != nil {
+			return err
+		}
+	case transactionStatus == entity.TransactionStatusRefund:
+		if err := s.journalRepo.Post(ctx, entity.NewPaymentRefundJournal(*payment, rental)); err != nil {
+			return err
+		}
+	}
+
+	// Kelebihan bayar, misalnya dua pembayaran lunas bersamaan, dikembalikan ke dompet pengguna
+	if settled && balance.Overpaid > 0 {
+		if err := s.walletRepo.Post(ctx, &entity.WalletEntry{
+			UserID:    rental.UserID,
+			Type:      entity.WalletEntryCredit,
+			Amount:    balance.Overpaid,
+			Reason:    entity.WalletReasonOverpayment,
+			RentalID:  &rental.ID,
+			PaymentID: &payment.ID,
+			Notes:     "Kelebihan pembayaran rental",
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Rental yang sudah dikembalikan hanya selesai setelah seluruh tagihannya lunas
+	returned := rental.ActualReturnDate != nil
+	var rentalPaymentStatus string
+	var rentalStatus string
+
+	switch transactionStatus {
+	case "capture", "settlement":
+		rentalPaymentStatus = entity.PaymentStatusPartiallyPaid
+		if balance.Outstanding <= 0 {
 			rentalPaymentStatus = entity.PaymentStatusPaid
+		}
+		if !returned {
 			rentalStatus = entity.RentalStatusActive
-		case "pending":
-			rentalPaymentStatus = entity.PaymentStatusPending
+		} else if balance.Outstanding <= 0 {
+			rentalStatus = entity.RentalStatusCompleted
+		}
+	case "pending":
+		rentalPaymentStatus = entity.PaymentStatusPending
+		if !returned {
 			rentalStatus = entity.RentalStatusPending
-		case "deny", "cancel", "expire", "failure":
-			rentalPaymentStatus = entity.PaymentStatusFailed
+		}
+	case "deny", "cancel", "expire", "failure":
+		rentalPaymentStatus = entity.PaymentStatusFailed
+		if balance.Paid > 0 {
+			rentalPaymentStatus = entity.PaymentStatusPartiallyPaid
+		}
+		if !returned {
 			rentalStatus = entity.RentalStatusPending
-		case "refund":
-			rentalPaymentStatus = entity.PaymentStatusRefunded
-			rentalStatus = ""
 		}
+	case "refund":
+		rentalPaymentStatus = entity.PaymentStatusRefunded
+		rentalStatus = ""
+	}
 
-		if rentalPaymentStatus != "" {
-			if err := s.rentalRepo.UpdatePaymentStatus(ctx, payment.RentalID.String(), rentalPaymentStatus); err != nil {
-				return err
-			}
+	if rentalPaymentStatus != "" {
+		if err := s.rentalRepo.UpdatePaymentStatus(ctx, payment.RentalID.String(), rentalPaymentStatus); err != nil {
+			return err
 		}
+	}
 
-		if rentalStatus != "" {
-			if err := s.rentalRepo.UpdateStatus(ctx, payment.RentalID.String(), rentalStatus); err != nil {
-				return err
-			}
+	if rentalStatus != "" {
+		if err := s.rentalRepo.UpdateStatus(ctx, payment.RentalID.String(), rentalStatus); err != nil {
+			return err
+		}
+	}
+
+	if settled && paymentHasLine(*payment, entity.PriceLineDeposit) {
+		if _, err := s.rentalRepo.UpdateDepositStatus(ctx, payment.RentalID.String(), entity.DepositStatusPending, entity.DepositStatusHeld); err != nil {
+			return err
 		}
 	}
 
@@ -216,3 +381,217 @@ func (s *PaymentService) GetPaymentByTransactionID(ctx context.Context, transact
 func (s *PaymentService) FindByRentalID(ctx context.Context, rentalID string) ([]entity.Payment, error) {
 	return s.paymentRepo.FindByRentalID(ctx, rentalID)
 }
+
+// GetRentalBalance menghitung tagihan rental yang sudah dan belum dibayar
+func (s *PaymentService) GetRentalBalance(ctx context.Context, rental entity.Rental) (*entity.RentalBalance, error) {
+	paid, err := s.paymentRepo.SumPaidByRentalID(ctx, rental.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	balance := entity.NewRentalBalance(rental, paid)
+	return &balance, nil
+}
+
+// RefundDeposit mengembalikan sisa deposit melalui pembayaran yang menagih deposit tersebut
+func (s *PaymentService) RefundDeposit(ctx context.Context, rental entity.Rental, amount money.Money) error {
+	if amount <= 0 {
+		return nil
+	}
+
+	payments, err := s.paymentRepo.FindByRentalID(ctx, rental.ID.String())
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		if payment.TransactionStatus != entity.TransactionStatusSettlement && payment.TransactionStatus != entity.TransactionStatusCapture {
+			continue
+		}
+
+		if !paymentHasLine(payment, entity.PriceLineDeposit) {
+			continue
+		}
+
+		// Deposit yang dibayar dengan saldo dompet dikembalikan ke dompet
+		if payment.PaymentMethod == entity.PaymentMethodWallet {
+			return s.walletRepo.Post(ctx, &entity.WalletEntry{
+				UserID:   rental.UserID,
+				Type:     entity.WalletEntryCredit,
+				Amount:   amount,
+				Reason:   entity.WalletReasonDepositRefund,
+				RentalID: &rental.ID,
+				Notes:    "Pengembalian deposit rental",
+			})
+		}
+		if err := s.midtransService.RefundTransaction(ctx, payment.OrderID, amount, "Pengembalian deposit rental"); err != nil {
+			return err
+		}
+		return s.journalRepo.Post(ctx, entity.NewDepositRefundJournal(rental, amount))
+	}
+
+	return errors.New("pembayaran deposit untuk rental ini tidak ditemukan")
+}
+
+// issueTaxInvoice memberi nomor faktur pada pembayaran lunas yang menagih PPN
+func (s *PaymentService) issueTaxInvoice(ctx context.Context, payment *entity.Payment) error {
+	if payment.TaxInvoiceNumber != nil || !paymentHasLine(*payment, entity.PriceLineTax) {
+		return nil
+	}
+	return s.paymentRepo.AssignTaxInvoiceNumber(ctx, payment)
+}
+
+// GetTaxInvoice menyusun faktur pembayaran dengan DPP dan PPN terpisah. DPP tidak termasuk deposit dan potongan saldo dompet.
+func (s *PaymentService) GetTaxInvoice(ctx context.Context, paymentID string) (*entity.TaxInvoice, error) {
+	payment, err := s.paymentRepo.FindByIdWithRental(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.TaxInvoiceNumber == nil || payment.TaxInvoiceDate == nil {
+		return nil, entity.ErrTaxInvoiceNotFound
+	}
+
+	invoice := &entity.TaxInvoice{
+		Number:        *payment.TaxInvoiceNumber,
+		IssuedAt:      *payment.TaxInvoiceDate,
+		PaymentID:     payment.ID,
+		OrderID:       payment.OrderID,
+		RentalID:      payment.RentalID,
+		UserID:        payment.Rental.UserID,
+		CustomerName:  payment.Rental.User.FullName,
+		CustomerEmail: payment.Rental.User.Email,
+		PaymentMethod: payment.PaymentMethod,
+		TaxRate:       payment.Rental.TaxRate,
+		GrossAmount:   payment.GrossAmount,
+	}
+
+	for _, item := range payment.Items {
+		invoice.Lines = append(invoice.Lines, item.PriceLine)
+
+		switch item.Kind {
+		case entity.PriceLineTax:
+			invoice.TaxAmount += item.Amount
+		case entity.PriceLineDeposit, entity.PriceLineWallet:
+		default:
+			invoice.NetAmount += item.Amount
+		}
+	}
+
+	return invoice, nil
+}
+
+func paymentHasLine(payment entity.Payment, kind string) bool {
+	for _, item := range payment.Items {
+		if item.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// rentalPaymentLines menyusun rincian tagihan dari sisa tagihan rental. Rincian harga asli
+// dipakai selama komponennya belum dibayar sama sekali, selebihnya ditagihkan sebagai satu baris sisa.
+// PPN seluruh komponen ditagihkan dalam satu baris, deposit tidak dikenai PPN.
+func rentalPaymentLines(rental entity.Rental, balance entity.RentalBalance) []entity.PriceLine {
+	tax := rental.TaxPolicy()
+
+	var taxable []entity.PriceLine
+	var grossDue, taxDue money.Money
+	addComponent := func(fullLines []entity.PriceLine, gross, componentTax, due money.Money, kind, remainder string) {
+		if due <= 0 {
+			return
+		}
+
+		dueTax := entity.TaxPortion(due, gross, componentTax)
+		grossDue += due
+		taxDue += dueTax
+
+		if due == gross && len(fullLines) > 0 {
+			taxable = append(taxable, fullLines...)
+			return
+		}
+
+		amount := due
+		if !tax.Inclusive {
+			amount = due - dueTax
+		}
+		taxable = append(taxable, entity.PriceLine{
+			Kind:        kind,
+			Description: remainder,
+			Quantity:    1,
+			UnitPrice:   amount,
+			Amount:      amount,
+		})
+	}
+
+	rentalLines := rentalPriceLines(rental)
+	if entity.SumPriceLines(rentalLines) != rental.TotalRentalPrice {
+		rentalLines = nil
+	}
+	addComponent(rentalLines, balance.RentalCharge, balance.RentalTax, balance.RentalDue, entity.PriceLineRental, "Sisa biaya rental")
+
+	addComponent([]entity.PriceLine{{
+		Kind:        entity.PriceLineLateFee,
+		Description: "Biaya Keterlambatan",
+		Quantity:    1,
+		UnitPrice:   rental.LateFee,
+		Amount:      rental.LateFee,
+	}}, balance.LateFee, balance.LateFeeTax, balance.LateFeeDue, entity.PriceLineLateFee, "Sisa biaya keterlambatan")
+
+	addComponent([]entity.PriceLine{{
+		Kind:        entity.PriceLineDamageFee,
+		Description: "Biaya Kerusakan",
+		Quantity:    1,
+		UnitPrice:   rental.DamageFee,
+		Amount:      rental.DamageFee,
+	}}, balance.DamageFee, balance.DamageFeeTax, balance.DamageFeeDue, entity.PriceLineDamageFee, "Sisa biaya kerusakan")
+
+	lines := tax.SplitTaxLines(taxable, grossDue, taxDue)
+
+	if rental.DepositStatus == entity.DepositStatusPending && rental.DepositAmount > 0 && rental.ActualReturnDate == nil {
+		lines = append(lines, entity.PriceLine{
+			Kind:        entity.PriceLineDeposit,
+			Description: "Deposit (dikembalikan setelah rental selesai)",
+			Quantity:    1,
+			UnitPrice:   rental.DepositAmount,
+			Amount:      rental.DepositAmount,
+		})
+	}
+
+	return lines
+}
+
+// rentalPriceLines mengembalikan rincian harga rental yang disepakati saat booking
+func rentalPriceLines(rental entity.Rental) []entity.PriceLine {
+	var lines []entity.PriceLine
+
+	if len(rental.PriceLines) > 0 {
+		for _, priceLine := range rental.PriceLines {
+			lines = append(lines, priceLine.PriceLine)
+		}
+		return lines
+	}
+
+	// Rental sebelum rincian harga disimpan dihitung dari harga harian
+	rentalDays := max(countRentalDays(rental.RentalDate, rental.ExpectedReturnDate), 1)
+	for _, item := range rental.RentalItems {
+		toyName := fmt.Sprintf("Item %s", item.ToyID.String())
+		if item.Toy.Name != "" {
+			toyName = item.Toy.Name
+		}
+
+		toyID := item.ToyID
+		unitPrice := item.PricePerUnit.Mul(rentalDays)
+		lines = append(lines, entity.PriceLine{
+			ToyID:       &toyID,
+			Kind:        entity.PriceLineRental,
+			Description: toyName,
+			Quantity:    item.Quantity,
+			UnitPrice:   unitPrice,
+			Amount:      unitPrice.Mul(item.Quantity),
+		})
+	}
+
+	return lines
+}
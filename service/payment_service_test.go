@@ -0,0 +1,180 @@
+package service
+
+import (
+	"final-project/entity"
+	"final-project/utils/money"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// testRental membuat rental dengan rincian harga tersimpan, termasuk potongan promo bila discount diisi
+func testRental(tax entity.TaxPolicy, discount money.Money) entity.Rental {
+	toyA, toyB := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	lines := []entity.PriceLine{
+		{ToyID: &toyA, Kind: entity.PriceLineRental, Description: "Mainan A - 3 hari", Quantity: 3, UnitPrice: 33333, Amount: 99999},
+		{ToyID: &toyA, Kind: entity.PriceLineWeekend, Description: "Tambahan akhir pekan Mainan A - 1 hari", Quantity: 3, UnitPrice: 1667, Amount: 5001},
+		{ToyID: &toyB, Kind: entity.PriceLineRental, Description: "Mainan B - 3 hari", Quantity: 1, UnitPrice: 12345, Amount: 12345},
+	}
+	if discount > 0 {
+		lines = append(lines, entity.PriceLine{Kind: entity.PriceLinePromotion, Description: "Promo HEMAT", Quantity: 1, UnitPrice: -discount, Amount: -discount})
+	}
+
+	rental := entity.Rental{
+		RentalDate:         time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		ExpectedReturnDate: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC),
+		TotalRentalPrice:   entity.SumPriceLines(lines),
+		DiscountAmount:     discount,
+		TaxRate:            tax.Rate,
+		TaxInclusive:       tax.Inclusive,
+	}
+	rental.ID = uuid.Must(uuid.NewV4())
+	for _, line := range lines {
+		rental.PriceLines = append(rental.PriceLines, entity.RentalPriceLine{RentalID: rental.ID, PriceLine: line})
+	}
+	return rental
+}
+
+func TestRentalPaymentItemsMatchGrossAmount(t *testing.T) {
+	exclusive := entity.TaxPolicy{Rate: 11}
+	inclusive := entity.TaxPolicy{Rate: 11, Inclusive: true}
+	returnedAt := time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		rental func() entity.Rental
+		paid   money.Money
+		wallet money.Money
+	}{
+		{
+			name:   "rental tanpa PPN",
+			rental: func() entity.Rental { return testRental(entity.TaxPolicy{}, 0) },
+		},
+		{
+			name:   "rental PPN eksklusif",
+			rental: func() entity.Rental { return testRental(exclusive, 0) },
+		},
+		{
+			name:   "rental PPN inklusif",
+			rental: func() entity.Rental { return testRental(inclusive, 0) },
+		},
+		{
+			name:   "promo dengan PPN eksklusif",
+			rental: func() entity.Rental { return testRental(exclusive, 7777) },
+		},
+		{
+			name:   "promo dengan PPN inklusif",
+			rental: func() entity.Rental { return testRental(inclusive, 7777) },
+		},
+		{
+			name: "deposit dengan PPN eksklusif",
+			rental: func() entity.Rental {
+				rental := testRental(exclusive, 0)
+				rental.DepositAmount = 50000
+				rental.DepositStatus = entity.DepositStatusPending
+				return rental
+			},
+		},
+		{
+			name: "deposit dan promo dengan PPN inklusif",
+			rental: func() entity.Rental {
+				rental := testRental(inclusive, 7777)
+				rental.DepositAmount = 50000
+				rental.DepositStatus = entity.DepositStatusPending
+				return rental
+			},
+		},
+		{
+			name:   "sebagian saldo dompet dengan PPN eksklusif",
+			rental: func() entity.Rental { return testRental(exclusive, 0) },
+			wallet: 30001,
+		},
+		{
+			name: "sebagian saldo dompet dengan deposit dan PPN inklusif",
+			rental: func() entity.Rental {
+				rental := testRental(inclusive, 7777)
+				rental.DepositAmount = 50000
+				rental.DepositStatus = entity.DepositStatusPending
+				return rental
+			},
+			wallet: 45555,
+		},
+		{
+			name:   "sisa rental setelah sebagian dibayar",
+			rental: func() entity.Rental { return testRental(exclusive, 0) },
+			paid:   50001,
+		},
+		{
+			name: "denda setelah pengembalian dengan PPN eksklusif",
+			rental: func() entity.Rental {
+				rental := testRental(exclusive, 0)
+				rental.ActualReturnDate = &returnedAt
+				rental.LateFee = 33333
+				rental.DamageFee = 12345
+				rental.DepositAmount = 50000
+				rental.DepositStatus = entity.DepositStatusHeld
+				return rental
+			},
+			paid: 130370,
+		},
+		{
+			name: "sisa denda setelah potongan deposit dengan PPN inklusif",
+			rental: func() entity.Rental {
+				rental := testRental(inclusive, 7777)
+				rental.ActualReturnDate = &returnedAt
+				rental.LateFee = 33333
+				rental.DamageFee = 12345
+				rental.DepositApplied = 20000
+				return rental
+			},
+			paid:   109568,
+			wallet: 10001,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rental := tt.rental()
+			balance := entity.NewRentalBalance(rental, tt.paid)
+
+			lines := rentalPaymentLines(rental, balance)
+			if len(lines) == 0 {
+				t.Fatal("tidak ada rincian yang ditagihkan")
+			}
+
+			// Potongan saldo dompet ditambahkan seperti pada CreatePaymentForRental
+			if tt.wallet > 0 {
+				lines = append(lines, entity.PriceLine{
+					Kind:        entity.PriceLineWallet,
+					Description: "Dibayar dengan saldo dompet",
+					Quantity:    1,
+					UnitPrice:   -tt.wallet,
+					Amount:      -tt.wallet,
+				})
+			}
+
+			payment := &entity.Payment{}
+			payment.SetItems(lines)
+
+			if payment.ItemsTotal() != payment.GrossAmount {
+				t.Errorf("jumlah rincian %d, GrossAmount %d", payment.ItemsTotal(), payment.GrossAmount)
+			}
+
+			deposit := money.Money(0)
+			if paymentHasLine(*payment, entity.PriceLineDeposit) {
+				deposit = rental.DepositAmount
+			}
+			if want := balance.Outstanding + deposit - tt.wallet; payment.GrossAmount != want {
+				t.Errorf("GrossAmount %d, ingin sisa tagihan %d + deposit %d - dompet %d = %d",
+					payment.GrossAmount, balance.Outstanding, deposit, tt.wallet, want)
+			}
+
+			for _, item := range payment.Items {
+				if item.UnitPrice.Mul(item.Quantity) != item.Amount {
+					t.Errorf("rincian %q: %d x %d != %d", item.Description, item.UnitPrice, item.Quantity, item.Amount)
+				}
+			}
+		})
+	}
+}
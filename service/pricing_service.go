@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+	"final-project/utils/money"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IPricingService interface {
+	IBaseService[entity.PricingRule]
+	QuoteRental(ctx context.Context, req entity.QuoteRentalRequest) (*entity.PriceQuote, error)
+	QuoteExtension(ctx context.Context, rental entity.Rental, newExpectedReturnDate time.Time) (*entity.PriceQuote, error)
+	CreateRule(ctx context.Context, req entity.PricingRuleRequest) (*entity.PricingRule, error)
+	UpdateRule(ctx context.Context, id string, req entity.PricingRuleRequest) (*entity.PricingRule, error)
+	FindAllHolidays(ctx context.Context, limit int, offset int) ([]entity.Holiday, int64, error)
+	CreateHoliday(ctx context.Context, req entity.HolidayRequest) (*entity.Holiday, error)
+	DeleteHoliday(ctx context.Context, id string) error
+}
+
+type PricingService struct {
+	BaseService[entity.PricingRule]
+	ruleRepo    repository.IPricingRuleRepository
+	holidayRepo repository.IHolidayRepository
+	toyRepo     repository.IToyRepository
+}
+
+func NewPricingService(
+	ruleRepo repository.IPricingRuleRepository,
+	holidayRepo repository.IHolidayRepository,
+	toyRepo repository.IToyRepository,
+) IPricingService {
+	return &PricingService{
+		BaseService: BaseService[entity.PricingRule]{repository: ruleRepo},
+		ruleRepo:    ruleRepo,
+		holidayRepo: holidayRepo,
+		toyRepo:     toyRepo,
+	}
+}
+
+func (s *PricingService) QuoteRental(ctx context.Context, req entity.QuoteRentalRequest) (*entity.PriceQuote, error) {
+	rentalDays := countRentalDays(req.RentalDate, req.ExpectedReturnDate)
+	if rentalDays < 1 {
+		return nil, errors.New("tanggal pengembalian harus setelah tanggal rental")
+	}
+	if rentalDays > entity.MaxRentalDays {
+		return nil, entity.ErrAboveMaxRentalDays
+	}
+
+	if len(req.Items) == 0 {
+		return nil, errors.New("item rental wajib diisi")
+	}
+
+	pricing, err := s.loadPricing(ctx, req.RentalDate, req.ExpectedReturnDate)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &entity.PriceQuote{
+		RentalDate:         req.RentalDate,
+		ExpectedReturnDate: req.ExpectedReturnDate,
+		RentalDays:         rentalDays,
+	}
+
+	for _, item := range req.Items {
+		if item.Quantity < 1 {
+			return nil, errors.New("jumlah item minimal 1")
+		}
+
+		toy, err := s.toyRepo.FindById(ctx, item.ToyID.String())
+		if err != nil {
+			return nil, errors.New("mainan tidak ditemukan: " + item.ToyID.String())
+		}
+
+		if minDays := max(toy.MinRentalDays, 1); rentalDays < minDays {
+			return nil, fmt.Errorf("%w: %s minimal %d hari", entity.ErrBelowMinRentalDays, toy.Name, minDays)
+		}
+
+		quote.Lines = append(quote.Lines, pricing.quoteWindow(toy, item.Quantity, req.RentalDate, req.RentalDate, req.ExpectedReturnDate, "")...)
+		quote.Deposit += toy.DepositAmount.Mul(item.Quantity)
+	}
+
+	quote.Total = entity.SumPriceLines(quote.Lines)
+	return quote, nil
+}
+
+// QuoteExtension menghitung biaya tambahan perpanjangan. Tarif bertingkat dihitung dari awal rental
+// sehingga perpanjangan yang membuat durasi mencapai satu minggu/bulan ikut mendapat tarif tersebut.
+func (s *PricingService) QuoteExtension(ctx context.Context, rental entity.Rental, newExpectedReturnDate time.Time) (*entity.PriceQuote, error) {
+	additionalDays := countRentalDays(rental.ExpectedReturnDate, newExpectedReturnDate)
+	if additionalDays < 1 {
+		return nil, errors.New("perpanjangan minimal 1 hari")
+	}
+	if countRentalDays(rental.RentalDate, newExpectedReturnDate) > entity.MaxRentalDays {
+		return nil, entity.ErrAboveMaxRentalDays
+	}
+
+	pricing, err := s.loadPricing(ctx, rental.RentalDate, newExpectedReturnDate)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &entity.PriceQuote{
+		RentalDate:         rental.ExpectedReturnDate,
+		ExpectedReturnDate: newExpectedReturnDate,
+		RentalDays:         additionalDays,
+	}
+
+	for _, item := range rental.RentalItems {
+		toy, err := s.toyRepo.FindById(ctx, item.ToyID.String())
+		if err != nil {
+			return nil, errors.New("tidak dapat mendapatkan data mainan: " + item.ToyID.String())
+		}
+
+		quote.Lines = append(quote.Lines, pricing.quoteWindow(toy, item.Quantity, rental.RentalDate, rental.ExpectedReturnDate, newExpectedReturnDate, "Perpanjangan ")...)
+	}
+
+	quote.Total = entity.SumPriceLines(quote.Lines)
+	return quote, nil
+}
+
+func (s *PricingService) CreateRule(ctx context.Context, req entity.PricingRuleRequest) (*entity.PricingRule, error) {
+	rule := &entity.PricingRule{
+		Name:     req.Name,
+		Type:     req.Type,
+		Percent:  req.Percent,
+		MinDays:  req.MinDays,
+		IsActive: req.IsActive,
+	}
+
+	if errs := rule.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if err := s.repository.Insert(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *PricingService) UpdateRule(ctx context.Context, id string, req entity.PricingRuleRequest) (*entity.PricingRule, error) {
+	rule, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Name = req.Name
+	rule.Type = req.Type
+	rule.Percent = req.Percent
+	rule.MinDays = req.MinDays
+	rule.IsActive = req.IsActive
+
+	if errs := rule.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if err := s.repository.UpdateById(ctx, id, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (s *PricingService) FindAllHolidays(ctx context.Context, limit int, offset int) ([]entity.Holiday, int64, error) {
+	return s.holidayRepo.FindAll(ctx, limit, offset)
+}
+
+func (s *PricingService) CreateHoliday(ctx context.Context, req entity.HolidayRequest) (*entity.Holiday, error) {
+	date, err := time.Parse(entity.HolidayDateFormat, req.Date)
+	if err != nil {
+		return nil, errors.New("format tanggal libur harus YYYY-MM-DD")
+	}
+
+	if _, err := s.holidayRepo.FindByDate(ctx, date); err == nil {
+		return nil, entity.ErrHolidayExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	holiday := &entity.Holiday{
+		Date: date,
+		Name: strings.TrimSpace(req.Name),
+	}
+	if err := s.holidayRepo.Insert(ctx, holiday); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (s *PricingService) DeleteHoliday(ctx context.Context, id string) error {
+	if _, err := s.holidayRepo.FindById(ctx, id); err != nil {
+		return err
+	}
+	return s.holidayRepo.DeleteById(ctx, id)
+}
+
+// pricingContext berisi aturan dan hari libur yang berlaku untuk satu perhitungan harga
+type pricingContext struct {
+	weekendPercent float64
+	holidayPercent float64
+	discounts      []entity.PricingRule
+	holidays       map[string]bool
+}
+
+func (s *PricingService) loadPricing(ctx context.Context, from time.Time, to time.Time) (*pricingContext, error) {
+	rules, err := s.ruleRepo.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	holidays, err := s.holidayRepo.FindBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing := &pricingContext{holidays: make(map[string]bool, len(holidays))}
+	for _, holiday := range holidays {
+		pricing.holidays[holiday.Date.Format(entity.HolidayDateFormat)] = true
+	}
+
+	for _, rule := range rules {
+		switch rule.Type {
+		case entity.PricingRuleWeekendSurcharge:
+			pricing.weekendPercent = math.Max(pricing.weekendPercent, rule.Percent)
+		case entity.PricingRuleHolidaySurcharge:
+			pricing.holidayPercent = math.Max(pricing.holidayPercent, rule.Percent)
+		case entity.PricingRuleLongRentalDiscount:
+			pricing.discounts = append(pricing.discounts, rule)
+		}
+	}
+
+	return pricing, nil
+}
+
+// quoteWindow menghitung rincian harga satu item untuk hari ke-(windowStart-start) sampai (windowEnd-start)
+func (p *pricingContext) quoteWindow(toy entity.Toy, quantity int, start time.Time, windowStart time.Time, windowEnd time.Time, label string) []entity.PriceLine {
+	totalDays := countRentalDays(start, windowEnd)
+	previousDays := countRentalDays(start, windowStart)
+	windowDays := totalDays - previousDays
+	if windowDays < 1 {
+		return nil
+	}
+
+	totalPrice, breakdown := tierPrice(toy, totalDays)
+	previousPrice, _ := tierPrice(toy, previousDays)
+	basePrice := money.Max(totalPrice-previousPrice, 0)
+
+	var lines []entity.PriceLine
+	addLine := func(kind string, description string, unitPrice money.Money) {
+		if unitPrice == 0 {
+			return
+		}
+		lines = append(lines, entity.PriceLine{
+			ToyID:       &toy.ID,
+			Kind:        kind,
+			Description: description,
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			Amount:      unitPrice.Mul(quantity),
+		})
+	}
+
+	description := fmt.Sprintf("%s%s - %d hari", label, toy.Name, windowDays)
+	if label == "" {
+		description += " (" + breakdown + ")"
+	}
+	addLine(entity.PriceLineRental, description, basePrice)
+
+	// Biaya tambahan dihitung dari tarif harian efektif setelah tarif bertingkat
+	surcharge := func(percent float64, days int) money.Money {
+		return totalPrice.MulDiv(money.BasisPoints(percent)*int64(days), 10000*int64(totalDays))
+	}
+	weekendDays, holidayDays := 0, 0
+	for day := previousDays; day < totalDays; day++ {
+		date := start.AddDate(0, 0, day)
+		switch {
+		case p.holidays[date.Format(entity.HolidayDateFormat)]:
+			holidayDays++
+		case date.Weekday() == time.Saturday || date.Weekday() == time.Sunday:
+			weekendDays++
+		}
+	}
+
+	if p.weekendPercent > 0 && weekendDays > 0 {
+		addLine(entity.PriceLineWeekend, fmt.Sprintf("Tambahan akhir pekan %s - %d hari", toy.Name, weekendDays),
+			surcharge(p.weekendPercent, weekendDays))
+	}
+	if p.holidayPercent > 0 && holidayDays > 0 {
+		addLine(entity.PriceLineHoliday, fmt.Sprintf("Tambahan hari libur %s - %d hari", toy.Name, holidayDays),
+			surcharge(p.holidayPercent, holidayDays))
+	}
+
+	// Potongan rental panjang memakai aturan dengan minimal hari tertinggi yang terpenuhi
+	var discount *entity.PricingRule
+	for i := range p.discounts {
+		if totalDays >= p.discounts[i].MinDays && (discount == nil || p.discounts[i].MinDays > discount.MinDays) {
+			discount = &p.discounts[i]
+		}
+	}
+	if discount != nil {
+		addLine(entity.PriceLineDiscount, fmt.Sprintf("%s %s", discount.Name, toy.Name),
+			-basePrice.Percent(discount.Percent))
+	}
+
+	return lines
+}
+
+// tierPrice mencari kombinasi tarif harian, mingguan dan bulanan termurah untuk sejumlah hari. Paket
+// mingguan atau bulanan boleh melebihi sisa hari bila lebih murah daripada tarif harian.
+func tierPrice(toy entity.Toy, days int) (money.Money, string) {
+	if days <= 0 {
+		return 0, ""
+	}
+
+	// Sisa hari setelah paket bulanan cukup dicoba dengan tanpa minggu, minggu dibulatkan ke bawah
+	// atau ke atas, karena biaya di antaranya berubah linear terhadap jumlah minggu
+	weekCombination := func(rest int) (money.Money, int, int) {
+		bestCost, bestWeeks, bestDays := toy.RentalPrice.Mul(rest), 0, rest
+		if toy.WeeklyPrice <= 0 {
+			return bestCost, bestWeeks, bestDays
+		}
+		for _, weeks := range []int{rest / entity.DaysPerWeek, (rest + entity.DaysPerWeek - 1) / entity.DaysPerWeek} {
+			dailyDays := max(rest-weeks*entity.DaysPerWeek, 0)
+			cost := toy.WeeklyPrice.Mul(weeks) + toy.RentalPrice.Mul(dailyDays)
+			if cost < bestCost {
+				bestCost, bestWeeks, bestDays = cost, weeks, dailyDays
+			}
+		}
+		return bestCost, bestWeeks, bestDays
+	}
+
+	bestCost, months, weeks, dailyDays := money.Money(math.MaxInt64), 0, 0, 0
+	maxMonths := 0
+	if toy.MonthlyPrice > 0 {
+		maxMonths = (days + entity.DaysPerMonth - 1) / entity.DaysPerMonth
+	}
+	for m := 0; m <= maxMonths; m++ {
+		restCost, w, d := weekCombination(max(days-m*entity.DaysPerMonth, 0))
+		if cost := toy.MonthlyPrice.Mul(m) + restCost; cost < bestCost {
+			bestCost, months, weeks, dailyDays = cost, m, w, d
+		}
+	}
+
+	var parts []string
+	if months > 0 {
+		parts = append(parts, fmt.Sprintf("%d bulan", months))
+	}
+	if weeks > 0 {
+		parts = append(parts, fmt.Sprintf("%d minggu", weeks))
+	}
+	if dailyDays > 0 {
+		parts = append(parts, fmt.Sprintf("%d hari", dailyDays))
+	}
+
+	return bestCost, strings.Join(parts, " + ")
+}
+
+// countRentalDays menghitung jumlah hari sewa penuh di antara dua tanggal
+func countRentalDays(from time.Time, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+	"final-project/utils/money"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type IPromotionService interface {
+	IBaseService[entity.Promotion]
+	CreatePromotion(ctx context.Context, req entity.PromotionRequest) (*entity.Promotion, error)
+	UpdatePromotion(ctx context.Context, id string, req entity.PromotionRequest) (*entity.Promotion, error)
+	ApplyToQuote(ctx context.Context, quote *entity.PriceQuote, code string, userID uuid.UUID) error
+	FindRedemptions(ctx context.Context, id string, limit int, offset int) ([]entity.PromotionRedemption, int64, error)
+	GetUsageReport(ctx context.Context, startDate, endDate time.Time) ([]entity.PromotionUsageItem, error)
+}
+
+type PromotionService struct {
+	BaseService[entity.Promotion]
+	promotionRepo repository.IPromotionRepository
+	categoryRepo  repository.IToyCategoryRepository
+	toyRepo       repository.IToyRepository
+}
+
+func NewPromotionService(
+	repo repository.IPromotionRepository,
+	categoryRepo repository.IToyCategoryRepository,
+	toyRepo repository.IToyRepository,
+) IPromotionService {
+	return &PromotionService{
+		BaseService:   BaseService[entity.Promotion]{repository: repo},
+		promotionRepo: repo,
+		categoryRepo:  categoryRepo,
+		toyRepo:       toyRepo,
+	}
+}
+
+func (s *PromotionService) CreatePromotion(ctx context.Context, req entity.PromotionRequest) (*entity.Promotion, error) {
+	promotion := &entity.Promotion{}
+	if err := s.applyRequest(ctx, promotion, req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.promotionRepo.FindByCode(ctx, promotion.Code); err == nil {
+		return nil, entity.ErrPromotionCodeExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.repository.Insert(ctx, promotion); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repository.FindById(ctx, promotion.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (s *PromotionService) UpdatePromotion(ctx context.Context, id string, req entity.PromotionRequest) (*entity.Promotion, error) {
+	promotion, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRequest(ctx, &promotion, req); err != nil {
+		return nil, err
+	}
+
+	if existing, err := s.promotionRepo.FindByCode(ctx, promotion.Code); err == nil && existing.ID != promotion.ID {
+		return nil, entity.ErrPromotionCodeExists
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateById(ctx, id, &promotion); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ApplyToQuote memvalidasi kode promo lalu menambahkan baris potongan ke rincian harga.
+// Kuota di sini hanya pemeriksaan awal, kuota yang mengikat dicek ulang saat rental disimpan.
+func (s *PromotionService) ApplyToQuote(ctx context.Context, quote *entity.PriceQuote, code string, userID uuid.UUID) error {
+	code = normalizePromoCode(code)
+	if code == "" {
+		return nil
+	}
+
+	promotion, err := s.promotionRepo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.ErrPromotionNotFound
+		}
+		return err
+	}
+
+	if !promotion.IsRunning(time.Now()) {
+		return entity.ErrPromotionInactive
+	}
+
+	if promotion.UsageLimit > 0 {
+		used, err := s.promotionRepo.CountRedemptions(ctx, promotion.ID.String(), "")
+		if err != nil {
+			return err
+		}
+		if used >= int64(promotion.UsageLimit) {
+			return entity.ErrPromotionUsageExceeded
+		}
+	}
+
+	if promotion.PerUserLimit > 0 && !userID.IsNil() {
+		used, err := s.promotionRepo.CountRedemptions(ctx, promotion.ID.String(), userID.String())
+		if err != nil {
+			return err
+		}
+		if used >= int64(promotion.PerUserLimit) {
+			return entity.ErrPromotionUserLimit
+		}
+	}
+
+	if quote.Total < promotion.MinOrderAmount {
+		return fmt.Errorf("%w (minimal %s)", entity.ErrPromotionMinOrder, promotion.MinOrderAmount)
+	}
+
+	eligible, err := s.eligibleSubtotal(ctx, promotion, quote.Lines)
+	if err != nil {
+		return err
+	}
+
+	discount := promotion.Discount(eligible)
+	if discount <= 0 {
+		return entity.ErrPromotionNotApplicable
+	}
+
+	quote.Lines = append(quote.Lines, entity.PriceLine{
+		Kind:        entity.PriceLinePromotion,
+		Description: fmt.Sprintf("Promo %s - %s", promotion.Code, promotion.Name),
+		Quantity:    1,
+		UnitPrice:   -discount,
+		Amount:      -discount,
+	})
+	quote.Total = entity.SumPriceLines(quote.Lines)
+	quote.PromoCode = promotion.Code
+	quote.DiscountAmount = discount
+	quote.Promotion = &promotion
+
+	return nil
+}
+
+func (s *PromotionService) FindRedemptions(ctx context.Context, id string, limit int, offset int) ([]entity.PromotionRedemption, int64, error) {
+	if _, err := s.repository.FindById(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.promotionRepo.FindRedemptions(ctx, id, limit, offset)
+}
+
+func (s *PromotionService) GetUsageReport(ctx context.Context, startDate, endDate time.Time) ([]entity.PromotionUsageItem, error) {
+	return s.promotionRepo.GetUsageReport(ctx, startDate, endDate)
+}
+
+// eligibleSubtotal menjumlahkan baris harga milik mainan yang dicakup promo. Promo tanpa
+// cakupan kategori maupun mainan berlaku untuk seluruh pesanan.
+func (s *PromotionService) eligibleSubtotal(ctx context.Context, promotion entity.Promotion, lines []entity.PriceLine) (money.Money, error) {
+	if len(promotion.Categories) == 0 && len(promotion.Toys) == 0 {
+		return entity.SumPriceLines(lines), nil
+	}
+
+	toyScope := make(map[uuid.UUID]bool, len(promotion.Toys))
+	for _, toy := range promotion.Toys {
+		toyScope[toy.ID] = true
+	}
+
+	categoryScope := make(map[string]bool)
+	for _, category := range promotion.Categories {
+		ids, err := s.categoryRepo.FindDescendantIDs(ctx, category.ID.String())
+		if err != nil {
+			return 0, err
+		}
+		for _, id := range ids {
+			categoryScope[id] = true
+		}
+	}
+
+	inScope := make(map[uuid.UUID]bool)
+	var eligible money.Money
+	for _, line := range lines {
+		if line.ToyID == nil {
+			continue
+		}
+
+		covered, checked := inScope[*line.ToyID]
+		if !checked {
+			covered = toyScope[*line.ToyID]
+			if !covered && len(categoryScope) > 0 {
+				toy, err := s.toyRepo.FindById(ctx, line.ToyID.String())
+				if err != nil {
+					return 0, err
+				}
+				for _, category := range toy.Categories {
+					if categoryScope[category.ID.String()] {
+						covered = true
+						break
+					}
+				}
+			}
+			inScope[*line.ToyID] = covered
+		}
+
+		if covered {
+			eligible += line.Amount
+		}
+	}
+
+	return eligible, nil
+}
+
+func (s *PromotionService) applyRequest(ctx context.Context, promotion *entity.Promotion, req entity.PromotionRequest) error {
+	promotion.Code = normalizePromoCode(req.Code)
+	promotion.Name = strings.TrimSpace(req.Name)
+	promotion.Description = req.Description
+	promotion.DiscountType = req.DiscountType
+	promotion.DiscountValue = req.DiscountValue
+	promotion.MinOrderAmount = req.MinOrderAmount
+	promotion.MaxDiscount = req.MaxDiscount
+	promotion.StartsAt = req.StartsAt
+	promotion.EndsAt = req.EndsAt
+	promotion.UsageLimit = req.UsageLimit
+	promotion.PerUserLimit = req.PerUserLimit
+	promotion.IsActive = req.IsActive
+
+	if errs := promotion.Validate(); len(errs) > 0 {
+		return errors.New("validasi gagal: " + errs[0])
+	}
+
+	promotion.Categories = make([]entity.ToyCategory, 0, len(req.CategoryIDs))
+	for _, categoryIDStr := range req.CategoryIDs {
+		categoryID, err := uuid.FromString(categoryIDStr)
+		if err != nil {
+			return errors.New("format ID kategori tidak valid")
+		}
+
+		if _, err := s.categoryRepo.FindById(ctx, categoryID.String()); err != nil {
+			return errors.New("kategori dengan ID " + categoryIDStr + " tidak ditemukan")
+		}
+
+		promotion.Categories = append(promotion.Categories, entity.ToyCategory{
+			BaseEntity: entity.BaseEntity{ID: categoryID},
+		})
+	}
+
+	promotion.Toys = make([]entity.Toy, 0, len(req.ToyIDs))
+	for _, toyIDStr := range req.ToyIDs {
+		toyID, err := uuid.FromString(toyIDStr)
+		if err != nil {
+			return errors.New("format ID mainan tidak valid")
+		}
+
+		if _, err := s.toyRepo.FindById(ctx, toyID.String()); err != nil {
+			return errors.New("mainan dengan ID " + toyIDStr + " tidak ditemukan")
+		}
+
+		promotion.Toys = append(promotion.Toys, entity.Toy{
+			BaseEntity: entity.BaseEntity{ID: toyID},
+		})
+	}
+
+	return nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
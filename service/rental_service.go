@@ -5,23 +5,40 @@ import (
 	"errors"
 	"final-project/entity"
 	"final-project/repository"
+	"final-project/utils/helpers"
+	"final-project/utils/money"
 	"fmt"
 	"github.com/gofrs/uuid/v5"
+	"time"
 )
 
 type IRentalService interface {
 	IBaseService[entity.Rental]
 	CreateRental(ctx context.Context, req entity.CreateRentalRequest) (*entity.Rental, error)
+	FindAllByBranch(ctx context.Context, branchID string, limit int, offset int) ([]entity.Rental, int64, error)
+	QuoteRental(ctx context.Context, req entity.QuoteRentalRequest) (*entity.PriceQuote, error)
 	ReturnRental(ctx context.Context, id string, req entity.ReturnRentalRequest) (*entity.Rental, error)
 	ExtendRental(ctx context.Context, id string, req entity.ExtendRentalRequest) (*entity.Rental, *entity.Payment, error)
+	RefundDeposit(ctx context.Context, id string) (*entity.Rental, error)
+	GetBalance(ctx context.Context, id string) (*entity.RentalBalance, error)
+	SettleRental(ctx context.Context, id string, useWallet bool) (*entity.Payment, error)
+	WriteOff(ctx context.Context, id string, req entity.WriteOffRentalRequest) (*entity.Rental, error)
 }
 
 type RentalService struct {
 	BaseService[entity.Rental]
-	rentalRepo repository.IRentalRepository
-	userRepo   repository.IUserRepository
-	toyRepo    repository.IToyRepository
-	paymentSvc IPaymentService
+	rentalRepo   repository.IRentalRepository
+	userRepo     repository.IUserRepository
+	toyRepo      repository.IToyRepository
+	paymentSvc   IPaymentService
+	feePolicySvc IFeePolicyService
+	pricingSvc   IPricingService
+	promotionSvc IPromotionService
+	branchRepo   repository.IBranchRepository
+	deliverySvc  IDeliveryService
+	turnaround   entity.MaintenanceTurnaround
+	tax          entity.TaxPolicy
+	rollupSvc    IReportRollupService
 }
 
 func NewRentalService(
@@ -29,17 +46,49 @@ func NewRentalService(
 	userRepo repository.IUserRepository,
 	toyRepo repository.IToyRepository,
 	paymentSvc IPaymentService,
+	feePolicySvc IFeePolicyService,
+	pricingSvc IPricingService,
+	promotionSvc IPromotionService,
+	branchRepo repository.IBranchRepository,
+	deliverySvc IDeliveryService,
+	turnaround entity.MaintenanceTurnaround,
+	tax entity.TaxPolicy,
+	rollupSvc IReportRollupService,
 ) IRentalService {
 	return &RentalService{
-		BaseService: BaseService[entity.Rental]{repository: repo},
-		rentalRepo:  repo,
-		userRepo:    userRepo,
-		toyRepo:     toyRepo,
-		paymentSvc:  paymentSvc,
+		BaseService:  BaseService[entity.Rental]{repository: repo},
+		rentalRepo:   repo,
+		userRepo:     userRepo,
+		toyRepo:      toyRepo,
+		paymentSvc:   paymentSvc,
+		feePolicySvc: feePolicySvc,
+		pricingSvc:   pricingSvc,
+		promotionSvc: promotionSvc,
+		branchRepo:   branchRepo,
+		deliverySvc:  deliverySvc,
+		turnaround:   turnaround,
+		tax:          tax,
+		rollupSvc:    rollupSvc,
 	}
 }
 
 func (s *RentalService) CreateRental(ctx context.Context, req entity.CreateRentalRequest) (*entity.Rental, error) {
+	if err := req.DeliveryOptions.Normalize(); err != nil {
+		return nil, err
+	}
+
+	pickupBranch, err := resolveBranch(ctx, s.branchRepo, req.PickupBranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	returnBranch := pickupBranch
+	if req.ReturnBranchID != nil {
+		if returnBranch, err = resolveBranch(ctx, s.branchRepo, req.ReturnBranchID); err != nil {
+			return nil, err
+		}
+	}
+
 	rental := &entity.Rental{
 		UserID:             req.UserID,
 		Status:             "pending",
@@ -48,45 +97,84 @@ func (s *RentalService) CreateRental(ctx context.Context, req entity.CreateRenta
 		TotalRentalPrice:   0,
 		PaymentStatus:      "unpaid",
 		Notes:              req.Notes,
+		PickupBranchID:     &pickupBranch.ID,
+		ReturnBranchID:     &returnBranch.ID,
+		DeliveryMethod:     req.DeliveryMethod,
+		ReturnMethod:       req.ReturnMethod,
 		RentalItems:        make([]entity.RentalItem, 0, len(req.Items)),
 	}
 
-	rentalDays := int(req.ExpectedReturnDate.Sub(req.RentalDate).Hours() / 24)
-	if rentalDays < 1 {
-		return nil, errors.New("tanggal pengembalian harus setelah tanggal rental")
+	quote, err := s.QuoteRental(ctx, entity.QuoteRentalRequest{
+		RentalDate:         req.RentalDate,
+		ExpectedReturnDate: req.ExpectedReturnDate,
+		Items:              req.Items,
+		PromoCode:          req.PromoCode,
+		UserID:             req.UserID,
+		PickupBranchID:     &pickupBranch.ID,
+		ReturnBranchID:     &returnBranch.ID,
+		DeliveryOptions:    req.DeliveryOptions,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var totalPrice float64 = 0
 	for _, item := range req.Items {
 		toy, err := s.toyRepo.FindById(ctx, item.ToyID.String())
 		if err != nil {
 			return nil, errors.New("mainan tidak ditemukan: " + item.ToyID.String())
 		}
 
-		if toy.Stock < item.Quantity {
-			return nil, errors.New("stok mainan tidak mencukupi: " + toy.Name)
+		available, err := s.branchRepo.CountAvailableUnits(ctx, pickupBranch.ID.String(), toy.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if available < int64(item.Quantity) {
+			return nil, fmt.Errorf("stok mainan di cabang %s tidak mencukupi: %s", pickupBranch.Name, toy.Name)
 		}
 
-		pricePerUnit := toy.RentalPrice
-		itemTotalPrice := float64(item.Quantity) * pricePerUnit * float64(rentalDays)
-		totalPrice += itemTotalPrice
+		conditionBefore := item.ConditionBefore
+		if conditionBefore == "" {
+			conditionBefore = toy.Condition
+		}
 
 		rentalItem := entity.RentalItem{
 			ToyID:           item.ToyID,
 			Quantity:        item.Quantity,
-			PricePerUnit:    pricePerUnit,
-			ConditionBefore: item.ConditionBefore,
-			ConditionAfter:  item.ConditionBefore,
+			PricePerUnit:    toy.RentalPrice,
+			ConditionBefore: conditionBefore,
+			ConditionAfter:  conditionBefore,
 			Status:          "rented",
 		}
 
 		rental.RentalItems = append(rental.RentalItems, rentalItem)
 	}
 
-	rental.TotalRentalPrice = totalPrice
+	for _, line := range quote.Lines {
+		rental.PriceLines = append(rental.PriceLines, entity.RentalPriceLine{PriceLine: line})
+	}
+
+	if quote.Promotion != nil {
+		rental.PromotionID = &quote.Promotion.ID
+		rental.PromoCode = quote.PromoCode
+		rental.DiscountAmount = quote.DiscountAmount
+	}
+
+	// Ongkos antar jemput sudah menjadi baris harga sehingga ikut ditagihkan sebagai rincian terpisah
+	rental.DeliveryFee = quote.DeliveryFee
+	rental.DeliveryTasks = quote.DeliveryTasks
+
+	rental.TotalRentalPrice = quote.Total
+	rental.TaxRate = quote.TaxRate
+	rental.TaxInclusive = quote.TaxInclusive
+	rental.DepositAmount = quote.Deposit
+	rental.DepositStatus = entity.DepositStatusNone
+	if rental.DepositAmount > 0 {
+		rental.DepositStatus = entity.DepositStatusPending
+	}
 	if err := s.repository.Insert(ctx, rental); err != nil {
 		return nil, err
 	}
+	s.rollupSvc.RefreshRental(ctx, rental.ID.String())
 
 	newRent, err := s.rentalRepo.FindById(ctx, rental.ID.String())
 	if err != nil {
@@ -98,6 +186,31 @@ func (s *RentalService) CreateRental(ctx context.Context, req entity.CreateRenta
 	return &newRent, nil
 }
 
+func (s *RentalService) FindAllByBranch(ctx context.Context, branchID string, limit int, offset int) ([]entity.Rental, int64, error) {
+	return s.rentalRepo.FindAllByBranch(ctx, branchID, limit, offset)
+}
+
+func (s *RentalService) QuoteRental(ctx context.Context, req entity.QuoteRentalRequest) (*entity.PriceQuote, error) {
+	quote, err := s.pricingSvc.QuoteRental(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.promotionSvc.ApplyToQuote(ctx, quote, req.PromoCode, req.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.deliverySvc.ApplyToQuote(ctx, quote, req); err != nil {
+		return nil, err
+	}
+
+	// PPN dihitung dari total setelah potongan promo
+	quote.TaxRate = s.tax.Rate
+	quote.TaxInclusive = s.tax.Inclusive
+	quote.GrandTotal, quote.Tax = s.tax.Apply(quote.Total)
+	return quote, nil
+}
+
 func (s *RentalService) ReturnRental(ctx context.Context, id string, req entity.ReturnRentalRequest) (*entity.Rental, error) {
 	rental, err := s.repository.FindById(ctx, id)
 	if err != nil {
@@ -112,40 +225,66 @@ func (s *RentalService) ReturnRental(ctx context.Context, id string, req entity.
 		return nil, errors.New("rental sudah selesai atau dibatalkan")
 	}
 
+	// Pengembalian kedua akan memindahkan unit ke perawatan lagi dan menghitung ulang denda
+	if rental.ActualReturnDate != nil {
+		return nil, entity.ErrRentalReturned
+	}
+
 	if req.ActualReturnDate.Before(rental.RentalDate) {
 		return nil, errors.New("tanggal pengembalian tidak boleh sebelum tanggal rental")
 	}
 
+	refundMethod := req.DepositRefundMethod
+	if refundMethod == "" {
+		refundMethod = entity.DepositRefundGateway
+	}
+	if refundMethod != entity.DepositRefundGateway && refundMethod != entity.DepositRefundCredit {
+		return nil, entity.ErrInvalidDepositRefundMethod
+	}
+
 	rental.ActualReturnDate = &req.ActualReturnDate
 
+	// Barang boleh dikembalikan di cabang lain, unitnya akan tercatat di cabang tersebut
+	if req.ReturnBranchID != nil {
+		returnBranch, err := resolveBranch(ctx, s.branchRepo, req.ReturnBranchID)
+		if err != nil {
+			return nil, err
+		}
+		rental.ReturnBranchID = &returnBranch.ID
+	}
+
 	rentalItemMap := make(map[uuid.UUID]*entity.RentalItem)
 	for i := range rental.RentalItems {
 		rentalItemMap[rental.RentalItems[i].ID] = &rental.RentalItems[i]
 	}
 
-	var totalLateFee float64 = 0
-
-	if req.ActualReturnDate.After(rental.ExpectedReturnDate) {
-		days := int(req.ActualReturnDate.Sub(rental.ExpectedReturnDate).Hours()/48) + 1
+	policy, err := s.feePolicySvc.FindActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rental.FeePolicyID = &policy.ID
+	rental.FeePolicyVersion = policy.Version
 
+	if days := policy.LateDays(rental.ExpectedReturnDate, req.ActualReturnDate); days > 0 {
+		var dailyLateFee money.Money = 0
 		for _, rentalItem := range rental.RentalItems {
 			toy, err := s.toyRepo.FindById(ctx, rentalItem.ToyID.String())
 			if err != nil {
 				return nil, errors.New("tidak dapat mendapatkan data mainan: " + rentalItem.ToyID.String())
 			}
 
-			itemLateFee := toy.LateFeePerDay * float64(days) * float64(rentalItem.Quantity)
-			totalLateFee += itemLateFee
+			dailyLateFee += toy.LateFeePerDay.Mul(rentalItem.Quantity)
 		}
 
+		rental.LateFee = policy.LateFee(dailyLateFee, days)
 		rental.Status = "overdue"
 	} else {
+		rental.LateFee = 0
 		rental.Status = "completed"
 	}
 
-	rental.LateFee = totalLateFee
-
-	var totalDamageFee float64 = 0
+	var totalDamageFee money.Money = 0
+	var returnedItems []*entity.RentalItem
 
 	for _, itemReq := range req.Items {
 		rentalItem, exists := rentalItemMap[itemReq.RentalItemID]
@@ -153,64 +292,81 @@ func (s *RentalService) ReturnRental(ctx context.Context, id string, req entity.
 			return nil, errors.New("item rental dengan ID " + itemReq.RentalItemID.String() + " tidak ditemukan")
 		}
 
-		validConditions := []string{"new", "excellent", "good", "fair", "poor", "damaged", "lost"}
-		validCondition := false
-		for _, c := range validConditions {
-			if itemReq.ConditionAfter == c {
-				validCondition = true
-				break
-			}
-		}
-		if !validCondition {
+		if !isValidReturnCondition(itemReq.ConditionAfter) {
 			return nil, errors.New("kondisi tidak valid: " + itemReq.ConditionAfter)
 		}
 
 		rentalItem.ConditionAfter = itemReq.ConditionAfter
 		rentalItem.DamageDescription = itemReq.DamageDescription
 
-		var damageFee float64 = 0
+		// Rental lama belum memiliki catatan unit, setiap kuantitas diperlakukan sebagai satu unit
+		if len(rentalItem.Units) == 0 {
+			for i := 0; i < rentalItem.Quantity; i++ {
+				rentalItem.Units = append(rentalItem.Units, entity.RentalItemUnit{
+					ConditionBefore: rentalItem.ConditionBefore,
+				})
+			}
+		}
 
-		toy, _ := s.toyRepo.FindById(ctx, rentalItem.ToyID.String())
+		unitReqMap := make(map[uuid.UUID]entity.ReturnRentalUnitRequest, len(itemReq.Units))
+		for _, unitReq := range itemReq.Units {
+			if !isValidReturnCondition(unitReq.ConditionAfter) {
+				return nil, errors.New("kondisi tidak valid: " + unitReq.ConditionAfter)
+			}
+			unitReqMap[unitReq.ToyUnitID] = unitReq
+		}
 
-		if itemReq.ConditionAfter == "lost" {
-			damageFee = toy.ReplacementPrice * float64(rentalItem.Quantity)
-			rentalItem.Status = "lost"
-		} else if itemReq.ConditionAfter == "damaged" {
-			damageFee = toy.ReplacementPrice * 0.7 * float64(rentalItem.Quantity)
-			rentalItem.Status = "damaged"
-		} else {
-			conditionValues := map[string]int{
-				"new":       5,
-				"excellent": 4,
-				"good":      3,
-				"fair":      2,
-				"poor":      1,
+		for unitID := range unitReqMap {
+			found := false
+			for _, itemUnit := range rentalItem.Units {
+				if itemUnit.ToyUnitID == unitID {
+					found = true
+					break
+				}
 			}
+			if !found {
+				return nil, errors.New("unit dengan ID " + unitID.String() + " bukan bagian dari item rental ini")
+			}
+		}
+
+		toy, _ := s.toyRepo.FindById(ctx, rentalItem.ToyID.String())
 
-			beforeValue := conditionValues[rentalItem.ConditionBefore]
-			afterValue := conditionValues[itemReq.ConditionAfter]
+		var damageFee money.Money = 0
+		rentalItem.Status = entity.RentalItemStatusReturned
 
-			if afterValue < beforeValue {
-				damageFee = toy.ReplacementPrice * 0.15 * float64(beforeValue-afterValue) * float64(rentalItem.Quantity)
+		for i := range rentalItem.Units {
+			itemUnit := &rentalItem.Units[i]
+
+			conditionAfter := itemReq.ConditionAfter
+			damageDescription := itemReq.DamageDescription
+			if unitReq, ok := unitReqMap[itemUnit.ToyUnitID]; ok {
+				conditionAfter = unitReq.ConditionAfter
+				if unitReq.DamageDescription != "" {
+					damageDescription = unitReq.DamageDescription
+				}
 			}
 
-			rentalItem.Status = "returned"
+			itemUnit.ConditionAfter = conditionAfter
+			itemUnit.DamageDescription = damageDescription
+			itemUnit.DamageFee = policy.UnitDamageFee(toy, itemUnit.ConditionBefore, conditionAfter)
+			itemUnit.ReturnedAt = &req.ActualReturnDate
+			damageFee += itemUnit.DamageFee
+
+			switch {
+			case conditionAfter == entity.ConditionLost:
+				rentalItem.Status = entity.RentalItemStatusLost
+			case conditionAfter == entity.ConditionDamaged && rentalItem.Status != entity.RentalItemStatusLost:
+				rentalItem.Status = entity.RentalItemStatusDamaged
+			}
 		}
 
 		rentalItem.DamageFee = damageFee
 		totalDamageFee += damageFee
 
-		if err := s.rentalRepo.UpdateRentalItem(ctx, rentalItem); err != nil {
-			return nil, err
-		}
-
-		toy.Stock += rentalItem.Quantity
-		if err := s.toyRepo.UpdateById(ctx, toy.ID.String(), &toy); err != nil {
-			return nil, err
-		}
+		returnedItems = append(returnedItems, rentalItem)
 	}
 
-	rental.DamageFee = totalDamageFee
+	rental.DamageFee = policy.CapDamageFee(totalDamageFee)
 
 	if req.Notes != "" {
 		rental.Notes = req.Notes
@@ -218,13 +374,142 @@ func (s *RentalService) ReturnRental(ctx context.Context, id string, req entity.
 
 	rental.TotalAmount = rental.TotalRentalPrice + rental.LateFee + rental.DamageFee
 
-	if err := s.rentalRepo.ReturnRental(ctx, &rental); err != nil {
+	// Deposit dipotong denda lebih dulu, sisanya dikembalikan lewat gateway setelah pengembalian tersimpan
+	refundDeposit := false
+	if rental.DepositStatus == entity.DepositStatusHeld {
+		settlement := rental.SettleDeposit()
+		now := time.Now()
+		rental.DepositApplied = settlement.Applied
+		rental.DepositSettledAt = &now
+
+		switch {
+		case settlement.Remainder <= 0:
+			rental.DepositStatus = entity.DepositStatusApplied
+		case refundMethod == entity.DepositRefundCredit:
+			rental.DepositStatus = entity.DepositStatusCredited
+			rental.DepositRefunded = settlement.Remainder
+		default:
+			rental.DepositStatus = entity.DepositStatusRefundPending
+			rental.DepositRefunded = settlement.Remainder
+			refundDeposit = true
+		}
+	}
+
+	balance, err := s.paymentSvc.GetRentalBalance(ctx, rental)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rental baru selesai bila tidak ada sisa tagihan, denda yang belum dibayar ditagih lewat SettleRental
+	if balance.Outstanding <= 0 {
+		rental.Status = entity.RentalStatusCompleted
+		rental.PaymentStatus = entity.PaymentStatusPaid
+	} else {
+		if rental.Status == entity.RentalStatusCompleted {
+			rental.Status = entity.RentalStatusActive
+		}
+		if balance.Paid+balance.DepositApplied > 0 {
+			rental.PaymentStatus = entity.PaymentStatusPartiallyPaid
+		}
+	}
+
+	if err := s.rentalRepo.ReturnRental(ctx, &rental, returnedItems, s.turnaround); err != nil {
+		return nil, err
+	}
+	s.rollupSvc.RefreshRental(ctx, rental.ID.String())
+
+	if refundDeposit {
+		if err := s.refundDeposit(ctx, &rental); err != nil {
+			helpers.Logger.Error("Gagal mengembalikan deposit rental ", rental.ID, ": ", err)
+		}
+	}
+
+	return &rental, nil
+}
+
+func (s *RentalService) GetBalance(ctx context.Context, id string) (*entity.RentalBalance, error) {
+	rental, err := s.rentalRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.paymentSvc.GetRentalBalance(ctx, rental)
+}
+
+// SettleRental membuat pembayaran untuk sisa tagihan rental, termasuk denda setelah pengembalian
+func (s *RentalService) SettleRental(ctx context.Context, id string, useWallet bool) (*entity.Payment, error) {
+	if _, err := s.rentalRepo.FindById(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.paymentSvc.CreatePaymentForRental(ctx, id, useWallet)
+}
+
+// WriteOff menghapus sisa tagihan rental yang sudah dikembalikan namun tidak tertagih lalu menutup rentalnya
+func (s *RentalService) WriteOff(ctx context.Context, id string, req entity.WriteOffRentalRequest) (*entity.Rental, error) {
+	if req.Notes == "" {
+		return nil, errors.New("alasan penghapusan piutang wajib diisi")
+	}
+
+	rental, err := s.rentalRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rental.ActualReturnDate == nil {
+		return nil, entity.ErrRentalNotReturned
+	}
+
+	balance, err := s.paymentSvc.GetRentalBalance(ctx, rental)
+	if err != nil {
 		return nil, err
 	}
+	if balance.Outstanding <= 0 {
+		return nil, entity.ErrNothingToSettle
+	}
 
+	now := time.Now()
+	rental.WrittenOffAmount = balance.Outstanding
+	rental.WrittenOffAt = &now
+	rental.Status = entity.RentalStatusCompleted
+
+	if err := s.rentalRepo.WriteOff(ctx, &rental, req.Notes); err != nil {
+		return nil, err
+	}
+	s.rollupSvc.RefreshRental(ctx, rental.ID.String())
 	return &rental, nil
 }
 
+// RefundDeposit mengulang pengembalian sisa deposit yang sebelumnya gagal diproses gateway
+func (s *RentalService) RefundDeposit(ctx context.Context, id string) (*entity.Rental, error) {
+	rental, err := s.rentalRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if rental.DepositStatus != entity.DepositStatusRefundPending {
+		return nil, errors.New("tidak ada deposit yang menunggu pengembalian")
+	}
+
+	if err := s.refundDeposit(ctx, &rental); err != nil {
+		return nil, err
+	}
+	return &rental, nil
+}
+
+func (s *RentalService) refundDeposit(ctx context.Context, rental *entity.Rental) error {
+	if err := s.paymentSvc.RefundDeposit(ctx, *rental, rental.DepositRefunded); err != nil {
+		return err
+	}
+
+	updated, err := s.rentalRepo.UpdateDepositStatus(ctx, rental.ID.String(), entity.DepositStatusRefundPending, entity.DepositStatusRefunded)
+	if err != nil {
+		return err
+	}
+	if updated {
+		rental.DepositStatus = entity.DepositStatusRefunded
+	}
+	return nil
+}
+
 func (s *RentalService) ExtendRental(ctx context.Context, id string, req entity.ExtendRentalRequest) (*entity.Rental, *entity.Payment, error) {
 	rental, err := s.rentalRepo.FindById(ctx, id)
 	if err != nil {
@@ -243,29 +528,15 @@ func (s *RentalService) ExtendRental(ctx context.Context, id string, req entity.
 		return nil, nil, errors.New("tanggal perpanjangan harus setelah tanggal pengembalian yang diharapkan saat ini")
 	}
 
-	additionalDays := int(req.NewExpectedReturnDate.Sub(rental.ExpectedReturnDate).Hours() / 24)
-	if additionalDays <= 0 {
-		return nil, nil, errors.New("perpanjangan minimal 1 hari")
+	quote, err := s.pricingSvc.QuoteExtension(ctx, rental, req.NewExpectedReturnDate)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	oldExpectedReturnDate := rental.ExpectedReturnDate
 	oldTotalPrice := rental.TotalRentalPrice
-
-	var additionalCost float64 = 0
-	for i, item := range rental.RentalItems {
-		toy, err := s.toyRepo.FindById(ctx, item.ToyID.String())
-		if err != nil {
-			return nil, nil, errors.New("tidak dapat mendapatkan data mainan: " + item.ToyID.String())
-		}
-
-		itemExtensionCost := toy.RentalPrice * float64(additionalDays) * float64(item.Quantity)
-		additionalCost += itemExtensionCost
-		fmt.Printf("Item %d: %s x %d = %f", i, toy.Name, item.Quantity, itemExtensionCost)
-		fmt.Println("")
-		fmt.Printf("Total: %f", additionalCost)
-	}
-
-	fmt.Println("Additional Cost svc:", additionalCost)
+	additionalDays := quote.RentalDays
+	additionalCost := quote.Total
 
 	var extensionNotes string
 	if req.Notes != "" {
@@ -279,6 +550,8 @@ func (s *RentalService) ExtendRental(ctx context.Context, id string, req entity.
 	if err != nil {
 		return nil, nil, errors.New("gagal memperpanjang rental: " + err.Error())
 	}
+	// Berlaku juga saat perpanjangan dibatalkan karena pembayarannya gagal dibuat
+	defer s.rollupSvc.RefreshRental(ctx, id)
 
 	rental.ExpectedReturnDate = req.NewExpectedReturnDate
 	rental.TotalRentalPrice += additionalCost
@@ -294,6 +567,7 @@ func (s *RentalService) ExtendRental(ctx context.Context, id string, req entity.
 		AdditionalDays:        additionalDays,
 		OriginalRentalPrice:   oldTotalPrice,
 		AdditionalCost:        additionalCost,
+		Lines:                 quote.Lines,
 	}
 
 	payment, err := s.paymentSvc.CreatePaymentForExtension(ctx, id, metadata)
@@ -304,3 +578,13 @@ func (s *RentalService) ExtendRental(ctx context.Context, id string, req entity.
 
 	return &rental, payment, nil
 }
+
+func isValidReturnCondition(condition string) bool {
+	validConditions := []string{"new", "excellent", "good", "fair", "poor", "damaged", "lost"}
+	for _, c := range validConditions {
+		if condition == c {
+			return true
+		}
+	}
+	return false
+}
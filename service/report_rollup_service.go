@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"final-project/entity"
+	"final-project/repository"
+	"final-project/utils/cache"
+	"final-project/utils/cron"
+	"final-project/utils/helpers"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// IReportRollupService memelihara rollup harian laporan bisnis. RefreshRental dipanggil setelah rental
+// atau pembayarannya berubah, sedangkan pembangunan ulang terjadwal memperbaiki rollup yang tertinggal
+// karena pembaruan yang gagal atau perubahan data di luar event tersebut.
+type IReportRollupService interface {
+	RefreshRental(ctx context.Context, rentalID string)
+	Rebuild(ctx context.Context) (*entity.ReportRollupRun, error)
+	StartRebuildScheduler(ctx context.Context, schedule cron.Schedule)
+}
+
+type ReportRollupService struct {
+	rollupRepo  repository.IReportRollupRepository
+	reportCache *cache.TTL
+	windowDays  int
+	location    *time.Location
+}
+
+// NewReportRollupService membuat layanan rollup. reportCache adalah cache yang sama dengan layanan
+// laporan dan dikosongkan setiap kali rollup berubah, boleh nil. windowDays adalah jumlah hari terakhir
+// yang dibangun ulang setiap kali, 0 atau kurang berarti semua hari. location adalah zona waktu laporan
+// untuk tanggal rollup dan jadwal pembangunan ulang.
+func NewReportRollupService(rollupRepo repository.IReportRollupRepository, reportCache *cache.TTL, windowDays int, location *time.Location) IReportRollupService {
+	return &ReportRollupService{
+		rollupRepo:  rollupRepo,
+		reportCache: reportCache,
+		windowDays:  windowDays,
+		location:    location,
+	}
+}
+
+// RefreshRental menghitung ulang rollup hari rental tersebut. Kegagalannya hanya dicatat dan harinya
+// ditandai agar transaksi rental tidak ikut gagal, rollup diperbaiki oleh pembangunan ulang berikutnya.
+func (s *ReportRollupService) RefreshRental(ctx context.Context, rentalID string) {
+	var logger = helpers.Logger
+
+	// Perubahan rental sudah tersimpan, rollup tetap diperbarui walaupun request sudah selesai
+	ctx = context.WithoutCancel(ctx)
+
+	day, err := s.rollupRepo.FindRentalDay(ctx, rentalID)
+	if err != nil {
+		logger.Error(fmt.Errorf("gagal mencari tanggal rollup rental %s: %v", rentalID, err))
+		return
+	}
+
+	if err := s.rollupRepo.RefreshDay(ctx, day); err != nil {
+		logger.Error(fmt.Errorf("gagal memperbarui rollup laporan %s: %v", day.Format("2006-01-02"), err))
+		if err := s.rollupRepo.MarkDirty(ctx, day); err != nil {
+			logger.Error(fmt.Errorf("gagal menandai rollup laporan %s: %v", day.Format("2006-01-02"), err))
+		}
+		return
+	}
+	s.reportCache.Purge()
+}
+
+// Rebuild menghitung ulang rollup hari per hari sehingga laporan tetap bisa dibaca selama proses
+// berjalan. Pembangunan ulang pertama mencakup semua hari, berikutnya hanya hari-hari terakhir sesuai
+// windowDays ditambah hari yang gagal diperbarui. Hasilnya dicatat di riwayat pembangunan ulang.
+func (s *ReportRollupService) Rebuild(ctx context.Context) (*entity.ReportRollupRun, error) {
+	run := &entity.ReportRollupRun{
+		Status:    entity.ReportRollupRunRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.rollupRepo.InsertRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	rebuildErr := s.rebuildDays(ctx, run)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = entity.ReportRollupRunCompleted
+	if rebuildErr != nil {
+		run.Status = entity.ReportRollupRunFailed
+		run.Error = rebuildErr.Error()
+	}
+
+	// Riwayat tetap ditutup walaupun ctx sudah dibatalkan saat server berhenti
+	if err := s.rollupRepo.FinishRun(context.WithoutCancel(ctx), run); err != nil {
+		return nil, err
+	}
+	s.reportCache.Purge()
+	return run, rebuildErr
+}
+
+func (s *ReportRollupService) rebuildDays(ctx context.Context, run *entity.ReportRollupRun) error {
+	days, err := s.findRebuildDays(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if err := s.rollupRepo.RefreshDay(ctx, day); err != nil {
+			return fmt.Errorf("gagal menghitung rollup %s: %w", day.Format("2006-01-02"), err)
+		}
+		run.DayCount++
+	}
+	return nil
+}
+
+// findRebuildDays mengambil hari yang dibangun ulang secara berurutan tanpa duplikat
+func (s *ReportRollupService) findRebuildDays(ctx context.Context) ([]time.Time, error) {
+	var from time.Time
+	if s.windowDays > 0 {
+		ready, err := s.rollupRepo.HasCompletedRun(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ready {
+			from = entity.ReportDay(time.Now(), s.location).AddDate(0, 0, -s.windowDays)
+		}
+	}
+
+	days, err := s.rollupRepo.FindDays(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	dirtyDays, err := s.rollupRepo.FindDirtyDays(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[time.Time]bool, len(days))
+	for _, day := range days {
+		seen[day] = true
+	}
+	for _, day := range dirtyDays {
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+	return days, nil
+}
+
+// StartRebuildScheduler membangun ulang rollup sesuai jadwal dalam zona waktu laporan sampai ctx
+// dibatalkan. Bila belum pernah ada pembangunan ulang yang selesai, rollup langsung dibangun saat
+// scheduler dimulai. Dipanggil sekali dari main dalam goroutine tersendiri.
+func (s *ReportRollupService) StartRebuildScheduler(ctx context.Context, schedule cron.Schedule) {
+	var logger = helpers.Logger
+
+	rebuild := func() {
+		run, err := s.Rebuild(ctx)
+		if err != nil {
+			logger.Error("Gagal membangun ulang rollup laporan: ", err)
+			return
+		}
+		logger.Infof("Rollup laporan dibangun ulang untuk %d hari dalam %s", run.DayCount, run.FinishedAt.Sub(run.StartedAt).Round(time.Second))
+	}
+
+	if ready, err := s.rollupRepo.HasCompletedRun(ctx); err != nil {
+		logger.Error("Gagal memeriksa rollup laporan: ", err)
+	} else if !ready {
+		rebuild()
+	}
+
+	for {
+		next := schedule.Next(time.Now().In(s.location))
+		if next.IsZero() {
+			logger.Error("Jadwal pembangunan ulang rollup laporan tidak pernah jatuh, scheduler berhenti")
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		rebuild()
+	}
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+	"final-project/utils/cron"
+	"final-project/utils/helpers"
+	"final-project/utils/mailer"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// reportSchedulerBatch membatasi jumlah langganan yang diproses per putaran scheduler
+const reportSchedulerBatch = 20
+
+// IReportRenderer menyusun berkas laporan untuk sebuah langganan. Implementasinya ada di controller
+// karena tabel ekspornya sama dengan unduhan CSV dan XLSX dari endpoint laporan.
+type IReportRenderer interface {
+	Render(ctx context.Context, subscription entity.ReportSubscription, startDate, endDate time.Time) (mailer.Attachment, error)
+}
+
+type IReportSubscriptionService interface {
+	FindAll(ctx context.Context, branchID string, limit int, offset int) ([]entity.ReportSubscription, int64, error)
+	FindById(ctx context.Context, id string) (*entity.ReportSubscription, error)
+	CreateSubscription(ctx context.Context, req entity.ReportSubscriptionRequest, createdBy uuid.UUID) (*entity.ReportSubscription, error)
+	UpdateSubscription(ctx context.Context, id string, req entity.ReportSubscriptionRequest) (*entity.ReportSubscription, error)
+	DeleteById(ctx context.Context, id string) error
+	FindDeliveries(ctx context.Context, id string, limit int, offset int) ([]entity.ReportDelivery, int64, error)
+	SendNow(ctx context.Context, id string) (*entity.ReportDelivery, error)
+	RunDue(ctx context.Context, now time.Time) error
+	StartScheduler(ctx context.Context, interval time.Duration)
+}
+
+type ReportSubscriptionService struct {
+	subscriptionRepo repository.IReportSubscriptionRepository
+	branchRepo       repository.IBranchRepository
+	renderer         IReportRenderer
+	mailer           mailer.Mailer
+	location         *time.Location
+}
+
+func NewReportSubscriptionService(
+	subscriptionRepo repository.IReportSubscriptionRepository,
+	branchRepo repository.IBranchRepository,
+	renderer IReportRenderer,
+	mailer mailer.Mailer,
+	location *time.Location,
+) IReportSubscriptionService {
+	return &ReportSubscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		branchRepo:       branchRepo,
+		renderer:         renderer,
+		mailer:           mailer,
+		location:         location,
+	}
+}
+
+func (s *ReportSubscriptionService) FindAll(ctx context.Context, branchID string, limit int, offset int) ([]entity.ReportSubscription, int64, error) {
+	return s.subscriptionRepo.FindByBranch(ctx, branchID, limit, offset)
+}
+
+func (s *ReportSubscriptionService) FindById(ctx context.Context, id string) (*entity.ReportSubscription, error) {
+	subscription, err := s.subscriptionRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (s *ReportSubscriptionService) CreateSubscription(ctx context.Context, req entity.ReportSubscriptionRequest, createdBy uuid.UUID) (*entity.ReportSubscription, error) {
+	subscription := &entity.ReportSubscription{CreatedBy: createdBy}
+	if err := s.applyRequest(ctx, subscription, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.Insert(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *ReportSubscriptionService) UpdateSubscription(ctx context.Context, id string, req entity.ReportSubscriptionRequest) (*entity.ReportSubscription, error) {
+	subscription, err := s.subscriptionRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyRequest(ctx, &subscription, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.UpdateById(ctx, id, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (s *ReportSubscriptionService) DeleteById(ctx context.Context, id string) error {
+	if _, err := s.subscriptionRepo.FindById(ctx, id); err != nil {
+		return err
+	}
+	return s.subscriptionRepo.DeleteById(ctx, id)
+}
+
+func (s *ReportSubscriptionService) FindDeliveries(ctx context.Context, id string, limit int, offset int) ([]entity.ReportDelivery, int64, error) {
+	if _, err := s.subscriptionRepo.FindById(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	return s.subscriptionRepo.FindDeliveries(ctx, id, limit, offset)
+}
+
+// SendNow mengirim laporan langganan saat ini juga tanpa mengubah jadwal berikutnya. Kegagalan
+// pengiriman tetap dicatat di riwayat dan dikembalikan bersama datanya.
+func (s *ReportSubscriptionService) SendNow(ctx context.Context, id string) (*entity.ReportDelivery, error) {
+	subscription, err := s.subscriptionRepo.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deliver(ctx, subscription, time.Now(), true)
+}
+
+// RunDue mengirim semua langganan yang jadwalnya sudah tiba. Jadwal yang terlewat saat server mati
+// hanya dikirim sekali lalu dilanjutkan ke jadwal setelah now.
+func (s *ReportSubscriptionService) RunDue(ctx context.Context, now time.Time) error {
+	var logger = helpers.Logger
+
+	subscriptions, err := s.subscriptionRepo.FindDue(ctx, now, reportSchedulerBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		scheduledAt := *subscription.NextRunAt
+		nextRunAt, err := s.nextRun(subscription.Schedule, now)
+		if err != nil {
+			// Laporan yang sudah jatuh tempo tetap dikirim, langganan berhenti sampai jadwalnya diperbaiki
+			logger.Error(fmt.Errorf("jadwal langganan laporan %s tidak valid: %v", subscription.ID, err))
+		}
+
+		claimed, err := s.subscriptionRepo.ClaimRun(ctx, subscription.ID.String(), scheduledAt, nextRunAt)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if _, err := s.deliver(ctx, subscription, scheduledAt, false); err != nil {
+			logger.Error(fmt.Errorf("gagal mengirim laporan langganan %s: %v", subscription.ID, err))
+		}
+	}
+	return nil
+}
+
+// StartScheduler menjalankan RunDue setiap interval sampai ctx dibatalkan. Dipanggil sekali dari
+// main dalam goroutine tersendiri.
+func (s *ReportSubscriptionService) StartScheduler(ctx context.Context, interval time.Duration) {
+	var logger = helpers.Logger
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunDue(ctx, time.Now()); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("Gagal menjalankan laporan terjadwal: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver menyusun laporan untuk periode yang dihitung dari scheduledAt, mengirimkannya lalu mencatat
+// riwayatnya. Error yang dikembalikan adalah kegagalan render atau pengiriman.
+func (s *ReportSubscriptionService) deliver(ctx context.Context, subscription entity.ReportSubscription, scheduledAt time.Time, manual bool) (*entity.ReportDelivery, error) {
+	startDate, endDate := subscription.Parameters.Range(scheduledAt.In(s.location))
+	recipients := subscription.RecipientList()
+
+	delivery := &entity.ReportDelivery{
+		SubscriptionID: subscription.ID,
+		ReportType:     subscription.ReportType,
+		PeriodStart:    startDate,
+		PeriodEnd:      endDate,
+		Recipients:     strings.Join(recipients, ","),
+		Manual:         manual,
+		ScheduledAt:    scheduledAt,
+	}
+
+	sendErr := s.send(ctx, subscription, recipients, startDate, endDate, delivery)
+	if sendErr != nil {
+		delivery.Status = entity.ReportDeliveryStatusFailed
+		delivery.Error = sendErr.Error()
+	} else {
+		deliveredAt := time.Now()
+		delivery.Status = entity.ReportDeliveryStatusSent
+		delivery.DeliveredAt = &deliveredAt
+	}
+
+	// Riwayat tetap dicatat walaupun ctx scheduler sudah dibatalkan saat server berhenti
+	if err := s.subscriptionRepo.InsertDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		return nil, err
+	}
+	return delivery, sendErr
+}
+
+func (s *ReportSubscriptionService) send(ctx context.Context, subscription entity.ReportSubscription, recipients []string, startDate, endDate time.Time, delivery *entity.ReportDelivery) error {
+	attachment, err := s.renderer.Render(ctx, subscription, startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("gagal menyusun laporan: %w", err)
+	}
+	delivery.Filename = attachment.Filename
+	delivery.SizeBytes = len(attachment.Data)
+
+	period := fmt.Sprintf("%s s.d. %s", startDate.Format("02/01/2006"), endDate.Format("02/01/2006"))
+	message := mailer.Message{
+		To:      recipients,
+		Subject: fmt.Sprintf("[ToyRental] %s - %s", subscription.Name, period),
+		Body: fmt.Sprintf("Halo,\n\nTerlampir laporan %s untuk periode %s.\n\n"+
+			"Email ini dikirim otomatis sesuai jadwal langganan laporan. Hubungi admin untuk mengubah penerima atau jadwalnya.\n",
+			subscription.Name, period),
+		Attachments: []mailer.Attachment{attachment},
+	}
+
+	if err := s.mailer.Send(ctx, message); err != nil {
+		return fmt.Errorf("gagal mengirim email: %w", err)
+	}
+	return nil
+}
+
+func (s *ReportSubscriptionService) applyRequest(ctx context.Context, subscription *entity.ReportSubscription, req entity.ReportSubscriptionRequest) error {
+	subscription.Name = strings.TrimSpace(req.Name)
+	subscription.ReportType = req.ReportType
+	subscription.Parameters = req.Parameters
+	subscription.Schedule = strings.TrimSpace(req.Schedule)
+	subscription.Format = strings.ToLower(req.Format)
+	subscription.BranchID = req.BranchID
+	subscription.IsActive = req.IsActive == nil || *req.IsActive
+
+	if subscription.Format == "" {
+		subscription.Format = "csv"
+	}
+	if subscription.Parameters.Period == "" {
+		subscription.Parameters.Period = entity.ReportPeriodLast7Days
+	}
+
+	recipients := make([]string, 0, len(req.Recipients))
+	for _, recipient := range req.Recipients {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, strings.ToLower(recipient))
+		}
+	}
+	subscription.Recipients = strings.Join(recipients, ",")
+
+	if errs := subscription.Validate(); len(errs) > 0 {
+		return errors.New("validasi gagal: " + errs[0])
+	}
+
+	if subscription.BranchID != nil {
+		if _, err := resolveBranch(ctx, s.branchRepo, subscription.BranchID); err != nil {
+			return err
+		}
+	}
+
+	// Jadwal dihitung ulang dari sekarang setiap kali langganan diubah
+	subscription.NextRunAt = nil
+	if subscription.IsActive {
+		nextRunAt, err := s.nextRun(subscription.Schedule, time.Now())
+		if err != nil {
+			return err
+		}
+		subscription.NextRunAt = nextRunAt
+	}
+	return nil
+}
+
+// nextRun menghitung jadwal berikutnya setelah after dalam zona waktu laporan
+func (s *ReportSubscriptionService) nextRun(spec string, after time.Time) (*time.Time, error) {
+	schedule, err := cron.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	next := schedule.Next(after.In(s.location))
+	if next.IsZero() {
+		return nil, entity.ErrReportScheduleNoRun
+	}
+	return &next, nil
+}
@@ -1,20 +1,205 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"final-project/entity"
 	"final-project/repository"
+	"gorm.io/gorm"
 )
 
 type IToyCategoryService interface {
 	IBaseService[entity.ToyCategory]
+	CreateCategory(ctx context.Context, req entity.ToyCategoryRequest) (*entity.ToyCategory, error)
+	UpdateCategory(ctx context.Context, id string, req entity.ToyCategoryRequest) (*entity.ToyCategory, error)
+	GetTree(ctx context.Context) ([]*entity.ToyCategoryTree, error)
+	DeleteCategory(ctx context.Context, id string, reassignTo string) error
 }
 
 type ToyCategoryService struct {
 	BaseService[entity.ToyCategory]
+	categoryRepo repository.IToyCategoryRepository
 }
 
 func NewToyCategoryService(repo repository.IToyCategoryRepository) IToyCategoryService {
 	return &ToyCategoryService{
-		BaseService: BaseService[entity.ToyCategory]{repository: repo},
+		BaseService:  BaseService[entity.ToyCategory]{repository: repo},
+		categoryRepo: repo,
 	}
 }
+
+func (s *ToyCategoryService) CreateCategory(ctx context.Context, req entity.ToyCategoryRequest) (*entity.ToyCategory, error) {
+	category := &entity.ToyCategory{
+		ParentID:    req.ParentID,
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: req.Description,
+		SortOrder:   req.SortOrder,
+	}
+
+	if err := s.prepareCategory(ctx, "", category); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Insert(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *ToyCategoryService) UpdateCategory(ctx context.Context, id string, req entity.ToyCategoryRequest) (*entity.ToyCategory, error) {
+	category, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	category.ParentID = req.ParentID
+	category.Name = req.Name
+	category.Slug = req.Slug
+	category.Description = req.Description
+	category.SortOrder = req.SortOrder
+
+	if err := s.prepareCategory(ctx, id, &category); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateById(ctx, id, &category); err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (s *ToyCategoryService) GetTree(ctx context.Context) ([]*entity.ToyCategoryTree, error) {
+	categories, err := s.categoryRepo.FindAllOrdered(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[string]*entity.ToyCategoryTree, len(categories))
+	for _, category := range categories {
+		nodes[category.ID.String()] = &entity.ToyCategoryTree{
+			ID:          category.ID,
+			ParentID:    category.ParentID,
+			Name:        category.Name,
+			Slug:        category.Slug,
+			Description: category.Description,
+			SortOrder:   category.SortOrder,
+			Children:    []*entity.ToyCategoryTree{},
+		}
+	}
+
+	roots := make([]*entity.ToyCategoryTree, 0)
+	for _, category := range categories {
+		node := nodes[category.ID.String()]
+		if category.ParentID != nil {
+			if parent, ok := nodes[category.ParentID.String()]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	return roots, nil
+}
+
+func (s *ToyCategoryService) DeleteById(ctx context.Context, id string) error {
+	return s.DeleteCategory(ctx, id, "")
+}
+
+func (s *ToyCategoryService) DeleteCategory(ctx context.Context, id string, reassignTo string) error {
+	if _, err := s.repository.FindById(ctx, id); err != nil {
+		return err
+	}
+
+	if reassignTo != "" {
+		if _, err := s.repository.FindById(ctx, reassignTo); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("kategori tujuan tidak ditemukan")
+			}
+			return err
+		}
+
+		descendantIDs, err := s.categoryRepo.FindDescendantIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, descendantID := range descendantIDs {
+			if descendantID == reassignTo {
+				return entity.ErrCategoryInvalidMove
+			}
+		}
+
+		return s.categoryRepo.ReassignAndDelete(ctx, id, reassignTo)
+	}
+
+	toyCount, err := s.categoryRepo.CountToys(ctx, id)
+	if err != nil {
+		return err
+	}
+	if toyCount > 0 {
+		return entity.ErrCategoryHasToys
+	}
+
+	childCount, err := s.categoryRepo.CountChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if childCount > 0 {
+		return entity.ErrCategoryHasChildren
+	}
+
+	return s.repository.DeleteById(ctx, id)
+}
+
+// prepareCategory melengkapi slug dan memvalidasi parent serta keunikan slug. Slug yang dibuat dari nama
+// diberi akhiran angka bila sudah dipakai, sedangkan slug yang diisi sendiri harus unik.
+func (s *ToyCategoryService) prepareCategory(ctx context.Context, id string, category *entity.ToyCategory) error {
+	if category.Slug == "" {
+		slug, err := s.categoryRepo.AvailableSlug(ctx, id, category.Name)
+		if err != nil {
+			return err
+		}
+		category.Slug = slug
+	}
+
+	if errs := category.Validate(); len(errs) > 0 {
+		return errors.New("validasi gagal: " + errs[0])
+	}
+
+	existing, err := s.categoryRepo.FindBySlug(ctx, category.Slug)
+	if err == nil && existing.ID.String() != id {
+		return entity.ErrCategorySlugConflict
+	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if category.ParentID == nil {
+		return nil
+	}
+
+	if _, err := s.repository.FindById(ctx, category.ParentID.String()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("kategori induk tidak ditemukan")
+		}
+		return err
+	}
+
+	if id == "" {
+		return nil
+	}
+
+	descendantIDs, err := s.categoryRepo.FindDescendantIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, descendantID := range descendantIDs {
+		if descendantID == category.ParentID.String() {
+			return entity.ErrCategoryInvalidMove
+		}
+	}
+
+	return nil
+}
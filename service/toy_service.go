@@ -12,6 +12,7 @@ type IToyService interface {
 	IBaseService[entity.Toy]
 	CreateToy(ctx context.Context, toyRequest entity.ToyRequest) (*entity.Toy, error)
 	UpdateToy(ctx context.Context, id string, toyRequest entity.ToyUpdateRequest) (*entity.Toy, error)
+	Search(ctx context.Context, categoryID string, branchID string, limit int, offset int) ([]entity.Toy, int64, error)
 }
 
 type ToyService struct {
@@ -64,8 +65,12 @@ func (s *ToyService) CreateToy(ctx context.Context, toyRequest entity.ToyRequest
 		AgeRecommendation: toyRequest.AgeRecommendation,
 		Condition:         toyRequest.Condition,
 		RentalPrice:       toyRequest.RentalPrice,
+		WeeklyPrice:       toyRequest.WeeklyPrice,
+		MonthlyPrice:      toyRequest.MonthlyPrice,
+		MinRentalDays:     toyRequest.MinRentalDays,
 		LateFeePerDay:     toyRequest.LateFeePerDay,
 		ReplacementPrice:  toyRequest.ReplacementPrice,
+		DepositAmount:     toyRequest.DepositAmount,
 		IsAvailable:       toyRequest.IsAvailable,
 		Stock:             toyRequest.Stock,
 		PrimaryImage:      primaryImageURL,
@@ -108,10 +113,13 @@ func (s *ToyService) UpdateToy(ctx context.Context, id string, toyRequest entity
 	existingToy.AgeRecommendation = toyRequest.AgeRecommendation
 	existingToy.Condition = toyRequest.Condition
 	existingToy.RentalPrice = toyRequest.RentalPrice
+	existingToy.WeeklyPrice = toyRequest.WeeklyPrice
+	existingToy.MonthlyPrice = toyRequest.MonthlyPrice
+	existingToy.MinRentalDays = toyRequest.MinRentalDays
 	existingToy.LateFeePerDay = toyRequest.LateFeePerDay
 	existingToy.ReplacementPrice = toyRequest.ReplacementPrice
+	existingToy.DepositAmount = toyRequest.DepositAmount
 	existingToy.IsAvailable = toyRequest.IsAvailable
-	existingToy.Stock = toyRequest.Stock
 
 	toyCategories, err := s.prepareCategoriesFromIDs(ctx, toyRequest.CategoryIDs)
 	if err != nil {
@@ -144,6 +152,36 @@ func (s *ToyService) UpdateToy(ctx context.Context, id string, toyRequest entity
 	return &existingToy, nil
 }
 
+// Search mengambil mainan pada kategori tertentu termasuk seluruh sub kategorinya,
+// dan bila cabang dipilih hanya mainan yang tersedia di cabang tersebut
+func (s *ToyService) Search(ctx context.Context, categoryID string, branchID string, limit int, offset int) ([]entity.Toy, int64, error) {
+	filter := entity.ToyFilter{BranchID: branchID}
+
+	if branchID != "" {
+		if _, err := uuid.FromString(branchID); err != nil {
+			return nil, 0, errors.New("format ID cabang tidak valid")
+		}
+	}
+
+	if categoryID != "" {
+		if _, err := uuid.FromString(categoryID); err != nil {
+			return nil, 0, errors.New("format ID kategori tidak valid")
+		}
+
+		categoryIDs, err := s.categoryRepo.FindDescendantIDs(ctx, categoryID)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if len(categoryIDs) == 0 {
+			return []entity.Toy{}, 0, nil
+		}
+		filter.CategoryIDs = categoryIDs
+	}
+
+	return s.toyRepo.FindAllFiltered(ctx, filter, limit, offset)
+}
+
 func (s *ToyService) prepareCategoriesFromIDs(ctx context.Context, categoryIDs []string) ([]entity.ToyCategory, error) {
 	toyCategories := make([]entity.ToyCategory, 0, len(categoryIDs))
 
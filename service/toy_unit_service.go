@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IToyUnitService interface {
+	IBaseService[entity.ToyUnit]
+	FindByToyID(ctx context.Context, toyID string, status string, branchID string) ([]entity.ToyUnit, error)
+	RegisterUnits(ctx context.Context, toyID string, req entity.RegisterToyUnitsRequest) ([]entity.ToyUnit, error)
+	UpdateUnit(ctx context.Context, id string, req entity.UpdateToyUnitRequest) (*entity.ToyUnit, error)
+	GetHistory(ctx context.Context, id string) (*entity.ToyUnitHistory, error)
+}
+
+type ToyUnitService struct {
+	BaseService[entity.ToyUnit]
+	unitRepo        repository.IToyUnitRepository
+	toyRepo         repository.IToyRepository
+	maintenanceRepo repository.IMaintenanceTaskRepository
+	branchRepo      repository.IBranchRepository
+}
+
+func NewToyUnitService(
+	repo repository.IToyUnitRepository,
+	toyRepo repository.IToyRepository,
+	maintenanceRepo repository.IMaintenanceTaskRepository,
+	branchRepo repository.IBranchRepository,
+) IToyUnitService {
+	return &ToyUnitService{
+		BaseService:     BaseService[entity.ToyUnit]{repository: repo},
+		unitRepo:        repo,
+		toyRepo:         toyRepo,
+		maintenanceRepo: maintenanceRepo,
+		branchRepo:      branchRepo,
+	}
+}
+
+func (s *ToyUnitService) FindByToyID(ctx context.Context, toyID string, status string, branchID string) ([]entity.ToyUnit, error) {
+	if _, err := s.toyRepo.FindById(ctx, toyID); err != nil {
+		return nil, err
+	}
+
+	return s.unitRepo.FindByToyID(ctx, toyID, status, branchID)
+}
+
+func (s *ToyUnitService) RegisterUnits(ctx context.Context, toyID string, req entity.RegisterToyUnitsRequest) ([]entity.ToyUnit, error) {
+	toy, err := s.toyRepo.FindById(ctx, toyID)
+	if err != nil {
+		return nil, err
+	}
+
+	condition := req.Condition
+	if condition == "" {
+		condition = toy.Condition
+	}
+
+	branch, err := resolveBranch(ctx, s.branchRepo, req.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.SerialNumbers) == 0 {
+		if req.Quantity < 1 || req.Quantity > 1000 {
+			return nil, errors.New("jumlah unit harus antara 1-1000")
+		}
+
+		if _, ok := entity.ConditionRank[condition]; !ok {
+			return nil, errors.New("kondisi tidak valid: " + condition)
+		}
+
+		return s.unitRepo.GenerateUnits(ctx, toy.ID, &branch.ID, condition, req.Quantity)
+	}
+
+	units := make([]entity.ToyUnit, 0, len(req.SerialNumbers))
+	seen := make(map[string]bool, len(req.SerialNumbers))
+	for _, serialNumber := range req.SerialNumbers {
+		serialNumber = strings.TrimSpace(serialNumber)
+		if seen[serialNumber] {
+			return nil, errors.New("nomor seri duplikat: " + serialNumber)
+		}
+		seen[serialNumber] = true
+
+		unit := entity.ToyUnit{
+			ToyID:        toy.ID,
+			SerialNumber: serialNumber,
+			Condition:    condition,
+			Status:       entity.ToyUnitStatusAvailable,
+			Notes:        req.Notes,
+			BranchID:     &branch.ID,
+		}
+
+		if errs := unit.Validate(); len(errs) > 0 {
+			return nil, errors.New("validasi gagal: " + errs[0])
+		}
+
+		_, err := s.unitRepo.FindBySerialNumber(ctx, serialNumber)
+		if err == nil {
+			return nil, entity.ErrSerialNumberExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		units = append(units, unit)
+	}
+
+	return s.unitRepo.RegisterUnits(ctx, toy.ID, units)
+}
+
+func (s *ToyUnitService) UpdateUnit(ctx context.Context, id string, req entity.UpdateToyUnitRequest) (*entity.ToyUnit, error) {
+	unit, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if unit.Status == entity.ToyUnitStatusRented {
+		return nil, entity.ErrUnitRented
+	}
+
+	if unit.TransferID != nil {
+		return nil, entity.ErrUnitInTransit
+	}
+
+	if req.Status == entity.ToyUnitStatusRented {
+		return nil, errors.New("status rented hanya dapat diberikan melalui transaksi rental")
+	}
+
+	// Unit dalam antrean perawatan hanya dapat dilepas melalui tugas perawatannya
+	if _, err := s.maintenanceRepo.FindOpenByUnitID(ctx, id); err == nil {
+		if unit.Status != req.Status {
+			return nil, entity.ErrUnitInMaintenance
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	log := &entity.ToyUnitLog{
+		ToyUnitID:     unit.ID,
+		StatusFrom:    unit.Status,
+		StatusTo:      req.Status,
+		ConditionFrom: unit.Condition,
+		ConditionTo:   req.Condition,
+		Notes:         req.Notes,
+		LoggedAt:      time.Now(),
+	}
+
+	switch {
+	case unit.Status != req.Status:
+		log.Event = entity.ToyUnitEventStatusChanged
+	case unit.Condition != req.Condition:
+		log.Event = entity.ToyUnitEventConditionChanged
+	default:
+		log = nil
+	}
+
+	unit.Barcode = req.Barcode
+	unit.Condition = req.Condition
+	unit.Status = req.Status
+	unit.Notes = req.Notes
+
+	if errs := unit.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if err := s.unitRepo.UpdateUnit(ctx, &unit, log); err != nil {
+		return nil, err
+	}
+
+	return &unit, nil
+}
+
+func (s *ToyUnitService) GetHistory(ctx context.Context, id string) (*entity.ToyUnitHistory, error) {
+	unit, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rentals, err := s.unitRepo.FindRentalHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.unitRepo.FindLogs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ToyUnitHistory{
+		Unit:    unit,
+		Rentals: rentals,
+		Logs:    logs,
+	}, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type IUserAddressService interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]entity.UserAddress, error)
+	FindOwned(ctx context.Context, userID uuid.UUID, id string) (*entity.UserAddress, error)
+	CreateAddress(ctx context.Context, userID uuid.UUID, req entity.UserAddressRequest) (*entity.UserAddress, error)
+	UpdateAddress(ctx context.Context, userID uuid.UUID, id string, req entity.UserAddressRequest) (*entity.UserAddress, error)
+	DeleteAddress(ctx context.Context, userID uuid.UUID, id string) error
+}
+
+type UserAddressService struct {
+	addressRepo repository.IUserAddressRepository
+}
+
+func NewUserAddressService(addressRepo repository.IUserAddressRepository) IUserAddressService {
+	return &UserAddressService{
+		addressRepo: addressRepo,
+	}
+}
+
+func (s *UserAddressService) FindByUserID(ctx context.Context, userID uuid.UUID) ([]entity.UserAddress, error) {
+	return s.addressRepo.FindByUserID(ctx, userID.String())
+}
+
+// FindOwned mengambil alamat milik pengguna, alamat pengguna lain dianggap tidak ditemukan
+func (s *UserAddressService) FindOwned(ctx context.Context, userID uuid.UUID, id string) (*entity.UserAddress, error) {
+	address, err := s.addressRepo.FindByUserAndID(ctx, userID.String(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entity.ErrAddressNotFound
+		}
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (s *UserAddressService) CreateAddress(ctx context.Context, userID uuid.UUID, req entity.UserAddressRequest) (*entity.UserAddress, error) {
+	address := &entity.UserAddress{UserID: userID}
+	applyUserAddressRequest(address, req)
+
+	if errs := address.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	count, err := s.addressRepo.CountByUserID(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		address.IsDefault = true
+	}
+
+	if err := s.addressRepo.Insert(ctx, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *UserAddressService) UpdateAddress(ctx context.Context, userID uuid.UUID, id string, req entity.UserAddressRequest) (*entity.UserAddress, error) {
+	address, err := s.FindOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Alamat utama hanya berpindah saat alamat lain dijadikan alamat utama
+	wasDefault := address.IsDefault
+	applyUserAddressRequest(address, req)
+	address.IsDefault = address.IsDefault || wasDefault
+
+	if errs := address.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	if err := s.addressRepo.UpdateById(ctx, id, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *UserAddressService) DeleteAddress(ctx context.Context, userID uuid.UUID, id string) error {
+	if _, err := s.FindOwned(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.addressRepo.DeleteById(ctx, id)
+}
+
+func applyUserAddressRequest(address *entity.UserAddress, req entity.UserAddressRequest) {
+	address.Label = strings.TrimSpace(req.Label)
+	address.RecipientName = strings.TrimSpace(req.RecipientName)
+	address.PhoneNumber = strings.TrimSpace(req.PhoneNumber)
+	address.Street = strings.TrimSpace(req.Street)
+	address.City = strings.TrimSpace(req.City)
+	address.PostalCode = strings.TrimSpace(req.PostalCode)
+	address.Latitude = req.Latitude
+	address.Longitude = req.Longitude
+	address.Notes = strings.TrimSpace(req.Notes)
+	address.IsDefault = req.IsDefault
+}
@@ -6,6 +6,7 @@ import (
 	"final-project/entity"
 	"final-project/repository"
 	"final-project/utils/helpers"
+	"github.com/gofrs/uuid/v5"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -13,6 +14,7 @@ import (
 type IUserService interface {
 	IBaseService[entity.User]
 	Login(ctx context.Context, emailOrUsername string, password string) (entity.User, entity.UserToken, error)
+	AssignBranch(ctx context.Context, id string, branchID *uuid.UUID) (*entity.User, error)
 }
 
 type UserService struct {
@@ -20,18 +22,21 @@ type UserService struct {
 	UserRepository      repository.IUserRepository
 	UserTokenRepository repository.IUserTokenRepository
 	JwtHelper           helpers.JWTHelper
+	BranchRepository    repository.IBranchRepository
 }
 
 func NewUserService(
 	userRepo repository.IUserRepository,
 	userTokenRepo repository.IUserTokenRepository,
 	jwtHelper helpers.JWTHelper,
+	branchRepo repository.IBranchRepository,
 ) IUserService {
 	return &UserService{
 		BaseService:         BaseService[entity.User]{repository: userRepo},
 		UserRepository:      userRepo,
 		UserTokenRepository: userTokenRepo,
 		JwtHelper:           jwtHelper,
+		BranchRepository:    branchRepo,
 	}
 }
 
@@ -69,7 +74,7 @@ func (s *UserService) Login(ctx context.Context, emailOrUsername string, passwor
 		return entity.User{}, entity.UserToken{}, errors.New("Password salah")
 	}
 
-	accessToken, accessTokenExp, _ := s.JwtHelper.GenerateAccessToken(user.ID, user.Email, user.Role)
+	accessToken, accessTokenExp, _ := s.JwtHelper.GenerateAccessToken(user.ID, user.Email, user.Role, user.BranchID)
 	refreshToken, refreshTokenExp, _ := s.JwtHelper.GenerateRefreshToken(user.ID)
 
 	userToken := &entity.UserToken{
@@ -87,3 +92,32 @@ func (s *UserService) Login(ctx context.Context, emailOrUsername string, passwor
 
 	return *user, *userToken, nil
 }
+
+// AssignBranch membatasi admin pada satu cabang, branchID nil menjadikannya admin pusat.
+// Perubahan berlaku pada token akses berikutnya setelah admin login ulang.
+func (s *UserService) AssignBranch(ctx context.Context, id string, branchID *uuid.UUID) (*entity.User, error) {
+	user, err := s.repository.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role != entity.RoleAdmin {
+		return nil, entity.ErrBranchAdminOnly
+	}
+
+	if branchID != nil {
+		branch, err := resolveBranch(ctx, s.BranchRepository, branchID)
+		if err != nil {
+			return nil, err
+		}
+		branchID = &branch.ID
+	}
+
+	if err := s.UserRepository.UpdateBranch(ctx, id, branchID); err != nil {
+		return nil, err
+	}
+
+	user.BranchID = branchID
+	user.Password = ""
+	return &user, nil
+}
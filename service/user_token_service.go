@@ -45,7 +45,7 @@ func (s *TokenService) RefreshToken(ctx context.Context, accessToken string, cla
 		return entity.UserToken{}, err
 	}
 
-	newAccessToken, accessTokenExp, err := s.jwtHelper.GenerateAccessToken(userToken.UserID, claimsToken.Email, claimsToken.Role)
+	newAccessToken, accessTokenExp, err := s.jwtHelper.GenerateAccessToken(userToken.UserID, claimsToken.Email, claimsToken.Role, claimsToken.BranchID)
 	if err != nil {
 		return entity.UserToken{}, err
 	}
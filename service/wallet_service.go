@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"final-project/entity"
+	"final-project/repository"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type IWalletService interface {
+	GetWallet(ctx context.Context, userID string) (*entity.Wallet, error)
+	FindEntries(ctx context.Context, userID string, limit int, offset int) ([]entity.WalletEntry, int64, error)
+	Adjust(ctx context.Context, userID string, adminID uuid.UUID, req entity.WalletAdjustmentRequest) (*entity.WalletEntry, error)
+}
+
+type WalletService struct {
+	walletRepo repository.IWalletRepository
+	userRepo   repository.IUserRepository
+}
+
+func NewWalletService(walletRepo repository.IWalletRepository, userRepo repository.IUserRepository) IWalletService {
+	return &WalletService{
+		walletRepo: walletRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// GetWallet mengembalikan dompet pengguna, dompet kosong bila belum pernah ada mutasi
+func (s *WalletService) GetWallet(ctx context.Context, userID string) (*entity.Wallet, error) {
+	user, err := s.userRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := s.walletRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &entity.Wallet{UserID: user.ID}, nil
+		}
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (s *WalletService) FindEntries(ctx context.Context, userID string, limit int, offset int) ([]entity.WalletEntry, int64, error) {
+	if _, err := s.userRepo.FindById(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+	return s.walletRepo.FindEntries(ctx, userID, limit, offset)
+}
+
+// Adjust mencatat penyesuaian saldo oleh admin beserta admin pembuatnya sebagai jejak audit
+func (s *WalletService) Adjust(ctx context.Context, userID string, adminID uuid.UUID, req entity.WalletAdjustmentRequest) (*entity.WalletEntry, error) {
+	if errs := req.Validate(); len(errs) > 0 {
+		return nil, errors.New("validasi gagal: " + errs[0])
+	}
+
+	user, err := s.userRepo.FindById(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &entity.WalletEntry{
+		UserID:    user.ID,
+		Type:      req.Type,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+		Notes:     req.Notes,
+		CreatedBy: &adminID,
+	}
+	if err := s.walletRepo.Post(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,91 @@
+// Package cache menyimpan hasil sementara di memori proses dengan masa berlaku per entri. Cache tidak
+// dibagi antar proses server, sehingga setiap proses bisa menyajikan data yang tertinggal paling lama
+// sebesar masa berlakunya.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// maxEntries membatasi jumlah entri agar kunci yang jarang dipakai tidak menumpuk
+const maxEntries = 1000
+
+type entry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// TTL adalah cache kunci-nilai yang aman dipakai bersamaan. TTL nol atau negatif mematikan cache,
+// begitu juga pointer nil.
+type TTL struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]entry
+}
+
+func NewTTL(ttl time.Duration) *TTL {
+	return &TTL{
+		ttl:     ttl,
+		entries: make(map[string]entry),
+	}
+}
+
+// Get mengambil nilai yang belum kedaluwarsa
+func (c *TTL) Get(key string) (any, bool) {
+	if c == nil || c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cached, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(cached.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return cached.value, true
+}
+
+// Set menyimpan nilai selama masa berlaku cache. Saat cache penuh entri kedaluwarsa dibuang lebih dulu,
+// bila masih penuh seluruh isinya dikosongkan.
+func (c *TTL) Set(key string, value any) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= maxEntries {
+		for k, cached := range c.entries {
+			if now.After(cached.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxEntries {
+			c.entries = make(map[string]entry)
+		}
+	}
+
+	c.entries[key] = entry{
+		value:     value,
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// Purge mengosongkan cache, dipakai saat data sumbernya berubah
+func (c *TTL) Purge() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]entry)
+}
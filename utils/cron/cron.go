@@ -0,0 +1,160 @@
+// Package cron membaca jadwal berformat cron lima kolom (menit jam tanggal bulan hari) tanpa dependensi luar.
+// Setiap kolom menerima *, angka, rentang a-b, daftar dipisah koma dan langkah /n. Singkatan
+// @hourly, @daily, @weekly dan @monthly juga didukung.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type field struct {
+	name     string
+	min, max int
+}
+
+var fields = []field{
+	{"menit", 0, 59},
+	{"jam", 0, 23},
+	{"tanggal", 1, 31},
+	{"bulan", 1, 12},
+	{"hari", 0, 7}, // 0 dan 7 sama-sama Minggu
+}
+
+var macros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// Schedule adalah jadwal hasil Parse. Nilai yang cocok disimpan sebagai bitmask per kolom.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// Sesuai cron standar, bila tanggal dan hari sama-sama dibatasi maka cukup salah satu yang cocok
+	domStar, dowStar bool
+}
+
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if macro, ok := macros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return Schedule{}, errors.New("jadwal cron harus terdiri dari 5 kolom: menit jam tanggal bulan hari")
+	}
+
+	masks := make([]uint64, len(fields))
+	for i, part := range parts {
+		mask, err := parseField(part, fields[i])
+		if err != nil {
+			return Schedule{}, err
+		}
+		masks[i] = mask
+	}
+
+	// Minggu boleh ditulis 7, disimpan sebagai 0 agar sama dengan time.Sunday
+	if masks[4]&(1<<7) != 0 {
+		masks[4] = masks[4]&^(1<<7) | 1
+	}
+
+	return Schedule{
+		minute:  masks[0],
+		hour:    masks[1],
+		dom:     masks[2],
+		month:   masks[3],
+		dow:     masks[4],
+		domStar: parts[2] == "*",
+		dowStar: parts[4] == "*",
+	}, nil
+}
+
+func parseField(value string, f field) (uint64, error) {
+	var mask uint64
+	for _, item := range strings.Split(value, ",") {
+		step := 1
+		if rangePart, stepPart, ok := strings.Cut(item, "/"); ok {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("langkah kolom %s tidak valid: %s", f.name, item)
+			}
+			step = n
+			item = rangePart
+		}
+
+		low, high := f.min, f.max
+		switch {
+		case item == "*":
+		case strings.Contains(item, "-"):
+			from, to, _ := strings.Cut(item, "-")
+			var err1, err2 error
+			low, err1 = strconv.Atoi(from)
+			high, err2 = strconv.Atoi(to)
+			if err1 != nil || err2 != nil || low > high {
+				return 0, fmt.Errorf("rentang kolom %s tidak valid: %s", f.name, item)
+			}
+		default:
+			n, err := strconv.Atoi(item)
+			if err != nil {
+				return 0, fmt.Errorf("nilai kolom %s tidak valid: %s", f.name, item)
+			}
+			low = n
+			// Angka tunggal dengan langkah seperti 5/15 berarti mulai dari 5 sampai batas atas
+			if step == 1 {
+				high = n
+			}
+		}
+
+		if low < f.min || high > f.max {
+			return 0, fmt.Errorf("kolom %s harus antara %d-%d: %s", f.name, f.min, f.max, item)
+		}
+		for i := low; i <= high; i += step {
+			mask |= 1 << uint(i)
+		}
+	}
+	return mask, nil
+}
+
+// Next mengembalikan waktu jadwal berikutnya setelah t, dihitung dalam zona waktu t.
+// Nilai nol dikembalikan bila tidak ada jadwal dalam lima tahun, misalnya tanggal 30 Februari.
+func (s Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
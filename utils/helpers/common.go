@@ -1,6 +1,10 @@
 package helpers
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
 
 func ParseToInt(value string) int {
 	result, err := strconv.Atoi(value)
@@ -9,3 +13,22 @@ func ParseToInt(value string) int {
 	}
 	return result
 }
+
+// Slugify mengubah teks bebas menjadi slug URL (huruf kecil, angka dan tanda hubung)
+func Slugify(value string) string {
+	var builder strings.Builder
+	lastDash := true
+
+	for _, r := range strings.ToLower(value) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			builder.WriteRune(r)
+			lastDash = false
+		case !lastDash:
+			builder.WriteRune('-')
+			lastDash = true
+		}
+	}
+
+	return strings.TrimSuffix(builder.String(), "-")
+}
@@ -19,6 +19,8 @@ type ClaimsToken struct {
 	UserID uuid.UUID `json:"user_id"`
 	Email  string    `json:"email"`
 	Role   string    `json:"role"`
+	// BranchID membatasi admin pada satu cabang, kosong untuk admin pusat dan pelanggan
+	BranchID *uuid.UUID `json:"branch_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -39,13 +41,14 @@ func NewJWTHelper(jwtSecret string, accessTokenExp int, refreshTokenExp int, iss
 }
 
 // GenerateAccessToken membuat token akses baru
-func (j *JWTHelper) GenerateAccessToken(userID uuid.UUID, email, role string) (string, time.Time, error) {
+func (j *JWTHelper) GenerateAccessToken(userID uuid.UUID, email, role string, branchID *uuid.UUID) (string, time.Time, error) {
 	expiryTime := time.Now().Add(j.accessTokenExpiry)
 
 	claims := &ClaimsToken{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:   userID,
+		Email:    email,
+		Role:     role,
+		BranchID: branchID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiryTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
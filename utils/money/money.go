@@ -0,0 +1,183 @@
+// Package money menyimpan nominal rupiah sebagai bilangan bulat agar penjumlahan rincian
+// selalu sama persis dengan total yang ditagihkan. Seluruh aturan pembulatan ada di paket ini.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money adalah nominal dalam rupiah utuh (tanpa sen). Payment gateway hanya menerima
+// rupiah utuh sehingga setiap nominal dibulatkan saat dibuat, bukan saat dikirim.
+type Money int64
+
+// FromFloat membulatkan nominal pecahan ke rupiah terdekat, setengah dibulatkan menjauhi nol
+func FromFloat(amount float64) Money {
+	return Money(math.Round(amount))
+}
+
+func (m Money) Float() float64 {
+	return float64(m)
+}
+
+func (m Money) Int64() int64 {
+	return int64(m)
+}
+
+// Mul mengalikan nominal dengan jumlah unit atau hari
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// MulDiv menghitung m * num / den dengan pembulatan ke rupiah terdekat tanpa kehilangan presisi
+func (m Money) MulDiv(num, den int64) Money {
+	if den == 0 {
+		return 0
+	}
+
+	product := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(num))
+	divisor := big.NewInt(den)
+	if divisor.Sign() < 0 {
+		product.Neg(product)
+		divisor.Neg(divisor)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(product, divisor, new(big.Int))
+	remainder.Abs(remainder).Mul(remainder, big.NewInt(2))
+	if remainder.Cmp(divisor) >= 0 {
+		if product.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return Money(quotient.Int64())
+}
+
+// Percent mengambil persentase dari nominal, contoh 15000.Percent(10) = 1500
+func (m Money) Percent(percent float64) Money {
+	return m.MulDiv(BasisPoints(percent), 10000)
+}
+
+// Prorate mengambil bagian nominal sebanding dengan part / whole
+func (m Money) Prorate(part, whole Money) Money {
+	return m.MulDiv(int64(part), int64(whole))
+}
+
+// BasisPoints mengubah persen dengan dua desimal menjadi bilangan bulat (11.5% = 1150)
+func BasisPoints(percent float64) int64 {
+	return int64(math.Round(percent * 100))
+}
+
+func Min(a, b Money) Money {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func Max(a, b Money) Money {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func Sum(amounts ...Money) Money {
+	var total Money
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
+
+// String menampilkan nominal dalam format rupiah, contoh Rp 1.250.000
+func (m Money) String() string {
+	value := int64(m)
+	negative := value < 0
+	if negative {
+		value = -value
+	}
+	digits := strconv.FormatInt(value, 10)
+
+	var builder strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			builder.WriteByte('.')
+		}
+		builder.WriteRune(r)
+	}
+
+	if negative {
+		return "-Rp " + builder.String()
+	}
+	return "Rp " + builder.String()
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(m), 10), nil
+}
+
+// UnmarshalJSON menerima angka bulat maupun pecahan dari klien lalu membulatkannya
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return fmt.Errorf("money: nominal harus berupa angka: %w", err)
+	}
+
+	parsed, err := Parse(number.String())
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Parse membaca nominal desimal seperti "1250.50" dari database atau input teks
+func Parse(value string) (Money, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("money: nominal %q tidak valid", value)
+	}
+	return FromFloat(amount), nil
+}
+
+// Scan membaca kolom decimal maupun hasil agregasi (SUM, COALESCE) dari database
+func (m *Money) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*m = 0
+	case int64:
+		*m = Money(v)
+	case float64:
+		*m = FromFloat(v)
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	default:
+		return fmt.Errorf("money: tipe %T tidak didukung", value)
+	}
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return int64(m), nil
+}
@@ -0,0 +1,96 @@
+package money
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMulDiv(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		num, den int64
+		want     Money
+	}{
+		{"pembagian pas", 15000, 1, 3, 5000},
+		{"pecahan di bawah setengah dibulatkan ke bawah", 10, 1, 3, 3},
+		{"pecahan di atas setengah dibulatkan ke atas", 20, 1, 3, 7},
+		{"setengah dibulatkan menjauhi nol", 5, 1, 2, 3},
+		{"setengah negatif dibulatkan menjauhi nol", -5, 1, 2, -3},
+		{"penyebut negatif", 5, 1, -2, -3},
+		{"pembilang dan penyebut negatif", 5, -1, -2, 3},
+		{"penyebut nol", 15000, 1, 0, 0},
+		{"nominal nol", 0, 7, 3, 0},
+		{"hasil kali melebihi int64", math.MaxInt64 / 2, 4, 4, math.MaxInt64 / 2},
+		{"PPN inklusif 11%", 111000, 1100, 11100, 11000},
+		{"PPN inklusif 11% dengan pembulatan", 9999, 1100, 11100, 991},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.amount.MulDiv(tt.num, tt.den); got != tt.want {
+				t.Errorf("%d.MulDiv(%d, %d) = %d, ingin %d", tt.amount, tt.num, tt.den, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		amount  Money
+		percent float64
+		want    Money
+	}{
+		{15000, 10, 1500},
+		{12345, 11, 1358},
+		{12345, 11.5, 1420},
+		{100, 0.5, 1},
+		{99, 0.5, 0},
+		{-12345, 11, -1358},
+		{15000, 0, 0},
+		{15000, 100, 15000},
+		{1, 33.33, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.Percent(tt.percent); got != tt.want {
+			t.Errorf("%d.Percent(%v) = %d, ingin %d", tt.amount, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestBasisPoints(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    int64
+	}{
+		{11, 1100},
+		{11.5, 1150},
+		{0.01, 1},
+		{0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := BasisPoints(tt.percent); got != tt.want {
+			t.Errorf("BasisPoints(%v) = %d, ingin %d", tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		amount, part, whole Money
+		want                Money
+	}{
+		{11000, 50000, 111000, 4955},
+		{11000, 111000, 111000, 11000},
+		{11000, 0, 111000, 0},
+		{11000, 50000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.Prorate(tt.part, tt.whole); got != tt.want {
+			t.Errorf("%d.Prorate(%d, %d) = %d, ingin %d", tt.amount, tt.part, tt.whole, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package pdf menulis dokumen PDF sederhana (teks dan garis) tanpa dependensi luar.
+// Font yang dipakai adalah Helvetica bawaan PDF sehingga tidak perlu disematkan.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	PageWidth  = 595.28 // A4 dalam point
+	PageHeight = 841.89
+)
+
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+)
+
+type Document struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+}
+
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+func (d *Document) AddPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+}
+
+// Text menulis teks dengan titik awal di kiri atas. Koordinat y dihitung dari tepi atas halaman.
+func (d *Document) Text(x, y, size float64, bold bool, text string) {
+	font := fontRegular
+	if bold {
+		font = fontBold
+	}
+	fmt.Fprintf(d.current, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y-size, escape(text))
+}
+
+// TextRight menulis teks rata kanan terhadap x
+func (d *Document) TextRight(x, y, size float64, bold bool, text string) {
+	d.Text(x-TextWidth(text, size, bold), y, size, bold, text)
+}
+
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(d.current, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Bytes menyusun seluruh halaman menjadi berkas PDF
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	writeObject := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+
+	// Objek 1 katalog, 2 daftar halaman, 3-4 font, selanjutnya pasangan halaman dan isinya
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	writeObject("<< /Type /Catalog /Pages 2 0 R >>")
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	writeObject("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range d.pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, fontRegular, fontBold, 6+i*2))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// escape menyesuaikan teks dengan string literal PDF. Karakter di luar Latin-1 diganti tanda tanya.
+func escape(text string) string {
+	var builder strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			builder.WriteByte('\\')
+			builder.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			builder.WriteByte(' ')
+		case r > 0xFF:
+			builder.WriteByte('?')
+		default:
+			builder.WriteByte(byte(r))
+		}
+	}
+	return builder.String()
+}
+
+// TextWidth memperkirakan lebar teks Helvetica dalam point. Angka dan tanda baca memakai
+// lebar sebenarnya agar kolom nominal rata kanan dengan rapi.
+func TextWidth(text string, size float64, bold bool) float64 {
+	var units float64
+	for _, r := range text {
+		switch {
+		case r >= '0' && r <= '9':
+			units += 556
+		case r == '.' || r == ',' || r == ' ' || r == ':':
+			units += 278
+		case r == '-' || r == '(' || r == ')':
+			units += 333
+		case r == '%':
+			units += 889
+		case r == 'i' || r == 'l' || r == 'I' || r == 'j':
+			units += 278
+		case r == 'm' || r == 'M' || r == 'W':
+			units += 833
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		default:
+			units += 556
+		}
+	}
+	if bold {
+		units *= 1.05
+	}
+	return units * size / 1000
+}
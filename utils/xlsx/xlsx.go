@@ -0,0 +1,283 @@
+// Package xlsx menulis workbook Excel (Office Open XML) sederhana tanpa dependensi luar.
+// Baris ditulis langsung ke zip sehingga laporan besar tidak perlu ditampung di memori.
+package xlsx
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	styleDefault = iota
+	styleHeader
+	styleInteger
+	styleDecimal
+	styleDate
+)
+
+type cellKind int
+
+const (
+	kindText cellKind = iota
+	kindNumber
+)
+
+// Cell adalah satu sel. Buat dengan Text, Header, Integer, Decimal atau Date.
+type Cell struct {
+	kind   cellKind
+	text   string
+	number float64
+	style  int
+}
+
+func Text(value string) Cell {
+	return Cell{kind: kindText, text: value, style: styleDefault}
+}
+
+// Header adalah teks tebal untuk baris judul kolom
+func Header(value string) Cell {
+	return Cell{kind: kindText, text: value, style: styleHeader}
+}
+
+// Integer ditampilkan dengan pemisah ribuan sesuai pengaturan Excel pembaca
+func Integer(value int64) Cell {
+	return Cell{kind: kindNumber, number: float64(value), style: styleInteger}
+}
+
+func Decimal(value float64) Cell {
+	return Cell{kind: kindNumber, number: value, style: styleDecimal}
+}
+
+// Date disimpan sebagai nomor seri Excel agar tetap bisa diurutkan dan dihitung
+func Date(value time.Time) Cell {
+	return Cell{kind: kindNumber, number: serial(value), style: styleDate}
+}
+
+type Writer struct {
+	zip    *zip.Writer
+	sheets []string
+	index  int
+	sheet  io.Writer
+	row    int
+}
+
+// NewWriter menulis bagian tetap workbook lalu membuka lembar pertama. dateFormat adalah kode
+// format tanggal Excel, misalnya "yyyy-mm-dd" atau "dd/mm/yyyy".
+func NewWriter(w io.Writer, dateFormat string, sheetNames ...string) (*Writer, error) {
+	if len(sheetNames) == 0 {
+		return nil, errors.New("xlsx: minimal satu lembar")
+	}
+
+	x := &Writer{zip: zip.NewWriter(w), index: -1}
+	for i, name := range sheetNames {
+		x.sheets = append(x.sheets, sheetName(name, i))
+	}
+
+	parts := []struct {
+		name, body string
+	}{
+		{"[Content_Types].xml", x.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", x.workbook()},
+		{"xl/_rels/workbook.xml.rels", x.workbookRels()},
+		{"xl/styles.xml", fmt.Sprintf(styles, escape(dateFormat))},
+	}
+	for _, part := range parts {
+		f, err := x.zip.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := x.NextSheet(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// NextSheet menutup lembar aktif dan membuka lembar berikutnya sesuai urutan nama di NewWriter
+func (x *Writer) NextSheet() error {
+	if err := x.closeSheet(); err != nil {
+		return err
+	}
+	if x.index+1 >= len(x.sheets) {
+		return errors.New("xlsx: tidak ada lembar berikutnya")
+	}
+
+	x.index++
+	f, err := x.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", x.index+1))
+	if err != nil {
+		return err
+	}
+	x.sheet = f
+	x.row = 0
+	_, err = io.WriteString(f, xmlHeader+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return err
+}
+
+func (x *Writer) WriteRow(cells ...Cell) error {
+	x.row++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, x.row)
+	for i, cell := range cells {
+		ref := columnName(i) + strconv.Itoa(x.row)
+		switch cell.kind {
+		case kindNumber:
+			fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, cell.style, strconv.FormatFloat(cell.number, 'f', -1, 64))
+		default:
+			fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, cell.style, escape(cell.text))
+		}
+	}
+	b.WriteString(`</row>`)
+
+	_, err := io.WriteString(x.sheet, b.String())
+	return err
+}
+
+// Close menutup lembar aktif, membuat lembar kosong untuk nama yang belum dipakai, lalu menutup zip
+func (x *Writer) Close() error {
+	for x.index+1 < len(x.sheets) {
+		if err := x.NextSheet(); err != nil {
+			return err
+		}
+	}
+	if err := x.closeSheet(); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
+
+func (x *Writer) closeSheet() error {
+	if x.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(x.sheet, `</sheetData></worksheet>`)
+	x.sheet = nil
+	return err
+}
+
+func (x *Writer) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range x.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (x *Writer) workbook() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range x.sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (x *Writer) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range x.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(x.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// sheetName menyesuaikan nama lembar dengan batasan Excel (maksimal 31 karakter, tanpa []:*?/\)
+func sheetName(name string, index int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", index+1)
+	}
+	return name
+}
+
+// columnName mengubah indeks kolom mulai nol menjadi huruf kolom Excel (A, B, ..., AA)
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// serial mengubah waktu menjadi nomor seri tanggal Excel (hari sejak 30 Desember 1899)
+func serial(t time.Time) float64 {
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return t.Sub(epoch).Hours() / 24
+}
+
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			// Karakter kontrol selain tab dan baris baru tidak sah di XML
+			if r < 0x20 && r != '\t' && r != '\n' && r != '\r' {
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles memuat urutan cellXfs yang sama dengan konstanta style di atas. numFmtId 3 adalah #,##0 dan 4 adalah #,##0.00.
+const styles = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="%s"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="5">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="3" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`